@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
 	"time"
 
@@ -14,10 +16,16 @@ import (
 	"sql2api/internal/config"
 	"sql2api/internal/handler"
 	"sql2api/internal/middleware"
+	"sql2api/internal/model"
 	"sql2api/internal/repository"
+	"sql2api/internal/rpc"
 	"sql2api/internal/service"
+	"sql2api/internal/tracing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 )
 
 // Server HTTP 服务器结构
@@ -25,11 +33,15 @@ type Server struct {
 	config        *config.Config
 	router        *gin.Engine
 	server        *http.Server
+	adminServer   *http.Server // 管理 API 独立监听，未启用时为 nil
+	grpcServer    *grpc.Server
 	repos         *repository.Repositories
 	services      *service.Services
 	handlers      *handler.Handlers
 	ipManager     *middleware.IPWhitelistManager
 	apiKeyManager *middleware.APIKeyManager
+	stopTracing   func(context.Context) error
+	startTime     time.Time
 }
 
 // NewServer 创建新的服务器实例
@@ -49,7 +61,18 @@ func NewServer() (*Server, error) {
 	docs.SwaggerInfo.Schemes = cfg.Swagger.Schemes
 
 	server := &Server{
-		config: cfg,
+		config:    cfg,
+		startTime: time.Now(),
+	}
+
+	// 初始化链路追踪（需在创建服务之前设置全局 TracerProvider）
+	stopTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	server.stopTracing = stopTracing
+	if cfg.Tracing.Enabled {
+		fmt.Printf("✅ Tracing enabled (exporter: %s)\n", cfg.Tracing.Exporter)
 	}
 
 	// 初始化组件
@@ -65,6 +88,12 @@ func NewServer() (*Server, error) {
 	// 创建 HTTP 服务器
 	server.createHTTPServer()
 
+	// 创建管理 API 服务器
+	server.createAdminServer()
+
+	// 创建 gRPC 服务器
+	server.createGRPCServer()
+
 	return server, nil
 }
 
@@ -84,7 +113,7 @@ func (s *Server) initializeComponents() error {
 
 	// 初始化服务层
 	if s.repos != nil {
-		services, err := service.NewServices(s.repos, &s.config)
+		services, err := service.NewServices(s.repos, s.config)
 		if err != nil {
 			return fmt.Errorf("failed to initialize services: %w", err)
 		}
@@ -134,6 +163,14 @@ func (s *Server) setupRoutes() error {
 	// 创建 Gin 路由器
 	s.router = gin.New()
 
+	// HTTP 指标中间件最先注册，以覆盖所有请求
+	if s.config.Metrics.Enabled {
+		s.router.Use(middleware.MetricsMiddleware())
+	}
+	if s.config.Tracing.Enabled {
+		s.router.Use(middleware.TracingMiddleware())
+	}
+
 	// 设置路由
 	if s.handlers != nil {
 		handler.SetupRoutes(s.router, s.handlers, s.ipManager, s.apiKeyManager)
@@ -144,9 +181,28 @@ func (s *Server) setupRoutes() error {
 		fmt.Println("✅ Basic routes configured")
 	}
 
+	s.setupMetrics()
+
 	return nil
 }
 
+// setupMetrics 注册 Prometheus 指标端点
+// 端点在其他路由之后注册，因此同样受 IP 白名单限制，但不需要 API Key
+func (s *Server) setupMetrics() {
+	if !s.config.Metrics.Enabled {
+		return
+	}
+
+	if s.repos != nil {
+		if err := prometheus.Register(repository.NewPoolCollector(s.repos.GetDatabase())); err != nil {
+			log.Printf("Failed to register connection pool metrics: %v", err)
+		}
+	}
+
+	s.router.GET(s.config.Metrics.Path, gin.WrapH(promhttp.Handler()))
+	fmt.Printf("✅ Metrics endpoint configured on %s\n", s.config.Metrics.Path)
+}
+
 // setupBasicRoutes 设置基础路由（当完整服务不可用时）
 func (s *Server) setupBasicRoutes() {
 	// 应用基础中间件
@@ -188,6 +244,53 @@ func (s *Server) createHTTPServer() {
 	fmt.Printf("✅ HTTP server configured on %s\n", serverAddr)
 }
 
+// createAdminServer 创建管理 API 服务器，与业务 API 使用不同的端口
+func (s *Server) createAdminServer() {
+	if !s.config.Admin.Enabled {
+		return
+	}
+
+	var adminService service.AdminService
+	if s.services != nil {
+		adminService = s.services.Admin
+	}
+	admin := handler.NewAdminHandler(adminService, s.apiKeyManager, s.ipManager, model.BuildInfo{
+		Version:   version,
+		GitCommit: gitCommit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+		StartTime: s.startTime,
+	})
+
+	router := gin.New()
+	handler.SetupAdminRoutes(router, admin, s.ipManager, s.apiKeyManager, s.config.Admin.Pprof)
+
+	// CPU profile 和 trace 的采集时间由请求指定，因此不设置写超时
+	s.adminServer = &http.Server{
+		Addr:           s.config.Admin.GetAdminAddress(),
+		Handler:        router,
+		ReadTimeout:    time.Duration(s.config.Server.ReadTimeout) * time.Second,
+		IdleTimeout:    time.Duration(s.config.Server.IdleTimeout) * time.Second,
+		MaxHeaderBytes: 1 << 20, // 1MB
+	}
+
+	fmt.Printf("✅ Admin server configured on %s\n", s.adminServer.Addr)
+}
+
+// createGRPCServer 创建 gRPC 服务器（需要启用 gRPC 且 SQL 服务可用）
+func (s *Server) createGRPCServer() {
+	if !s.config.GRPC.Enabled {
+		return
+	}
+	if s.services == nil || s.services.SQL == nil {
+		fmt.Println("⚠️  gRPC server disabled: SQL service is not available")
+		return
+	}
+
+	s.grpcServer = rpc.NewServer(&s.config.GRPC, s.services.SQL, s.services.Audit, s.ipManager, s.apiKeyManager)
+	fmt.Printf("✅ gRPC server configured on %s\n", s.config.GetGRPCAddress())
+}
+
 // Start 启动服务器
 func (s *Server) Start() error {
 	// 启动服务器（在 goroutine 中）
@@ -200,6 +303,30 @@ func (s *Server) Start() error {
 		}
 	}()
 
+	// 启动管理 API 服务器
+	if s.adminServer != nil {
+		go func() {
+			fmt.Printf("🚀 Admin server starting on %s\n", s.adminServer.Addr)
+			if err := s.adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start admin server: %v", err)
+			}
+		}()
+	}
+
+	// 启动 gRPC 服务器
+	if s.grpcServer != nil {
+		listener, err := net.Listen("tcp", s.config.GetGRPCAddress())
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", s.config.GetGRPCAddress(), err)
+		}
+		go func() {
+			fmt.Printf("🚀 gRPC server starting on %s\n", listener.Addr())
+			if err := s.grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// 等待中断信号以优雅关闭服务器
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -211,8 +338,46 @@ func (s *Server) Start() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// 优雅关闭 gRPC 服务器，超时后强制关闭
+	if s.grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			s.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			s.grpcServer.Stop()
+		}
+	}
+
+	// 结束变更订阅的长连接，否则 HTTP 服务器会等待其超时
+	if s.services != nil {
+		s.services.CloseSubscriptions()
+	}
+
 	// 优雅关闭服务器
-	if err := s.server.Shutdown(ctx); err != nil {
+	err := s.server.Shutdown(ctx)
+
+	// 关闭管理 API 服务器，正在采集的 profile 不需要等待
+	if s.adminServer != nil {
+		if err := s.adminServer.Close(); err != nil {
+			log.Printf("Failed to close admin server: %v", err)
+		}
+	}
+
+	// 停止 webhook 投递，未投递的记录保留在本地队列中
+	if s.services != nil {
+		s.services.Close()
+	}
+
+	// 导出尚未发送的 span
+	if err := s.stopTracing(ctx); err != nil {
+		log.Printf("Failed to shutdown tracing: %v", err)
+	}
+
+	if err != nil {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
@@ -237,8 +402,13 @@ func (s *Server) PrintStartupInfo() {
 	fmt.Println("SQL2API Server - Starting...")
 	fmt.Printf("Configuration loaded successfully:\n")
 	fmt.Printf("- Server: %s\n", s.config.Server.GetServerAddress())
+	if s.config.GRPC.Enabled {
+		fmt.Printf("- gRPC: %s\n", s.config.GetGRPCAddress())
+	}
+	if s.config.Admin.Enabled {
+		fmt.Printf("- Admin: %s\n", s.config.Admin.GetAdminAddress())
+	}
 	fmt.Printf("- Database: %s\n", s.config.Database.Type)
-	fmt.Printf("- JWT Issuer: %s\n", s.config.JWT.Issuer)
 	fmt.Printf("- Log Level: %s\n", s.config.Log.Level)
 	fmt.Printf("- IP Whitelist: %v\n", s.config.Security.IPWhitelist)
 }
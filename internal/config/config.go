@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"regexp"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -16,6 +19,10 @@ type Config struct {
 	Swagger  SwaggerConfig  `mapstructure:"swagger"`
 	APIKeys  APIKeyConfig   `mapstructure:"api_keys"`
 	SQL      SQLConfig      `mapstructure:"sql"`
+	GRPC     GRPCConfig     `mapstructure:"grpc"`
+	Metrics  MetricsConfig  `mapstructure:"metrics"`
+	Tracing  TracingConfig  `mapstructure:"tracing"`
+	Admin    AdminConfig    `mapstructure:"admin"`
 }
 
 // ServerConfig 服务器配置
@@ -28,9 +35,49 @@ type ServerConfig struct {
 	IdleTimeout  int    `mapstructure:"idle_timeout"`  // 秒
 }
 
+// GRPCConfig gRPC 服务配置（与 HTTP 服务共用 server.host）
+type GRPCConfig struct {
+	Enabled        bool `mapstructure:"enabled"`          // 是否启用 gRPC 服务
+	Port           int  `mapstructure:"port"`             // 监听端口
+	MaxMessageSize int  `mapstructure:"max_message_size"` // 单条消息的最大字节数
+}
+
+// AdminConfig 管理 API 配置（独立监听，所有端点需要 admin 权限）
+type AdminConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // 是否启用管理 API
+	Host    string `mapstructure:"host"`    // 监听地址，默认只监听本机
+	Port    int    `mapstructure:"port"`    // 监听端口，不能与 server.port 和 grpc.port 相同
+	Pprof   bool   `mapstructure:"pprof"`   // 是否开放 /debug/pprof 性能分析端点
+}
+
+// MetricsConfig Prometheus 指标配置
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // 是否启用指标端点
+	Path    string `mapstructure:"path"`    // 指标端点路径
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置
+type TracingConfig struct {
+	Enabled     bool              `mapstructure:"enabled"`      // 是否启用链路追踪
+	Exporter    string            `mapstructure:"exporter"`     // 导出方式：otlp、stdout、file
+	Endpoint    string            `mapstructure:"endpoint"`     // OTLP/HTTP 接收地址（host:port）
+	Insecure    bool              `mapstructure:"insecure"`     // OTLP 是否使用 HTTP 而不是 HTTPS
+	Headers     map[string]string `mapstructure:"headers"`      // OTLP 请求附加的请求头（如认证信息）
+	File        string            `mapstructure:"file"`         // file 导出方式的文件路径（每行一个 span）
+	ServiceName string            `mapstructure:"service_name"` // 服务名称（service.name）
+	SampleRatio float64           `mapstructure:"sample_ratio"` // 采样比例（0-1），有上游 traceparent 时跟随上游的采样决定
+}
+
+// 链路追踪导出方式
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Type         string `mapstructure:"type"` // postgres, oracle
+	Type         string `mapstructure:"type"` // postgres, oracle, sqlite, mysql, sqlserver
 	Host         string `mapstructure:"host"`
 	Port         int    `mapstructure:"port"`
 	Username     string `mapstructure:"username"`
@@ -38,6 +85,7 @@ type DatabaseConfig struct {
 	Database     string `mapstructure:"database"`
 	Service      string `mapstructure:"service"`  // Oracle service name
 	SSLMode      string `mapstructure:"ssl_mode"` // PostgreSQL SSL mode
+	Path         string `mapstructure:"path"`     // SQLite 数据库文件路径（":memory:" 表示内存数据库）
 	MaxOpenConns int    `mapstructure:"max_open_conns"`
 	MaxIdleConns int    `mapstructure:"max_idle_conns"`
 	MaxLifetime  int    `mapstructure:"max_lifetime"` // 分钟
@@ -66,6 +114,7 @@ type SwaggerConfig struct {
 	Host        string   `mapstructure:"host"`         // API 主机地址
 	BasePath    string   `mapstructure:"base_path"`    // API 基础路径
 	Schemes     []string `mapstructure:"schemes"`      // 支持的协议
+	Servers     []string `mapstructure:"servers"`      // /openapi.json 中的服务器地址，为空时使用请求地址
 }
 
 // APIKeyConfig API Key 配置
@@ -88,14 +137,399 @@ type APIKeyItem struct {
 
 // SQLConfig SQL 功能配置
 type SQLConfig struct {
-	Enabled            bool     `mapstructure:"enabled"`              // 是否启用 SQL 功能
-	AllowedTables      []string `mapstructure:"allowed_tables"`       // 允许访问的表列表
-	AllowedActions     []string `mapstructure:"allowed_actions"`      // 允许的操作类型
-	MaxQueryTime       int      `mapstructure:"max_query_time"`       // 最大查询时间（秒）
-	MaxResultSize      int      `mapstructure:"max_result_size"`      // 最大结果集大小（行数）
-	EnableRawSQL       bool     `mapstructure:"enable_raw_sql"`       // 是否允许原生 SQL
-	EnableBatch        bool     `mapstructure:"enable_batch"`         // 是否启用批量操作
-	EnableTransactions bool     `mapstructure:"enable_transactions"`  // 是否启用事务支持
+	Enabled            bool               `mapstructure:"enabled"`             // 是否启用 SQL 功能
+	AllowedTables      []string           `mapstructure:"allowed_tables"`      // 允许访问的表列表
+	AllowedActions     []string           `mapstructure:"allowed_actions"`     // 允许的操作类型
+	MaxQueryTime       int                `mapstructure:"max_query_time"`      // 最大查询时间（秒）
+	MaxResultSize      int                `mapstructure:"max_result_size"`     // 最大结果集大小（行数）
+	EnableRawSQL       bool               `mapstructure:"enable_raw_sql"`      // 是否允许原生 SQL
+	EnableBatch        bool               `mapstructure:"enable_batch"`        // 是否启用批量操作
+	EnableTransactions bool               `mapstructure:"enable_transactions"` // 是否启用事务支持
+	ValidateWrites     bool               `mapstructure:"validate_writes"`     // 写入前按表结构校验并转换数据
+	Routines           []RoutineConfig    `mapstructure:"routines"`            // 允许调用的存储过程和函数
+	SavedQueries       []SavedQueryConfig `mapstructure:"saved_queries"`       // 以 REST 端点发布的命名查询
+	Resources          []ResourceConfig   `mapstructure:"resources"`           // 以 REST 资源发布的表
+	Subscriptions      SubscriptionConfig `mapstructure:"subscriptions"`       // 表变更订阅（SSE / WebSocket）
+	Webhooks           WebhookConfig      `mapstructure:"webhooks"`            // 网关写操作的出站 webhook
+	Import             ImportConfig       `mapstructure:"import"`              // CSV / NDJSON 批量数据导入
+	Versioning         []VersionConfig    `mapstructure:"versioning"`          // 乐观并发控制使用的版本列
+	SoftDelete         []SoftDeleteConfig `mapstructure:"soft_delete"`         // 软删除的表
+	Audit              AuditConfig        `mapstructure:"audit"`               // 写操作和权限拒绝的审计日志
+	StatementStats     StatementStatsConfig `mapstructure:"statement_stats"`   // 按语句指纹汇总的执行统计和慢查询日志
+}
+
+// 变更事件来源
+const (
+	SubscriptionSourceGateway = "gateway" // 由 SQLEngine 在网关执行写操作后发出
+	SubscriptionSourceNotify  = "notify"  // 由服务器管理的 PostgreSQL 触发器通过 LISTEN/NOTIFY 发出
+)
+
+// SubscriptionConfig 表变更订阅配置
+type SubscriptionConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`            // 是否启用变更订阅
+	Source            string `mapstructure:"source"`             // 事件来源：gateway 或 notify（仅 PostgreSQL）
+	Channel           string `mapstructure:"channel"`            // notify 模式使用的通知通道名
+	BufferSize        int    `mapstructure:"buffer_size"`        // 每个订阅缓冲的事件数，缓冲满时断开订阅
+	HeartbeatInterval int    `mapstructure:"heartbeat_interval"` // 心跳间隔（秒）
+}
+
+// ImportConfig 批量数据导入配置
+type ImportConfig struct {
+	Enabled      bool `mapstructure:"enabled"`        // 是否启用数据导入
+	ChunkSize    int  `mapstructure:"chunk_size"`     // 默认每次提交的行数
+	MaxChunkSize int  `mapstructure:"max_chunk_size"` // 请求可指定的最大提交行数
+	MaxErrors    int  `mapstructure:"max_errors"`     // 默认允许拒绝的行数，超过时中止导入（-1 表示不限制）
+}
+
+// 审计日志的存储方式
+const (
+	AuditSinkDatabase = "database" // 写入所连接数据库中的审计表
+	AuditSinkFile     = "file"     // 追加写入 JSONL 文件，每条记录包含前一条记录的哈希
+)
+
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`        // 是否启用审计日志
+	Sinks        []string `mapstructure:"sinks"`          // 存储方式：database、file，可同时使用
+	Table        string   `mapstructure:"table"`          // database 方式使用的审计表（不能在 allowed_tables 中）
+	File         string   `mapstructure:"file"`           // file 方式使用的 JSONL 文件路径
+	Redact       []string `mapstructure:"redact"`         // 需要脱敏的列名和参数名（不区分大小写，支持 * 通配符）
+	RowImages    []string `mapstructure:"row_images"`     // 记录修改前后行数据的表，"*" 表示所有表
+	MaxImageRows int      `mapstructure:"max_image_rows"` // 单条语句记录的最大行数，超出时不记录行数据
+}
+
+// StatementStatsConfig 语句统计配置
+type StatementStatsConfig struct {
+	Enabled       bool `mapstructure:"enabled"`         // 是否启用语句统计
+	MaxStatements int  `mapstructure:"max_statements"`  // 最多统计的语句指纹数，超出时淘汰执行次数最少的
+	SlowLogSize   int  `mapstructure:"slow_log_size"`   // 慢查询日志保留的最慢样本数
+	SlowLogWindow int  `mapstructure:"slow_log_window"` // 慢查询样本的保留时间（分钟）
+	SlowQueryMs   int  `mapstructure:"slow_query_ms"`   // 进入慢查询日志的最小耗时（毫秒），0 表示只按耗时排名
+}
+
+// HasSink 检查是否启用了指定的存储方式
+func (a *AuditConfig) HasSink(sink string) bool {
+	return containsFold(a.Sinks, sink)
+}
+
+// CapturesRowImages 检查表是否记录修改前后的行数据
+func (a *AuditConfig) CapturesRowImages(table string) bool {
+	return containsFold(a.RowImages, "*") || containsFold(a.RowImages, table)
+}
+
+// WebhookConfig 出站 webhook 配置
+type WebhookConfig struct {
+	Enabled        bool                    `mapstructure:"enabled"`         // 是否启用 webhook
+	QueuePath      string                  `mapstructure:"queue_path"`      // 本地投递队列（SQLite 文件）路径
+	Timeout        int                     `mapstructure:"timeout"`         // 单次投递超时（秒）
+	MaxAttempts    int                     `mapstructure:"max_attempts"`    // 最大投递次数，仍失败时移入死信列表
+	InitialBackoff int                     `mapstructure:"initial_backoff"` // 首次重试间隔（秒），之后每次翻倍
+	MaxBackoff     int                     `mapstructure:"max_backoff"`     // 最大重试间隔（秒）
+	Endpoints      []WebhookEndpointConfig `mapstructure:"endpoints"`       // 配置文件中定义的接收端点
+}
+
+// WebhookEndpointConfig webhook 接收端点配置
+type WebhookEndpointConfig struct {
+	Name        string   `mapstructure:"name" json:"name"`                           // 端点名称
+	URL         string   `mapstructure:"url" json:"url" binding:"required"`          // 接收地址（http 或 https）
+	Secret      string   `mapstructure:"secret" json:"secret,omitempty"`             // HMAC-SHA256 签名密钥，为空时不签名
+	Tables      []string `mapstructure:"tables" json:"tables,omitempty"`             // 订阅的表，为空时为所有允许访问的表
+	Actions     []string `mapstructure:"actions" json:"actions,omitempty"`           // 订阅的操作：insert, update, delete（默认全部）
+	IncludeRows bool     `mapstructure:"include_rows" json:"include_rows,omitempty"` // 是否在负载中包含行数据
+}
+
+// WebhookActions webhook 支持的操作
+var WebhookActions = []string{"insert", "update", "delete"}
+
+// Matches 检查端点是否订阅了指定表的操作
+func (w *WebhookEndpointConfig) Matches(table, action string) bool {
+	if len(w.Tables) > 0 && !containsFold(w.Tables, table) {
+		return false
+	}
+	return len(w.Actions) == 0 || containsFold(w.Actions, action)
+}
+
+// containsFold 检查列表中是否包含指定值（忽略大小写）
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWebhookEndpoint 验证 webhook 端点定义，订阅的表必须在允许列表中
+func ValidateWebhookEndpoint(endpoint *WebhookEndpointConfig, allowedTables []string) error {
+	if !identifierNamePattern.MatchString(endpoint.Name) {
+		return fmt.Errorf("invalid webhook name: %s", endpoint.Name)
+	}
+
+	target, err := url.Parse(endpoint.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("webhook %s: invalid url: %s", endpoint.Name, endpoint.URL)
+	}
+
+	for _, table := range endpoint.Tables {
+		if !containsFold(allowedTables, table) {
+			return fmt.Errorf("webhook %s: table %s is not in allowed_tables", endpoint.Name, table)
+		}
+	}
+	for _, action := range endpoint.Actions {
+		if !containsFold(WebhookActions, action) {
+			return fmt.Errorf("webhook %s: invalid action: %s", endpoint.Name, action)
+		}
+	}
+	return nil
+}
+
+// RoutineConfig 可调用的存储过程或函数配置
+type RoutineConfig struct {
+	Name    string               `mapstructure:"name"`    // API 中使用的名称，对应权限 sql.call.<name>
+	Routine string               `mapstructure:"routine"` // 数据库中的例程名（可带 schema 或 package 前缀），为空时使用 name
+	Kind    string               `mapstructure:"kind"`    // procedure 或 function
+	Returns string               `mapstructure:"returns"` // 函数返回类型：标量类型、rows（返回结果集）或 cursor（返回游标）
+	Params  []RoutineParamConfig `mapstructure:"params"`  // 参数列表，按声明顺序排列
+}
+
+// RoutineParamConfig 例程参数配置
+type RoutineParamConfig struct {
+	Name      string `mapstructure:"name"`      // 参数名
+	Type      string `mapstructure:"type"`      // string, integer, number, boolean, date, timestamp, cursor
+	Direction string `mapstructure:"direction"` // in, out, inout（默认 in）
+	Size      int    `mapstructure:"size"`      // 输出字符串的最大长度（Oracle 需要）
+}
+
+// GetRoutine 获取允许调用的例程配置
+func (c *SQLConfig) GetRoutine(name string) (*RoutineConfig, bool) {
+	for i := range c.Routines {
+		if c.Routines[i].Name == name {
+			return &c.Routines[i], true
+		}
+	}
+	return nil, false
+}
+
+// DatabaseName 获取数据库中的例程名
+func (r *RoutineConfig) DatabaseName() string {
+	if r.Routine != "" {
+		return r.Routine
+	}
+	return r.Name
+}
+
+// SavedQueryConfig 命名查询配置，发布为 /api/v1/queries/{name}
+type SavedQueryConfig struct {
+	Name        string             `mapstructure:"name" json:"name" binding:"required"`      // 查询名称，用于 URL
+	Description string             `mapstructure:"description" json:"description,omitempty"` // 查询描述
+	SQL         string             `mapstructure:"sql" json:"sql" binding:"required"`        // SQL 文本，参数使用 :name 形式
+	Params      []QueryParamConfig `mapstructure:"params" json:"params,omitempty"`           // 参数定义
+	Permission  string             `mapstructure:"permission" json:"permission,omitempty"`   // 所需权限，为空时为 query.<name>
+	CacheTTL    int                `mapstructure:"cache_ttl" json:"cache_ttl,omitempty"`     // 结果缓存时间（秒），0 表示不缓存
+	Datasource  string             `mapstructure:"datasource" json:"datasource,omitempty"`   // 数据源，目前仅支持 default
+}
+
+// QueryParamConfig 命名查询参数定义
+type QueryParamConfig struct {
+	Name      string        `mapstructure:"name" json:"name"`                       // 参数名
+	Type      string        `mapstructure:"type" json:"type"`                       // string, integer, number, boolean, date, timestamp
+	Required  bool          `mapstructure:"required" json:"required,omitempty"`     // 是否必填
+	Default   interface{}   `mapstructure:"default" json:"default,omitempty"`       // 默认值
+	Min       *float64      `mapstructure:"min" json:"min,omitempty"`               // 数值最小值
+	Max       *float64      `mapstructure:"max" json:"max,omitempty"`               // 数值最大值
+	MaxLength int           `mapstructure:"max_length" json:"max_length,omitempty"` // 字符串最大长度
+	Pattern   string        `mapstructure:"pattern" json:"pattern,omitempty"`       // 字符串正则约束
+	Enum      []interface{} `mapstructure:"enum" json:"enum,omitempty"`             // 允许的取值
+}
+
+// DefaultDatasource 默认数据源名称
+const DefaultDatasource = "default"
+
+// GetPermission 获取执行命名查询所需的权限
+func (q *SavedQueryConfig) GetPermission() string {
+	if q.Permission != "" {
+		return q.Permission
+	}
+	return "query." + q.Name
+}
+
+// ValidateSavedQuery 验证命名查询定义
+func ValidateSavedQuery(query *SavedQueryConfig) error {
+	if !identifierNamePattern.MatchString(query.Name) {
+		return fmt.Errorf("invalid saved query name: %s", query.Name)
+	}
+	if strings.TrimSpace(query.SQL) == "" {
+		return fmt.Errorf("saved query %s: sql is required", query.Name)
+	}
+	if query.CacheTTL < 0 {
+		return fmt.Errorf("saved query %s: cache_ttl cannot be negative", query.Name)
+	}
+	if query.Datasource != "" && query.Datasource != DefaultDatasource {
+		return fmt.Errorf("saved query %s: unknown datasource: %s", query.Name, query.Datasource)
+	}
+
+	validTypes := map[string]bool{
+		"string": true, "integer": true, "number": true, "boolean": true,
+		"date": true, "timestamp": true,
+	}
+
+	params := make(map[string]bool)
+	for _, param := range query.Params {
+		if !identifierNamePattern.MatchString(param.Name) {
+			return fmt.Errorf("saved query %s: invalid parameter name: %s", query.Name, param.Name)
+		}
+		if params[param.Name] {
+			return fmt.Errorf("saved query %s: duplicate parameter: %s", query.Name, param.Name)
+		}
+		params[param.Name] = true
+
+		if !validTypes[param.Type] {
+			return fmt.Errorf("saved query %s: invalid type for parameter %s: %s", query.Name, param.Name, param.Type)
+		}
+		if param.Min != nil && param.Max != nil && *param.Min > *param.Max {
+			return fmt.Errorf("saved query %s: parameter %s has min greater than max", query.Name, param.Name)
+		}
+		if param.Pattern != "" {
+			if _, err := regexp.Compile(param.Pattern); err != nil {
+				return fmt.Errorf("saved query %s: invalid pattern for parameter %s: %w", query.Name, param.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResourceConfig REST 资源配置，发布为 /api/v1/resources/{name}
+type ResourceConfig struct {
+	Name        string                `mapstructure:"name"`          // API 中的资源名称，对应权限 resource.<name>.<operation>
+	Table       string                `mapstructure:"table"`         // 数据库表名（需在 allowed_tables 中）
+	PrimaryKey  string                `mapstructure:"primary_key"`   // 主键列（默认 id）
+	KeyType     string                `mapstructure:"key_type"`      // 主键类型：integer（默认）或 string
+	Fields      []ResourceFieldConfig `mapstructure:"fields"`        // 公开的字段，为空时公开所有列
+	Operations  []string              `mapstructure:"operations"`    // 允许的操作：list, get, create, update, delete, restore（默认全部）
+	DefaultSort string                `mapstructure:"default_sort"`  // 默认排序，如 "created_at desc"
+	MaxPageSize int                   `mapstructure:"max_page_size"` // 每页最大行数（默认 100）
+}
+
+// ResourceFieldConfig 资源字段配置
+type ResourceFieldConfig struct {
+	Column   string `mapstructure:"column"`    // 数据库列名
+	Name     string `mapstructure:"name"`      // API 中的字段名，为空时使用列名
+	ReadOnly bool   `mapstructure:"read_only"` // 是否只读（不能通过创建或更新写入）
+}
+
+// 版本列类型
+const (
+	VersionTypeInteger   = "integer"   // 每次更新加 1
+	VersionTypeTimestamp = "timestamp" // 每次更新设为当前时间
+)
+
+// VersionConfig 表的版本列配置，更新时自动递增，更新和删除可按期望版本做乐观并发检查
+type VersionConfig struct {
+	Table  string `mapstructure:"table"`  // 表名（需在 allowed_tables 中）
+	Column string `mapstructure:"column"` // 版本列名
+	Type   string `mapstructure:"type"`   // 版本列类型：integer（默认）或 timestamp
+}
+
+// GetType 获取版本列类型
+func (v *VersionConfig) GetType() string {
+	if v.Type != "" {
+		return v.Type
+	}
+	return VersionTypeInteger
+}
+
+// GetVersion 获取表的版本列配置（表名不区分大小写）
+func (c *SQLConfig) GetVersion(table string) (*VersionConfig, bool) {
+	for i := range c.Versioning {
+		if strings.EqualFold(c.Versioning[i].Table, table) {
+			return &c.Versioning[i], true
+		}
+	}
+	return nil, false
+}
+
+// 软删除标记类型
+const (
+	SoftDeleteTypeTimestamp = "timestamp" // 删除时设为当前时间，未删除的行为 NULL
+	SoftDeleteTypeValue     = "value"     // 删除时设为 deleted_value，恢复时设为 active_value
+)
+
+// SoftDeleteConfig 表的软删除配置：删除时设置标记列而不物理删除行，查询自动排除已删除的行
+type SoftDeleteConfig struct {
+	Table        string      `mapstructure:"table"`         // 表名（需在 allowed_tables 中）
+	Column       string      `mapstructure:"column"`        // 删除标记列名
+	Type         string      `mapstructure:"type"`          // 标记类型：timestamp（默认）或 value
+	DeletedValue interface{} `mapstructure:"deleted_value"` // value 类型删除时设置的值，如 true 或 1
+	ActiveValue  interface{} `mapstructure:"active_value"`  // value 类型恢复时设置的值，为空时置为 NULL
+}
+
+// GetType 获取软删除标记类型
+func (d *SoftDeleteConfig) GetType() string {
+	if d.Type != "" {
+		return d.Type
+	}
+	return SoftDeleteTypeTimestamp
+}
+
+// GetSoftDelete 获取表的软删除配置（表名不区分大小写）
+func (c *SQLConfig) GetSoftDelete(table string) (*SoftDeleteConfig, bool) {
+	for i := range c.SoftDelete {
+		if strings.EqualFold(c.SoftDelete[i].Table, table) {
+			return &c.SoftDelete[i], true
+		}
+	}
+	return nil, false
+}
+
+// ResourceOperations 资源支持的操作
+var ResourceOperations = []string{"list", "get", "create", "update", "delete", "restore"}
+
+// GetResource 获取资源配置
+func (c *SQLConfig) GetResource(name string) (*ResourceConfig, bool) {
+	for i := range c.Resources {
+		if c.Resources[i].Name == name {
+			return &c.Resources[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetPrimaryKey 获取主键列
+func (r *ResourceConfig) GetPrimaryKey() string {
+	if r.PrimaryKey != "" {
+		return r.PrimaryKey
+	}
+	return "id"
+}
+
+// GetMaxPageSize 获取每页最大行数
+func (r *ResourceConfig) GetMaxPageSize() int {
+	if r.MaxPageSize > 0 {
+		return r.MaxPageSize
+	}
+	return 100
+}
+
+// AllowsOperation 检查资源是否允许指定操作
+func (r *ResourceConfig) AllowsOperation(operation string) bool {
+	if len(r.Operations) == 0 {
+		return true
+	}
+	for _, op := range r.Operations {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldName 获取字段在 API 中的名称
+func (f *ResourceFieldConfig) FieldName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return f.Column
 }
 
 // Load 加载配置
@@ -150,6 +584,30 @@ func setDefaults() {
 	viper.SetDefault("server.read_timeout", 30)
 	viper.SetDefault("server.write_timeout", 30)
 
+	// gRPC 默认配置
+	viper.SetDefault("grpc.enabled", false)
+	viper.SetDefault("grpc.port", 9090)
+	viper.SetDefault("grpc.max_message_size", 4<<20)
+
+	// 管理 API 默认配置
+	viper.SetDefault("admin.enabled", true)
+	viper.SetDefault("admin.host", "127.0.0.1")
+	viper.SetDefault("admin.port", 8090)
+	viper.SetDefault("admin.pprof", true)
+
+	// 指标默认配置
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.path", "/metrics")
+
+	// 链路追踪默认配置
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", TracingExporterOTLP)
+	viper.SetDefault("tracing.endpoint", "localhost:4318")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.file", "traces.jsonl")
+	viper.SetDefault("tracing.service_name", "sql2api")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
 	// 数据库默认配置
 	viper.SetDefault("database.type", "postgres")
 	viper.SetDefault("database.host", "localhost")
@@ -180,6 +638,7 @@ func setDefaults() {
 	viper.SetDefault("swagger.host", "localhost:8081")
 	viper.SetDefault("swagger.base_path", "/api/v1")
 	viper.SetDefault("swagger.schemes", []string{"http", "https"})
+	viper.SetDefault("swagger.servers", []string{})
 
 	// API Key 默认配置
 	viper.SetDefault("api_keys.enabled", false)
@@ -197,20 +656,106 @@ func setDefaults() {
 	viper.SetDefault("sql.enable_raw_sql", true)
 	viper.SetDefault("sql.enable_batch", true)
 	viper.SetDefault("sql.enable_transactions", true)
+	viper.SetDefault("sql.validate_writes", true)
+	viper.SetDefault("sql.subscriptions.enabled", false)
+	viper.SetDefault("sql.subscriptions.source", SubscriptionSourceGateway)
+	viper.SetDefault("sql.subscriptions.channel", "sql2api_changes")
+	viper.SetDefault("sql.subscriptions.buffer_size", 256)
+	viper.SetDefault("sql.subscriptions.heartbeat_interval", 30)
+	viper.SetDefault("sql.webhooks.enabled", false)
+	viper.SetDefault("sql.webhooks.queue_path", "webhooks.db")
+	viper.SetDefault("sql.webhooks.timeout", 10)
+	viper.SetDefault("sql.webhooks.max_attempts", 10)
+	viper.SetDefault("sql.webhooks.initial_backoff", 5)
+	viper.SetDefault("sql.webhooks.max_backoff", 3600)
+	viper.SetDefault("sql.import.enabled", false)
+	viper.SetDefault("sql.import.chunk_size", 1000)
+	viper.SetDefault("sql.import.max_chunk_size", 10000)
+	viper.SetDefault("sql.import.max_errors", 0)
+	viper.SetDefault("sql.audit.enabled", false)
+	viper.SetDefault("sql.audit.sinks", []string{AuditSinkDatabase})
+	viper.SetDefault("sql.audit.table", "sql2api_audit")
+	viper.SetDefault("sql.audit.file", "audit.jsonl")
+	viper.SetDefault("sql.audit.max_image_rows", 100)
+	viper.SetDefault("sql.statement_stats.enabled", true)
+	viper.SetDefault("sql.statement_stats.max_statements", 1000)
+	viper.SetDefault("sql.statement_stats.slow_log_size", 50)
+	viper.SetDefault("sql.statement_stats.slow_log_window", 60)
+	viper.SetDefault("sql.statement_stats.slow_query_ms", 0)
 }
 
 // validateConfig 验证配置
 func validateConfig(config *Config) error {
 	// 验证数据库类型
-	if config.Database.Type != "postgres" && config.Database.Type != "oracle" {
+	validDatabaseTypes := map[string]bool{
+		"postgres":  true,
+		"oracle":    true,
+		"sqlite":    true,
+		"mysql":     true,
+		"sqlserver": true,
+	}
+	if !validDatabaseTypes[config.Database.Type] {
 		return fmt.Errorf("unsupported database type: %s", config.Database.Type)
 	}
 
+	// SQLite 需要数据库文件路径
+	if config.Database.Type == "sqlite" && config.Database.Path == "" && config.Database.Database == "" {
+		return fmt.Errorf("database.path is required for sqlite")
+	}
+
 	// 验证服务器端口
 	if config.Server.Port < 1 || config.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", config.Server.Port)
 	}
 
+	// 验证 gRPC 端口
+	if config.GRPC.Enabled {
+		if config.GRPC.Port < 1 || config.GRPC.Port > 65535 {
+			return fmt.Errorf("invalid grpc port: %d", config.GRPC.Port)
+		}
+		if config.GRPC.Port == config.Server.Port {
+			return fmt.Errorf("grpc port must differ from server port: %d", config.GRPC.Port)
+		}
+		if config.GRPC.MaxMessageSize <= 0 {
+			return fmt.Errorf("invalid grpc max_message_size: %d", config.GRPC.MaxMessageSize)
+		}
+	}
+
+	// 验证管理 API 端口
+	if config.Admin.Enabled {
+		if config.Admin.Port < 1 || config.Admin.Port > 65535 {
+			return fmt.Errorf("invalid admin port: %d", config.Admin.Port)
+		}
+		if config.Admin.Port == config.Server.Port || (config.GRPC.Enabled && config.Admin.Port == config.GRPC.Port) {
+			return fmt.Errorf("admin port must differ from server and grpc ports: %d", config.Admin.Port)
+		}
+	}
+
+	// 验证指标端点路径
+	if config.Metrics.Enabled && (!strings.HasPrefix(config.Metrics.Path, "/") || strings.HasPrefix(config.Metrics.Path, "/api/")) {
+		return fmt.Errorf("invalid metrics path: %s", config.Metrics.Path)
+	}
+
+	// 验证链路追踪配置
+	if config.Tracing.Enabled {
+		switch config.Tracing.Exporter {
+		case TracingExporterOTLP:
+			if config.Tracing.Endpoint == "" {
+				return fmt.Errorf("tracing.endpoint is required for the otlp exporter")
+			}
+		case TracingExporterStdout:
+		case TracingExporterFile:
+			if config.Tracing.File == "" {
+				return fmt.Errorf("tracing.file is required for the file exporter")
+			}
+		default:
+			return fmt.Errorf("invalid tracing exporter: %s", config.Tracing.Exporter)
+		}
+		if config.Tracing.SampleRatio < 0 || config.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("invalid tracing sample_ratio: %v", config.Tracing.SampleRatio)
+		}
+	}
+
 	// 验证日志级别
 	validLogLevels := []string{"debug", "info", "warn", "error"}
 	isValidLevel := false
@@ -248,11 +793,419 @@ func validateConfig(config *Config) error {
 				return fmt.Errorf("invalid SQL action: %s", action)
 			}
 		}
+
+		// 验证可调用例程
+		if err := validateRoutines(config.SQL.Routines); err != nil {
+			return err
+		}
+
+		// 验证命名查询
+		savedQueries := make(map[string]bool)
+		for i := range config.SQL.SavedQueries {
+			query := &config.SQL.SavedQueries[i]
+			if savedQueries[query.Name] {
+				return fmt.Errorf("duplicate saved query: %s", query.Name)
+			}
+			savedQueries[query.Name] = true
+
+			if err := ValidateSavedQuery(query); err != nil {
+				return err
+			}
+		}
+
+		// 验证资源注册表
+		if err := validateResources(&config.SQL); err != nil {
+			return err
+		}
+
+		// 验证版本列
+		if err := validateVersioning(&config.SQL); err != nil {
+			return err
+		}
+
+		// 验证软删除
+		if err := validateSoftDelete(&config.SQL); err != nil {
+			return err
+		}
+
+		// 验证变更订阅
+		if err := validateSubscriptions(&config.SQL.Subscriptions, config.Database.Type); err != nil {
+			return err
+		}
+
+		// 验证 webhook
+		if err := validateWebhooks(&config.SQL); err != nil {
+			return err
+		}
+
+		// 验证数据导入
+		if err := validateImport(&config.SQL.Import); err != nil {
+			return err
+		}
+
+		// 验证审计日志
+		if err := validateAudit(&config.SQL); err != nil {
+			return err
+		}
+	}
+
+	// 验证语句统计配置
+	if stats := config.SQL.StatementStats; stats.Enabled {
+		if stats.MaxStatements < 1 || stats.SlowLogSize < 0 || stats.SlowLogWindow < 1 || stats.SlowQueryMs < 0 {
+			return fmt.Errorf("invalid sql.statement_stats: max_statements and slow_log_window must be positive, slow_log_size and slow_query_ms must not be negative")
+		}
+	}
+
+	return nil
+}
+
+// channelNamePattern PostgreSQL 通知通道名（不需要引用的标识符）
+var channelNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// validateSubscriptions 验证变更订阅配置
+func validateSubscriptions(sub *SubscriptionConfig, databaseType string) error {
+	if !sub.Enabled {
+		return nil
+	}
+
+	switch sub.Source {
+	case SubscriptionSourceGateway:
+	case SubscriptionSourceNotify:
+		if databaseType != "postgres" {
+			return fmt.Errorf("subscription source notify requires postgres, got %s", databaseType)
+		}
+		if !channelNamePattern.MatchString(sub.Channel) {
+			return fmt.Errorf("invalid subscription channel: %s", sub.Channel)
+		}
+	default:
+		return fmt.Errorf("invalid subscription source: %s", sub.Source)
+	}
+
+	if sub.BufferSize <= 0 {
+		return fmt.Errorf("invalid subscription buffer_size: %d", sub.BufferSize)
+	}
+	if sub.HeartbeatInterval <= 0 {
+		return fmt.Errorf("invalid subscription heartbeat_interval: %d", sub.HeartbeatInterval)
+	}
+	return nil
+}
+
+// validateImport 验证数据导入配置
+func validateImport(importConfig *ImportConfig) error {
+	if !importConfig.Enabled {
+		return nil
+	}
+
+	if importConfig.ChunkSize <= 0 || importConfig.MaxChunkSize < importConfig.ChunkSize {
+		return fmt.Errorf("invalid import chunk size: chunk_size %d, max_chunk_size %d", importConfig.ChunkSize, importConfig.MaxChunkSize)
+	}
+	if importConfig.MaxErrors < -1 {
+		return fmt.Errorf("invalid import max_errors: %d", importConfig.MaxErrors)
+	}
+	return nil
+}
+
+// validateAudit 验证审计日志配置，审计表不能通过 API 访问
+func validateAudit(sqlConfig *SQLConfig) error {
+	audit := &sqlConfig.Audit
+	if !audit.Enabled {
+		return nil
+	}
+
+	if len(audit.Sinks) == 0 {
+		return fmt.Errorf("audit sinks are required")
+	}
+	for _, sink := range audit.Sinks {
+		if !strings.EqualFold(sink, AuditSinkDatabase) && !strings.EqualFold(sink, AuditSinkFile) {
+			return fmt.Errorf("invalid audit sink: %s", sink)
+		}
+	}
+	if audit.HasSink(AuditSinkDatabase) {
+		if !identifierNamePattern.MatchString(audit.Table) {
+			return fmt.Errorf("invalid audit table: %s", audit.Table)
+		}
+		if containsFold(sqlConfig.AllowedTables, audit.Table) {
+			return fmt.Errorf("audit table %s must not be in allowed_tables", audit.Table)
+		}
+	}
+	if audit.HasSink(AuditSinkFile) && audit.File == "" {
+		return fmt.Errorf("audit file is required for the file sink")
+	}
+
+	for _, pattern := range audit.Redact {
+		if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+			return fmt.Errorf("invalid audit redact pattern: %s", pattern)
+		}
+	}
+	for _, table := range audit.RowImages {
+		if table != "*" && !containsFold(sqlConfig.AllowedTables, table) {
+			return fmt.Errorf("audit row_images: table %s is not in allowed_tables", table)
+		}
+	}
+	if audit.MaxImageRows <= 0 {
+		return fmt.Errorf("invalid audit max_image_rows: %d", audit.MaxImageRows)
+	}
+	return nil
+}
+
+// validateWebhooks 验证 webhook 配置
+func validateWebhooks(sqlConfig *SQLConfig) error {
+	webhooks := &sqlConfig.Webhooks
+	if !webhooks.Enabled {
+		return nil
+	}
+
+	if webhooks.QueuePath == "" {
+		return fmt.Errorf("webhook queue_path is required")
+	}
+	if webhooks.Timeout <= 0 {
+		return fmt.Errorf("invalid webhook timeout: %d", webhooks.Timeout)
+	}
+	if webhooks.MaxAttempts <= 0 {
+		return fmt.Errorf("invalid webhook max_attempts: %d", webhooks.MaxAttempts)
+	}
+	if webhooks.InitialBackoff <= 0 || webhooks.MaxBackoff < webhooks.InitialBackoff {
+		return fmt.Errorf("invalid webhook backoff: initial %d, max %d", webhooks.InitialBackoff, webhooks.MaxBackoff)
 	}
 
+	names := make(map[string]bool)
+	for i := range webhooks.Endpoints {
+		endpoint := &webhooks.Endpoints[i]
+		if err := ValidateWebhookEndpoint(endpoint, sqlConfig.AllowedTables); err != nil {
+			return err
+		}
+		if names[endpoint.Name] {
+			return fmt.Errorf("duplicate webhook: %s", endpoint.Name)
+		}
+		names[endpoint.Name] = true
+	}
 	return nil
 }
 
+// routineNamePattern 例程名（最多 schema.package.routine 三段）
+var routineNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$#]*(\.[A-Za-z_][A-Za-z0-9_$#]*){0,2}$`)
+
+// validateRoutines 验证例程配置
+func validateRoutines(routines []RoutineConfig) error {
+	validTypes := map[string]bool{
+		"string": true, "integer": true, "number": true, "boolean": true,
+		"date": true, "timestamp": true, "cursor": true,
+	}
+	validDirections := map[string]bool{"": true, "in": true, "out": true, "inout": true}
+
+	names := make(map[string]bool)
+	for _, routine := range routines {
+		if routine.Name == "" {
+			return fmt.Errorf("routine name is required")
+		}
+		if names[routine.Name] {
+			return fmt.Errorf("duplicate routine: %s", routine.Name)
+		}
+		names[routine.Name] = true
+
+		// 例程名会拼接进 SQL，只允许标识符
+		if !routineNamePattern.MatchString(routine.DatabaseName()) {
+			return fmt.Errorf("invalid routine name: %s", routine.DatabaseName())
+		}
+
+		switch routine.Kind {
+		case "procedure":
+			if routine.Returns != "" {
+				return fmt.Errorf("routine %s: procedures cannot declare returns", routine.Name)
+			}
+		case "function":
+			if routine.Returns != "" && routine.Returns != "rows" && !validTypes[routine.Returns] {
+				return fmt.Errorf("routine %s: invalid returns type: %s", routine.Name, routine.Returns)
+			}
+		default:
+			return fmt.Errorf("routine %s: invalid kind: %s (must be procedure or function)", routine.Name, routine.Kind)
+		}
+
+		params := make(map[string]bool)
+		for _, param := range routine.Params {
+			if !identifierNamePattern.MatchString(param.Name) {
+				return fmt.Errorf("routine %s: invalid parameter name: %s", routine.Name, param.Name)
+			}
+			if params[param.Name] {
+				return fmt.Errorf("routine %s: duplicate parameter: %s", routine.Name, param.Name)
+			}
+			params[param.Name] = true
+
+			if !validTypes[param.Type] {
+				return fmt.Errorf("routine %s: invalid type for parameter %s: %s", routine.Name, param.Name, param.Type)
+			}
+			if !validDirections[param.Direction] {
+				return fmt.Errorf("routine %s: invalid direction for parameter %s: %s", routine.Name, param.Name, param.Direction)
+			}
+			if param.Type == "cursor" && param.Direction != "out" {
+				return fmt.Errorf("routine %s: cursor parameter %s must be out", routine.Name, param.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateResources 验证资源配置
+func validateResources(sqlConfig *SQLConfig) error {
+	allowedTables := make(map[string]bool)
+	for _, table := range sqlConfig.AllowedTables {
+		allowedTables[strings.ToLower(table)] = true
+	}
+	validOperations := make(map[string]bool)
+	for _, op := range ResourceOperations {
+		validOperations[op] = true
+	}
+
+	names := make(map[string]bool)
+	for _, resource := range sqlConfig.Resources {
+		if !identifierNamePattern.MatchString(resource.Name) {
+			return fmt.Errorf("invalid resource name: %s", resource.Name)
+		}
+		if names[resource.Name] {
+			return fmt.Errorf("duplicate resource: %s", resource.Name)
+		}
+		names[resource.Name] = true
+
+		// 表名和列名会拼接进 SQL，只允许标识符
+		if !routineNamePattern.MatchString(resource.Table) {
+			return fmt.Errorf("resource %s: invalid table name: %s", resource.Name, resource.Table)
+		}
+		if !allowedTables[strings.ToLower(resource.Table)] {
+			return fmt.Errorf("resource %s: table %s is not in allowed_tables", resource.Name, resource.Table)
+		}
+		if !identifierNamePattern.MatchString(resource.GetPrimaryKey()) {
+			return fmt.Errorf("resource %s: invalid primary key: %s", resource.Name, resource.GetPrimaryKey())
+		}
+		if resource.KeyType != "" && resource.KeyType != "integer" && resource.KeyType != "string" {
+			return fmt.Errorf("resource %s: invalid key_type: %s (must be integer or string)", resource.Name, resource.KeyType)
+		}
+
+		for _, op := range resource.Operations {
+			if !validOperations[op] {
+				return fmt.Errorf("resource %s: invalid operation: %s", resource.Name, op)
+			}
+		}
+
+		columns := make(map[string]bool)
+		fieldNames := make(map[string]bool)
+		for _, field := range resource.Fields {
+			if !identifierNamePattern.MatchString(field.Column) {
+				return fmt.Errorf("resource %s: invalid column: %s", resource.Name, field.Column)
+			}
+			if !identifierNamePattern.MatchString(field.FieldName()) {
+				return fmt.Errorf("resource %s: invalid field name: %s", resource.Name, field.FieldName())
+			}
+			if columns[field.Column] || fieldNames[field.FieldName()] {
+				return fmt.Errorf("resource %s: duplicate field: %s", resource.Name, field.FieldName())
+			}
+			columns[field.Column] = true
+			fieldNames[field.FieldName()] = true
+		}
+		if len(resource.Fields) > 0 && !columns[resource.GetPrimaryKey()] {
+			return fmt.Errorf("resource %s: primary key %s must be one of the fields", resource.Name, resource.GetPrimaryKey())
+		}
+
+		if resource.DefaultSort != "" {
+			parts := strings.Fields(resource.DefaultSort)
+			if len(parts) > 2 || !identifierNamePattern.MatchString(parts[0]) ||
+				(len(parts) == 2 && !strings.EqualFold(parts[1], "asc") && !strings.EqualFold(parts[1], "desc")) {
+				return fmt.Errorf("resource %s: invalid default_sort: %s", resource.Name, resource.DefaultSort)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateVersioning 验证版本列配置
+func validateVersioning(sqlConfig *SQLConfig) error {
+	allowedTables := make(map[string]bool)
+	for _, table := range sqlConfig.AllowedTables {
+		allowedTables[strings.ToLower(table)] = true
+	}
+
+	tables := make(map[string]bool)
+	for _, version := range sqlConfig.Versioning {
+		if !routineNamePattern.MatchString(version.Table) {
+			return fmt.Errorf("versioning: invalid table name: %s", version.Table)
+		}
+		if !allowedTables[strings.ToLower(version.Table)] {
+			return fmt.Errorf("versioning: table %s is not in allowed_tables", version.Table)
+		}
+		if tables[strings.ToLower(version.Table)] {
+			return fmt.Errorf("versioning: duplicate table: %s", version.Table)
+		}
+		tables[strings.ToLower(version.Table)] = true
+
+		if !identifierNamePattern.MatchString(version.Column) {
+			return fmt.Errorf("versioning %s: invalid column: %s", version.Table, version.Column)
+		}
+		if version.GetType() != VersionTypeInteger && version.GetType() != VersionTypeTimestamp {
+			return fmt.Errorf("versioning %s: invalid type: %s (must be integer or timestamp)", version.Table, version.Type)
+		}
+	}
+
+	return nil
+}
+
+// validateSoftDelete 验证软删除配置
+func validateSoftDelete(sqlConfig *SQLConfig) error {
+	allowedTables := make(map[string]bool)
+	for _, table := range sqlConfig.AllowedTables {
+		allowedTables[strings.ToLower(table)] = true
+	}
+
+	tables := make(map[string]bool)
+	for _, softDelete := range sqlConfig.SoftDelete {
+		if !routineNamePattern.MatchString(softDelete.Table) {
+			return fmt.Errorf("soft_delete: invalid table name: %s", softDelete.Table)
+		}
+		if !allowedTables[strings.ToLower(softDelete.Table)] {
+			return fmt.Errorf("soft_delete: table %s is not in allowed_tables", softDelete.Table)
+		}
+		if tables[strings.ToLower(softDelete.Table)] {
+			return fmt.Errorf("soft_delete: duplicate table: %s", softDelete.Table)
+		}
+		tables[strings.ToLower(softDelete.Table)] = true
+
+		if !identifierNamePattern.MatchString(softDelete.Column) {
+			return fmt.Errorf("soft_delete %s: invalid column: %s", softDelete.Table, softDelete.Column)
+		}
+		if version, ok := sqlConfig.GetVersion(softDelete.Table); ok && strings.EqualFold(version.Column, softDelete.Column) {
+			return fmt.Errorf("soft_delete %s: column %s is already the version column", softDelete.Table, softDelete.Column)
+		}
+
+		switch softDelete.GetType() {
+		case SoftDeleteTypeTimestamp:
+		case SoftDeleteTypeValue:
+			if !isScalarValue(softDelete.DeletedValue) {
+				return fmt.Errorf("soft_delete %s: deleted_value must be a string, number or boolean", softDelete.Table)
+			}
+			if softDelete.ActiveValue != nil && !isScalarValue(softDelete.ActiveValue) {
+				return fmt.Errorf("soft_delete %s: active_value must be a string, number or boolean", softDelete.Table)
+			}
+		default:
+			return fmt.Errorf("soft_delete %s: invalid type: %s (must be timestamp or value)", softDelete.Table, softDelete.Type)
+		}
+	}
+
+	return nil
+}
+
+// isScalarValue 检查配置值是否为字符串、数字或布尔值
+func isScalarValue(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int64, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// identifierNamePattern 参数名
+var identifierNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // GetDSN 获取数据库连接字符串
 func (c *DatabaseConfig) GetDSN() string {
 	switch c.Type {
@@ -266,6 +1219,30 @@ func (c *DatabaseConfig) GetDSN() string {
 		}
 		return fmt.Sprintf("oracle://%s:%s@%s:%d/%s",
 			c.Username, c.Password, c.Host, c.Port, c.Database)
+	case "mysql":
+		// parseTime 使 DATE/DATETIME 列扫描为 time.Time
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local",
+			c.Username, c.Password, c.Host, c.Port, c.Database)
+	case "sqlserver":
+		dsn := url.URL{
+			Scheme:   "sqlserver",
+			User:     url.UserPassword(c.Username, c.Password),
+			Host:     fmt.Sprintf("%s:%d", c.Host, c.Port),
+			RawQuery: url.Values{"database": {c.Database}}.Encode(),
+		}
+		return dsn.String()
+	case "sqlite":
+		// 未配置 path 时使用 database 作为文件名
+		path := c.Path
+		if path == "" {
+			path = c.Database
+		}
+		// 开启外键约束，并在数据库被锁定时等待而不是立即失败
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		return path + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
 	default:
 		return ""
 	}
@@ -275,3 +1252,72 @@ func (c *DatabaseConfig) GetDSN() string {
 func (c *ServerConfig) GetServerAddress() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// GetAdminAddress 获取管理 API 监听地址
+func (c *AdminConfig) GetAdminAddress() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+// GetGRPCAddress 获取 gRPC 服务地址
+func (c *Config) GetGRPCAddress() string {
+	return fmt.Sprintf("%s:%d", c.Server.Host, c.GRPC.Port)
+}
+
+// redactedSettingKeys 有效配置中需要隐藏取值的键
+var redactedSettingKeys = map[string]bool{
+	"password": true,
+	"secret":   true,
+	"key":      true,
+	"token":    true,
+	"headers":  true,
+}
+
+// EffectiveSettings 返回合并默认值、配置文件和环境变量后的有效配置，密码、API Key 等敏感值已隐藏
+func EffectiveSettings() map[string]interface{} {
+	return redactSettings(viper.AllSettings())
+}
+
+// redactSettings 递归隐藏敏感配置项，空值保持不变以便区分是否已设置
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if redactedSettingKeys[strings.ToLower(key)] && !isEmptySetting(value) {
+			result[key] = "[REDACTED]"
+			continue
+		}
+		result[key] = redactSettingValue(value)
+	}
+	return result
+}
+
+// redactSettingValue 隐藏嵌套配置中的敏感项
+func redactSettingValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactSettings(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = redactSettingValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// isEmptySetting 检查配置值是否为空
+func isEmptySetting(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case map[string]string:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
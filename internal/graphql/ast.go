@@ -0,0 +1,310 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Document GraphQL 请求文档
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation 操作定义（query、mutation 或 subscription）
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []*VariableDefinition
+	Selections []Selection
+}
+
+// VariableDefinition 变量定义
+type VariableDefinition struct {
+	Name     string
+	Type     string
+	Default  interface{}
+	Required bool // 类型以 ! 结尾
+}
+
+// Selection 选择集中的元素：*Field、*FragmentSpread 或 *InlineFragment
+type Selection interface{}
+
+// Field 字段选择
+type Field struct {
+	Alias      string
+	Name       string
+	Arguments  []*Argument
+	Directives []*Directive
+	Selections []Selection
+	Line       int
+	Column     int
+}
+
+// FragmentSpread 命名片段展开
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+// InlineFragment 内联片段
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	Selections    []Selection
+}
+
+// Fragment 命名片段定义
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []Selection
+}
+
+// Directive 指令
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+}
+
+// Argument 参数
+type Argument struct {
+	Name  string
+	Value interface{}
+}
+
+// Variable 值中的变量引用
+type Variable struct {
+	Name string
+}
+
+// EnumValue 值中的枚举字面量，解析后为字符串
+type EnumValue string
+
+// ResponseKey 字段在响应中的键（别名优先）
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// ArgumentValues 解析字段参数，替换其中的变量
+func (f *Field) ArgumentValues(variables map[string]interface{}) (map[string]interface{}, error) {
+	return resolveArguments(f.Arguments, variables)
+}
+
+// Operation 按名称选择要执行的操作，文档只有一个操作时名称可为空
+func (d *Document) Operation(name string) (*Operation, error) {
+	if name == "" {
+		if len(d.Operations) != 1 {
+			return nil, fmt.Errorf("operationName is required when the document contains %d operations", len(d.Operations))
+		}
+		return d.Operations[0], nil
+	}
+
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation: %s", name)
+}
+
+// CollectFields 展开片段并应用 @skip/@include 指令，返回对象类型 typeName 上选中的字段
+func (d *Document) CollectFields(typeName string, selections []Selection, variables map[string]interface{}) ([]*Field, error) {
+	var fields []*Field
+	visited := make(map[string]bool)
+
+	var collect func(selections []Selection) error
+	collect = func(selections []Selection) error {
+		for _, selection := range selections {
+			switch s := selection.(type) {
+			case *Field:
+				include, err := shouldInclude(s.Directives, variables)
+				if err != nil {
+					return err
+				}
+				if include {
+					fields = append(fields, s)
+				}
+
+			case *FragmentSpread:
+				include, err := shouldInclude(s.Directives, variables)
+				if err != nil {
+					return err
+				}
+				if !include || visited[s.Name] {
+					continue
+				}
+				fragment, ok := d.Fragments[s.Name]
+				if !ok {
+					return fmt.Errorf("unknown fragment: %s", s.Name)
+				}
+				if fragment.TypeCondition != typeName {
+					continue
+				}
+				visited[s.Name] = true
+				if err := collect(fragment.Selections); err != nil {
+					return err
+				}
+
+			case *InlineFragment:
+				include, err := shouldInclude(s.Directives, variables)
+				if err != nil {
+					return err
+				}
+				if !include || (s.TypeCondition != "" && s.TypeCondition != typeName) {
+					continue
+				}
+				if err := collect(s.Selections); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := collect(selections); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// CoerceVariables 合并请求中的变量和变量默认值，并检查必填变量
+func (o *Operation) CoerceVariables(input map[string]interface{}) (map[string]interface{}, error) {
+	variables := make(map[string]interface{}, len(o.Variables))
+	for _, def := range o.Variables {
+		value, ok := input[def.Name]
+		if !ok {
+			value = def.Default
+		}
+		if value == nil && def.Required {
+			return nil, fmt.Errorf("variable $%s of type %s is required", def.Name, def.Type)
+		}
+		variables[def.Name] = value
+	}
+	return variables, nil
+}
+
+// shouldInclude 计算 @skip 和 @include 指令
+func shouldInclude(directives []*Directive, variables map[string]interface{}) (bool, error) {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			continue
+		}
+		args, err := resolveArguments(directive.Arguments, variables)
+		if err != nil {
+			return false, err
+		}
+		condition, ok := args["if"].(bool)
+		if !ok {
+			return false, fmt.Errorf("directive @%s requires a boolean if argument", directive.Name)
+		}
+		if directive.Name == "skip" && condition {
+			return false, nil
+		}
+		if directive.Name == "include" && !condition {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// resolveArguments 将参数列表解析为映射
+func resolveArguments(arguments []*Argument, variables map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(arguments))
+	for _, arg := range arguments {
+		value, err := ResolveValue(arg.Value, variables)
+		if err != nil {
+			return nil, err
+		}
+		values[arg.Name] = value
+	}
+	return values, nil
+}
+
+// ResolveValue 将字面量中的变量和枚举替换为 Go 值
+func ResolveValue(value interface{}, variables map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case Variable:
+		resolved, ok := variables[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", v.Name)
+		}
+		return resolved, nil
+	case EnumValue:
+		return string(v), nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := ResolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved, err := ResolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = resolved
+		}
+		return object, nil
+	}
+	return value, nil
+}
+
+// Object 保持字段顺序的响应对象（GraphQL 要求响应字段按选择顺序排列）
+type Object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewObject 创建响应对象
+func NewObject() *Object {
+	return &Object{values: make(map[string]interface{})}
+}
+
+// Set 设置字段值，已存在的字段保持原位置
+func (o *Object) Set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Get 获取字段值
+func (o *Object) Get(key string) (interface{}, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// MarshalJSON 按字段顺序编码
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
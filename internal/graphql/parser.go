@@ -0,0 +1,769 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SyntaxError GraphQL 语法错误
+type SyntaxError struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+// Error 实现 error 接口
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token 词法单元
+type token struct {
+	kind   tokenKind
+	value  string
+	line   int
+	column int
+}
+
+// lexer GraphQL 词法分析器
+type lexer struct {
+	source    string
+	pos       int
+	line      int
+	lineStart int
+}
+
+// Parse 解析 GraphQL 请求文档
+// 支持操作、变量、别名、参数、指令、命名片段和内联片段；不支持类型系统定义
+func Parse(source string) (*Document, error) {
+	p := &parser{lexer: &lexer{source: source, line: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peekPunct("{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: selections})
+
+		case p.peekName("query"), p.peekName("mutation"), p.peekName("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+
+		case p.peekName("fragment"):
+			fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, p.errorf("duplicate fragment %s", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+
+		default:
+			return nil, p.errorf("unexpected %q", p.tok.value)
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, &SyntaxError{Message: "document contains no operations", Line: 1, Column: 1}
+	}
+	return doc, nil
+}
+
+// next 读取下一个词法单元
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	if l.pos >= len(l.source) {
+		return token{kind: tokenEOF, line: l.line, column: l.column()}, nil
+	}
+
+	start := l.pos
+	line, column := l.line, l.column()
+	ch := l.source[l.pos]
+
+	switch {
+	case strings.HasPrefix(l.source[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, value: "...", line: line, column: column}, nil
+
+	case strings.ContainsRune("!$():=@[]{}|&", rune(ch)):
+		l.pos++
+		return token{kind: tokenPunct, value: string(ch), line: line, column: column}, nil
+
+	case ch == '_' || isLetter(ch):
+		for l.pos < len(l.source) && (l.source[l.pos] == '_' || isLetter(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.source[start:l.pos], line: line, column: column}, nil
+
+	case ch == '-' || isDigit(ch):
+		return l.readNumber(line, column)
+
+	case ch == '"':
+		if strings.HasPrefix(l.source[l.pos:], `"""`) {
+			return l.readBlockString(line, column)
+		}
+		return l.readString(line, column)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.source[l.pos:])
+	return token{}, &SyntaxError{Message: fmt.Sprintf("unexpected character %q", r), Line: line, Column: column}
+}
+
+// skipIgnored 跳过空白、逗号和注释
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.source) {
+		switch ch := l.source[l.pos]; ch {
+		case '\n':
+			l.pos++
+			l.line++
+			l.lineStart = l.pos
+		case ' ', '\t', '\r', ',':
+			l.pos++
+		case '#':
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' {
+				l.pos++
+			}
+		default:
+			// UTF-8 BOM
+			if strings.HasPrefix(l.source[l.pos:], "\uFEFF") {
+				l.pos += len("\uFEFF")
+				continue
+			}
+			return
+		}
+	}
+}
+
+// column 当前位置的列号（从 1 开始）
+func (l *lexer) column() int {
+	return l.pos - l.lineStart + 1
+}
+
+// readNumber 读取整数或浮点数
+func (l *lexer) readNumber(line, column int) (token, error) {
+	start := l.pos
+	isFloat := false
+
+	if l.source[l.pos] == '-' {
+		l.pos++
+	}
+	digits := l.pos
+	for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+		l.pos++
+	}
+	if l.pos == digits {
+		return token{}, &SyntaxError{Message: "invalid number", Line: line, Column: column}
+	}
+	if l.pos < len(l.source) && l.source[l.pos] == '.' {
+		isFloat = true
+		l.pos++
+		for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+			l.pos++
+		}
+	}
+	if l.pos < len(l.source) && (l.source[l.pos] == 'e' || l.source[l.pos] == 'E') {
+		isFloat = true
+		l.pos++
+		if l.pos < len(l.source) && (l.source[l.pos] == '+' || l.source[l.pos] == '-') {
+			l.pos++
+		}
+		for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+			l.pos++
+		}
+	}
+
+	kind := tokenInt
+	if isFloat {
+		kind = tokenFloat
+	}
+	return token{kind: kind, value: l.source[start:l.pos], line: line, column: column}, nil
+}
+
+// readString 读取带转义的字符串
+func (l *lexer) readString(line, column int) (token, error) {
+	var sb strings.Builder
+	l.pos++ // 开头的引号
+
+	for l.pos < len(l.source) {
+		ch := l.source[l.pos]
+		switch {
+		case ch == '"':
+			l.pos++
+			return token{kind: tokenString, value: sb.String(), line: line, column: column}, nil
+
+		case ch == '\n':
+			return token{}, &SyntaxError{Message: "unterminated string", Line: line, Column: column}
+
+		case ch == '\\':
+			if l.pos+1 >= len(l.source) {
+				return token{}, &SyntaxError{Message: "unterminated string", Line: line, Column: column}
+			}
+			escape := l.source[l.pos+1]
+			l.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				sb.WriteByte(escape)
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.source) {
+					return token{}, &SyntaxError{Message: "invalid unicode escape", Line: line, Column: column}
+				}
+				code, err := strconv.ParseUint(l.source[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, &SyntaxError{Message: "invalid unicode escape", Line: line, Column: column}
+				}
+				sb.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, &SyntaxError{Message: fmt.Sprintf("invalid escape \\%c", escape), Line: line, Column: column}
+			}
+
+		default:
+			sb.WriteByte(ch)
+			l.pos++
+		}
+	}
+
+	return token{}, &SyntaxError{Message: "unterminated string", Line: line, Column: column}
+}
+
+// readBlockString 读取三引号块字符串（去除公共缩进）
+func (l *lexer) readBlockString(line, column int) (token, error) {
+	l.pos += 3
+	start := l.pos
+
+	for l.pos < len(l.source) {
+		if strings.HasPrefix(l.source[l.pos:], `\"""`) {
+			l.pos += 4
+			continue
+		}
+		if strings.HasPrefix(l.source[l.pos:], `"""`) {
+			raw := strings.ReplaceAll(l.source[start:l.pos], `\"""`, `"""`)
+			l.pos += 3
+			return token{kind: tokenString, value: blockStringValue(raw), line: line, column: column}, nil
+		}
+		if l.source[l.pos] == '\n' {
+			l.line++
+			l.lineStart = l.pos + 1
+		}
+		l.pos++
+	}
+
+	return token{}, &SyntaxError{Message: "unterminated block string", Line: line, Column: column}
+}
+
+// blockStringValue 按规范去除块字符串的公共缩进和首尾空行
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if width := len(line) - len(trimmed); indent < 0 || width < indent {
+			indent = width
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isLetter 检查是否为 ASCII 字母
+func isLetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// isDigit 检查是否为数字
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// parser GraphQL 语法分析器
+type parser struct {
+	lexer *lexer
+	tok   token
+}
+
+// advance 前进到下一个词法单元
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// errorf 生成当前位置的语法错误
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Message: fmt.Sprintf(format, args...), Line: p.tok.line, Column: p.tok.column}
+}
+
+// peekPunct 检查当前是否为指定标点
+func (p *parser) peekPunct(value string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == value
+}
+
+// peekName 检查当前是否为指定名称
+func (p *parser) peekName(value string) bool {
+	return p.tok.kind == tokenName && p.tok.value == value
+}
+
+// expectPunct 读取指定标点
+func (p *parser) expectPunct(value string) error {
+	if !p.peekPunct(value) {
+		return p.errorf("expected %q, found %q", value, p.tok.value)
+	}
+	return p.advance()
+}
+
+// expectName 读取名称
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected name, found %q", p.tok.value)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+// parseOperation 解析操作定义
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekPunct("(") {
+		variables, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		op.Variables = variables
+	}
+
+	// 操作级指令不影响执行
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selections = selections
+	return op, nil
+}
+
+// parseVariableDefinitions 解析变量定义列表
+func (p *parser) parseVariableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+
+	var definitions []*VariableDefinition
+	for !p.peekPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		typeName, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		def := &VariableDefinition{Name: name, Type: typeName, Required: strings.HasSuffix(typeName, "!")}
+		if p.peekPunct("=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			value, err := p.parseValue(true)
+			if err != nil {
+				return nil, err
+			}
+			def.Default, _ = ResolveValue(value, nil)
+		}
+		if _, err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, def)
+	}
+
+	return definitions, p.advance()
+}
+
+// parseType 解析类型引用，如 [Int!]!
+func (p *parser) parseType() (string, error) {
+	var typeName string
+	if p.peekPunct("[") {
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		inner, err := p.parseType()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return "", err
+		}
+		typeName = "[" + inner + "]"
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return "", err
+		}
+		typeName = name
+	}
+
+	if p.peekPunct("!") {
+		typeName += "!"
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+	}
+	return typeName, nil
+}
+
+// parseFragment 解析命名片段定义
+func (p *parser) parseFragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peekName("on") {
+		return nil, p.errorf("expected \"on\", found %q", p.tok.value)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Name: name, TypeCondition: typeCondition, Selections: selections}, nil
+}
+
+// parseSelectionSet 解析 { ... } 选择集
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []Selection
+	for !p.peekPunct("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.errorf("unexpected end of document")
+		}
+
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+
+	if len(selections) == 0 {
+		return nil, p.errorf("selection set cannot be empty")
+	}
+	return selections, p.advance()
+}
+
+// parseSelection 解析字段或片段
+func (p *parser) parseSelection() (Selection, error) {
+	if p.peekPunct("...") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		// 命名片段展开
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			spread := &FragmentSpread{Name: p.tok.value}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			directives, err := p.parseDirectives()
+			if err != nil {
+				return nil, err
+			}
+			spread.Directives = directives
+			return spread, nil
+		}
+
+		// 内联片段
+		fragment := &InlineFragment{}
+		if p.peekName("on") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			typeCondition, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			fragment.TypeCondition = typeCondition
+		}
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		fragment.Directives = directives
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		fragment.Selections = selections
+		return fragment, nil
+	}
+
+	return p.parseField()
+}
+
+// parseField 解析字段
+func (p *parser) parseField() (*Field, error) {
+	field := &Field{Line: p.tok.line, Column: p.tok.column}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if p.peekPunct(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		field.Alias = name
+		if name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	field.Name = name
+
+	if p.peekPunct("(") {
+		if field.Arguments, err = p.parseArguments(false); err != nil {
+			return nil, err
+		}
+	}
+	if field.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peekPunct("{") {
+		if field.Selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+// parseArguments 解析 (name: value, ...) 参数列表
+func (p *parser) parseArguments(constant bool) ([]*Argument, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+
+	var arguments []*Argument
+	for !p.peekPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, &Argument{Name: name, Value: value})
+	}
+
+	return arguments, p.advance()
+}
+
+// parseDirectives 解析 @name(args) 指令列表
+func (p *parser) parseDirectives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peekPunct("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		directive := &Directive{Name: name}
+		if p.peekPunct("(") {
+			if directive.Arguments, err = p.parseArguments(false); err != nil {
+				return nil, err
+			}
+		}
+		directives = append(directives, directive)
+	}
+	return directives, nil
+}
+
+// parseValue 解析值字面量，constant 为 true 时不允许变量
+func (p *parser) parseValue(constant bool) (interface{}, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenInt:
+		value, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %s", tok.value)
+		}
+		return value, p.advance()
+
+	case tokenFloat:
+		value, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %s", tok.value)
+		}
+		return value, p.advance()
+
+	case tokenString:
+		return tok.value, p.advance()
+
+	case tokenName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch tok.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return EnumValue(tok.value), nil
+
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.errorf("variables are not allowed here")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			return Variable{Name: name}, nil
+
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := make([]interface{}, 0)
+			for !p.peekPunct("]") {
+				if p.tok.kind == tokenEOF {
+					return nil, p.errorf("unterminated list")
+				}
+				item, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			return list, p.advance()
+
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			object := make(map[string]interface{})
+			for !p.peekPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				value, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				object[name] = value
+			}
+			return object, p.advance()
+		}
+	}
+
+	return nil, p.errorf("unexpected %q in value", tok.value)
+}
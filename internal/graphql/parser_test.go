@@ -0,0 +1,78 @@
+package graphql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse_OperationsAndFragments(t *testing.T) {
+	doc, err := Parse(`
+		query Items($min: Int = 1, $withTags: Boolean!) {
+			list: items(where: {value: {gt: $min}}, order_by: [{name: desc}]) {
+				...itemFields
+				tags @include(if: $withTags) { name }
+			}
+		}
+
+		fragment itemFields on items { id name }
+
+		mutation Remove { delete_items(where: {id: {in: [1, 2]}}) { affected_rows } }
+	`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, err := doc.Operation(""); err == nil {
+		t.Error("Expected operationName to be required for multiple operations")
+	}
+	op, err := doc.Operation("Items")
+	if err != nil {
+		t.Fatalf("Operation lookup failed: %v", err)
+	}
+
+	vars, err := op.CoerceVariables(map[string]interface{}{"withTags": false})
+	if err != nil {
+		t.Fatalf("CoerceVariables failed: %v", err)
+	}
+	if vars["min"] != int64(1) {
+		t.Errorf("Expected default $min = 1, got %#v", vars["min"])
+	}
+	if _, err := op.CoerceVariables(nil); err == nil {
+		t.Error("Expected missing $withTags to fail")
+	}
+
+	root, _ := doc.CollectFields("Query", op.Selections, vars)
+	if len(root) != 1 || root[0].ResponseKey() != "list" || root[0].Name != "items" {
+		t.Fatalf("Unexpected root fields: %+v", root)
+	}
+
+	args, err := root[0].ArgumentValues(vars)
+	if err != nil {
+		t.Fatalf("ArgumentValues failed: %v", err)
+	}
+	where := args["where"].(map[string]interface{})["value"].(map[string]interface{})
+	if where["gt"] != int64(1) {
+		t.Errorf("Expected variable in where, got %#v", where)
+	}
+	orderBy := args["order_by"].([]interface{})[0].(map[string]interface{})
+	if orderBy["name"] != "desc" {
+		t.Errorf("Expected enum value desc, got %#v", orderBy["name"])
+	}
+
+	fields, _ := doc.CollectFields("items", root[0].Selections, vars)
+	if len(fields) != 2 || fields[0].Name != "id" || fields[1].Name != "name" {
+		t.Errorf("Expected fragment fields without skipped tags, got %+v", fields)
+	}
+}
+
+func TestParse_SyntaxError(t *testing.T) {
+	_, err := Parse("{\n  items(where: {id: }) { id }\n}")
+
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Expected SyntaxError, got %v", err)
+	}
+	if syntaxErr.Line != 2 {
+		t.Errorf("Expected error on line 2, got %d:%d", syntaxErr.Line, syntaxErr.Column)
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/middleware"
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler 管理 API 处理器，在独立的管理端口上提供服务（需要 admin 权限）
+type AdminHandler struct {
+	adminService  service.AdminService // 数据库不可用时为 nil
+	apiKeyManager *middleware.APIKeyManager
+	ipManager     *middleware.IPWhitelistManager
+	buildInfo     model.BuildInfo
+}
+
+// NewAdminHandler 创建管理 API 处理器
+func NewAdminHandler(
+	adminService service.AdminService,
+	apiKeyManager *middleware.APIKeyManager,
+	ipManager *middleware.IPWhitelistManager,
+	buildInfo model.BuildInfo,
+) *AdminHandler {
+	return &AdminHandler{
+		adminService:  adminService,
+		apiKeyManager: apiKeyManager,
+		ipManager:     ipManager,
+		buildInfo:     buildInfo,
+	}
+}
+
+// Build 获取构建信息
+// @Summary 获取构建信息
+// @Description 返回版本、Git 提交、构建时间、Go 版本和服务运行时长。需要 admin 权限，仅在管理端口提供
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse{data=model.BuildInfo} "构建信息"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /admin/build [get]
+func (h *AdminHandler) Build(c *gin.Context) {
+	info := h.buildInfo
+	info.Uptime = time.Since(info.StartTime).Round(time.Second).String()
+	c.JSON(http.StatusOK, model.NewSuccessResponse(info, "Build information retrieved successfully"))
+}
+
+// Config 获取有效配置
+// @Summary 获取有效配置
+// @Description 返回合并默认值、配置文件和环境变量后的有效配置，密码、密钥、API Key 和请求头的值显示为 [REDACTED]。需要 admin 权限，仅在管理端口提供
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "有效配置"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /admin/config [get]
+func (h *AdminHandler) Config(c *gin.Context) {
+	c.JSON(http.StatusOK, model.NewSuccessResponse(config.EffectiveSettings(), "Configuration retrieved successfully"))
+}
+
+// Stats 获取运行状态统计
+// @Summary 获取运行状态统计
+// @Description 返回数据库连接池、SQL 性能监控器和 Go 运行时统计。需要 admin 权限，仅在管理端口提供
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse{data=model.AdminStats} "运行状态统计"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 503 {object} model.ErrorResponse "数据库不可用"
+// @Router /admin/stats [get]
+func (h *AdminHandler) Stats(c *gin.Context) {
+	if h.adminService == nil {
+		c.JSON(http.StatusServiceUnavailable, model.NewErrorResponse(http.StatusServiceUnavailable, "Database is not available"))
+		return
+	}
+
+	stats, err := h.adminService.Stats()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, model.NewErrorResponse(http.StatusServiceUnavailable, "Failed to get statistics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(stats, "Statistics retrieved successfully"))
+}
+
+// Keys 列出活跃的 API Key
+// @Summary 列出活跃的 API Key
+// @Description 返回活跃 API Key 的名称、描述和权限，不包含 Key 的值。需要 admin 权限，仅在管理端口提供
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "活跃的 API Key"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /admin/keys [get]
+func (h *AdminHandler) Keys(c *gin.Context) {
+	c.JSON(http.StatusOK, model.NewSuccessResponse(h.apiKeyManager.ListActiveKeys(), "Active API keys retrieved successfully"))
+}
+
+// IPWhitelist 获取 IP 白名单
+// @Summary 获取 IP 白名单
+// @Description 返回 IP 白名单是否启用以及允许的 IP 和 CIDR。需要 admin 权限，仅在管理端口提供
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "IP 白名单"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /admin/ip-whitelist [get]
+func (h *AdminHandler) IPWhitelist(c *gin.Context) {
+	whitelist := gin.H{"enabled": false, "allowed": []string{}}
+	if h.ipManager != nil && h.ipManager.IsEnabled() {
+		whitelist = gin.H{"enabled": true, "allowed": h.ipManager.GetAllowedIPs()}
+	}
+	c.JSON(http.StatusOK, model.NewSuccessResponse(whitelist, "IP whitelist retrieved successfully"))
+}
+
+// RunningQueries 列出正在执行的查询
+// @Summary 列出正在执行的查询
+// @Description 返回正在执行的查询，最早开始的在前，SQL 中的字面量替换为 ?。需要 admin 权限，仅在管理端口提供
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse{data=[]model.RunningQuery} "正在执行的查询"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /admin/queries/running [get]
+func (h *AdminHandler) RunningQueries(c *gin.Context) {
+	queries := []model.RunningQuery{}
+	if h.adminService != nil {
+		queries = h.adminService.RunningQueries()
+	}
+	c.JSON(http.StatusOK, model.NewSuccessResponse(queries, "Running queries retrieved successfully"))
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler 审计日志处理器（需要 audit 或 admin 权限）
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler 创建审计日志处理器
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// Query 查询审计日志
+// @Summary 查询审计日志
+// @Description 按 API Key、表、操作、结果、请求 ID 和时间范围查询写操作和权限拒绝的审计记录，最新的在前；
+// @Description 还有更早的记录时返回 next_before_id，作为下一页的 before_id。需要 audit 或 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param api_key_name query string false "API Key 名称"
+// @Param table query string false "表名（例程调用为例程名）"
+// @Param action query string false "操作类型（insert、update、delete、call 等）"
+// @Param outcome query string false "结果（success、failed、denied）"
+// @Param request_id query string false "请求 ID"
+// @Param from query string false "起始时间（RFC3339，包含）"
+// @Param to query string false "结束时间（RFC3339，不包含）"
+// @Param before_id query int false "只返回 ID 小于该值的记录"
+// @Param limit query int false "返回记录数（默认 100，最大 1000）"
+// @Success 200 {object} model.SuccessResponse{data=model.AuditQueryResponse} "审计记录"
+// @Failure 400 {object} model.SQLResponse "参数错误"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 500 {object} model.ErrorResponse "查询失败"
+// @Router /api/v1/admin/audit [get]
+func (h *AuditHandler) Query(c *gin.Context) {
+	if !h.checkAuditPermission(c) {
+		return
+	}
+
+	var req model.AuditQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid request format", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response, err := h.auditService.Query(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(http.StatusInternalServerError, "Failed to query audit records", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(response, "Audit records retrieved successfully"))
+}
+
+// Verify 校验审计文件的哈希链
+// @Summary 校验审计文件
+// @Description 重新计算 JSONL 审计文件中每条记录的哈希并检查哈希链，返回第一条校验失败的行号。需要 audit 或 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse{data=model.AuditVerifyResult} "校验结果"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 404 {object} model.ErrorResponse "未配置审计文件"
+// @Failure 500 {object} model.ErrorResponse "读取审计文件失败"
+// @Router /api/v1/admin/audit/verify [get]
+func (h *AuditHandler) Verify(c *gin.Context) {
+	if !h.checkAuditPermission(c) {
+		return
+	}
+
+	result, err := h.auditService.Verify()
+	if errors.Is(err, service.ErrAuditFileNotConfigured) {
+		c.JSON(http.StatusNotFound, model.NewErrorResponse(http.StatusNotFound, "Audit file is not configured"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(http.StatusInternalServerError, "Failed to verify audit file", err.Error()))
+		return
+	}
+
+	message := "Audit file verified successfully"
+	if !result.Valid {
+		message = "Audit file verification failed"
+	}
+	c.JSON(http.StatusOK, model.NewSuccessResponse(result, message))
+}
+
+// RecordDenials 将被拒绝（403）的请求写入审计日志的中间件
+func (h *AuditHandler) RecordDenials() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Status() != http.StatusForbidden {
+			return
+		}
+
+		h.auditService.RecordDenial(c.Request.Context(), &model.AuditRecord{
+			Table:      c.Param("table"),
+			Permission: c.GetString("denied_permission"),
+		})
+	}
+}
+
+// checkAuditPermission 检查审计权限，无权限时直接写入 403 响应
+func (h *AuditHandler) checkAuditPermission(c *gin.Context) bool {
+	if hasPermission(c, "audit") || hasPermission(c, "admin") {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, model.NewErrorResponse(model.SQLErrorPermission, "Audit permission required"))
+	return false
+}
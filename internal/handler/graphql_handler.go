@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"sql2api/internal/graphql"
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler GraphQL 请求处理器
+type GraphQLHandler struct {
+	graphQLService service.GraphQLService
+}
+
+// NewGraphQLHandler 创建 GraphQL 请求处理器
+func NewGraphQLHandler(graphQLService service.GraphQLService) *GraphQLHandler {
+	return &GraphQLHandler{
+		graphQLService: graphQLService,
+	}
+}
+
+// HandlePost 执行 GraphQL 查询或变更
+// @Summary 执行 GraphQL 请求
+// @Description 执行由允许访问的表生成的 GraphQL 查询和变更，查询需要 sql.query 权限，变更需要 sql.insert/sql.update/sql.delete 权限
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.GraphQLRequest true "GraphQL 请求"
+// @Success 200 {object} model.GraphQLResponse "执行结果（字段错误在 errors 中返回）"
+// @Failure 400 {object} model.ErrorResponse "请求参数错误"
+// @Failure 401 {object} model.ErrorResponse "未认证"
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) HandlePost(c *gin.Context) {
+	var req model.GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid GraphQL request", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.graphQLService.Execute(c.Request.Context(), &req, h.permissionChecker(c)))
+}
+
+// HandleGet 通过 URL 参数执行 GraphQL 查询（不允许变更）
+// @Summary 通过 GET 执行 GraphQL 查询
+// @Description 通过 query、operationName 和 JSON 编码的 variables 参数执行 GraphQL 查询，变更操作必须使用 POST
+// @Tags GraphQL
+// @Produce json
+// @Security ApiKeyAuth
+// @Param query query string true "GraphQL 查询"
+// @Param operationName query string false "操作名称"
+// @Param variables query string false "JSON 编码的变量"
+// @Success 200 {object} model.GraphQLResponse "执行结果"
+// @Failure 400 {object} model.ErrorResponse "请求参数错误"
+// @Failure 405 {object} model.ErrorResponse "GET 请求不允许变更"
+// @Router /api/v1/graphql [get]
+func (h *GraphQLHandler) HandleGet(c *gin.Context) {
+	var req model.GraphQLRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid GraphQL request", err.Error()))
+		return
+	}
+
+	if variables := c.Query("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid GraphQL variables", err.Error()))
+			return
+		}
+	}
+
+	// GET 请求只允许查询，语法错误交给执行阶段按 GraphQL 格式返回
+	if doc, err := graphql.Parse(req.Query); err == nil {
+		if op, err := doc.Operation(req.OperationName); err == nil && op.Type != "query" {
+			c.JSON(http.StatusMethodNotAllowed, model.NewErrorResponse(model.SQLErrorParams, "Only queries are allowed over GET", op.Type))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, h.graphQLService.Execute(c.Request.Context(), &req, h.permissionChecker(c)))
+}
+
+// GetSchema 获取 GraphQL SDL
+// @Summary 获取 GraphQL 模式
+// @Description 返回当前 API Key 可用的 GraphQL 模式（SDL 格式），代替内省查询
+// @Tags GraphQL
+// @Produce plain
+// @Security ApiKeyAuth
+// @Success 200 {string} string "GraphQL SDL"
+// @Failure 401 {object} model.ErrorResponse "未认证"
+// @Failure 500 {object} model.ErrorResponse "生成失败"
+// @Router /api/v1/graphql/schema [get]
+func (h *GraphQLHandler) GetSchema(c *gin.Context) {
+	sdl, err := h.graphQLService.SDL(c.Request.Context(), h.permissionChecker(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(model.SQLErrorConnection, "Failed to generate GraphQL schema", err.Error()))
+		return
+	}
+
+	c.String(http.StatusOK, sdl)
+}
+
+// permissionChecker 返回基于当前 API Key 的权限判断函数
+func (h *GraphQLHandler) permissionChecker(c *gin.Context) func(permission string) bool {
+	return func(permission string) bool {
+		return hasPermission(c, permission)
+	}
+}
@@ -37,16 +37,26 @@ func (h *AuthHandler) Health(c *gin.Context) {
 
 // Handlers 处理器集合
 type Handlers struct {
-	Auth     *AuthHandler
-	Resource *ResourceHandler
-	SQL      *SQLHandler
+	Auth          *AuthHandler
+	Resource      *ResourceHandler
+	SQL           *SQLHandler
+	Queries       *QueryHandler
+	Schema        *SchemaHandler
+	OpenAPI       *OpenAPIHandler
+	GraphQL       *GraphQLHandler
+	Tables        *TableHandler
+	OData         *ODataHandler
+	Subscriptions *SubscriptionHandler
+	Webhooks      *WebhookHandler
+	Import        *ImportHandler
+	Audit         *AuditHandler
+	Statements    *StatementHandler
 }
 
 // NewHandlers 创建处理器集合
 func NewHandlers(services *service.Services) *Handlers {
 	handlers := &Handlers{
-		Auth:     NewAuthHandler(),
-		Resource: NewResourceHandler(services.Item),
+		Auth: NewAuthHandler(),
 	}
 
 	// 如果 SQL 服务可用，则创建 SQL 处理器
@@ -54,6 +64,66 @@ func NewHandlers(services *service.Services) *Handlers {
 		handlers.SQL = NewSQLHandler(services.SQL)
 	}
 
+	// 如果资源服务可用，则创建资源处理器
+	if services.Resources != nil {
+		handlers.Resource = NewResourceHandler(services.Resources)
+	}
+
+	// 如果命名查询服务可用，则创建命名查询处理器
+	if services.Queries != nil {
+		handlers.Queries = NewQueryHandler(services.Queries)
+	}
+
+	// 如果表结构服务可用，则创建表结构处理器
+	if services.Schema != nil {
+		handlers.Schema = NewSchemaHandler(services.Schema)
+	}
+
+	// 如果 OpenAPI 文档服务可用，则创建 OpenAPI 处理器
+	if services.OpenAPI != nil {
+		handlers.OpenAPI = NewOpenAPIHandler(services.OpenAPI)
+	}
+
+	// 如果 GraphQL 服务可用，则创建 GraphQL 处理器
+	if services.GraphQL != nil {
+		handlers.GraphQL = NewGraphQLHandler(services.GraphQL)
+	}
+
+	// 如果表查询服务可用，则创建表查询处理器
+	if services.Tables != nil {
+		handlers.Tables = NewTableHandler(services.Tables)
+	}
+
+	// 如果 OData 服务可用，则创建 OData 处理器
+	if services.OData != nil {
+		handlers.OData = NewODataHandler(services.OData)
+	}
+
+	// 如果表变更订阅服务可用，则创建订阅处理器
+	if services.Subscriptions != nil {
+		handlers.Subscriptions = NewSubscriptionHandler(services.Subscriptions)
+	}
+
+	// 如果 webhook 服务可用，则创建 webhook 管理处理器
+	if services.Webhooks != nil {
+		handlers.Webhooks = NewWebhookHandler(services.Webhooks)
+	}
+
+	// 如果数据导入服务可用，则创建数据导入处理器
+	if services.Import != nil {
+		handlers.Import = NewImportHandler(services.Import)
+	}
+
+	// 如果审计日志服务可用，则创建审计日志处理器
+	if services.Audit != nil {
+		handlers.Audit = NewAuditHandler(services.Audit)
+	}
+
+	// 如果语句统计服务可用，则创建语句统计处理器
+	if services.Statements != nil {
+		handlers.Statements = NewStatementHandler(services.Statements)
+	}
+
 	return handlers
 }
 
@@ -62,6 +132,17 @@ type HandlerManager interface {
 	GetAuthHandler() *AuthHandler
 	GetResourceHandler() *ResourceHandler
 	GetSQLHandler() *SQLHandler
+	GetQueryHandler() *QueryHandler
+	GetSchemaHandler() *SchemaHandler
+	GetOpenAPIHandler() *OpenAPIHandler
+	GetGraphQLHandler() *GraphQLHandler
+	GetTableHandler() *TableHandler
+	GetODataHandler() *ODataHandler
+	GetSubscriptionHandler() *SubscriptionHandler
+	GetWebhookHandler() *WebhookHandler
+	GetImportHandler() *ImportHandler
+	GetAuditHandler() *AuditHandler
+	GetStatementHandler() *StatementHandler
 }
 
 // handlerManager 处理器管理器实现
@@ -90,3 +171,131 @@ func (hm *handlerManager) GetResourceHandler() *ResourceHandler {
 func (hm *handlerManager) GetSQLHandler() *SQLHandler {
 	return hm.handlers.SQL
 }
+
+// GetQueryHandler 获取命名查询处理器
+func (hm *handlerManager) GetQueryHandler() *QueryHandler {
+	return hm.handlers.Queries
+}
+
+// GetSchemaHandler 获取表结构处理器
+func (hm *handlerManager) GetSchemaHandler() *SchemaHandler {
+	return hm.handlers.Schema
+}
+
+// GetOpenAPIHandler 获取 OpenAPI 处理器
+func (hm *handlerManager) GetOpenAPIHandler() *OpenAPIHandler {
+	return hm.handlers.OpenAPI
+}
+
+// GetGraphQLHandler 获取 GraphQL 处理器
+func (hm *handlerManager) GetGraphQLHandler() *GraphQLHandler {
+	return hm.handlers.GraphQL
+}
+
+// GetTableHandler 获取表查询处理器
+func (hm *handlerManager) GetTableHandler() *TableHandler {
+	return hm.handlers.Tables
+}
+
+// GetODataHandler 获取 OData 处理器
+func (hm *handlerManager) GetODataHandler() *ODataHandler {
+	return hm.handlers.OData
+}
+
+// GetSubscriptionHandler 获取表变更订阅处理器
+func (hm *handlerManager) GetSubscriptionHandler() *SubscriptionHandler {
+	return hm.handlers.Subscriptions
+}
+
+// GetWebhookHandler 获取 webhook 管理处理器
+func (hm *handlerManager) GetWebhookHandler() *WebhookHandler {
+	return hm.handlers.Webhooks
+}
+
+// GetImportHandler 获取数据导入处理器
+func (hm *handlerManager) GetImportHandler() *ImportHandler {
+	return hm.handlers.Import
+}
+
+// GetAuditHandler 获取审计日志处理器
+func (hm *handlerManager) GetAuditHandler() *AuditHandler {
+	return hm.handlers.Audit
+}
+
+// GetStatementHandler 获取语句统计处理器
+func (hm *handlerManager) GetStatementHandler() *StatementHandler {
+	return hm.handlers.Statements
+}
+
+// ===== 公共辅助函数 =====
+
+// hasPermission 检查是否有指定权限
+func hasPermission(c *gin.Context, permission string) bool {
+	// 从上下文中获取 API Key 管理器
+	apiKeyManager, exists := c.Get("api_key_manager")
+	if !exists {
+		return false
+	}
+
+	// 获取 API Key
+	apiKey := getAPIKey(c)
+	if apiKey == "" {
+		return false
+	}
+
+	// 检查权限
+	if manager, ok := apiKeyManager.(interface{ HasPermission(string, string) bool }); ok && manager.HasPermission(apiKey, permission) {
+		return true
+	}
+
+	// 记录第一个缺少的权限，请求被拒绝时写入审计日志
+	if _, exists := c.Get("denied_permission"); !exists {
+		c.Set("denied_permission", permission)
+	}
+	return false
+}
+
+// getAPIKey 从请求中获取 API Key
+func getAPIKey(c *gin.Context) string {
+	// 从 Header 中获取
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return apiKey
+	}
+
+	// 从查询参数中获取
+	if apiKey := c.Query("api_key"); apiKey != "" {
+		return apiKey
+	}
+
+	return ""
+}
+
+// httpStatusFromSQLError 根据 SQL 错误码获取 HTTP 状态码
+func httpStatusFromSQLError(sqlError *model.SQLError) int {
+	if sqlError == nil {
+		return http.StatusInternalServerError
+	}
+
+	switch sqlError.Code {
+	case model.SQLErrorParams:
+		return http.StatusBadRequest
+	case model.SQLErrorPermission:
+		return http.StatusForbidden
+	case model.SQLErrorConnection:
+		return http.StatusServiceUnavailable
+	case model.SQLErrorTimeout:
+		return http.StatusRequestTimeout
+	case model.SQLErrorSyntax:
+		return http.StatusBadRequest
+	case model.SQLErrorTransaction:
+		return http.StatusInternalServerError
+	case model.SQLErrorResultSize:
+		return http.StatusRequestEntityTooLarge
+	case model.SQLErrorConflict:
+		return http.StatusConflict
+	case model.SQLErrorPrecondition:
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
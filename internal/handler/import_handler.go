@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+	"sql2api/internal/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importContentTypes 按 Content-Type 判断的导入格式
+var importContentTypes = map[string]string{
+	"text/csv":                service.ImportFormatCSV,
+	"application/csv":         service.ImportFormatCSV,
+	"application/x-ndjson":    service.ImportFormatNDJSON,
+	"application/ndjson":      service.ImportFormatNDJSON,
+	"application/jsonl":       service.ImportFormatNDJSON,
+	"application/x-jsonlines": service.ImportFormatNDJSON,
+}
+
+// ImportHandler 数据导入处理器
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+// NewImportHandler 创建数据导入处理器
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// Import 批量导入数据
+// @Summary 批量导入 CSV / NDJSON 数据
+// @Description 以流式方式上传 CSV 或 NDJSON 数据（可为 gzip 压缩），按列类型转换后分批写入允许访问的表，需要 sql.insert 权限
+// @Description 响应为 NDJSON 事件流：每批提交后发送 progress，被拒绝的行发送 rejected（含行号和原因），最后发送 summary
+// @Description 被拒绝的行数超过 max_errors 时中止导入，已提交的批次保留
+// @Tags SQL
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce application/x-ndjson
+// @Security ApiKeyAuth
+// @Param table path string true "表名"
+// @Param format query string false "数据格式：csv 或 ndjson，默认按 Content-Type 判断"
+// @Param columns query string false "列映射，如 src:dest,name；无表头的 CSV 按位置列出目标列，空项表示跳过"
+// @Param header query bool false "CSV 第一行是否为表头（默认 true）"
+// @Param delimiter query string false "CSV 分隔符（默认为逗号）"
+// @Param null query string false "CSV 中表示 NULL 的值（默认为空字符串）"
+// @Param chunk_size query int false "每次提交的行数"
+// @Param max_errors query int false "允许拒绝的行数，-1 表示不限制"
+// @Success 200 {object} service.ImportEvent "导入事件流"
+// @Failure 400 {object} model.SQLResponse "请求参数错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "表不存在"
+// @Router /api/v1/import/{table} [post]
+func (h *ImportHandler) Import(c *gin.Context) {
+	if !hasPermission(c, "sql.insert") {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Insufficient permissions for import operation")
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	req, err := h.parseRequest(c)
+	if err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid import request", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// 上传和导入可能持续很长时间，不受服务器读写超时限制；边读取上传数据边发送进度
+	controller := http.NewResponseController(c.Writer)
+	controller.SetReadDeadline(time.Time{})
+	controller.SetWriteDeadline(time.Time{})
+	controller.EnableFullDuplex()
+
+	// 第一个事件发送前才开始响应，之前的错误仍以普通 JSON 响应返回
+	started := false
+	encoder := json.NewEncoder(c.Writer)
+	send := func(event *service.ImportEvent) {
+		if !started {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("X-Accel-Buffering", "no")
+			c.Status(http.StatusOK)
+			started = true
+		}
+		encoder.Encode(event)
+		c.Writer.Flush()
+	}
+
+	summary, err := h.importService.Import(c.Request.Context(), req, c.Request.Body, send)
+	if err != nil {
+		switch {
+		// 不在允许列表中的表与不存在的表返回相同的响应
+		case errors.Is(err, sql.ErrTableNotAllowed), errors.Is(err, sql.ErrTableNotFound):
+			response := model.NewSQLErrorResponse(model.SQLErrorParams, "Table not found", req.Table)
+			c.JSON(http.StatusNotFound, response)
+		case errors.Is(err, service.ErrInvalidImportRequest):
+			response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid import request", err.Error())
+			c.JSON(http.StatusBadRequest, response)
+		default:
+			response := model.NewSQLErrorResponse(model.SQLErrorConnection, "Import failed", err.Error())
+			c.JSON(http.StatusInternalServerError, response)
+		}
+		return
+	}
+
+	send(summary)
+}
+
+// parseRequest 从路径、查询参数和 Content-Type 解析导入请求
+func (h *ImportHandler) parseRequest(c *gin.Context) (*service.ImportRequest, error) {
+	req := &service.ImportRequest{
+		Table:     c.Param("table"),
+		Format:    c.Query("format"),
+		Header:    true,
+		Delimiter: c.Query("delimiter"),
+		NullValue: c.Query("null"),
+	}
+
+	if req.Format == "" {
+		req.Format = importContentTypes[c.ContentType()]
+	}
+
+	columns, err := service.ParseImportColumns(c.Query("columns"))
+	if err != nil {
+		return nil, err
+	}
+	req.Columns = columns
+
+	if raw := c.Query("header"); raw != "" {
+		header, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("invalid header: " + raw)
+		}
+		req.Header = header
+	}
+
+	if raw := c.Query("chunk_size"); raw != "" {
+		chunkSize, err := strconv.Atoi(raw)
+		if err != nil || chunkSize <= 0 {
+			return nil, errors.New("invalid chunk_size: " + raw)
+		}
+		req.ChunkSize = chunkSize
+	}
+
+	if raw := c.Query("max_errors"); raw != "" {
+		maxErrors, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errors.New("invalid max_errors: " + raw)
+		}
+		req.MaxErrors = &maxErrors
+	}
+
+	return req, nil
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"sql2api/internal/odata"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// odataPath OData 服务根路径
+const odataPath = "/api/v1/odata"
+
+// ODataHandler OData 查询处理器
+type ODataHandler struct {
+	odataService service.ODataService
+}
+
+// NewODataHandler 创建 OData 查询处理器
+func NewODataHandler(odataService service.ODataService) *ODataHandler {
+	return &ODataHandler{
+		odataService: odataService,
+	}
+}
+
+// ServiceDocument 返回 OData 服务文档（实体集列表）
+// @Summary OData 服务文档
+// @Description 列出可查询的实体集，需要 sql.query 权限
+// @Tags OData
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "服务文档"
+// @Failure 403 {object} map[string]interface{} "权限不足"
+// @Router /api/v1/odata [get]
+func (h *ODataHandler) ServiceDocument(c *gin.Context) {
+	if !h.authorize(c) {
+		return
+	}
+
+	sets, err := h.odataService.EntitySets(c.Request.Context())
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	value := make([]gin.H, len(sets))
+	for i, set := range sets {
+		value[i] = gin.H{"name": set, "kind": "EntitySet", "url": set}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"@odata.context": h.baseURL(c) + "/$metadata",
+		"value":          value,
+	})
+}
+
+// Metadata 返回 $metadata 文档
+// @Summary OData 元数据
+// @Description 返回根据表结构生成的 CSDL XML 文档，需要 sql.query 权限
+// @Tags OData
+// @Produce xml
+// @Security ApiKeyAuth
+// @Success 200 {string} string "CSDL 文档"
+// @Failure 403 {object} map[string]interface{} "权限不足"
+// @Router /api/v1/odata/$metadata [get]
+func (h *ODataHandler) Metadata(c *gin.Context) {
+	if !h.authorize(c) {
+		return
+	}
+
+	document, err := h.odataService.Metadata(c.Request.Context())
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/xml", document)
+}
+
+// HandleEntitySet 查询实体集或按键获取单个实体
+// @Summary OData 实体集查询
+// @Description 查询实体集，支持 $filter、$select、$orderby、$top、$skip、$count、$expand；使用 set(key) 获取单个实体，set/$count 返回计数，需要 sql.query 权限
+// @Tags OData
+// @Produce json
+// @Security ApiKeyAuth
+// @Param set path string true "实体集名称，可带键，如 users(1)"
+// @Param $filter query string false "过滤条件，如 age gt 18 and contains(name,'a')"
+// @Param $select query string false "返回的属性，如 id,name"
+// @Param $orderby query string false "排序，如 name desc"
+// @Param $top query int false "返回实体数"
+// @Param $skip query int false "跳过实体数"
+// @Param $count query bool false "是否返回总数"
+// @Param $expand query string false "展开导航属性，如 orders($top=5)"
+// @Success 200 {object} map[string]interface{} "查询结果"
+// @Failure 400 {object} map[string]interface{} "查询选项无效"
+// @Failure 403 {object} map[string]interface{} "权限不足"
+// @Failure 404 {object} map[string]interface{} "实体集或实体不存在"
+// @Router /api/v1/odata/{set} [get]
+func (h *ODataHandler) HandleEntitySet(c *gin.Context) {
+	if !h.authorize(c) {
+		return
+	}
+
+	values, opts, err := h.parseOptions(c)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	set := c.Param("set")
+
+	// set(key) 获取单个实体
+	if idx := strings.Index(set, "("); idx > 0 && strings.HasSuffix(set, ")") {
+		name, key := set[:idx], set[idx+1:len(set)-1]
+		entity, err := h.odataService.Get(c.Request.Context(), name, key, opts)
+		if err != nil {
+			h.error(c, err)
+			return
+		}
+
+		body := gin.H{"@odata.context": h.baseURL(c) + "/$metadata#" + name + "/$entity"}
+		for property, value := range entity {
+			body[property] = value
+		}
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
+	result, err := h.odataService.Query(c.Request.Context(), set, opts)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	body := gin.H{
+		"@odata.context": h.baseURL(c) + "/$metadata#" + set,
+		"value":          result.Rows,
+	}
+	if result.Count != nil {
+		body["@odata.count"] = *result.Count
+	}
+	if result.NextSkip > 0 {
+		body["@odata.nextLink"] = h.nextLink(c, values, result)
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// Count 返回实体集中满足 $filter 的实体数（纯文本）
+// @Summary OData 实体计数
+// @Description 返回实体集中满足 $filter 的实体数，需要 sql.query 权限
+// @Tags OData
+// @Produce plain
+// @Security ApiKeyAuth
+// @Param set path string true "实体集名称"
+// @Param $filter query string false "过滤条件"
+// @Success 200 {string} string "实体数"
+// @Failure 400 {object} map[string]interface{} "查询选项无效"
+// @Failure 404 {object} map[string]interface{} "实体集不存在"
+// @Router /api/v1/odata/{set}/$count [get]
+func (h *ODataHandler) Count(c *gin.Context) {
+	if !h.authorize(c) {
+		return
+	}
+
+	_, opts, err := h.parseOptions(c)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	count, err := h.odataService.Count(c.Request.Context(), c.Param("set"), opts)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+
+	c.String(http.StatusOK, strconv.FormatInt(count, 10))
+}
+
+// parseOptions 解析请求中的查询参数和系统查询选项
+func (h *ODataHandler) parseOptions(c *gin.Context) (url.Values, *odata.Options, error) {
+	values, err := odata.ParseQuery(c.Request.URL.RawQuery)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", service.ErrInvalidODataQuery, err)
+	}
+
+	opts, err := odata.ParseOptions(values)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", service.ErrInvalidODataQuery, err)
+	}
+	return values, opts, nil
+}
+
+// authorize 检查 sql.query 权限并设置 OData-Version 响应头
+func (h *ODataHandler) authorize(c *gin.Context) bool {
+	c.Header("OData-Version", "4.0")
+	if !hasPermission(c, "sql.query") {
+		odataError(c, http.StatusForbidden, "Forbidden", "Insufficient permissions")
+		return false
+	}
+	return true
+}
+
+// error 将服务错误转换为 OData 错误响应
+func (h *ODataHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidODataQuery):
+		odataError(c, http.StatusBadRequest, "BadRequest", err.Error())
+	case errors.Is(err, service.ErrEntitySetNotFound), errors.Is(err, service.ErrEntityNotFound):
+		odataError(c, http.StatusNotFound, "NotFound", err.Error())
+	default:
+		sqlError := service.ExecutionError(err)
+		message := sqlError.Message
+		if sqlError.Details != "" {
+			message += ": " + sqlError.Details
+		}
+		odataError(c, httpStatusFromSQLError(sqlError), strconv.Itoa(sqlError.Code), message)
+	}
+}
+
+// baseURL 返回 OData 服务根地址
+func (h *ODataHandler) baseURL(c *gin.Context) string {
+	return requestBaseURL(c) + odataPath
+}
+
+// nextLink 生成下一页链接，保留原请求的其它参数
+func (h *ODataHandler) nextLink(c *gin.Context, values url.Values, result *service.ODataResult) string {
+	values.Set("$skip", strconv.Itoa(result.NextSkip))
+	if result.NextTop >= 0 {
+		values.Set("$top", strconv.Itoa(result.NextTop))
+	} else {
+		values.Del("$top")
+	}
+	next := url.URL{Path: c.Request.URL.Path, RawQuery: values.Encode()}
+	return requestBaseURL(c) + next.String()
+}
+
+// odataError 返回 OData 格式的错误响应
+func odataError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
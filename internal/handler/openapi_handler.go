@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPIHandler 动态 OpenAPI 文档处理器
+type OpenAPIHandler struct {
+	openAPIService service.OpenAPIService
+}
+
+// NewOpenAPIHandler 创建动态 OpenAPI 文档处理器
+func NewOpenAPIHandler(openAPIService service.OpenAPIService) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		openAPIService: openAPIService,
+	}
+}
+
+// GetDocument 生成当前 API Key 可用操作的 OpenAPI 文档
+// @Summary 动态 OpenAPI 文档
+// @Description 根据资源、允许访问的表、命名查询和数据库表结构生成 OpenAPI 3.0 文档，只包含当前 API Key 有权限的操作（表的查询和导入操作按 sql.query、sql.insert 权限生成，表结构操作和按列生成的记录结构需要 schema.<table> 权限）
+// @Tags 系统
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "OpenAPI 文档"
+// @Failure 401 {object} model.ErrorResponse "未认证"
+// @Failure 500 {object} model.ErrorResponse "生成失败"
+// @Router /openapi.json [get]
+func (h *OpenAPIHandler) GetDocument(c *gin.Context) {
+	document, err := h.openAPIService.Generate(c.Request.Context(), service.OpenAPIOptions{
+		DefaultServer: requestBaseURL(c),
+		Allow: func(permission string) bool {
+			return hasPermission(c, permission)
+		},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(model.SQLErrorConnection, "Failed to generate OpenAPI document", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// requestBaseURL 根据请求推断服务地址（支持反向代理的 X-Forwarded-Proto）
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
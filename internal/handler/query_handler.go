@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryHandler 命名查询 API 处理器
+type QueryHandler struct {
+	queryService service.QueryService
+}
+
+// NewQueryHandler 创建命名查询 API 处理器
+func NewQueryHandler(queryService service.QueryService) *QueryHandler {
+	return &QueryHandler{
+		queryService: queryService,
+	}
+}
+
+// ListQueries 列出当前 API Key 可执行的命名查询
+// @Summary 列出命名查询
+// @Description 返回当前 API Key 有权限执行的命名查询及其参数定义
+// @Tags Queries
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "查询列表"
+// @Failure 401 {object} model.ErrorResponse "未认证"
+// @Router /api/v1/queries [get]
+func (h *QueryHandler) ListQueries(c *gin.Context) {
+	queries := make([]gin.H, 0)
+	for _, query := range h.queryService.ListQueries() {
+		if !hasPermission(c, query.GetPermission()) {
+			continue
+		}
+		queries = append(queries, gin.H{
+			"name":        query.Name,
+			"description": query.Description,
+			"params":      query.Params,
+			"cache_ttl":   query.CacheTTL,
+		})
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(queries, "Saved queries retrieved successfully"))
+}
+
+// HandleGetQuery 通过查询字符串执行命名查询
+// @Summary 执行命名查询（GET）
+// @Description 查询字符串中的参数按参数定义转换类型后绑定到 SQL
+// @Tags Queries
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "命名查询名称"
+// @Success 200 {object} model.SQLResponse "执行成功"
+// @Failure 400 {object} model.SQLResponse "参数错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "命名查询不存在"
+// @Router /api/v1/queries/{name} [get]
+func (h *QueryHandler) HandleGetQuery(c *gin.Context) {
+	params := make(map[string]interface{})
+	for key, values := range c.Request.URL.Query() {
+		// API Key 查询参数不属于命名查询参数
+		if key == "api_key" || len(values) == 0 {
+			continue
+		}
+		params[key] = values[0]
+	}
+
+	h.executeQuery(c, params)
+}
+
+// HandlePostQuery 通过 JSON 请求体执行命名查询
+// @Summary 执行命名查询（POST）
+// @Description 请求体中的参数按参数定义校验后绑定到 SQL
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "命名查询名称"
+// @Param request body model.SavedQueryRequest false "查询参数"
+// @Success 200 {object} model.SQLResponse "执行成功"
+// @Failure 400 {object} model.SQLResponse "参数错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "命名查询不存在"
+// @Router /api/v1/queries/{name} [post]
+func (h *QueryHandler) HandlePostQuery(c *gin.Context) {
+	var req model.SavedQueryRequest
+
+	// 允许空请求体
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid request format", err.Error())
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	h.executeQuery(c, req.Params)
+}
+
+// executeQuery 检查权限并执行命名查询
+func (h *QueryHandler) executeQuery(c *gin.Context, params map[string]interface{}) {
+	name := c.Param("name")
+
+	query, ok := h.queryService.GetQuery(name)
+	if !ok {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Saved query not found", name)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	// 检查命名查询权限
+	if !hasPermission(c, query.GetPermission()) {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Insufficient permissions for saved query")
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	response, err := h.queryService.ExecuteQuery(c.Request.Context(), name, params)
+	if err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorSyntax, "Saved query execution failed", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !response.Success {
+		c.JSON(httpStatusFromSQLError(response.Error), response)
+		return
+	}
+
+	if query.CacheTTL > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", query.CacheTTL))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ===== 管理接口 =====
+
+// AdminListQueries 列出所有命名查询定义
+// @Summary 列出命名查询定义
+// @Description 返回所有命名查询的完整定义（包含 SQL），需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "查询定义列表"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/admin/queries [get]
+func (h *QueryHandler) AdminListQueries(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(h.queryService.ListQueries(), "Saved queries retrieved successfully"))
+}
+
+// AdminSaveQuery 新增或替换命名查询
+// @Summary 保存命名查询
+// @Description 新增或替换命名查询定义，需要 admin 权限；运行时修改不会写回配置文件
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "命名查询名称"
+// @Param request body config.SavedQueryConfig true "命名查询定义"
+// @Success 200 {object} model.SuccessResponse "保存成功"
+// @Failure 400 {object} model.ErrorResponse "定义无效"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/admin/queries/{name} [put]
+func (h *QueryHandler) AdminSaveQuery(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	var query config.SavedQueryConfig
+	if err := c.ShouldBindJSON(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid request format", err.Error()))
+		return
+	}
+
+	// 路径中的名称优先
+	query.Name = c.Param("name")
+
+	if err := h.queryService.SaveQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid saved query", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(query, "Saved query stored successfully"))
+}
+
+// AdminDeleteQuery 删除命名查询
+// @Summary 删除命名查询
+// @Description 删除命名查询定义，需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "命名查询名称"
+// @Success 200 {object} model.SuccessResponse "删除成功"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 404 {object} model.ErrorResponse "命名查询不存在"
+// @Router /api/v1/admin/queries/{name} [delete]
+func (h *QueryHandler) AdminDeleteQuery(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	name := c.Param("name")
+	if err := h.queryService.DeleteQuery(name); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSavedQueryNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.NewErrorResponse(model.SQLErrorParams, "Failed to delete saved query", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(nil, "Saved query deleted successfully"))
+}
+
+// checkAdminPermission 检查管理权限，无权限时直接写入 403 响应
+func (h *QueryHandler) checkAdminPermission(c *gin.Context) bool {
+	if hasPermission(c, "admin") {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, model.NewErrorResponse(model.SQLErrorPermission, "Admin permission required"))
+	return false
+}
@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResourceHandler REST 资源处理器
+type ResourceHandler struct {
+	resourceService service.ResourceService
+}
+
+// NewResourceHandler 创建 REST 资源处理器
+func NewResourceHandler(resourceService service.ResourceService) *ResourceHandler {
+	return &ResourceHandler{
+		resourceService: resourceService,
+	}
+}
+
+// List 列出资源记录
+// @Summary 列出资源记录
+// @Description 支持分页、排序（sort=field 或 sort=-field）和字段等值过滤（field=value）
+// @Tags Resource
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "资源名称"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页大小"
+// @Param sort query string false "排序字段，前缀 - 表示降序"
+// @Param include_deleted query bool false "包含软删除的记录（需要 resource.<name>.include_deleted 权限）"
+// @Success 200 {object} model.SQLResponse "查询成功"
+// @Failure 400 {object} model.SQLResponse "请求参数错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "资源不存在"
+// @Router /api/v1/resources/{name} [get]
+func (h *ResourceHandler) List(c *gin.Context) {
+	if !h.checkResourcePermission(c, "list") {
+		return
+	}
+
+	var req model.ResourceListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid request format", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if req.IncludeDeleted && !h.checkIncludeDeleted(c) {
+		return
+	}
+
+	// 其余查询参数作为等值过滤条件
+	req.Filters = make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		switch key {
+		case "page", "page_size", "sort", "include_deleted", "api_key":
+			continue
+		}
+		if len(values) > 0 {
+			req.Filters[key] = values[0]
+		}
+	}
+
+	response, err := h.resourceService.List(c.Request.Context(), c.Param("name"), &req)
+	h.writeResponse(c, response, err, http.StatusOK)
+}
+
+// Get 获取单条记录
+// @Summary 获取资源记录
+// @Description 按主键获取单条记录，表配置了版本列时在 ETag 响应头中返回记录版本
+// @Tags Resource
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "资源名称"
+// @Param id path string true "主键"
+// @Param include_deleted query bool false "包含软删除的记录（需要 resource.<name>.include_deleted 权限）"
+// @Success 200 {object} model.SQLResponse "查询成功"
+// @Header 200 {string} ETag "记录版本（表配置了版本列时）"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "记录不存在"
+// @Router /api/v1/resources/{name}/{id} [get]
+func (h *ResourceHandler) Get(c *gin.Context) {
+	if !h.checkResourcePermission(c, "get") {
+		return
+	}
+
+	includeDeleted := c.Query("include_deleted") == "true"
+	if includeDeleted && !h.checkIncludeDeleted(c) {
+		return
+	}
+
+	response, err := h.resourceService.Get(c.Request.Context(), c.Param("name"), c.Param("id"), includeDeleted)
+	h.writeResponse(c, response, err, http.StatusOK)
+}
+
+// Create 创建记录
+// @Summary 创建资源记录
+// @Description 请求体中的字段名为资源的 API 字段名
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "资源名称"
+// @Param request body map[string]interface{} true "记录数据"
+// @Success 201 {object} model.SQLResponse "创建成功"
+// @Failure 400 {object} model.SQLResponse "请求数据错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Router /api/v1/resources/{name} [post]
+func (h *ResourceHandler) Create(c *gin.Context) {
+	if !h.checkResourcePermission(c, "create") {
+		return
+	}
+
+	data, ok := h.bindData(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.resourceService.Create(c.Request.Context(), c.Param("name"), data)
+	h.writeResponse(c, response, err, http.StatusCreated)
+}
+
+// Replace 整体更新记录
+// @Summary 替换资源记录
+// @Description 未提供的可写字段将被置为 NULL；带 If-Match 时仅在记录版本匹配时更新
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "资源名称"
+// @Param id path string true "主键"
+// @Param If-Match header string false "读取记录时返回的 ETag"
+// @Param request body map[string]interface{} true "记录数据"
+// @Success 200 {object} model.SQLResponse "更新成功"
+// @Failure 400 {object} model.SQLResponse "请求数据错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "记录不存在"
+// @Failure 412 {object} model.SQLResponse "记录版本与 If-Match 不匹配"
+// @Router /api/v1/resources/{name}/{id} [put]
+func (h *ResourceHandler) Replace(c *gin.Context) {
+	h.update(c, true)
+}
+
+// Patch 部分更新记录
+// @Summary 部分更新资源记录
+// @Description 只更新请求中提供的字段；带 If-Match 时仅在记录版本匹配时更新
+// @Tags Resource
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "资源名称"
+// @Param id path string true "主键"
+// @Param If-Match header string false "读取记录时返回的 ETag"
+// @Param request body map[string]interface{} true "记录数据"
+// @Success 200 {object} model.SQLResponse "更新成功"
+// @Failure 400 {object} model.SQLResponse "请求数据错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "记录不存在"
+// @Failure 412 {object} model.SQLResponse "记录版本与 If-Match 不匹配"
+// @Router /api/v1/resources/{name}/{id} [patch]
+func (h *ResourceHandler) Patch(c *gin.Context) {
+	h.update(c, false)
+}
+
+// Delete 删除记录
+// @Summary 删除资源记录
+// @Description 按主键删除单条记录，表配置了软删除时只设置删除标记；带 If-Match 时仅在记录版本匹配时删除
+// @Tags Resource
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "资源名称"
+// @Param id path string true "主键"
+// @Param If-Match header string false "读取记录时返回的 ETag"
+// @Success 200 {object} model.SQLResponse "删除成功"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "记录不存在"
+// @Failure 412 {object} model.SQLResponse "记录版本与 If-Match 不匹配"
+// @Router /api/v1/resources/{name}/{id} [delete]
+func (h *ResourceHandler) Delete(c *gin.Context) {
+	if !h.checkResourcePermission(c, "delete") {
+		return
+	}
+
+	response, err := h.resourceService.Delete(c.Request.Context(), c.Param("name"), c.Param("id"), c.GetHeader("If-Match"))
+	h.writeResponse(c, response, err, http.StatusOK)
+}
+
+// Restore 恢复软删除的记录
+// @Summary 恢复资源记录
+// @Description 清除软删除记录的删除标记，仅适用于配置了软删除的表；带 If-Match 时仅在记录版本匹配时恢复
+// @Tags Resource
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "资源名称"
+// @Param id path string true "主键"
+// @Param If-Match header string false "读取记录时返回的 ETag"
+// @Success 200 {object} model.SQLResponse "恢复成功"
+// @Header 200 {string} ETag "记录版本（表配置了版本列时）"
+// @Failure 400 {object} model.SQLResponse "资源未配置软删除"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "记录不存在或未被删除"
+// @Failure 412 {object} model.SQLResponse "记录版本与 If-Match 不匹配"
+// @Router /api/v1/resources/{name}/{id}/restore [post]
+func (h *ResourceHandler) Restore(c *gin.Context) {
+	if !h.checkResourcePermission(c, "restore") {
+		return
+	}
+
+	response, err := h.resourceService.Restore(c.Request.Context(), c.Param("name"), c.Param("id"), c.GetHeader("If-Match"))
+	h.writeResponse(c, response, err, http.StatusOK)
+}
+
+// ===== 辅助方法 =====
+
+// update 执行 PUT 或 PATCH 更新
+func (h *ResourceHandler) update(c *gin.Context, replace bool) {
+	if !h.checkResourcePermission(c, "update") {
+		return
+	}
+
+	data, ok := h.bindData(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.resourceService.Update(c.Request.Context(), c.Param("name"), c.Param("id"), data, replace, c.GetHeader("If-Match"))
+	h.writeResponse(c, response, err, http.StatusOK)
+}
+
+// bindData 绑定 JSON 记录数据
+func (h *ResourceHandler) bindData(c *gin.Context) (map[string]interface{}, bool) {
+	var data map[string]interface{}
+	if err := c.ShouldBindJSON(&data); err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid request format", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return nil, false
+	}
+	return data, true
+}
+
+// checkResourcePermission 检查调用方权限（resource.<name>.<operation>）、资源是否存在以及是否允许该操作
+// 先检查权限，避免向无权限的调用方暴露已注册的资源和操作
+func (h *ResourceHandler) checkResourcePermission(c *gin.Context, operation string) bool {
+	name := c.Param("name")
+
+	if !hasPermission(c, "resource."+name+"."+operation) {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Insufficient permissions for resource operation")
+		c.JSON(http.StatusForbidden, response)
+		return false
+	}
+
+	resource, ok := h.resourceService.GetResource(name)
+	if !ok {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Resource not found", name)
+		c.JSON(http.StatusNotFound, response)
+		return false
+	}
+
+	if !resource.AllowsOperation(operation) {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Operation not allowed for resource", operation)
+		c.JSON(http.StatusMethodNotAllowed, response)
+		return false
+	}
+
+	return true
+}
+
+// checkIncludeDeleted 检查调用方是否有读取软删除记录的权限（resource.<name>.include_deleted）
+func (h *ResourceHandler) checkIncludeDeleted(c *gin.Context) bool {
+	if !hasPermission(c, "resource."+c.Param("name")+".include_deleted") {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Insufficient permissions to include deleted records")
+		c.JSON(http.StatusForbidden, response)
+		return false
+	}
+	return true
+}
+
+// writeResponse 根据服务结果写入响应
+func (h *ResourceHandler) writeResponse(c *gin.Context, response *model.SQLResponse, err error, successStatus int) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrResourceNotFound) || errors.Is(err, service.ErrRecordNotFound) {
+			status = http.StatusNotFound
+		}
+		errorResponse := model.NewSQLErrorResponse(model.SQLErrorParams, err.Error())
+		c.JSON(status, errorResponse)
+		return
+	}
+
+	if !response.Success {
+		c.JSON(httpStatusFromSQLError(response.Error), response)
+		return
+	}
+
+	if response.ETag != "" {
+		c.Header("ETag", response.ETag)
+	}
+	c.JSON(successStatus, response)
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"net/http/pprof"
+
 	"sql2api/internal/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -19,6 +21,14 @@ func SetupRoutes(
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
+	// 请求 ID 中间件（审计日志按请求 ID 关联）
+	router.Use(middleware.RequestIDMiddleware())
+
+	// 记录被拒绝的请求，需在 IP 白名单之前注册
+	if handlers.Audit != nil {
+		router.Use(handlers.Audit.RecordDenials())
+	}
+
 	// IP 白名单中间件
 	if ipManager != nil {
 		router.Use(middleware.IPWhitelistMiddleware(ipManager))
@@ -37,11 +47,19 @@ func SetupRoutes(
 		auth.POST("/health", handlers.Auth.Health)
 	}
 
-	// 统一资源路由（需要 API Key 认证）
-	resource := v1.Group("/resource")
-	resource.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
-	{
-		resource.POST("", handlers.Resource.HandleResource)
+	// REST 资源路由（需要认证和 resource.<name>.<operation> 权限）
+	if handlers.Resource != nil {
+		resources := v1.Group("/resources")
+		resources.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			resources.GET("/:name", handlers.Resource.List)
+			resources.POST("/:name", handlers.Resource.Create)
+			resources.GET("/:name/:id", handlers.Resource.Get)
+			resources.PUT("/:name/:id", handlers.Resource.Replace)
+			resources.PATCH("/:name/:id", handlers.Resource.Patch)
+			resources.DELETE("/:name/:id", handlers.Resource.Delete)
+			resources.POST("/:name/:id/restore", handlers.Resource.Restore)
+		}
 	}
 
 	// SQL 操作路由（需要认证和相应权限）
@@ -60,9 +78,135 @@ func SetupRoutes(
 
 			// 批量插入端点
 			sql.POST("/batch-insert", handlers.SQL.HandleBatchInsert)
+
+			// 存储过程/函数调用端点
+			sql.POST("/call", handlers.SQL.HandleCall)
+		}
+	}
+
+	// 表查询路由（PostgREST 风格 URL 查询，需要认证和 sql.query 权限）
+	if handlers.Tables != nil {
+		tables := v1.Group("/tables")
+		tables.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			tables.GET("/:table", handlers.Tables.Query)
+		}
+	}
+
+	// 命名查询路由（需要认证和各查询的权限）
+	if handlers.Queries != nil {
+		queries := v1.Group("/queries")
+		queries.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			queries.GET("", handlers.Queries.ListQueries)
+			queries.GET("/:name", handlers.Queries.HandleGetQuery)
+			queries.POST("/:name", handlers.Queries.HandlePostQuery)
+		}
+
+		// 命名查询管理路由（需要 admin 权限）
+		admin := v1.Group("/admin/queries")
+		admin.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			admin.GET("", handlers.Queries.AdminListQueries)
+			admin.PUT("/:name", handlers.Queries.AdminSaveQuery)
+			admin.DELETE("/:name", handlers.Queries.AdminDeleteQuery)
+		}
+	}
+
+	// 表结构路由（需要认证和 schema.<table> 权限，刷新需要 admin 权限）
+	if handlers.Schema != nil {
+		schema := v1.Group("/schema")
+		schema.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			schema.GET("", handlers.Schema.ListTables)
+			schema.GET("/:table", handlers.Schema.GetTable)
+			schema.POST("/refresh", handlers.Schema.Refresh)
+		}
+	}
+
+	// GraphQL 路由（需要认证，字段按 sql.<action> 权限过滤）
+	if handlers.GraphQL != nil {
+		graphql := v1.Group("/graphql")
+		graphql.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			graphql.POST("", handlers.GraphQL.HandlePost)
+			graphql.GET("", handlers.GraphQL.HandleGet)
+			graphql.GET("/schema", handlers.GraphQL.GetSchema)
+		}
+	}
+
+	// OData 路由（需要认证和 sql.query 权限）
+	if handlers.OData != nil {
+		odata := v1.Group("/odata")
+		odata.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			odata.GET("", handlers.OData.ServiceDocument)
+			odata.GET("/", handlers.OData.ServiceDocument)
+			odata.GET("/$metadata", handlers.OData.Metadata)
+			odata.GET("/:set", handlers.OData.HandleEntitySet)
+			odata.GET("/:set/$count", handlers.OData.Count)
+		}
+	}
+
+	// 表变更订阅路由（需要认证和 sql.query 权限）
+	if handlers.Subscriptions != nil {
+		subscriptions := v1.Group("/subscriptions")
+		subscriptions.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			subscriptions.GET("/:table", handlers.Subscriptions.Stream)
+			subscriptions.GET("/:table/ws", handlers.Subscriptions.WebSocket)
+		}
+	}
+
+	// 数据导入路由（需要认证和 sql.insert 权限）
+	if handlers.Import != nil {
+		imports := v1.Group("/import")
+		imports.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			imports.POST("/:table", handlers.Import.Import)
 		}
 	}
 
+	// webhook 管理路由（需要 admin 权限）
+	if handlers.Webhooks != nil {
+		webhooks := v1.Group("/admin/webhooks")
+		webhooks.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			webhooks.GET("/endpoints", handlers.Webhooks.ListEndpoints)
+			webhooks.PUT("/endpoints/:name", handlers.Webhooks.SaveEndpoint)
+			webhooks.DELETE("/endpoints/:name", handlers.Webhooks.DeleteEndpoint)
+			webhooks.GET("/dead-letters", handlers.Webhooks.ListDeadLetters)
+			webhooks.POST("/dead-letters/:id/replay", handlers.Webhooks.ReplayDeadLetter)
+			webhooks.DELETE("/dead-letters/:id", handlers.Webhooks.DeleteDeadLetter)
+		}
+	}
+
+	// 审计日志路由（需要 audit 或 admin 权限）
+	if handlers.Audit != nil {
+		audit := v1.Group("/admin/audit")
+		audit.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			audit.GET("", handlers.Audit.Query)
+			audit.GET("/verify", handlers.Audit.Verify)
+		}
+	}
+
+	// 语句统计路由（需要 admin 权限）
+	if handlers.Statements != nil {
+		statements := v1.Group("/admin/statements")
+		statements.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+		{
+			statements.GET("", handlers.Statements.List)
+			statements.DELETE("", handlers.Statements.Reset)
+			statements.GET("/slow", handlers.Statements.SlowQueries)
+		}
+	}
+
+	// 动态 OpenAPI 文档（只包含当前 API Key 可用的操作）
+	if handlers.OpenAPI != nil {
+		router.GET("/openapi.json", middleware.SimpleAuthMiddleware(apiKeyManager, true), handlers.OpenAPI.GetDocument)
+	}
+
 	// 健康检查路由（不需要认证）
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -72,11 +216,54 @@ func SetupRoutes(
 		})
 	})
 
-	// IP 信息查看路由（调试用）
-	router.GET("/debug/ip", middleware.CreateIPInfoEndpoint())
-
 	// Swagger 文档路由
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
 
+// SetupAdminRoutes 设置管理端口的路由，所有路由都需要 admin 权限
+func SetupAdminRoutes(
+	router *gin.Engine,
+	admin *AdminHandler,
+	ipManager *middleware.IPWhitelistManager,
+	apiKeyManager *middleware.APIKeyManager,
+	enablePprof bool,
+) {
+	router.Use(gin.Logger())
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestIDMiddleware())
+
+	// IP 白名单同样适用于管理端口
+	if ipManager != nil {
+		router.Use(middleware.IPWhitelistMiddleware(ipManager))
+	}
 
+	router.Use(middleware.SimpleAuthMiddleware(apiKeyManager, true))
+	router.Use(middleware.RequirePermission(apiKeyManager, "admin"))
+
+	group := router.Group("/admin")
+	{
+		group.GET("/build", admin.Build)
+		group.GET("/config", admin.Config)
+		group.GET("/stats", admin.Stats)
+		group.GET("/keys", admin.Keys)
+		group.GET("/ip-whitelist", admin.IPWhitelist)
+		group.GET("/queries/running", admin.RunningQueries)
+	}
+
+	// IP 信息查看路由（调试用）
+	router.GET("/debug/ip", middleware.CreateIPInfoEndpoint())
+
+	// 性能分析路由，/debug/pprof/<profile> 由 pprof.Index 按名称分发
+	if enablePprof {
+		profiles := router.Group("/debug/pprof")
+		{
+			profiles.GET("/", gin.WrapF(pprof.Index))
+			profiles.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+			profiles.GET("/profile", gin.WrapF(pprof.Profile))
+			profiles.GET("/symbol", gin.WrapF(pprof.Symbol))
+			profiles.POST("/symbol", gin.WrapF(pprof.Symbol))
+			profiles.GET("/trace", gin.WrapF(pprof.Trace))
+			profiles.GET("/:name", gin.WrapF(pprof.Index))
+		}
+	}
+}
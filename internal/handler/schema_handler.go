@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+	"sql2api/internal/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchemaHandler 表结构查询处理器
+type SchemaHandler struct {
+	schemaService service.SchemaService
+}
+
+// NewSchemaHandler 创建表结构查询处理器
+func NewSchemaHandler(schemaService service.SchemaService) *SchemaHandler {
+	return &SchemaHandler{
+		schemaService: schemaService,
+	}
+}
+
+// ListTables 列出当前 API Key 可见的表结构
+// @Summary 列出表结构
+// @Description 返回允许访问且当前 API Key 拥有 schema.<table> 权限的表的列、主键、外键、索引和注释
+// @Tags Schema
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "表结构列表"
+// @Failure 401 {object} model.ErrorResponse "未认证"
+// @Failure 500 {object} model.ErrorResponse "读取表结构失败"
+// @Router /api/v1/schema [get]
+func (h *SchemaHandler) ListTables(c *gin.Context) {
+	tables := make([]*sql.TableSchema, 0)
+	for _, table := range h.schemaService.ListTables() {
+		if !hasPermission(c, schemaPermission(table)) {
+			continue
+		}
+
+		schema, err := h.schemaService.GetTable(c.Request.Context(), table)
+		if err != nil {
+			// 配置中存在但数据库中不存在的表不列出
+			if errors.Is(err, sql.ErrTableNotFound) {
+				continue
+			}
+			c.JSON(http.StatusInternalServerError, model.NewErrorResponse(model.SQLErrorConnection, "Failed to read table schema", err.Error()))
+			return
+		}
+		tables = append(tables, schema)
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(tables, "Table schemas retrieved successfully"))
+}
+
+// GetTable 获取单个表结构
+// @Summary 获取表结构
+// @Description 返回指定表的列、主键、外键、索引和注释，需要 schema.<table> 权限
+// @Tags Schema
+// @Produce json
+// @Security ApiKeyAuth
+// @Param table path string true "表名"
+// @Success 200 {object} model.SuccessResponse "表结构"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 404 {object} model.ErrorResponse "表不存在"
+// @Router /api/v1/schema/{table} [get]
+func (h *SchemaHandler) GetTable(c *gin.Context) {
+	table := c.Param("table")
+
+	// 不在允许列表中的表与不存在的表返回相同的响应
+	if !h.isListed(table) {
+		c.JSON(http.StatusNotFound, model.NewErrorResponse(model.SQLErrorParams, "Table not found", table))
+		return
+	}
+
+	if !hasPermission(c, schemaPermission(table)) {
+		c.JSON(http.StatusForbidden, model.NewErrorResponse(model.SQLErrorPermission, "Insufficient permissions for table schema"))
+		return
+	}
+
+	schema, err := h.schemaService.GetTable(c.Request.Context(), table)
+	if err != nil {
+		if errors.Is(err, sql.ErrTableNotFound) || errors.Is(err, sql.ErrTableNotAllowed) {
+			c.JSON(http.StatusNotFound, model.NewErrorResponse(model.SQLErrorParams, "Table not found", table))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(model.SQLErrorConnection, "Failed to read table schema", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(schema, "Table schema retrieved successfully"))
+}
+
+// Refresh 刷新表结构缓存
+// @Summary 刷新表结构缓存
+// @Description 清空缓存并重新读取所有允许访问的表结构，需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "刷新结果"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/schema/refresh [post]
+func (h *SchemaHandler) Refresh(c *gin.Context) {
+	if !hasPermission(c, "admin") {
+		c.JSON(http.StatusForbidden, model.NewErrorResponse(model.SQLErrorPermission, "Admin permission required"))
+		return
+	}
+
+	result, err := h.schemaService.Refresh(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(model.SQLErrorConnection, "Failed to refresh table schemas", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(result, "Table schemas refreshed successfully"))
+}
+
+// isListed 检查表是否在允许列表中（不区分大小写）
+func (h *SchemaHandler) isListed(table string) bool {
+	for _, allowed := range h.schemaService.ListTables() {
+		if strings.EqualFold(allowed, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaPermission 表结构查看权限：schema.<table>
+func schemaPermission(table string) string {
+	return "schema." + strings.ToLower(table)
+}
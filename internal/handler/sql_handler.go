@@ -7,8 +7,10 @@ import (
 
 	"sql2api/internal/model"
 	"sql2api/internal/service"
+	"sql2api/internal/tracing"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SQLHandler SQL API 处理器
@@ -26,18 +28,26 @@ func NewSQLHandler(sqlService service.SQLService) *SQLHandler {
 // HandleSQL 通用 SQL 查询端点
 // @Summary 执行 SQL 查询
 // @Description 支持原生 SQL 和结构化查询，包含分页和排序功能
+// @Description 结构化更新、删除可通过 expected_version 字段或 If-Match 请求头指定期望的版本列取值，没有行匹配时分别返回 409 和 412
+// @Description 配置了软删除的表：delete 只设置删除标记，restore 清除删除标记，select 和 update 默认排除已删除的行，include_deleted 需要 sql.include_deleted 权限
 // @Tags SQL
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Param If-Match header string false "结构化更新、删除期望的记录版本（ETag）"
 // @Param request body model.SQLRequest true "SQL 查询请求"
 // @Success 200 {object} model.SQLResponse "查询成功"
 // @Failure 400 {object} model.SQLResponse "请求格式错误"
 // @Failure 401 {object} model.SQLResponse "未认证"
 // @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 409 {object} model.SQLResponse "版本冲突（expected_version 不匹配）"
+// @Failure 412 {object} model.SQLResponse "前置条件失败（If-Match 不匹配）"
 // @Failure 500 {object} model.SQLResponse "服务器内部错误"
 // @Router /api/v1/sql [post]
 func (h *SQLHandler) HandleSQL(c *gin.Context) {
+	span := h.startSpan(c, "HandleSQL")
+	defer span.End()
+
 	var req model.SQLRequest
 
 	// 绑定请求数据
@@ -47,6 +57,11 @@ func (h *SQLHandler) HandleSQL(c *gin.Context) {
 		return
 	}
 
+	// If-Match 请求头用于结构化更新、删除的乐观并发检查
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && req.Query != nil {
+		req.Query.IfMatch = ifMatch
+	}
+
 	// 检查权限
 	if !h.checkSQLPermission(c, &req) {
 		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Insufficient permissions")
@@ -67,8 +82,8 @@ func (h *SQLHandler) HandleSQL(c *gin.Context) {
 	}
 
 	if err != nil {
-		response = model.NewSQLErrorResponse(model.SQLErrorSyntax, "SQL execution failed", err.Error())
-		c.JSON(http.StatusInternalServerError, *response)
+		errorResponse := model.NewSQLErrorResponse(model.SQLErrorSyntax, "SQL execution failed", err.Error())
+		c.JSON(http.StatusInternalServerError, errorResponse)
 		return
 	}
 
@@ -96,6 +111,9 @@ func (h *SQLHandler) HandleSQL(c *gin.Context) {
 // @Failure 500 {object} model.BatchSQLResponse "服务器内部错误"
 // @Router /api/v1/sql/batch [post]
 func (h *SQLHandler) HandleBatchSQL(c *gin.Context) {
+	span := h.startSpan(c, "HandleBatchSQL")
+	defer span.End()
+
 	var req model.BatchSQLRequest
 
 	// 绑定请求数据
@@ -126,8 +144,8 @@ func (h *SQLHandler) HandleBatchSQL(c *gin.Context) {
 	// 执行批量操作
 	response, err := h.sqlService.ExecuteBatch(c.Request.Context(), &req)
 	if err != nil {
-		response = model.NewBatchSQLErrorResponse(model.SQLErrorTransaction, "Batch execution failed", err.Error())
-		c.JSON(http.StatusInternalServerError, *response)
+		errorResponse := model.NewBatchSQLErrorResponse(model.SQLErrorTransaction, "Batch execution failed", err.Error())
+		c.JSON(http.StatusInternalServerError, errorResponse)
 		return
 	}
 
@@ -143,6 +161,7 @@ func (h *SQLHandler) HandleBatchSQL(c *gin.Context) {
 // HandleInsertSQL 便捷插入端点
 // @Summary 执行便捷插入操作
 // @Description 提供简化的插入操作，支持冲突处理
+// @Description 写入前按表结构校验并转换数据，所有字段错误在 4002 响应的 error.fields 中一并返回
 // @Tags SQL
 // @Accept json
 // @Produce json
@@ -155,6 +174,9 @@ func (h *SQLHandler) HandleBatchSQL(c *gin.Context) {
 // @Failure 500 {object} model.SQLResponse "服务器内部错误"
 // @Router /api/v1/sql/insert [post]
 func (h *SQLHandler) HandleInsertSQL(c *gin.Context) {
+	span := h.startSpan(c, "HandleInsertSQL")
+	defer span.End()
+
 	var req model.InsertRequest
 
 	// 绑定请求数据
@@ -174,8 +196,8 @@ func (h *SQLHandler) HandleInsertSQL(c *gin.Context) {
 	// 执行插入操作
 	response, err := h.sqlService.ExecuteInsert(c.Request.Context(), &req)
 	if err != nil {
-		response = model.NewSQLErrorResponse(model.SQLErrorSyntax, "Insert execution failed", err.Error())
-		c.JSON(http.StatusInternalServerError, *response)
+		errorResponse := model.NewSQLErrorResponse(model.SQLErrorSyntax, "Insert execution failed", err.Error())
+		c.JSON(http.StatusInternalServerError, errorResponse)
 		return
 	}
 
@@ -191,6 +213,8 @@ func (h *SQLHandler) HandleInsertSQL(c *gin.Context) {
 // HandleBatchInsert 批量插入端点
 // @Summary 执行批量插入操作
 // @Description 提供批量插入功能，支持冲突处理
+// @Description 字段取所有记录的并集，missing_fields 指定缺少的字段使用列默认值（default）或 NULL（null）
+// @Description 语句按数据库的绑定参数上限自动拆分，chunks 返回各语句的结果；transactional 为 true 时所有语句在同一事务中执行
 // @Tags SQL
 // @Accept json
 // @Produce json
@@ -203,6 +227,9 @@ func (h *SQLHandler) HandleInsertSQL(c *gin.Context) {
 // @Failure 500 {object} model.SQLResponse "服务器内部错误"
 // @Router /api/v1/sql/batch-insert [post]
 func (h *SQLHandler) HandleBatchInsert(c *gin.Context) {
+	span := h.startSpan(c, "HandleBatchInsert")
+	defer span.End()
+
 	var req model.BatchInsertRequest
 
 	// 绑定请求数据
@@ -222,8 +249,8 @@ func (h *SQLHandler) HandleBatchInsert(c *gin.Context) {
 	// 执行批量插入操作
 	response, err := h.sqlService.ExecuteBatchInsert(c.Request.Context(), &req)
 	if err != nil {
-		response = model.NewSQLErrorResponse(model.SQLErrorSyntax, "Batch insert execution failed", err.Error())
-		c.JSON(http.StatusInternalServerError, *response)
+		errorResponse := model.NewSQLErrorResponse(model.SQLErrorSyntax, "Batch insert execution failed", err.Error())
+		c.JSON(http.StatusInternalServerError, errorResponse)
 		return
 	}
 
@@ -236,8 +263,66 @@ func (h *SQLHandler) HandleBatchInsert(c *gin.Context) {
 	c.JSON(statusCode, response)
 }
 
+// HandleCall 存储过程/函数调用端点
+// @Summary 调用存储过程或函数
+// @Description 调用配置中允许的存储过程或函数，支持 IN、OUT、IN OUT 参数、游标和结果集返回
+// @Tags SQL
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.CallRequest true "调用请求"
+// @Success 200 {object} model.CallResponse "调用成功"
+// @Failure 400 {object} model.CallResponse "请求格式错误"
+// @Failure 401 {object} model.CallResponse "未认证"
+// @Failure 403 {object} model.CallResponse "权限不足"
+// @Failure 500 {object} model.CallResponse "服务器内部错误"
+// @Router /api/v1/sql/call [post]
+func (h *SQLHandler) HandleCall(c *gin.Context) {
+	span := h.startSpan(c, "HandleCall")
+	defer span.End()
+
+	var req model.CallRequest
+
+	// 绑定请求数据
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := model.NewCallErrorResponse("", model.SQLErrorParams, "Invalid request format", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// 每个例程需要单独的权限
+	if !h.checkCallPermission(c, req.Routine) {
+		response := model.NewCallErrorResponse(req.Routine, model.SQLErrorPermission, "Insufficient permissions for routine call")
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	// 执行调用
+	response, err := h.sqlService.CallRoutine(c.Request.Context(), &req)
+	if err != nil {
+		response := model.NewCallErrorResponse(req.Routine, model.SQLErrorSyntax, "Routine call failed", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// 根据响应状态设置 HTTP 状态码
+	statusCode := http.StatusOK
+	if !response.Success {
+		statusCode = h.getHTTPStatusFromSQLError(response.Error)
+	}
+
+	c.JSON(statusCode, response)
+}
+
 // ===== 辅助方法 =====
 
+// startSpan 开始处理器 span，并放入请求上下文，使服务层和引擎的 span 成为其子 span
+func (h *SQLHandler) startSpan(c *gin.Context, name string) trace.Span {
+	ctx, span := tracing.Start(c.Request.Context(), "SQLHandler."+name)
+	c.Request = c.Request.WithContext(ctx)
+	return span
+}
+
 // isQueryOperation 判断是否为查询操作
 func (h *SQLHandler) isQueryOperation(req *model.SQLRequest) bool {
 	if req.SQL != "" {
@@ -264,7 +349,15 @@ func (h *SQLHandler) checkSQLPermission(c *gin.Context, req *model.SQLRequest) b
 
 	// 检查对应的权限
 	permission := fmt.Sprintf("sql.%s", action)
-	return h.hasPermission(c, permission)
+	if !h.hasPermission(c, permission) {
+		return false
+	}
+
+	// 包含软删除表中已删除的行需要额外权限
+	if req.Query != nil && req.Query.IncludeDeleted {
+		return h.hasPermission(c, "sql.include_deleted")
+	}
+	return true
 }
 
 // checkBatchPermission 检查批量操作权限
@@ -285,97 +378,22 @@ func (h *SQLHandler) checkInsertPermission(c *gin.Context, table string) bool {
 	return true
 }
 
+// checkCallPermission 检查例程调用权限（sql.call.<routine>）
+func (h *SQLHandler) checkCallPermission(c *gin.Context, routine string) bool {
+	return h.hasPermission(c, "sql.call."+routine)
+}
+
 // getSQLAction 获取 SQL 操作类型
 func (h *SQLHandler) getSQLAction(req *model.SQLRequest) string {
-	if req.SQL != "" {
-		// 从原生 SQL 中提取操作类型
-		sql := strings.TrimSpace(strings.ToLower(req.SQL))
-		words := strings.Fields(sql)
-		if len(words) > 0 {
-			switch words[0] {
-			case "select", "with":
-				return "query"
-			case "insert":
-				return "insert"
-			case "update":
-				return "update"
-			case "delete":
-				return "delete"
-			}
-		}
-	}
-
-	if req.Query != nil {
-		// 从结构化查询中获取操作类型
-		action := strings.ToLower(req.Query.Action)
-		if action == "select" {
-			return "query"
-		}
-		return action
-	}
-
-	return ""
+	return service.SQLAction(req)
 }
 
 // hasPermission 检查是否有指定权限
 func (h *SQLHandler) hasPermission(c *gin.Context, permission string) bool {
-	// 从上下文中获取 API Key 管理器
-	apiKeyManager, exists := c.Get("api_key_manager")
-	if !exists {
-		return false
-	}
-
-	// 获取 API Key
-	apiKey := h.getAPIKey(c)
-	if apiKey == "" {
-		return false
-	}
-
-	// 检查权限
-	if manager, ok := apiKeyManager.(interface{ HasPermission(string, string) bool }); ok {
-		return manager.HasPermission(apiKey, permission)
-	}
-
-	return false
-}
-
-// getAPIKey 从请求中获取 API Key
-func (h *SQLHandler) getAPIKey(c *gin.Context) string {
-	// 从 Header 中获取
-	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
-		return apiKey
-	}
-
-	// 从查询参数中获取
-	if apiKey := c.Query("api_key"); apiKey != "" {
-		return apiKey
-	}
-
-	return ""
+	return hasPermission(c, permission)
 }
 
 // getHTTPStatusFromSQLError 根据 SQL 错误码获取 HTTP 状态码
 func (h *SQLHandler) getHTTPStatusFromSQLError(sqlError *model.SQLError) int {
-	if sqlError == nil {
-		return http.StatusInternalServerError
-	}
-
-	switch sqlError.Code {
-	case model.SQLErrorParams:
-		return http.StatusBadRequest
-	case model.SQLErrorPermission:
-		return http.StatusForbidden
-	case model.SQLErrorConnection:
-		return http.StatusServiceUnavailable
-	case model.SQLErrorTimeout:
-		return http.StatusRequestTimeout
-	case model.SQLErrorSyntax:
-		return http.StatusBadRequest
-	case model.SQLErrorTransaction:
-		return http.StatusInternalServerError
-	case model.SQLErrorResultSize:
-		return http.StatusRequestEntityTooLarge
-	default:
-		return http.StatusInternalServerError
-	}
+	return httpStatusFromSQLError(sqlError)
 }
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementHandler 语句统计处理器（需要 admin 权限）
+type StatementHandler struct {
+	statementService service.StatementService
+}
+
+// NewStatementHandler 创建语句统计处理器
+func NewStatementHandler(statementService service.StatementService) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+	}
+}
+
+// List 查询语句统计
+// @Summary 查询语句统计
+// @Description 返回按语句指纹汇总的执行次数、耗时（毫秒）、行数、失败次数和执行过的 API Key。
+// @Description SQL 中的字面量替换为 ?，IN 列表折叠为 in (...)，只有字面量不同的语句合并统计。需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sort query string false "排序字段，降序（total_time、mean_time、max_time、p95_time、calls、errors、rows，默认 total_time）"
+// @Param limit query int false "返回条数（默认 100，最大 1000）"
+// @Param api_key query string false "只返回该 API Key 执行过的语句"
+// @Success 200 {object} model.SuccessResponse{data=model.StatementStatsResponse} "语句统计"
+// @Failure 400 {object} model.SQLResponse "参数错误"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/admin/statements [get]
+func (h *StatementHandler) List(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	var req model.StatementStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid request format", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(h.statementService.List(&req), "Statement statistics retrieved successfully"))
+}
+
+// Reset 清空语句统计
+// @Summary 清空语句统计
+// @Description 清空语句统计和慢查询日志，并从当前时间重新开始统计。需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "清空成功"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/admin/statements [delete]
+func (h *StatementHandler) Reset(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	h.statementService.Reset()
+	c.JSON(http.StatusOK, model.NewSuccessResponse(nil, "Statement statistics reset successfully"))
+}
+
+// SlowQueries 查询慢查询日志
+// @Summary 查询慢查询日志
+// @Description 返回保留时间内最慢的查询样本，最慢的在前。SQL 中的字面量替换为 ?，参数值替换为 [REDACTED]。需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse{data=[]model.SlowQuerySample} "慢查询样本"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/admin/statements/slow [get]
+func (h *StatementHandler) SlowQueries(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(h.statementService.SlowQueries(), "Slow queries retrieved successfully"))
+}
+
+// checkAdminPermission 检查管理权限，无权限时直接写入 403 响应
+func (h *StatementHandler) checkAdminPermission(c *gin.Context) bool {
+	if hasPermission(c, "admin") {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, model.NewErrorResponse(model.SQLErrorPermission, "Admin permission required"))
+	return false
+}
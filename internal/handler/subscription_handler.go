@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+	"sql2api/internal/sql"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// wsWriteTimeout WebSocket 单条消息的写超时
+const wsWriteTimeout = 10 * time.Second
+
+// SubscriptionHandler 表变更订阅处理器（SSE 和 WebSocket）
+type SubscriptionHandler struct {
+	subscriptionService service.SubscriptionService
+	upgrader            websocket.Upgrader
+}
+
+// NewSubscriptionHandler 创建表变更订阅处理器
+func NewSubscriptionHandler(subscriptionService service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+		upgrader: websocket.Upgrader{
+			// 认证使用 API Key 而不是 Cookie，与 CORS 配置一致允许任意来源
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// subscriptionMessage WebSocket 消息
+type subscriptionMessage struct {
+	Type  string           `json:"type"` // subscribed, change, error
+	Table string           `json:"table,omitempty"`
+	Event *sql.ChangeEvent `json:"event,omitempty"`
+	Error string           `json:"error,omitempty"`
+}
+
+// Stream 通过 Server-Sent Events 订阅表变更
+// @Summary 订阅表变更（SSE）
+// @Description 订阅允许访问的表的 insert、update、delete 事件，需要 sql.query 权限
+// @Description 事件名为操作类型，数据为变更事件；订阅成功时发送 subscribed 事件，订阅被服务器结束时发送 error 事件
+// @Tags SQL
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param table path string true "表名"
+// @Param where query string false "JSON 格式的过滤条件，与结构化查询的 where 相同，如 {\"status\":\"active\"}"
+// @Success 200 {object} sql.ChangeEvent "变更事件流"
+// @Failure 400 {object} model.SQLResponse "过滤条件无效"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "表不存在"
+// @Router /api/v1/subscriptions/{table} [get]
+func (h *SubscriptionHandler) Stream(c *gin.Context) {
+	subscription, ok := h.subscribe(c)
+	if !ok {
+		return
+	}
+	defer subscription.Close()
+
+	// 长连接不受服务器写超时限制
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	send := func(event sse.Event) {
+		c.Render(-1, event)
+		c.Writer.Flush()
+	}
+	send(sse.Event{Event: "subscribed", Data: gin.H{"table": c.Param("table")}})
+
+	heartbeat := time.NewTicker(h.subscriptionService.HeartbeatInterval())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-subscription.Events():
+			send(sse.Event{
+				Id:    strconv.FormatUint(event.ID, 10),
+				Event: event.Operation,
+				Data:  event,
+			})
+		case <-heartbeat.C:
+			// 注释行用于保持连接
+			c.Writer.WriteString(": heartbeat\n\n")
+			c.Writer.Flush()
+		case <-subscription.Done():
+			if err := subscription.Err(); err != nil {
+				send(sse.Event{Event: "error", Data: gin.H{"error": err.Error()}})
+			}
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// WebSocket 通过 WebSocket 订阅表变更
+// @Summary 订阅表变更（WebSocket）
+// @Description 订阅允许访问的表的 insert、update、delete 事件，需要 sql.query 权限
+// @Description 服务器发送 JSON 消息：{"type":"subscribed"}、{"type":"change","event":{...}}、{"type":"error","error":"..."}
+// @Tags SQL
+// @Security ApiKeyAuth
+// @Param table path string true "表名"
+// @Param where query string false "JSON 格式的过滤条件，与结构化查询的 where 相同"
+// @Success 101 "切换到 WebSocket 协议"
+// @Failure 400 {object} model.SQLResponse "过滤条件无效"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "表不存在"
+// @Router /api/v1/subscriptions/{table}/ws [get]
+func (h *SubscriptionHandler) WebSocket(c *gin.Context) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "WebSocket upgrade required")
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	subscription, ok := h.subscribe(c)
+	if !ok {
+		return
+	}
+	defer subscription.Close()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 已返回错误响应
+		return
+	}
+	defer conn.Close()
+
+	heartbeatInterval := h.subscriptionService.HeartbeatInterval()
+
+	// 读取客户端消息以处理 pong 和关闭帧，两个心跳周期内没有响应时断开
+	closed := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeatInterval))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(message subscriptionMessage) error {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		return conn.WriteJSON(message)
+	}
+	if err := send(subscriptionMessage{Type: "subscribed", Table: c.Param("table")}); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-subscription.Events():
+			if err := send(subscriptionMessage{Type: "change", Event: event}); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case <-subscription.Done():
+			code, reason := websocket.CloseNormalClosure, ""
+			if err := subscription.Err(); err != nil {
+				send(subscriptionMessage{Type: "error", Error: err.Error()})
+				code, reason = websocket.CloseGoingAway, err.Error()
+				if errors.Is(err, sql.ErrSubscriptionOverflow) {
+					code = websocket.ClosePolicyViolation
+				}
+			}
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteTimeout))
+			return
+		case <-closed:
+			return
+		}
+	}
+}
+
+// subscribe 检查权限、解析过滤条件并创建订阅，失败时写入错误响应
+func (h *SubscriptionHandler) subscribe(c *gin.Context) (*sql.Subscription, bool) {
+	if !hasPermission(c, "sql.query") {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Insufficient permissions")
+		c.JSON(http.StatusForbidden, response)
+		return nil, false
+	}
+
+	var where map[string]interface{}
+	if raw := c.Query("where"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &where); err != nil {
+			response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid filter", err.Error())
+			c.JSON(http.StatusBadRequest, response)
+			return nil, false
+		}
+	}
+
+	table := c.Param("table")
+	subscription, err := h.subscriptionService.Subscribe(c.Request.Context(), table, where)
+	if err != nil {
+		switch {
+		// 不在允许列表中的表与不存在的表返回相同的响应
+		case errors.Is(err, sql.ErrTableNotAllowed), errors.Is(err, sql.ErrTableNotFound):
+			response := model.NewSQLErrorResponse(model.SQLErrorParams, "Table not found", table)
+			c.JSON(http.StatusNotFound, response)
+		case errors.Is(err, service.ErrInvalidSubscriptionFilter):
+			response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid filter", err.Error())
+			c.JSON(http.StatusBadRequest, response)
+		case errors.Is(err, service.ErrSubscriptionsClosed):
+			response := model.NewSQLErrorResponse(model.SQLErrorConnection, "Server is shutting down")
+			c.JSON(http.StatusServiceUnavailable, response)
+		default:
+			response := model.NewSQLErrorResponse(model.SQLErrorParams, err.Error())
+			c.JSON(http.StatusInternalServerError, response)
+		}
+		return nil, false
+	}
+
+	return subscription, true
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+	"sql2api/internal/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TableHandler 表查询处理器（PostgREST 风格 URL 查询）
+type TableHandler struct {
+	tableService service.TableService
+}
+
+// NewTableHandler 创建表查询处理器
+func NewTableHandler(tableService service.TableService) *TableHandler {
+	return &TableHandler{
+		tableService: tableService,
+	}
+}
+
+// Query 通过 URL 参数查询表
+// @Summary 通过 URL 查询表
+// @Description 使用 PostgREST 风格的 URL 参数查询允许访问的表，返回记录数组，需要 sql.query 权限
+// @Description 过滤：<column>=<op>.<value>，op 为 eq、neq、gt、gte、lt、lte、like、ilike、in、is，可加 not. 前缀
+// @Tags SQL
+// @Produce json
+// @Security ApiKeyAuth
+// @Param table path string true "表名"
+// @Param select query string false "返回的列，如 id,name"
+// @Param order query string false "排序，如 created_at.desc,id"
+// @Param limit query int false "返回行数"
+// @Param offset query int false "偏移量"
+// @Param Range header string false "行范围，如 0-24"
+// @Param Prefer header string false "count=exact 时在 Content-Range 中返回总行数"
+// @Success 200 {array} map[string]interface{} "查询结果"
+// @Success 206 {array} map[string]interface{} "部分结果"
+// @Failure 400 {object} model.SQLResponse "请求参数错误"
+// @Failure 403 {object} model.SQLResponse "权限不足"
+// @Failure 404 {object} model.SQLResponse "表不存在"
+// @Failure 416 {object} model.SQLResponse "范围超出结果"
+// @Router /api/v1/tables/{table} [get]
+func (h *TableHandler) Query(c *gin.Context) {
+	if !hasPermission(c, "sql.query") {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Insufficient permissions")
+		c.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	values := c.Request.URL.Query()
+	values.Del("api_key")
+
+	query, err := sql.ParseURLQuery(c.Param("table"), values, c.Request.Header)
+	if err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid query", err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response, err := h.tableService.Query(c.Request.Context(), query)
+	if err != nil {
+		// 不在允许列表中的表与不存在的表返回相同的响应
+		if errors.Is(err, sql.ErrTableNotAllowed) {
+			errorResponse := model.NewSQLErrorResponse(model.SQLErrorParams, "Table not found", query.Query.Table)
+			c.JSON(http.StatusNotFound, errorResponse)
+			return
+		}
+		errorResponse := model.NewSQLErrorResponse(model.SQLErrorParams, err.Error())
+		c.JSON(http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	if !response.Success {
+		c.JSON(httpStatusFromSQLError(response.Error), response)
+		return
+	}
+
+	rows := response.Data
+	if rows == nil {
+		rows = []map[string]interface{}{}
+	}
+
+	// Content-Range: <first>-<last>/<total>，未请求计数时总数为 *
+	total := "*"
+	if query.Count {
+		total = fmt.Sprint(response.Total)
+	}
+	contentRange := "*/" + total
+	if len(rows) > 0 {
+		contentRange = fmt.Sprintf("%d-%d/%s", query.Offset, query.Offset+len(rows)-1, total)
+	}
+	c.Header("Content-Range", contentRange)
+
+	status := http.StatusOK
+	if query.Count && query.Ranged {
+		if query.Offset > 0 && int64(query.Offset) >= response.Total {
+			errorResponse := model.NewSQLErrorResponse(model.SQLErrorParams, "Requested range not satisfiable", contentRange)
+			c.JSON(http.StatusRequestedRangeNotSatisfiable, errorResponse)
+			return
+		}
+		if int64(len(rows)) < response.Total {
+			status = http.StatusPartialContent
+		}
+	}
+
+	c.JSON(status, rows)
+}
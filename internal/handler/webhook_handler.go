@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler webhook 管理 API 处理器（需要 admin 权限）
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler 创建 webhook 管理 API 处理器
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ListEndpoints 列出 webhook 端点
+// @Summary 列出 webhook 端点
+// @Description 返回配置文件中定义和通过 API 注册的端点，密钥不会返回，需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "端点列表"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/admin/webhooks/endpoints [get]
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	endpoints := h.webhookService.ListEndpoints()
+	result := make([]gin.H, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result = append(result, webhookEndpointView(&endpoint))
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(result, "Webhook endpoints retrieved successfully"))
+}
+
+// SaveEndpoint 注册或替换 webhook 端点
+// @Summary 保存 webhook 端点
+// @Description 注册或替换端点，保存在本地存储中，重启后仍然有效；配置文件中定义的端点不能修改，需要 admin 权限
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "端点名称"
+// @Param request body config.WebhookEndpointConfig true "端点定义"
+// @Success 200 {object} model.SuccessResponse "保存成功"
+// @Failure 400 {object} model.ErrorResponse "定义无效"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 409 {object} model.ErrorResponse "端点由配置文件定义"
+// @Router /api/v1/admin/webhooks/endpoints/{name} [put]
+func (h *WebhookHandler) SaveEndpoint(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	var endpoint config.WebhookEndpointConfig
+	if err := c.ShouldBindJSON(&endpoint); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid request format", err.Error()))
+		return
+	}
+
+	// 路径中的名称优先
+	endpoint.Name = c.Param("name")
+
+	if err := h.webhookService.SaveEndpoint(&endpoint); err != nil {
+		if errors.Is(err, service.ErrWebhookEndpointReadOnly) {
+			c.JSON(http.StatusConflict, model.NewErrorResponse(model.SQLErrorParams, "Webhook endpoint is read-only", err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid webhook endpoint", err.Error()))
+		return
+	}
+
+	saved := service.WebhookEndpoint{WebhookEndpointConfig: endpoint, Source: service.WebhookSourceAPI}
+	c.JSON(http.StatusOK, model.NewSuccessResponse(webhookEndpointView(&saved), "Webhook endpoint stored successfully"))
+}
+
+// DeleteEndpoint 删除 webhook 端点
+// @Summary 删除 webhook 端点
+// @Description 删除通过 API 注册的端点，尚未投递的记录会移入死信列表，需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param name path string true "端点名称"
+// @Success 200 {object} model.SuccessResponse "删除成功"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 404 {object} model.ErrorResponse "端点不存在"
+// @Failure 409 {object} model.ErrorResponse "端点由配置文件定义"
+// @Router /api/v1/admin/webhooks/endpoints/{name} [delete]
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(c.Param("name")); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrWebhookEndpointNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrWebhookEndpointReadOnly):
+			status = http.StatusConflict
+		}
+		c.JSON(status, model.NewErrorResponse(model.SQLErrorParams, "Failed to delete webhook endpoint", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(nil, "Webhook endpoint deleted successfully"))
+}
+
+// ListDeadLetters 列出死信记录
+// @Summary 列出 webhook 死信
+// @Description 返回超过最大投递次数的投递记录（最新的在前，最多 1000 条），需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.SuccessResponse "死信列表"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Router /api/v1/admin/webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeadLetters()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.NewErrorResponse(model.SQLErrorConnection, "Failed to read dead letters", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(deliveries, "Dead letters retrieved successfully"))
+}
+
+// ReplayDeadLetter 重新投递死信记录
+// @Summary 重新投递 webhook 死信
+// @Description 将死信记录重新加入投递队列，投递次数清零，需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "投递记录 ID"
+// @Success 200 {object} model.SuccessResponse "已重新加入队列"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 404 {object} model.ErrorResponse "死信记录不存在"
+// @Router /api/v1/admin/webhooks/dead-letters/{id}/replay [post]
+func (h *WebhookHandler) ReplayDeadLetter(c *gin.Context) {
+	h.handleDeadLetter(c, h.webhookService.ReplayDeadLetter, "Dead letter queued for redelivery")
+}
+
+// DeleteDeadLetter 删除死信记录
+// @Summary 删除 webhook 死信
+// @Description 删除死信记录，需要 admin 权限
+// @Tags Admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "投递记录 ID"
+// @Success 200 {object} model.SuccessResponse "删除成功"
+// @Failure 403 {object} model.ErrorResponse "权限不足"
+// @Failure 404 {object} model.ErrorResponse "死信记录不存在"
+// @Router /api/v1/admin/webhooks/dead-letters/{id} [delete]
+func (h *WebhookHandler) DeleteDeadLetter(c *gin.Context) {
+	h.handleDeadLetter(c, h.webhookService.DeleteDeadLetter, "Dead letter deleted successfully")
+}
+
+// handleDeadLetter 解析死信记录 ID 并执行操作
+func (h *WebhookHandler) handleDeadLetter(c *gin.Context, action func(id uint64) error, message string) {
+	if !h.checkAdminPermission(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.NewErrorResponse(model.SQLErrorParams, "Invalid dead letter id", c.Param("id")))
+		return
+	}
+
+	if err := action(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, model.NewErrorResponse(model.SQLErrorParams, "Dead letter operation failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, model.NewSuccessResponse(gin.H{"id": id}, message))
+}
+
+// webhookEndpointView 端点的对外表示，不包含密钥
+func webhookEndpointView(endpoint *service.WebhookEndpoint) gin.H {
+	return gin.H{
+		"name":         endpoint.Name,
+		"url":          endpoint.URL,
+		"tables":       endpoint.Tables,
+		"actions":      endpoint.Actions,
+		"include_rows": endpoint.IncludeRows,
+		"signed":       endpoint.Secret != "",
+		"source":       endpoint.Source,
+	}
+}
+
+// checkAdminPermission 检查管理权限，无权限时直接写入 403 响应
+func (h *WebhookHandler) checkAdminPermission(c *gin.Context) bool {
+	if hasPermission(c, "admin") {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, model.NewErrorResponse(model.SQLErrorPermission, "Admin permission required"))
+	return false
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sql2api",
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "sql2api",
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "sql2api",
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+)
+
+// metricsMethods 指标中保留的 HTTP 方法，其他方法记为 OTHER
+var metricsMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// MetricsMiddleware 记录 HTTP 请求指标的中间件
+// 路由使用注册时的模板（如 /api/v1/resources/:name/:id），未匹配的请求记为 unmatched，以控制标签基数
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		if !metricsMethods[method] {
+			method = "OTHER"
+		}
+
+		httpRequests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
@@ -9,6 +11,7 @@ import (
 
 	"sql2api/internal/config"
 	"sql2api/internal/model"
+	"sql2api/internal/sql"
 
 	"github.com/gin-gonic/gin"
 )
@@ -148,20 +151,32 @@ func (m *APIKeyManager) ListActiveKeys() []map[string]interface{} {
 // ValidatePermissions 验证权限格式
 func ValidatePermissions(permissions []string) error {
 	validPermissions := map[string]bool{
-		"*":          true,
-		"all":        true,
-		"sql.query":  true,
-		"sql.insert": true,
-		"sql.update": true,
-		"sql.delete": true,
-		"sql.batch":  true,
-		"sql.*":      true,
-		"admin":      true,
-		"read":       true,
-		"write":      true,
+		"*":                   true,
+		"all":                 true,
+		"sql.query":           true,
+		"sql.insert":          true,
+		"sql.update":          true,
+		"sql.delete":          true,
+		"sql.batch":           true,
+		"sql.restore":         true,
+		"sql.include_deleted": true,
+		"sql.call.*":          true,
+		"sql.*":               true,
+		"query.*":             true,
+		"resource.*":          true,
+		"schema.*":            true,
+		"admin":               true,
+		"audit":               true,
+		"read":                true,
+		"write":               true,
 	}
 
 	for _, perm := range permissions {
+		// 例程调用权限：sql.call.<routine>；命名查询权限：query.<name>；
+		// 资源权限：resource.<name>.<operation>；表结构权限：schema.<table>
+		if hasScopedPrefix(perm, "sql.call.", "query.", "resource.", "schema.") {
+			continue
+		}
 		if !validPermissions[perm] {
 			return fmt.Errorf("invalid permission: %s", perm)
 		}
@@ -170,6 +185,16 @@ func ValidatePermissions(permissions []string) error {
 	return nil
 }
 
+// hasScopedPrefix 检查权限是否为带名称的作用域权限（如 query.<name>）
+func hasScopedPrefix(perm string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(perm, prefix) && len(perm) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // SimpleAuthMiddleware 简化认证中间件
 func SimpleAuthMiddleware(apiKeyManager *APIKeyManager, required bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -233,6 +258,11 @@ func tryAPIKeyAuth(c *gin.Context, apiKeyManager *APIKeyManager) bool {
 	c.Set("api_key_permissions", keyItem.Permissions)
 	c.Set("authenticated", true)
 
+	// 审计日志记录发起写操作的 API Key
+	if caller := sql.CallerFrom(c.Request.Context()); caller != nil {
+		caller.APIKeyName = keyItem.Name
+	}
+
 	return true
 }
 
@@ -290,6 +320,7 @@ func RequirePermission(apiKeyManager *APIKeyManager, permission string) gin.Hand
 			}
 
 			if !apiKeyManager.HasPermission(apiKey.(string), permission) {
+				c.Set("denied_permission", permission)
 				c.JSON(http.StatusForbidden, model.NewErrorResponse(
 					http.StatusForbidden,
 					"Insufficient permissions",
@@ -486,3 +517,100 @@ func GetClientIP(c *gin.Context) string {
 
 	return "unknown"
 }
+
+// CORSMiddleware 跨域资源共享中间件
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	allowAll := false
+	origins := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		origins[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin != "" && (allowAll || origins[origin]) {
+			if allowAll {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)
+				c.Header("Vary", "Origin")
+			}
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-API-Key, Range, Prefer, If-Match, X-Request-ID, traceparent, tracestate")
+			c.Header("Access-Control-Expose-Headers", "Content-Range, ETag, X-Request-ID, X-Trace-ID")
+			c.Header("Access-Control-Max-Age", "86400")
+		}
+
+		// 预检请求直接返回
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequestIDHeader 请求 ID 的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 客户端传入的请求 ID 的最大长度
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware 请求 ID 中间件，沿用客户端传入的 X-Request-ID 或生成新的 ID，
+// 并将请求 ID、客户端 IP 和请求路径作为调用方信息写入请求上下文，供审计日志使用
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := ResolveRequestID(c.GetHeader(RequestIDHeader))
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(sql.WithCaller(c.Request.Context(), &sql.Caller{
+			RequestID: requestID,
+			ClientIP:  GetClientIP(c),
+			Endpoint:  c.Request.Method + " " + c.Request.URL.Path,
+		}))
+		c.Next()
+	}
+}
+
+// ResolveRequestID 沿用客户端传入的合法请求 ID，否则生成随机的请求 ID
+func ResolveRequestID(requestID string) string {
+	if isValidRequestID(requestID) {
+		return requestID
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// isValidRequestID 检查客户端传入的请求 ID，只允许字母、数字和 -_.:
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateIPInfoEndpoint 创建 IP 信息查看端点（调试用）
+func CreateIPInfoEndpoint() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, model.NewSuccessResponse(map[string]interface{}{
+			"client_ip":       GetClientIP(c),
+			"remote_addr":     c.Request.RemoteAddr,
+			"x_forwarded_for": c.GetHeader("X-Forwarded-For"),
+			"x_real_ip":       c.GetHeader("X-Real-IP"),
+		}, "Client IP information"))
+	}
+}
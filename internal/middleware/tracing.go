@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"sql2api/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TraceIDHeader 响应中返回 trace ID 的请求头
+const TraceIDHeader = "X-Trace-ID"
+
+// TracingMiddleware 为每个请求创建服务端 span 的中间件
+// 请求头中的 W3C traceparent/tracestate 会被解析，使该 span 成为调用方链路的一部分
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		// 使用路由模板命名 span，以控制 span 名称的数量
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		ctx, span := tracing.Start(ctx, name,
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("url.path", c.Request.URL.Path),
+			attribute.String("client.address", GetClientIP(c)),
+		)
+		defer span.End()
+		if span.SpanContext().IsValid() {
+			c.Header(TraceIDHeader, span.SpanContext().TraceID().String())
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(
+			attribute.Int("http.response.status_code", status),
+			attribute.String("sql2api.request_id", c.GetString("request_id")),
+		)
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
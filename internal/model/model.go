@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 )
@@ -29,22 +30,52 @@ type APIError struct {
 
 // SQLError SQL 专用错误结构
 type SQLError struct {
-	Code     int    `json:"code"`     // 错误码：4001-4007
+	Code     int    `json:"code"`     // 错误码：4001-4009
 	Message  string `json:"message"`  // 错误消息
 	Details  string `json:"details,omitempty"` // 详细信息
 	SQLState string `json:"sql_state,omitempty"` // 数据库特定的错误状态
 	Query    string `json:"query,omitempty"` // 出错的查询（敏感信息已脱敏）
+	Fields   []FieldError `json:"fields,omitempty"` // 写入数据的字段校验错误
+}
+
+// FieldError 写入数据的字段校验错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段名，批量插入时为 data[i].field
+	Code    string `json:"code"`    // 错误类型
+	Message string `json:"message"` // 错误说明
+}
+
+// 字段校验错误类型
+const (
+	FieldErrorUnknownColumn = "unknown_column" // 表中不存在的列
+	FieldErrorRequired      = "required"       // 插入时缺少没有默认值的 NOT NULL 列
+	FieldErrorNotNull       = "not_null"       // NOT NULL 列的值为 null
+	FieldErrorInvalidType   = "invalid_type"   // 值的类型与列类型不符
+	FieldErrorInvalidFormat = "invalid_format" // 数值、日期等格式错误
+	FieldErrorMaxLength     = "max_length"     // 超过字符串长度限制
+	FieldErrorOutOfRange    = "out_of_range"   // 超出整数范围或数值精度
+	FieldErrorEnum          = "enum"           // 不在枚举或 CHECK 约束允许的取值中
+)
+
+// Error 实现 error 接口
+func (e *SQLError) Error() string {
+	if e.Details != "" {
+		return e.Message + ": " + e.Details
+	}
+	return e.Message
 }
 
 // SQL 错误码常量
 const (
-	SQLErrorSyntax      = 4001 // SQL 语法错误
-	SQLErrorParams      = 4002 // 参数错误或缺失
-	SQLErrorPermission  = 4003 // 权限不足
-	SQLErrorConnection  = 4004 // 数据库连接错误
-	SQLErrorTransaction = 4005 // 事务执行失败
-	SQLErrorTimeout     = 4006 // 查询超时
-	SQLErrorResultSize  = 4007 // 结果集过大
+	SQLErrorSyntax       = 4001 // SQL 语法错误
+	SQLErrorParams       = 4002 // 参数错误或缺失
+	SQLErrorPermission   = 4003 // 权限不足
+	SQLErrorConnection   = 4004 // 数据库连接错误
+	SQLErrorTransaction  = 4005 // 事务执行失败
+	SQLErrorTimeout      = 4006 // 查询超时
+	SQLErrorResultSize   = 4007 // 结果集过大
+	SQLErrorConflict     = 4008 // 版本冲突（expected_version 不匹配）
+	SQLErrorPrecondition = 4009 // 前置条件失败（If-Match 不匹配）
 )
 
 // SuccessResponse 成功响应类型别名（用于 Swagger 文档）
@@ -156,29 +187,37 @@ type DatabaseType string
 const (
 	DatabasePostgreSQL DatabaseType = "postgres"
 	DatabaseOracle     DatabaseType = "oracle"
+	DatabaseSQLite     DatabaseType = "sqlite"
+	DatabaseMySQL      DatabaseType = "mysql"
+	DatabaseSQLServer  DatabaseType = "sqlserver"
 )
 
 // SQLRequest 通用 SQL 请求结构
 type SQLRequest struct {
-	DatabaseType string                 `json:"database_type" binding:"required,oneof=postgres oracle" example:"postgres"`
+	DatabaseType string                 `json:"database_type" binding:"required,oneof=postgres oracle sqlite mysql sqlserver" example:"postgres"`
 	SQL          string                 `json:"sql,omitempty" example:"SELECT * FROM items WHERE active = $1"`
 	Query        *StructuredQuery       `json:"query,omitempty"`
 	Params       map[string]interface{} `json:"params,omitempty" example:"{\"active\": true}"`
 	Pagination   *PaginationConfig      `json:"pagination,omitempty"`
 	Sort         *SortConfig            `json:"sort,omitempty"`
+	SQLDialect   string                 `json:"sql_dialect,omitempty" binding:"omitempty,oneof=portable postgres oracle sqlite mysql sqlserver" example:"portable"` // 原生 SQL 的源方言，设置后转换为目标数据库的 SQL
+	Debug        bool                   `json:"debug,omitempty" example:"false"`                                                                                    // 在响应中返回实际执行的 SQL
 }
 
 // StructuredQuery 结构化查询（JSON 转 SQL）
 type StructuredQuery struct {
-	Table   string                 `json:"table" binding:"required" example:"items"`
-	Action  string                 `json:"action" binding:"required,oneof=select insert update delete" example:"select"`
-	Fields  []string               `json:"fields,omitempty" example:"[\"id\", \"name\", \"created_at\"]"`
-	Where   map[string]interface{} `json:"where,omitempty" example:"{\"active\": true, \"category\": \"electronics\"}"`
-	Data    map[string]interface{} `json:"data,omitempty" example:"{\"name\": \"New Item\", \"category\": \"electronics\"}"`
-	GroupBy []string               `json:"group_by,omitempty" example:"[\"category\"]"`
-	Having  map[string]interface{} `json:"having,omitempty"`
-	OrderBy []OrderByClause        `json:"order_by,omitempty"`
-	Limit   int                    `json:"limit,omitempty" example:"100"`
+	Table           string                 `json:"table" binding:"required" example:"items"`
+	Action          string                 `json:"action" binding:"required,oneof=select insert update delete restore" example:"select"`
+	Fields          []string               `json:"fields,omitempty" example:"[\"id\", \"name\", \"created_at\"]"`
+	Where           map[string]interface{} `json:"where,omitempty" example:"{\"active\": true, \"category\": \"electronics\"}"`
+	Data            map[string]interface{} `json:"data,omitempty" example:"{\"name\": \"New Item\", \"category\": \"electronics\"}"`
+	GroupBy         []string               `json:"group_by,omitempty" example:"[\"category\"]"`
+	Having          map[string]interface{} `json:"having,omitempty"`
+	OrderBy         []OrderByClause        `json:"order_by,omitempty"`
+	Limit           int                    `json:"limit,omitempty" example:"100"`
+	ExpectedVersion interface{}            `json:"expected_version,omitempty" example:"3"`     // 更新、删除时期望的版本列取值（表需配置版本列），不匹配时返回 409
+	IfMatch         string                 `json:"-"`                                          // 来自 If-Match 请求头的实体标签，不匹配时返回 412
+	IncludeDeleted  bool                   `json:"include_deleted,omitempty" example:"false"` // 软删除表的查询、更新包含已删除的行（需要 sql.include_deleted 权限）
 }
 
 // OrderByClause 排序子句
@@ -201,7 +240,7 @@ type SortConfig struct {
 
 // BatchSQLRequest 批量 SQL 请求结构
 type BatchSQLRequest struct {
-	DatabaseType   string       `json:"database_type" binding:"required,oneof=postgres oracle" example:"postgres"`
+	DatabaseType   string       `json:"database_type" binding:"required,oneof=postgres oracle sqlite mysql sqlserver" example:"postgres"`
 	Operations     []SQLRequest `json:"operations" binding:"required,min=1,max=100"`
 	Transactional  bool         `json:"transactional" example:"true"`
 	ContinueOnError bool        `json:"continue_on_error" example:"false"`
@@ -209,20 +248,68 @@ type BatchSQLRequest struct {
 
 // InsertRequest 便捷插入请求结构
 type InsertRequest struct {
-	DatabaseType string                 `json:"database_type" binding:"required,oneof=postgres oracle" example:"postgres"`
+	DatabaseType string                 `json:"database_type" binding:"required,oneof=postgres oracle sqlite mysql sqlserver" example:"postgres"`
 	Table        string                 `json:"table" binding:"required" example:"items"`
 	Data         map[string]interface{} `json:"data" binding:"required" example:"{\"name\": \"New Item\", \"category\": \"electronics\"}"`
 	OnConflict   string                 `json:"on_conflict,omitempty" binding:"omitempty,oneof=ignore update" example:"ignore"`
+	ConflictKeys []string               `json:"conflict_keys,omitempty" example:"[\"name\"]"` // 冲突判定列（SQL Server MERGE 必填）
 	ReturnFields []string               `json:"return_fields,omitempty" example:"[\"id\", \"created_at\"]"`
 }
 
 // BatchInsertRequest 批量插入请求结构
 type BatchInsertRequest struct {
-	DatabaseType string                   `json:"database_type" binding:"required,oneof=postgres oracle" example:"postgres"`
-	Table        string                   `json:"table" binding:"required" example:"items"`
-	Data         []map[string]interface{} `json:"data" binding:"required,min=1,max=1000"`
-	OnConflict   string                   `json:"on_conflict,omitempty" binding:"omitempty,oneof=ignore update" example:"ignore"`
-	ReturnFields []string                 `json:"return_fields,omitempty" example:"[\"id\", \"created_at\"]"`
+	DatabaseType  string                   `json:"database_type" binding:"required,oneof=postgres oracle sqlite mysql sqlserver" example:"postgres"`
+	Table         string                   `json:"table" binding:"required" example:"items"`
+	Data          []map[string]interface{} `json:"data" binding:"required,min=1,max=1000"`
+	OnConflict    string                   `json:"on_conflict,omitempty" binding:"omitempty,oneof=ignore update" example:"ignore"`
+	ConflictKeys  []string                 `json:"conflict_keys,omitempty" example:"[\"name\"]"` // 冲突判定列（SQL Server MERGE 必填）
+	ReturnFields  []string                 `json:"return_fields,omitempty" example:"[\"id\", \"created_at\"]"`
+	MissingFields string                   `json:"missing_fields,omitempty" binding:"omitempty,oneof=default null" example:"default"` // 记录中缺少的列：default 使用列默认值（默认），null 写入 NULL
+	Transactional bool                     `json:"transactional,omitempty" example:"true"`                                            // 所有分批语句在同一事务中执行
+}
+
+// 批量插入时记录中缺少的列的处理方式
+const (
+	MissingFieldsDefault = "default" // 按字段组合分组插入，缺少的列使用列默认值
+	MissingFieldsNull    = "null"    // 缺少的列写入 NULL
+)
+
+// BatchInsertChunkResult 批量插入拆分后单条语句的执行结果
+type BatchInsertChunkResult struct {
+	Index        int    `json:"index"`
+	Rows         int    `json:"rows"`
+	AffectedRows int64  `json:"affected_rows"`
+	LastInsertID int64  `json:"last_insert_id,omitempty"`
+	Success      bool   `json:"success"`
+	Error        string `json:"error,omitempty"`
+}
+
+// CallRequest 存储过程/函数调用请求结构
+type CallRequest struct {
+	DatabaseType string                 `json:"database_type" binding:"required,oneof=postgres oracle sqlite mysql sqlserver" example:"oracle"`
+	Routine      string                 `json:"routine" binding:"required" example:"get_orders"`
+	Params       map[string]interface{} `json:"params,omitempty" example:"{\"customer_id\": 42}"` // IN 和 IN OUT 参数值
+}
+
+// SavedQueryRequest 命名查询执行请求结构
+type SavedQueryRequest struct {
+	Params map[string]interface{} `json:"params,omitempty" example:"{\"category\": \"electronics\"}"`
+}
+
+// ResourceListRequest 资源列表请求（来自查询字符串）
+type ResourceListRequest struct {
+	Page           int               `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize       int               `form:"page_size" binding:"omitempty,min=1" example:"20"`
+	Sort           string            `form:"sort" example:"-created_at"` // 排序字段，前缀 - 表示降序
+	IncludeDeleted bool              `form:"include_deleted"`            // 软删除表包含已删除的行（需要 resource.<name>.include_deleted 权限）
+	Filters        map[string]string `form:"-"`                          // 字段等值过滤
+}
+
+// GraphQLRequest GraphQL 请求结构
+type GraphQLRequest struct {
+	Query         string                 `json:"query" form:"query" binding:"required" example:"{ items(limit: 10) { id name } }"`
+	OperationName string                 `json:"operationName,omitempty" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables,omitempty" form:"-"`
 }
 
 // ===== SQL 响应结构 =====
@@ -239,7 +326,32 @@ type SQLResponse struct {
 	Page         int                      `json:"page,omitempty"`
 	PageSize     int                      `json:"page_size,omitempty"`
 	Columns      []string                 `json:"columns,omitempty"`
+	LastInsertID int64                    `json:"last_insert_id,omitempty"` // 自增主键（MySQL、SQLite）
 	ExecutionTime float64                 `json:"execution_time,omitempty"` // 执行时间（毫秒）
+	Debug        *SQLDebugInfo            `json:"debug,omitempty"`
+	Chunks       []BatchInsertChunkResult `json:"chunks,omitempty"` // 批量插入各分批语句的执行结果
+	ETag         string                   `json:"-"`                // 单行读取时记录版本对应的实体标签，由处理器写入 ETag 头
+}
+
+// SQLDebugInfo SQL 调试信息
+type SQLDebugInfo struct {
+	SQLDialect    string `json:"sql_dialect,omitempty"` // 源方言
+	DatabaseType  string `json:"database_type"`         // 目标数据库类型
+	TranslatedSQL string `json:"translated_sql"`        // 实际执行的 SQL
+}
+
+// CallResponse 存储过程/函数调用响应结构
+type CallResponse struct {
+	Success       bool                     `json:"success"`
+	Message       string                   `json:"message,omitempty"`
+	Routine       string                   `json:"routine,omitempty"`
+	ReturnValue   interface{}              `json:"return_value,omitempty"` // 函数的标量返回值
+	OutParams     map[string]interface{}   `json:"out_params,omitempty"`   // OUT 和 IN OUT 参数值，游标参数为行数组
+	Data          []map[string]interface{} `json:"data,omitempty"`         // 返回结果集的函数或游标函数的行
+	Columns       []string                 `json:"columns,omitempty"`
+	Error         *SQLError                `json:"error,omitempty"`
+	Timestamp     time.Time                `json:"timestamp"`
+	ExecutionTime float64                  `json:"execution_time,omitempty"`
 }
 
 // BatchSQLResponse 批量 SQL 响应结构
@@ -260,9 +372,30 @@ type SQLOperationResult struct {
 	Index         int                      `json:"index"`
 	Success       bool                     `json:"success"`
 	AffectedRows  int64                    `json:"affected_rows"`
+	LastInsertID  int64                    `json:"last_insert_id,omitempty"`
 	Data          []map[string]interface{} `json:"data,omitempty"`
 	Error         *SQLError                `json:"error,omitempty"`
 	ExecutionTime float64                  `json:"execution_time,omitempty"`
+	Debug         *SQLDebugInfo            `json:"debug,omitempty"`
+}
+
+// GraphQLResponse GraphQL 响应结构
+type GraphQLResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// GraphQLError GraphQL 错误
+type GraphQLError struct {
+	Message   string            `json:"message"`
+	Locations []GraphQLLocation `json:"locations,omitempty"`
+	Path      []interface{}     `json:"path,omitempty"`
+}
+
+// GraphQLLocation GraphQL 错误在请求文档中的位置
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
 }
 
 // ===== SQL 响应创建函数 =====
@@ -351,11 +484,30 @@ func NewBatchSQLErrorResponse(code int, message string, details ...string) Batch
 	}
 }
 
+// NewCallErrorResponse 创建调用错误响应
+func NewCallErrorResponse(routine string, code int, message string, details ...string) CallResponse {
+	sqlError := &SQLError{
+		Code:    code,
+		Message: message,
+	}
+
+	if len(details) > 0 && details[0] != "" {
+		sqlError.Details = details[0]
+	}
+
+	return CallResponse{
+		Success:   false,
+		Routine:   routine,
+		Error:     sqlError,
+		Timestamp: time.Now(),
+	}
+}
+
 // ===== 验证函数 =====
 
 // ValidateDatabaseType 验证数据库类型
 func ValidateDatabaseType(dbType string) bool {
-	validTypes := []DatabaseType{DatabasePostgreSQL, DatabaseOracle}
+	validTypes := []DatabaseType{DatabasePostgreSQL, DatabaseOracle, DatabaseSQLite, DatabaseMySQL, DatabaseSQLServer}
 	for _, validType := range validTypes {
 		if DatabaseType(dbType) == validType {
 			return true
@@ -366,7 +518,7 @@ func ValidateDatabaseType(dbType string) bool {
 
 // ValidateSQLAction 验证 SQL 操作类型
 func ValidateSQLAction(action string) bool {
-	validActions := []string{"select", "insert", "update", "delete"}
+	validActions := []string{"select", "insert", "update", "delete", "restore"}
 	for _, validAction := range validActions {
 		if strings.ToLower(action) == validAction {
 			return true
@@ -406,13 +558,15 @@ func ValidateSortOrder(order string) bool {
 // GetSQLErrorMessage 根据错误码获取错误消息
 func GetSQLErrorMessage(code int) string {
 	messages := map[int]string{
-		SQLErrorSyntax:      "SQL syntax error",
-		SQLErrorParams:      "Parameter error or missing",
-		SQLErrorPermission:  "Insufficient permissions",
-		SQLErrorConnection:  "Database connection error",
-		SQLErrorTransaction: "Transaction execution failed",
-		SQLErrorTimeout:     "Query timeout",
-		SQLErrorResultSize:  "Result set too large",
+		SQLErrorSyntax:       "SQL syntax error",
+		SQLErrorParams:       "Parameter error or missing",
+		SQLErrorPermission:   "Insufficient permissions",
+		SQLErrorConnection:   "Database connection error",
+		SQLErrorTransaction:  "Transaction execution failed",
+		SQLErrorTimeout:      "Query timeout",
+		SQLErrorResultSize:   "Result set too large",
+		SQLErrorConflict:     "Version conflict",
+		SQLErrorPrecondition: "Precondition failed",
 	}
 
 	if msg, exists := messages[code]; exists {
@@ -420,3 +574,207 @@ func GetSQLErrorMessage(code int) string {
 	}
 	return "Unknown SQL error"
 }
+
+// ===== Webhook 相关数据结构 =====
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryPending = "pending" // 等待投递或重试
+	WebhookDeliveryDead    = "dead"    // 超过最大投递次数，进入死信列表
+)
+
+// WebhookDelivery webhook 投递记录，保存在本地投递队列中
+type WebhookDelivery struct {
+	ID            uint64          `gorm:"primaryKey" json:"id"`
+	Endpoint      string          `gorm:"size:128;index" json:"endpoint"`
+	Table         string          `gorm:"column:target_table;size:128" json:"table"`
+	Action        string          `gorm:"size:16" json:"action"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `gorm:"size:16;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `gorm:"index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// ===== 审计日志相关数据结构 =====
+
+// 审计记录的结果
+const (
+	AuditOutcomeSuccess = "success" // 写操作成功
+	AuditOutcomeFailed  = "failed"  // 写操作执行失败
+	AuditOutcomeDenied  = "denied"  // 请求因权限不足被拒绝
+)
+
+// AuditRecord 审计记录：网关执行的写操作或被拒绝的请求
+// 参数和行数据按 audit.redact 脱敏；Before 和 After 为配置了 row_images 的表在同一事务中读取的修改前后的行；
+// PrevHash 和 Hash 为 JSONL 文件的哈希链，只写入文件时才有
+type AuditRecord struct {
+	ID           uint64                   `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Timestamp    time.Time                `gorm:"column:recorded_at;index" json:"timestamp"`
+	RequestID    string                   `gorm:"size:64;index" json:"request_id,omitempty"`
+	APIKeyName   string                   `gorm:"size:128;index" json:"api_key_name,omitempty"`
+	ClientIP     string                   `gorm:"size:64" json:"client_ip,omitempty"`
+	Endpoint     string                   `gorm:"size:512" json:"endpoint,omitempty"`
+	Table        string                   `gorm:"column:target_table;size:128;index" json:"table,omitempty"`
+	Action       string                   `gorm:"size:32" json:"action"`
+	Outcome      string                   `gorm:"size:16;index" json:"outcome"`
+	Permission   string                   `gorm:"size:256" json:"permission,omitempty"`
+	SQL          string                   `gorm:"column:sql_text" json:"sql,omitempty"`
+	Params       map[string]interface{}   `gorm:"serializer:json" json:"params,omitempty"`
+	AffectedRows int64                    `json:"affected_rows"`
+	Error        string                   `gorm:"column:error_message" json:"error,omitempty"`
+	Before       []map[string]interface{} `gorm:"column:before_image;serializer:json" json:"before,omitempty"`
+	After        []map[string]interface{} `gorm:"column:after_image;serializer:json" json:"after,omitempty"`
+	PrevHash     string                   `gorm:"size:64" json:"prev_hash,omitempty"`
+	Hash         string                   `gorm:"size:64" json:"hash,omitempty"`
+}
+
+// AuditQueryRequest 审计日志查询条件，结果按时间倒序返回
+type AuditQueryRequest struct {
+	APIKeyName string    `form:"api_key_name"`
+	Table      string    `form:"table"`
+	Action     string    `form:"action"`
+	Outcome    string    `form:"outcome" binding:"omitempty,oneof=success failed denied"`
+	RequestID  string    `form:"request_id"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	BeforeID   uint64    `form:"before_id"`                                // 只返回 ID 小于该值的记录，用于翻页
+	Limit      int       `form:"limit" binding:"omitempty,min=1,max=1000"` // 默认 100
+}
+
+// Matches 检查记录是否满足查询条件
+func (r *AuditQueryRequest) Matches(record *AuditRecord) bool {
+	switch {
+	case r.APIKeyName != "" && record.APIKeyName != r.APIKeyName,
+		r.Table != "" && !strings.EqualFold(record.Table, r.Table),
+		r.Action != "" && !strings.EqualFold(record.Action, r.Action),
+		r.Outcome != "" && record.Outcome != r.Outcome,
+		r.RequestID != "" && record.RequestID != r.RequestID,
+		!r.From.IsZero() && record.Timestamp.Before(r.From),
+		!r.To.IsZero() && !record.Timestamp.Before(r.To),
+		r.BeforeID != 0 && record.ID >= r.BeforeID:
+		return false
+	}
+	return true
+}
+
+// AuditQueryResponse 审计日志查询结果
+type AuditQueryResponse struct {
+	Records      []AuditRecord `json:"records"`
+	NextBeforeID uint64        `json:"next_before_id,omitempty"` // 还有更早的记录时，下一页的 before_id
+}
+
+// AuditVerifyResult JSONL 审计文件哈希链的校验结果
+type AuditVerifyResult struct {
+	Valid   bool   `json:"valid"`
+	Records int64  `json:"records"`           // 校验通过的记录数
+	Line    int64  `json:"line,omitempty"`    // 校验失败的行号
+	Error   string `json:"error,omitempty"`   // 校验失败的原因
+	LastID  uint64 `json:"last_id,omitempty"` // 最后一条通过校验的记录 ID
+}
+
+// ===== 语句统计相关数据结构 =====
+
+// 语句统计的排序字段
+const (
+	StatementSortTotalTime = "total_time"
+	StatementSortMeanTime  = "mean_time"
+	StatementSortMaxTime   = "max_time"
+	StatementSortP95Time   = "p95_time"
+	StatementSortCalls     = "calls"
+	StatementSortErrors    = "errors"
+	StatementSortRows      = "rows"
+)
+
+// StatementStat 按语句指纹汇总的执行统计，时间单位为毫秒
+type StatementStat struct {
+	Fingerprint string    `json:"fingerprint"` // 规范化 SQL 的哈希
+	Query       string    `json:"query"`       // 规范化的 SQL（字面量替换为 ?，IN 列表折叠）
+	QueryType   string    `json:"query_type"`  // select、insert、update、delete 等
+	Calls       int64     `json:"calls"`       // 执行次数
+	Errors      int64     `json:"errors"`      // 失败次数
+	TotalTime   float64   `json:"total_time"`  // 总耗时
+	MeanTime    float64   `json:"mean_time"`   // 平均耗时
+	P95Time     float64   `json:"p95_time"`    // 最近执行的 95 分位耗时
+	MaxTime     float64   `json:"max_time"`    // 最大耗时
+	Rows        int64     `json:"rows"`        // 返回和影响的总行数
+	APIKeys     []string  `json:"api_keys"`    // 执行过该语句的 API Key 名称
+	FirstSeen   time.Time `json:"first_seen"`  // 首次执行时间
+	LastSeen    time.Time `json:"last_seen"`   // 最近执行时间
+}
+
+// StatementStatsRequest 语句统计查询条件
+type StatementStatsRequest struct {
+	Sort   string `form:"sort" binding:"omitempty,oneof=total_time mean_time max_time p95_time calls errors rows"` // 默认 total_time，降序
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=1000"`                                                // 默认 100
+	APIKey string `form:"api_key"`                                                                                 // 只返回该 API Key 执行过的语句
+}
+
+// StatementStatsResponse 语句统计结果
+type StatementStatsResponse struct {
+	Statements []StatementStat `json:"statements"`
+	Total      int             `json:"total"` // 统计的语句指纹总数
+	Since      time.Time       `json:"since"` // 开始统计（或上次重置）的时间
+}
+
+// SlowQuerySample 慢查询样本，SQL 中的字面量和参数值已脱敏
+type SlowQuerySample struct {
+	QueryID       string            `json:"query_id"`
+	Fingerprint   string            `json:"fingerprint"`
+	QueryType     string            `json:"query_type"`
+	SQL           string            `json:"sql"`
+	Params        map[string]string `json:"params,omitempty"`
+	ExecutionTime float64           `json:"execution_time"` // 毫秒
+	Rows          int64             `json:"rows"`
+	Success       bool              `json:"success"`
+	ErrorCode     int               `json:"error_code,omitempty"`
+	APIKeyName    string            `json:"api_key_name,omitempty"`
+	RequestID     string            `json:"request_id,omitempty"`
+	TraceID       string            `json:"trace_id,omitempty"`
+	Timestamp     time.Time         `json:"timestamp"`
+}
+
+// ===== 管理 API 相关数据结构 =====
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	Version   string    `json:"version"`
+	GitCommit string    `json:"git_commit"`
+	BuildTime string    `json:"build_time"`
+	GoVersion string    `json:"go_version"`
+	StartTime time.Time `json:"start_time"` // 服务启动时间
+	Uptime    string    `json:"uptime"`
+}
+
+// RunningQuery 正在执行的查询，SQL 中的字面量已脱敏
+type RunningQuery struct {
+	QueryID      string    `json:"query_id"`
+	QueryType    string    `json:"query_type"`
+	DatabaseType string    `json:"database_type"`
+	SQL          string    `json:"sql"`
+	StartTime    time.Time `json:"start_time"`
+	Duration     float64   `json:"duration"` // 已执行的时间（毫秒）
+	APIKeyName   string    `json:"api_key_name,omitempty"`
+	RequestID    string    `json:"request_id,omitempty"`
+	ClientIP     string    `json:"client_ip,omitempty"`
+	TraceID      string    `json:"trace_id,omitempty"`
+}
+
+// AdminStats 运行状态统计
+type AdminStats struct {
+	Pool    map[string]interface{} `json:"pool"`    // 数据库连接池统计
+	Monitor map[string]interface{} `json:"monitor"` // SQL 性能监控器状态
+	Runtime RuntimeStats           `json:"runtime"` // Go 运行时统计
+}
+
+// RuntimeStats Go 运行时统计
+type RuntimeStats struct {
+	Goroutines   int    `json:"goroutines"`
+	HeapAlloc    uint64 `json:"heap_alloc"`    // 字节
+	HeapInuse    uint64 `json:"heap_inuse"`    // 字节
+	Sys          uint64 `json:"sys"`           // 字节
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotalNs uint64 `json:"pause_total_ns"`
+}
@@ -0,0 +1,559 @@
+package odata
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenString
+	tokenNumber
+	tokenDate
+	tokenPunct
+)
+
+// token 词法单元
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// datePattern 未加引号的日期和时间字面量，如 2024-01-31 或 2024-01-31T08:00:00Z
+var datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2})?)?`)
+
+// numberPattern 数字字面量，允许 OData 类型后缀（如 10M、1.5d）
+var numberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][+-]?\d+)?[mMdDfFlL]?`)
+
+// comparisonOperators OData 比较运算符到 QueryBuilder 条件运算符的映射
+var comparisonOperators = map[string]string{
+	"eq": "eq",
+	"ne": "neq",
+	"gt": "gt",
+	"ge": "gte",
+	"lt": "lt",
+	"le": "lte",
+}
+
+// flippedOperators 字面量在左侧时交换比较方向
+var flippedOperators = map[string]string{
+	"eq":  "eq",
+	"neq": "neq",
+	"gt":  "lt",
+	"gte": "lte",
+	"lt":  "gt",
+	"lte": "gte",
+}
+
+// stringFunctions 支持的字符串函数
+var stringFunctions = map[string]bool{
+	"contains":   true,
+	"startswith": true,
+	"endswith":   true,
+}
+
+// tokenize 将表达式拆分为词法单元
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(input); {
+		ch := input[pos]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			pos++
+
+		case ch == '(' || ch == ')' || ch == ',':
+			tokens = append(tokens, token{kind: tokenPunct, value: string(ch), pos: pos})
+			pos++
+
+		case ch == '\'':
+			// 字符串字面量，'' 表示单引号
+			var sb strings.Builder
+			start := pos
+			pos++
+			for {
+				if pos >= len(input) {
+					return nil, fmt.Errorf("unterminated string at position %d", start)
+				}
+				if input[pos] == '\'' {
+					if pos+1 < len(input) && input[pos+1] == '\'' {
+						sb.WriteByte('\'')
+						pos += 2
+						continue
+					}
+					pos++
+					break
+				}
+				sb.WriteByte(input[pos])
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: start})
+
+		case ch == '-' || (ch >= '0' && ch <= '9'):
+			if match := datePattern.FindString(input[pos:]); match != "" {
+				tokens = append(tokens, token{kind: tokenDate, value: match, pos: pos})
+				pos += len(match)
+				continue
+			}
+			match := numberPattern.FindString(input[pos:])
+			if match == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", ch, pos)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: match, pos: pos})
+			pos += len(match)
+
+		case ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z'):
+			start := pos
+			for pos < len(input) && isNameChar(input[pos]) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenName, value: input[start:pos], pos: start})
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", ch, pos)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// isNameChar 检查是否为名称字符
+func isNameChar(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
+// ===== 语法树 =====
+
+// node 过滤表达式节点
+type node interface{}
+
+// logicalNode and/or 组合
+type logicalNode struct {
+	op       string
+	operands []node
+}
+
+// notNode not 取反
+type notNode struct {
+	operand node
+}
+
+// compareNode 比较（包括 in）
+type compareNode struct {
+	op    string
+	left  node
+	right node
+}
+
+// callNode 函数调用
+type callNode struct {
+	name string
+	args []node
+}
+
+// propertyNode 属性引用
+type propertyNode struct {
+	name string
+}
+
+// literalNode 字面量
+type literalNode struct {
+	value interface{}
+}
+
+// listNode in 运算符的值列表
+type listNode struct {
+	items []interface{}
+}
+
+// parser 过滤表达式解析器
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek 查看当前词法单元
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next 读取当前词法单元
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword 检查当前词法单元是否为指定关键字
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokenName && tok.value == keyword
+}
+
+// expectPunct 读取指定标点
+func (p *parser) expectPunct(value string) error {
+	tok := p.next()
+	if tok.kind != tokenPunct || tok.value != value {
+		return fmt.Errorf("expected %q at position %d", value, tok.pos)
+	}
+	return nil
+}
+
+// parseOr or 表达式
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	operands := []node{left}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+	if len(operands) == 1 {
+		return left, nil
+	}
+	return &logicalNode{op: "or", operands: operands}, nil
+}
+
+// parseAnd and 表达式
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	operands := []node{left}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+	if len(operands) == 1 {
+		return left, nil
+	}
+	return &logicalNode{op: "and", operands: operands}, nil
+}
+
+// parseUnary not 表达式
+func (p *parser) parseUnary() (node, error) {
+	if p.isKeyword("not") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison 比较表达式
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	if tok.kind != tokenName {
+		return left, nil
+	}
+
+	if tok.value == "in" {
+		p.next()
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: "in", left: left, right: list}, nil
+	}
+
+	op, ok := comparisonOperators[tok.value]
+	if !ok {
+		return left, nil
+	}
+	p.next()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return &compareNode{op: op, left: left, right: right}, nil
+}
+
+// parsePrimary 括号表达式、函数调用、字面量或属性
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenPunct:
+		if tok.value != "(" {
+			return nil, fmt.Errorf("unexpected %q at position %d", tok.value, tok.pos)
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+
+	case tokenString, tokenDate:
+		return &literalNode{value: tok.value}, nil
+
+	case tokenNumber:
+		value, err := parseNumber(tok.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", tok.value, tok.pos)
+		}
+		return &literalNode{value: value}, nil
+
+	case tokenName:
+		switch tok.value {
+		case "true", "false":
+			return &literalNode{value: tok.value == "true"}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+
+		// 函数调用
+		if next := p.peek(); next.kind == tokenPunct && next.value == "(" {
+			p.next()
+			var args []node
+			for {
+				arg, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if next := p.peek(); next.kind == tokenPunct && next.value == "," {
+					p.next()
+					continue
+				}
+				break
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			return &callNode{name: tok.value, args: args}, nil
+		}
+		return &propertyNode{name: tok.value}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected end of expression at position %d", tok.pos)
+}
+
+// parseList in 运算符的值列表，如 ('a','b')
+func (p *parser) parseList() (node, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	list := &listNode{}
+	for {
+		item, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		literal, ok := item.(*literalNode)
+		if !ok || literal.value == nil {
+			return nil, fmt.Errorf("in list items must be literal values")
+		}
+		list.items = append(list.items, literal.value)
+
+		tok := p.next()
+		if tok.kind == tokenPunct && tok.value == ")" {
+			return list, nil
+		}
+		if tok.kind != tokenPunct || tok.value != "," {
+			return nil, fmt.Errorf("expected \",\" or \")\" at position %d", tok.pos)
+		}
+	}
+}
+
+// parseNumber 解析数字字面量，整数为 int64，其他为 float64
+func parseNumber(value string) (interface{}, error) {
+	value = strings.TrimRight(value, "mMdDfFlL")
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// ===== 转换为查询条件 =====
+
+// ParseFilter 将 $filter 表达式转换为 QueryBuilder 条件（使用 $and、$or、$not 组合）
+// resolve 将属性名映射为列名，未知属性返回 false
+func ParseFilter(expr string, resolve func(property string) (string, bool)) (map[string]interface{}, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.value, tok.pos)
+	}
+
+	return toCondition(root, resolve)
+}
+
+// toCondition 将语法树节点转换为条件
+func toCondition(n node, resolve func(string) (string, bool)) (map[string]interface{}, error) {
+	switch v := n.(type) {
+	case *logicalNode:
+		conditions := make([]interface{}, 0, len(v.operands))
+		for _, operand := range v.operands {
+			condition, err := toCondition(operand, resolve)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		return map[string]interface{}{"$" + v.op: conditions}, nil
+
+	case *notNode:
+		condition, err := toCondition(v.operand, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"$not": condition}, nil
+
+	case *compareNode:
+		return compareCondition(v, resolve)
+
+	case *callNode:
+		return callCondition(v, resolve)
+
+	case *propertyNode:
+		// 单独的布尔属性等价于 <property> eq true
+		column, err := resolveProperty(v, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{column: map[string]interface{}{"eq": true}}, nil
+	}
+
+	return nil, fmt.Errorf("expression is not a boolean condition")
+}
+
+// compareCondition 比较表达式转换为条件，必须是属性与字面量比较
+func compareCondition(n *compareNode, resolve func(string) (string, bool)) (map[string]interface{}, error) {
+	if n.op == "in" {
+		property, ok := n.left.(*propertyNode)
+		if !ok {
+			return nil, fmt.Errorf("the left side of in must be a property")
+		}
+		column, err := resolveProperty(property, resolve)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{column: map[string]interface{}{"in": n.right.(*listNode).items}}, nil
+	}
+
+	// 函数结果与布尔值比较，如 contains(name,'a') eq false
+	if call, ok := n.left.(*callNode); ok {
+		literal, isLiteral := n.right.(*literalNode)
+		value, isBool := literalValue(literal, isLiteral).(bool)
+		if !isBool || (n.op != "eq" && n.op != "neq") {
+			return nil, fmt.Errorf("function %s can only be compared with true or false", call.name)
+		}
+		condition, err := callCondition(call, resolve)
+		if err != nil {
+			return nil, err
+		}
+		if value == (n.op == "eq") {
+			return condition, nil
+		}
+		return map[string]interface{}{"$not": condition}, nil
+	}
+
+	op := n.op
+	property, isProperty := n.left.(*propertyNode)
+	literal, isLiteral := n.right.(*literalNode)
+	if !isProperty || !isLiteral {
+		// 字面量在左侧时交换两侧
+		property, isProperty = n.right.(*propertyNode)
+		literal, isLiteral = n.left.(*literalNode)
+		op = flippedOperators[op]
+	}
+	if !isProperty || !isLiteral {
+		return nil, fmt.Errorf("comparisons must be between a property and a literal value")
+	}
+
+	column, err := resolveProperty(property, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	if literal.value == nil {
+		switch op {
+		case "eq":
+			return map[string]interface{}{column: map[string]interface{}{"is_null": true}}, nil
+		case "neq":
+			return map[string]interface{}{column: map[string]interface{}{"is_null": false}}, nil
+		}
+		return nil, fmt.Errorf("null can only be compared with eq or ne")
+	}
+
+	return map[string]interface{}{column: map[string]interface{}{op: literal.value}}, nil
+}
+
+// callCondition 字符串函数转换为条件
+func callCondition(n *callNode, resolve func(string) (string, bool)) (map[string]interface{}, error) {
+	if !stringFunctions[n.name] {
+		return nil, fmt.Errorf("unsupported function %s", n.name)
+	}
+	if len(n.args) != 2 {
+		return nil, fmt.Errorf("function %s requires 2 arguments", n.name)
+	}
+
+	property, ok := n.args[0].(*propertyNode)
+	if !ok {
+		return nil, fmt.Errorf("the first argument of %s must be a property", n.name)
+	}
+	literal, ok := n.args[1].(*literalNode)
+	text, isString := literalValue(literal, ok).(string)
+	if !isString {
+		return nil, fmt.Errorf("the second argument of %s must be a string", n.name)
+	}
+
+	column, err := resolveProperty(property, resolve)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{column: map[string]interface{}{n.name: text}}, nil
+}
+
+// resolveProperty 将属性名解析为列名
+func resolveProperty(n *propertyNode, resolve func(string) (string, bool)) (string, error) {
+	column, ok := resolve(n.name)
+	if !ok {
+		return "", fmt.Errorf("unknown property %s", n.name)
+	}
+	return column, nil
+}
+
+// literalValue 读取字面量的值，节点不是字面量时返回 nil
+func literalValue(literal *literalNode, ok bool) interface{} {
+	if !ok || literal == nil {
+		return nil
+	}
+	return literal.value
+}
@@ -0,0 +1,98 @@
+package odata
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	resolve := func(property string) (string, bool) {
+		return property, property != "secret"
+	}
+
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"age gt 18", "map[age:map[gt:18]]"},
+		{"18 lt age", "map[age:map[gt:18]]"},
+		{"name eq 'O''Neil' or not contains(name,'x')", "map[$or:[map[name:map[eq:O'Neil]] map[$not:map[name:map[contains:x]]]]]"},
+		{"age ge 18 and (deleted eq null or startswith(name,'a'))", "map[$and:[map[age:map[gte:18]] map[$or:[map[deleted:map[is_null:true]] map[name:map[startswith:a]]]]]]"},
+		{"price in (1,2.5,'a')", "map[price:map[in:[1 2.5 a]]]"},
+		{"endswith(name,'z') eq false", "map[$not:map[name:map[endswith:z]]]"},
+		{"active", "map[active:map[eq:true]]"},
+		{"created lt 2024-01-01", "map[created:map[lt:2024-01-01]]"},
+	}
+
+	for _, tt := range tests {
+		where, err := ParseFilter(tt.expr, resolve)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.expr, err)
+			continue
+		}
+		if got := fmt.Sprint(where); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.expr, tt.expected, got)
+		}
+	}
+
+	for _, expr := range []string{"secret eq 1", "age gt", "age eq 'x", "length(name) eq 3", "age gt 1 and"} {
+		if _, err := ParseFilter(expr, resolve); err == nil {
+			t.Errorf("%s: expected error", expr)
+		}
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	values, err := ParseQuery("$select=id,name&$orderby=name%20desc,id&$top=5&$skip=10&$count=true&$expand=orders($select=total;$filter=total%20gt%201;$top=2),profile&api_key=k")
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+
+	opts, err := ParseOptions(values)
+	if err != nil {
+		t.Fatalf("ParseOptions failed: %v", err)
+	}
+
+	if len(opts.Select) != 2 || opts.Top != 5 || opts.Skip != 10 || !opts.Count {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if len(opts.OrderBy) != 2 || !opts.OrderBy[0].Desc || opts.OrderBy[1].Desc {
+		t.Errorf("Unexpected $orderby: %+v", opts.OrderBy)
+	}
+	if len(opts.Expand) != 2 || opts.Expand[0].Property != "orders" || opts.Expand[1].Property != "profile" {
+		t.Fatalf("Unexpected $expand: %+v", opts.Expand)
+	}
+	nested := opts.Expand[0].Options
+	if nested.Filter != "total gt 1" || nested.Top != 2 || len(nested.Select) != 1 {
+		t.Errorf("Unexpected nested options: %+v", nested)
+	}
+	if opts.Expand[1].Options.Top != -1 {
+		t.Errorf("Expected unset $top in expand, got %d", opts.Expand[1].Options.Top)
+	}
+
+	for _, query := range []string{"$top=-1", "$count=yes", "$unknown=1", "$expand=orders($format=json)", "$expand=orders($top=1"} {
+		values, _ := ParseQuery(query)
+		if _, err := ParseOptions(values); err == nil {
+			t.Errorf("%s: expected error", query)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := ParseKey("42", []string{"id"})
+	if err != nil || fmt.Sprint(key) != "map[id:42]" {
+		t.Errorf("Unexpected single key: %v, %v", key, err)
+	}
+
+	key, err = ParseKey("order_id=1,code='a,b'", []string{"order_id", "code"})
+	if err != nil || fmt.Sprint(key) != "map[code:a,b order_id:1]" {
+		t.Errorf("Unexpected composite key: %v, %v", key, err)
+	}
+
+	if _, err := ParseKey("1", []string{"order_id", "code"}); err == nil {
+		t.Error("Expected error for unnamed composite key")
+	}
+	if _, err := ParseKey("id=1,other=2", []string{"id"}); err == nil {
+		t.Error("Expected error for unknown key property")
+	}
+}
@@ -0,0 +1,140 @@
+package odata
+
+import (
+	"encoding/xml"
+)
+
+// Namespace 实体类型所在的命名空间
+const Namespace = "SQL2API"
+
+// EntityType 实体类型定义
+type EntityType struct {
+	Name       string
+	Key        []string
+	Properties []Property
+	Navigation []NavigationProperty
+}
+
+// Property 结构属性
+type Property struct {
+	Name     string
+	Type     string // Edm 类型，如 Edm.Int64
+	Nullable bool
+}
+
+// NavigationProperty 导航属性
+type NavigationProperty struct {
+	Name       string
+	Target     string // 目标实体类型（同时也是实体集名称）
+	Collection bool
+}
+
+// ===== CSDL XML 结构 =====
+
+type edmxDocument struct {
+	XMLName      xml.Name         `xml:"edmx:Edmx"`
+	Version      string           `xml:"Version,attr"`
+	Xmlns        string           `xml:"xmlns:edmx,attr"`
+	DataServices edmxDataServices `xml:"edmx:DataServices"`
+}
+
+type edmxDataServices struct {
+	Schema edmSchema `xml:"Schema"`
+}
+
+type edmSchema struct {
+	Xmlns       string          `xml:"xmlns,attr"`
+	Namespace   string          `xml:"Namespace,attr"`
+	EntityTypes []edmEntityType `xml:"EntityType"`
+	Container   edmContainer    `xml:"EntityContainer"`
+}
+
+type edmEntityType struct {
+	Name       string          `xml:"Name,attr"`
+	Key        edmKey          `xml:"Key"`
+	Properties []edmProperty   `xml:"Property"`
+	Navigation []edmNavigation `xml:"NavigationProperty"`
+}
+
+type edmKey struct {
+	Refs []edmPropertyRef `xml:"PropertyRef"`
+}
+
+type edmPropertyRef struct {
+	Name string `xml:"Name,attr"`
+}
+
+type edmProperty struct {
+	Name     string `xml:"Name,attr"`
+	Type     string `xml:"Type,attr"`
+	Nullable string `xml:"Nullable,attr,omitempty"`
+}
+
+type edmNavigation struct {
+	Name string `xml:"Name,attr"`
+	Type string `xml:"Type,attr"`
+}
+
+type edmContainer struct {
+	Name       string         `xml:"Name,attr"`
+	EntitySets []edmEntitySet `xml:"EntitySet"`
+}
+
+type edmEntitySet struct {
+	Name       string          `xml:"Name,attr"`
+	EntityType string          `xml:"EntityType,attr"`
+	Bindings   []edmNavBinding `xml:"NavigationPropertyBinding"`
+}
+
+type edmNavBinding struct {
+	Path   string `xml:"Path,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+// Metadata 生成 $metadata 文档（CSDL XML），每个实体类型对应一个同名实体集
+func Metadata(types []EntityType) ([]byte, error) {
+	schema := edmSchema{
+		Xmlns:     "http://docs.oasis-open.org/odata/ns/edm",
+		Namespace: Namespace,
+		Container: edmContainer{Name: "Container"},
+	}
+
+	for _, entityType := range types {
+		et := edmEntityType{Name: entityType.Name}
+		for _, key := range entityType.Key {
+			et.Key.Refs = append(et.Key.Refs, edmPropertyRef{Name: key})
+		}
+		for _, property := range entityType.Properties {
+			p := edmProperty{Name: property.Name, Type: property.Type}
+			if !property.Nullable {
+				p.Nullable = "false"
+			}
+			et.Properties = append(et.Properties, p)
+		}
+
+		set := edmEntitySet{Name: entityType.Name, EntityType: Namespace + "." + entityType.Name}
+		for _, nav := range entityType.Navigation {
+			navType := Namespace + "." + nav.Target
+			if nav.Collection {
+				navType = "Collection(" + navType + ")"
+			}
+			et.Navigation = append(et.Navigation, edmNavigation{Name: nav.Name, Type: navType})
+			set.Bindings = append(set.Bindings, edmNavBinding{Path: nav.Name, Target: nav.Target})
+		}
+
+		schema.EntityTypes = append(schema.EntityTypes, et)
+		schema.Container.EntitySets = append(schema.Container.EntitySets, set)
+	}
+
+	document := edmxDocument{
+		Version:      "4.0",
+		Xmlns:        "http://docs.oasis-open.org/odata/ns/edmx",
+		DataServices: edmxDataServices{Schema: schema},
+	}
+
+	output, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), output...), nil
+}
@@ -0,0 +1,306 @@
+package odata
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Options OData 查询选项
+type Options struct {
+	Filter  string
+	Select  []string
+	OrderBy []OrderItem
+	Top     int // -1 表示未指定
+	Skip    int
+	Count   bool
+	Expand  []*Expand
+}
+
+// OrderItem $orderby 排序项
+type OrderItem struct {
+	Property string
+	Desc     bool
+}
+
+// Expand $expand 展开的导航属性及其嵌套选项
+type Expand struct {
+	Property string
+	Options  *Options
+}
+
+// ParseOptions 解析 URL 中的系统查询选项，不以 $ 开头的参数被忽略
+func ParseOptions(values url.Values) (*Options, error) {
+	options := map[string]string{}
+	for key, items := range values {
+		if strings.HasPrefix(key, "$") {
+			options[key] = items[len(items)-1]
+		}
+	}
+	return parseOptionMap(options, true)
+}
+
+// ParseQuery 解析查询字符串，只按 & 分隔（$expand 的嵌套选项使用分号，url.ParseQuery 会丢弃含分号的参数）
+func ParseQuery(rawQuery string) (url.Values, error) {
+	values := url.Values{}
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter name: %w", err)
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s: %w", key, err)
+		}
+		values.Add(key, value)
+	}
+	return values, nil
+}
+
+// parseOptionMap 解析选项映射，topLevel 为 false 时不允许 $format 等只用于请求的选项
+func parseOptionMap(values map[string]string, topLevel bool) (*Options, error) {
+	opts := &Options{Top: -1}
+
+	for key, value := range values {
+		var err error
+		switch key {
+		case "$filter":
+			opts.Filter = value
+
+		case "$select":
+			opts.Select, err = parseSelect(value)
+
+		case "$orderby":
+			opts.OrderBy, err = parseOrderBy(value)
+
+		case "$top":
+			opts.Top, err = parseNonNegative(key, value)
+
+		case "$skip":
+			opts.Skip, err = parseNonNegative(key, value)
+
+		case "$count":
+			switch value {
+			case "true":
+				opts.Count = true
+			case "false":
+			default:
+				err = fmt.Errorf("$count must be true or false")
+			}
+
+		case "$expand":
+			opts.Expand, err = parseExpand(value)
+
+		case "$format":
+			if !topLevel || (value != "json" && !strings.HasPrefix(value, "application/json")) {
+				err = fmt.Errorf("unsupported $format: %s", value)
+			}
+
+		default:
+			err = fmt.Errorf("unsupported query option %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
+
+// parseSelect 解析 $select
+func parseSelect(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "*" {
+		return nil, nil
+	}
+
+	var properties []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if !isName(item) {
+			return nil, fmt.Errorf("invalid property in $select: %s", item)
+		}
+		properties = append(properties, item)
+	}
+	return properties, nil
+}
+
+// parseOrderBy 解析 $orderby，如 name desc,id
+func parseOrderBy(value string) ([]OrderItem, error) {
+	var items []OrderItem
+	for _, part := range strings.Split(value, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 || len(fields) > 2 || !isName(fields[0]) {
+			return nil, fmt.Errorf("invalid $orderby item: %s", strings.TrimSpace(part))
+		}
+
+		item := OrderItem{Property: fields[0]}
+		if len(fields) == 2 {
+			switch fields[1] {
+			case "asc":
+			case "desc":
+				item.Desc = true
+			default:
+				return nil, fmt.Errorf("invalid $orderby direction: %s", fields[1])
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseExpand 解析 $expand，如 author,books($select=title;$top=5)
+func parseExpand(value string) ([]*Expand, error) {
+	parts, err := splitTopLevel(value, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	var expands []*Expand
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		property, nested := part, ""
+		if idx := strings.Index(part, "("); idx >= 0 {
+			if !strings.HasSuffix(part, ")") {
+				return nil, fmt.Errorf("invalid $expand item: %s", part)
+			}
+			property, nested = strings.TrimSpace(part[:idx]), part[idx+1:len(part)-1]
+		}
+		if !isName(property) {
+			return nil, fmt.Errorf("invalid navigation property in $expand: %s", property)
+		}
+
+		// 嵌套选项以分号分隔
+		values := map[string]string{}
+		if strings.TrimSpace(nested) != "" {
+			items, err := splitTopLevel(nested, ';')
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				idx := strings.Index(item, "=")
+				if idx < 0 {
+					return nil, fmt.Errorf("invalid $expand option: %s", item)
+				}
+				values[strings.TrimSpace(item[:idx])] = strings.TrimSpace(item[idx+1:])
+			}
+		}
+
+		opts, err := parseOptionMap(values, false)
+		if err != nil {
+			return nil, fmt.Errorf("in $expand of %s: %w", property, err)
+		}
+		expands = append(expands, &Expand{Property: property, Options: opts})
+	}
+	return expands, nil
+}
+
+// splitTopLevel 按分隔符拆分，忽略括号和字符串内的分隔符
+func splitTopLevel(value string, separator byte) ([]string, error) {
+	var parts []string
+	depth, start, quoted := 0, 0, false
+	for i := 0; i < len(value); i++ {
+		switch ch := value[i]; {
+		case ch == '\'':
+			quoted = !quoted
+		case quoted:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %s", value)
+			}
+		case ch == separator && depth == 0:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	if depth != 0 || quoted {
+		return nil, fmt.Errorf("unbalanced parentheses or quotes in %s", value)
+	}
+	return append(parts, value[start:]), nil
+}
+
+// parseNonNegative 解析非负整数选项
+func parseNonNegative(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+// isName 检查是否为合法的属性名
+func isName(value string) bool {
+	if value == "" || (value[0] >= '0' && value[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if !isNameChar(value[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseKey 解析实体键，如 1、'abc' 或 id=1,code='x'，返回属性到值的映射
+func ParseKey(value string, keyProperties []string) (map[string]interface{}, error) {
+	parts, err := splitTopLevel(value, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	key := make(map[string]interface{}, len(parts))
+	for _, part := range parts {
+		name, literal := "", strings.TrimSpace(part)
+		if idx := strings.Index(literal, "="); idx >= 0 && !strings.HasPrefix(literal, "'") {
+			name, literal = strings.TrimSpace(literal[:idx]), strings.TrimSpace(literal[idx+1:])
+		}
+		if name == "" {
+			// 单一键可以省略属性名
+			if len(parts) != 1 || len(keyProperties) != 1 {
+				return nil, fmt.Errorf("key properties must be named for composite keys")
+			}
+			name = keyProperties[0]
+		}
+
+		parsed, err := parseLiteral(literal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key value for %s: %w", name, err)
+		}
+		key[name] = parsed
+	}
+
+	if len(key) != len(keyProperties) {
+		return nil, fmt.Errorf("expected %d key properties, got %d", len(keyProperties), len(key))
+	}
+	for _, property := range keyProperties {
+		if _, ok := key[property]; !ok {
+			return nil, fmt.Errorf("missing key property %s", property)
+		}
+	}
+	return key, nil
+}
+
+// parseLiteral 解析单个字面量
+func parseLiteral(value string) (interface{}, error) {
+	tokens, err := tokenize(value)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	literal, ok := n.(*literalNode)
+	if !ok || literal.value == nil || p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("expected a literal value")
+	}
+	return literal.value, nil
+}
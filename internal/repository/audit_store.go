@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"sql2api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// auditHashField JSONL 审计记录中哈希字段的前缀，哈希字段总是记录的最后一个字段
+const auditHashField = `,"hash":"`
+
+// AuditTable 审计表，保存在所连接的数据库中
+type AuditTable struct {
+	db    *gorm.DB
+	table string
+}
+
+// NewAuditTable 打开审计表，不存在时自动创建
+func NewAuditTable(db *gorm.DB, table string) (*AuditTable, error) {
+	// 每次写操作都会写入审计表，只记录警告以上的日志
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Warn)})
+	if err := db.Table(table).AutoMigrate(&model.AuditRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate audit table %s: %w", table, err)
+	}
+	return &AuditTable{db: db, table: table}, nil
+}
+
+// WriteAudit 写入审计记录
+func (t *AuditTable) WriteAudit(record *model.AuditRecord) error {
+	return t.db.Table(t.table).Create(record).Error
+}
+
+// LastAuditID 获取最大的记录 ID，表为空时返回 0
+func (t *AuditTable) LastAuditID() (uint64, error) {
+	var lastID *uint64
+	if err := t.db.Table(t.table).Select("MAX(id)").Scan(&lastID).Error; err != nil {
+		return 0, err
+	}
+	if lastID == nil {
+		return 0, nil
+	}
+	return *lastID, nil
+}
+
+// QueryAudit 按条件查询审计记录（最新的在前）
+func (t *AuditTable) QueryAudit(req *model.AuditQueryRequest, limit int) ([]model.AuditRecord, error) {
+	query := t.db.Table(t.table)
+	if req.APIKeyName != "" {
+		query = query.Where("api_key_name = ?", req.APIKeyName)
+	}
+	if req.Table != "" {
+		query = query.Where("LOWER(target_table) = ?", strings.ToLower(req.Table))
+	}
+	if req.Action != "" {
+		query = query.Where("LOWER(action) = ?", strings.ToLower(req.Action))
+	}
+	if req.Outcome != "" {
+		query = query.Where("outcome = ?", req.Outcome)
+	}
+	if req.RequestID != "" {
+		query = query.Where("request_id = ?", req.RequestID)
+	}
+	if !req.From.IsZero() {
+		query = query.Where("recorded_at >= ?", req.From)
+	}
+	if !req.To.IsZero() {
+		query = query.Where("recorded_at < ?", req.To)
+	}
+	if req.BeforeID != 0 {
+		query = query.Where("id < ?", req.BeforeID)
+	}
+
+	var records []model.AuditRecord
+	err := query.Order("id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
+
+// AuditFile 只追加写入的 JSONL 审计文件
+// 每条记录的 prev_hash 为前一条记录的哈希，hash 为去掉 hash 字段后整行 JSON 的 SHA-256，
+// 修改或删除任一条记录都会使之后的哈希链校验失败
+type AuditFile struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	lastID   uint64
+	lastHash string
+}
+
+// OpenAuditFile 打开或创建审计文件，从最后一条记录恢复哈希链
+func OpenAuditFile(path string) (*AuditFile, error) {
+	last, err := readLastLine(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+
+	auditFile := &AuditFile{path: path}
+	if len(last) > 0 {
+		var record model.AuditRecord
+		if err := json.Unmarshal(last, &record); err != nil || record.Hash == "" {
+			return nil, fmt.Errorf("audit file %s ends with an incomplete record", path)
+		}
+		auditFile.lastID = record.ID
+		auditFile.lastHash = record.Hash
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	auditFile.file = file
+	return auditFile, nil
+}
+
+// WriteAudit 追加审计记录，设置记录的 prev_hash 和 hash
+func (f *AuditFile) WriteAudit(record *model.AuditRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	record.PrevHash = f.lastHash
+	record.Hash = ""
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	hash := auditHash(body)
+
+	line := make([]byte, 0, len(body)+len(auditHashField)+len(hash)+3)
+	line = append(line, body[:len(body)-1]...)
+	line = append(line, auditHashField...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+	if _, err := f.file.Write(line); err != nil {
+		return err
+	}
+	if err := f.file.Sync(); err != nil {
+		return err
+	}
+
+	record.Hash = hash
+	f.lastID = record.ID
+	f.lastHash = hash
+	return nil
+}
+
+// LastAuditID 获取最后一条记录的 ID
+func (f *AuditFile) LastAuditID() (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastID, nil
+}
+
+// QueryAudit 按条件查询审计记录（最新的在前），需要顺序读取整个文件
+func (f *AuditFile) QueryAudit(req *model.AuditQueryRequest, limit int) ([]model.AuditRecord, error) {
+	// 只保留最后 limit 条匹配的记录
+	matches := make([]model.AuditRecord, 0, limit)
+	next := 0
+	err := f.scan(func(_ int64, line []byte) error {
+		var record model.AuditRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		if !req.Matches(&record) {
+			return nil
+		}
+		if len(matches) < limit {
+			matches = append(matches, record)
+		} else {
+			matches[next] = record
+		}
+		next = (next + 1) % limit
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]model.AuditRecord, 0, len(matches))
+	for i := 0; i < len(matches); i++ {
+		records = append(records, matches[(next-1-i+2*len(matches))%len(matches)])
+	}
+	return records, nil
+}
+
+// VerifyAudit 校验整个文件的哈希链
+func (f *AuditFile) VerifyAudit() (*model.AuditVerifyResult, error) {
+	result := &model.AuditVerifyResult{Valid: true}
+	prevHash := ""
+	err := f.scan(func(lineNumber int64, line []byte) error {
+		var record model.AuditRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("invalid record: %w", err)
+		}
+
+		index := bytes.LastIndex(line, []byte(auditHashField))
+		if index < 0 || record.Hash == "" {
+			return errors.New("record has no hash")
+		}
+		body := append(line[:index:index], '}')
+		if auditHash(body) != record.Hash {
+			return errors.New("record hash mismatch")
+		}
+		if record.PrevHash != prevHash {
+			return errors.New("previous hash mismatch")
+		}
+
+		prevHash = record.Hash
+		result.Records++
+		result.LastID = record.ID
+		return nil
+	})
+
+	var lineErr *auditLineError
+	if errors.As(err, &lineErr) {
+		result.Valid = false
+		result.Line = lineErr.line
+		result.Error = lineErr.err.Error()
+		return result, nil
+	}
+	return result, err
+}
+
+// Close 关闭审计文件
+func (f *AuditFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// auditLineError 处理某一行时的错误
+type auditLineError struct {
+	line int64
+	err  error
+}
+
+func (e *auditLineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+
+// scan 依次读取文件中的每一行，handler 返回的错误附带行号
+func (f *AuditFile) scan(handler func(lineNumber int64, line []byte) error) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var lineNumber int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNumber++
+			if err := handler(lineNumber, bytes.TrimRight(line, "\r\n")); err != nil {
+				return &auditLineError{line: lineNumber, err: err}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readLastLine 读取文件最后一个非空行，文件不存在时返回空
+func readLastLine(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// 从文件末尾向前按块读取，直到找到上一个换行符
+	const chunkSize = 64 * 1024
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		size := int64(chunkSize)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+
+		chunk := make([]byte, size)
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if index := bytes.LastIndexByte(trimmed, '\n'); index >= 0 {
+			return trimmed[index+1:], nil
+		}
+		if offset == 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// auditHash 计算审计记录的哈希
+func auditHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
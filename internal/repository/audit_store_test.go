@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"sql2api/internal/model"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAuditFile(t *testing.T) {
+	path := t.TempDir() + "/audit.jsonl"
+	auditFile, err := OpenAuditFile(path)
+	if err != nil {
+		t.Fatalf("OpenAuditFile failed: %v", err)
+	}
+
+	for i, table := range []string{"items", "orders", "items"} {
+		record := &model.AuditRecord{
+			ID:        uint64(i + 1),
+			Timestamp: time.Now(),
+			Table:     table,
+			Action:    "update",
+			Outcome:   model.AuditOutcomeSuccess,
+			Params:    map[string]interface{}{"param_1": i},
+		}
+		if err := auditFile.WriteAudit(record); err != nil {
+			t.Fatalf("WriteAudit failed: %v", err)
+		}
+		if record.Hash == "" || (i > 0) != (record.PrevHash != "") {
+			t.Errorf("Unexpected hash chain for record %d: %q %q", i, record.PrevHash, record.Hash)
+		}
+	}
+
+	records, err := auditFile.QueryAudit(&model.AuditQueryRequest{Table: "ITEMS"}, 10)
+	if err != nil || len(records) != 2 || records[0].ID != 3 || records[1].ID != 1 {
+		t.Fatalf("Unexpected query result: %v %v", records, err)
+	}
+	records, _ = auditFile.QueryAudit(&model.AuditQueryRequest{}, 2)
+	if len(records) != 2 || records[0].ID != 3 || records[1].ID != 2 {
+		t.Errorf("Expected the 2 newest records, got %v", records)
+	}
+
+	result, err := auditFile.VerifyAudit()
+	if err != nil || !result.Valid || result.Records != 3 || result.LastID != 3 {
+		t.Fatalf("Unexpected verify result: %+v %v", result, err)
+	}
+	auditFile.Close()
+
+	// 重新打开时从最后一条记录继续哈希链
+	auditFile, err = OpenAuditFile(path)
+	if err != nil {
+		t.Fatalf("OpenAuditFile failed: %v", err)
+	}
+	if id, _ := auditFile.LastAuditID(); id != 3 {
+		t.Errorf("Expected last ID 3, got %d", id)
+	}
+	if err := auditFile.WriteAudit(&model.AuditRecord{ID: 4, Action: "delete", Outcome: model.AuditOutcomeSuccess}); err != nil {
+		t.Fatalf("WriteAudit failed: %v", err)
+	}
+	if result, _ := auditFile.VerifyAudit(); !result.Valid || result.Records != 4 {
+		t.Errorf("Expected 4 valid records after reopening, got %+v", result)
+	}
+	auditFile.Close()
+
+	// 修改一条记录后校验失败
+	content, _ := os.ReadFile(path)
+	tampered := bytes.Replace(content, []byte(`"table":"orders"`), []byte(`"table":"others"`), 1)
+	if err := os.WriteFile(path, tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+	auditFile, _ = OpenAuditFile(path)
+	defer auditFile.Close()
+	result, err = auditFile.VerifyAudit()
+	if err != nil || result.Valid || result.Line != 2 || result.Records != 1 {
+		t.Errorf("Expected verification to fail at line 2, got %+v %v", result, err)
+	}
+}
+
+func TestAuditTable(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(t.TempDir()+"/audit.db"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := NewAuditTable(db, "audit_log")
+	if err != nil {
+		t.Fatalf("NewAuditTable failed: %v", err)
+	}
+	if id, err := table.LastAuditID(); err != nil || id != 0 {
+		t.Fatalf("Expected empty table, got %d %v", id, err)
+	}
+
+	start := time.Now()
+	records := []*model.AuditRecord{
+		{ID: 1, Timestamp: start, APIKeyName: "writer", Table: "items", Action: "insert", Outcome: model.AuditOutcomeSuccess,
+			After: []map[string]interface{}{{"id": 1, "name": "a"}}},
+		{ID: 2, Timestamp: start.Add(time.Second), APIKeyName: "reader", Action: "update", Outcome: model.AuditOutcomeDenied, Permission: "sql.update"},
+		{ID: 3, Timestamp: start.Add(2 * time.Second), APIKeyName: "writer", Table: "items", Action: "delete", Outcome: model.AuditOutcomeFailed, Error: "boom"},
+	}
+	for _, record := range records {
+		if err := table.WriteAudit(record); err != nil {
+			t.Fatalf("WriteAudit failed: %v", err)
+		}
+	}
+	if id, _ := table.LastAuditID(); id != 3 {
+		t.Errorf("Expected last ID 3, got %d", id)
+	}
+
+	found, err := table.QueryAudit(&model.AuditQueryRequest{APIKeyName: "writer", Table: "Items"}, 10)
+	if err != nil || len(found) != 2 || found[0].ID != 3 || found[1].ID != 1 {
+		t.Fatalf("Unexpected query result: %v %v", found, err)
+	}
+	if len(found[1].After) != 1 || found[1].After[0]["name"] != "a" {
+		t.Errorf("Expected after image to round-trip, got %v", found[1].After)
+	}
+	found, _ = table.QueryAudit(&model.AuditQueryRequest{Outcome: model.AuditOutcomeDenied}, 10)
+	if len(found) != 1 || found[0].Permission != "sql.update" {
+		t.Errorf("Unexpected denied records: %v", found)
+	}
+	found, _ = table.QueryAudit(&model.AuditQueryRequest{BeforeID: 3, From: start.Add(time.Second)}, 10)
+	if len(found) != 1 || found[0].ID != 2 {
+		t.Errorf("Unexpected records for before_id and from: %v", found)
+	}
+}
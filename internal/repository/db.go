@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 	"time"
@@ -8,8 +9,11 @@ import (
 	"sql2api/internal/config"
 	"sql2api/internal/model"
 
+	"github.com/glebarez/sqlite"
 	oracle "github.com/godoes/gorm-oracle"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlserver"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -43,6 +47,12 @@ func (d *Database) Connect() error {
 		dialector = postgres.Open(d.Config.GetDSN())
 	case "oracle":
 		dialector = oracle.Open(d.Config.GetDSN())
+	case "sqlite":
+		dialector = sqlite.Open(d.Config.GetDSN())
+	case "mysql":
+		dialector = mysql.Open(d.Config.GetDSN())
+	case "sqlserver":
+		dialector = sqlserver.Open(d.Config.GetDSN())
 	default:
 		return fmt.Errorf("unsupported database type: %s", d.Config.Type)
 	}
@@ -136,18 +146,26 @@ func (d *Database) HealthCheck() error {
 	return nil
 }
 
-// GetConnectionStats 获取连接池统计信息
-func (d *Database) GetConnectionStats() (map[string]interface{}, error) {
+// Stats 获取底层连接池的统计信息
+func (d *Database) Stats() (sql.DBStats, error) {
 	if d.DB == nil {
-		return nil, fmt.Errorf("database connection is nil")
+		return sql.DBStats{}, fmt.Errorf("database connection is nil")
 	}
-	
+
 	sqlDB, err := d.DB.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+		return sql.DBStats{}, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	return sqlDB.Stats(), nil
+}
+
+// GetConnectionStats 获取连接池统计信息
+func (d *Database) GetConnectionStats() (map[string]interface{}, error) {
+	stats, err := d.Stats()
+	if err != nil {
+		return nil, err
 	}
-	
-	stats := sqlDB.Stats()
 	
 	return map[string]interface{}{
 		"max_open_connections":     stats.MaxOpenConnections,
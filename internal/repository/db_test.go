@@ -43,7 +43,7 @@ func TestNewDatabase(t *testing.T) {
 
 func TestDatabase_UnsupportedType(t *testing.T) {
 	cfg := &config.DatabaseConfig{
-		Type: "mysql", // 不支持的数据库类型
+		Type: "db2", // 不支持的数据库类型
 	}
 
 	_, err := NewDatabase(cfg)
@@ -51,7 +51,7 @@ func TestDatabase_UnsupportedType(t *testing.T) {
 		t.Error("Expected error for unsupported database type")
 	}
 
-	expectedError := "unsupported database type: mysql"
+	expectedError := "unsupported database type: db2"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error message '%s', got '%s'", expectedError, err.Error())
 	}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector 将数据库连接池统计信息导出为 Prometheus 指标
+type PoolCollector struct {
+	db           *Database
+	maxOpen      *prometheus.Desc
+	open         *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+}
+
+// NewPoolCollector 创建连接池指标收集器，每次抓取时读取最新的连接池统计信息
+func NewPoolCollector(db *Database) *PoolCollector {
+	labels := prometheus.Labels{"datasource": db.Config.Type}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("sql2api", "db_pool", name), help, nil, labels)
+	}
+
+	return &PoolCollector{
+		db:           db,
+		maxOpen:      desc("max_open_connections", "Maximum number of open connections to the database."),
+		open:         desc("open_connections", "Number of established connections, both in use and idle."),
+		inUse:        desc("in_use_connections", "Number of connections currently in use."),
+		idle:         desc("idle_connections", "Number of idle connections."),
+		waitCount:    desc("wait_count_total", "Total number of connections waited for."),
+		waitDuration: desc("wait_duration_seconds_total", "Total time blocked waiting for a new connection."),
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+}
+
+// Collect 实现 prometheus.Collector
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.db.Stats()
+	if err != nil {
+		log.Printf("Failed to collect connection pool stats: %v", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"sql2api/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPoolCollector(t *testing.T) {
+	db, err := NewDatabase(&config.DatabaseConfig{
+		Type:         "sqlite",
+		Path:         ":memory:",
+		MaxOpenConns: 3,
+		MaxIdleConns: 1,
+		MaxLifetime:  60,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open sqlite database: %v", err)
+	}
+	defer db.Close()
+
+	expected := `
+# HELP sql2api_db_pool_max_open_connections Maximum number of open connections to the database.
+# TYPE sql2api_db_pool_max_open_connections gauge
+sql2api_db_pool_max_open_connections{datasource="sqlite"} 3
+# HELP sql2api_db_pool_wait_count_total Total number of connections waited for.
+# TYPE sql2api_db_pool_wait_count_total counter
+sql2api_db_pool_wait_count_total{datasource="sqlite"} 0
+`
+	collector := NewPoolCollector(db)
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"sql2api_db_pool_max_open_connections", "sql2api_db_pool_wait_count_total"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(collector); count != 6 {
+		t.Errorf("Expected 6 pool metrics, got %d", count)
+	}
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ErrWebhookDeliveryNotFound 死信记录不存在
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+// webhookEndpointRecord 通过 API 注册的 webhook 端点
+type webhookEndpointRecord struct {
+	Name       string `gorm:"primaryKey;size:128"`
+	Definition string // 端点定义（JSON）
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName 端点表名
+func (webhookEndpointRecord) TableName() string {
+	return "webhook_endpoints"
+}
+
+// WebhookStore webhook 本地存储（投递队列、死信列表和 API 注册的端点），使用独立的 SQLite 文件
+type WebhookStore struct {
+	db *gorm.DB
+}
+
+// NewWebhookStore 打开或创建 webhook 本地存储
+func NewWebhookStore(path string) (*WebhookStore, error) {
+	// 轮询查询较频繁，只记录警告以上的日志
+	db, err := gorm.Open(sqlite.Open(path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open webhook store: %w", err)
+	}
+
+	// SQLite 只允许一个写连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&model.WebhookDelivery{}, &webhookEndpointRecord{}); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to migrate webhook store: %w", err)
+	}
+
+	return &WebhookStore{db: db}, nil
+}
+
+// Close 关闭存储
+func (s *WebhookStore) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Enqueue 将投递记录加入队列
+func (s *WebhookStore) Enqueue(deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	for _, delivery := range deliveries {
+		delivery.Status = model.WebhookDeliveryPending
+		if delivery.NextAttemptAt.IsZero() {
+			delivery.NextAttemptAt = time.Now()
+		}
+	}
+	return s.db.Create(deliveries).Error
+}
+
+// Due 获取到期的待投递记录（按入队顺序）
+func (s *WebhookStore) Due(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := s.db.Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		Order("id").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// NextAttempt 获取最近一次待投递的时间，队列为空时返回 false
+func (s *WebhookStore) NextAttempt() (time.Time, bool, error) {
+	var deliveries []model.WebhookDelivery
+	err := s.db.Select("next_attempt_at").Where("status = ?", model.WebhookDeliveryPending).
+		Order("next_attempt_at").Limit(1).Find(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return time.Time{}, false, err
+	}
+	return deliveries[0].NextAttemptAt, true, nil
+}
+
+// Complete 投递成功，从队列中删除
+func (s *WebhookStore) Complete(id uint64) error {
+	return s.db.Delete(&model.WebhookDelivery{}, id).Error
+}
+
+// Retry 记录失败并安排下一次投递
+func (s *WebhookStore) Retry(id uint64, attempts int, lastError string, next time.Time) error {
+	return s.db.Model(&model.WebhookDelivery{ID: id}).Updates(map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      lastError,
+		"next_attempt_at": next,
+	}).Error
+}
+
+// Kill 记录失败并移入死信列表
+func (s *WebhookStore) Kill(id uint64, attempts int, lastError string) error {
+	return s.db.Model(&model.WebhookDelivery{ID: id}).Updates(map[string]interface{}{
+		"status":     model.WebhookDeliveryDead,
+		"attempts":   attempts,
+		"last_error": lastError,
+	}).Error
+}
+
+// ListDead 列出死信记录（最新的在前）
+func (s *WebhookStore) ListDead(limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := s.db.Where("status = ?", model.WebhookDeliveryDead).
+		Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// Replay 将死信记录重新加入队列，投递次数清零
+func (s *WebhookStore) Replay(id uint64) error {
+	result := s.db.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ?", id, model.WebhookDeliveryDead).
+		Updates(map[string]interface{}{
+			"status":          model.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+// DeleteDead 删除死信记录
+func (s *WebhookStore) DeleteDead(id uint64) error {
+	result := s.db.Where("id = ? AND status = ?", id, model.WebhookDeliveryDead).Delete(&model.WebhookDelivery{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+// ListEndpoints 列出通过 API 注册的端点
+func (s *WebhookStore) ListEndpoints() ([]config.WebhookEndpointConfig, error) {
+	var records []webhookEndpointRecord
+	if err := s.db.Order("name").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]config.WebhookEndpointConfig, 0, len(records))
+	for _, record := range records {
+		var endpoint config.WebhookEndpointConfig
+		if err := json.Unmarshal([]byte(record.Definition), &endpoint); err != nil {
+			return nil, fmt.Errorf("invalid webhook endpoint %s: %w", record.Name, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// SaveEndpoint 新增或替换通过 API 注册的端点
+func (s *WebhookStore) SaveEndpoint(endpoint *config.WebhookEndpointConfig) error {
+	definition, err := json.Marshal(endpoint)
+	if err != nil {
+		return err
+	}
+	return s.db.Save(&webhookEndpointRecord{Name: endpoint.Name, Definition: string(definition)}).Error
+}
+
+// DeleteEndpoint 删除通过 API 注册的端点
+func (s *WebhookStore) DeleteEndpoint(name string) error {
+	return s.db.Delete(&webhookEndpointRecord{Name: name}).Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+)
+
+func TestWebhookStore(t *testing.T) {
+	store, err := NewWebhookStore(t.TempDir() + "/webhooks.db")
+	if err != nil {
+		t.Fatalf("NewWebhookStore failed: %v", err)
+	}
+	defer store.Close()
+
+	deliveries := []*model.WebhookDelivery{
+		{Endpoint: "a", Table: "items", Action: "insert", Payload: []byte(`{"id":1}`)},
+		{Endpoint: "b", Table: "items", Action: "delete", Payload: []byte(`{"id":2}`)},
+	}
+	if err := store.Enqueue(deliveries); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	due, err := store.Due(time.Now(), 10)
+	if err != nil || len(due) != 2 || due[0].Endpoint != "a" || string(due[0].Payload) != `{"id":1}` {
+		t.Fatalf("Unexpected due deliveries: %v %v", due, err)
+	}
+
+	// 重试安排在未来，不再到期
+	next := time.Now().Add(time.Hour)
+	if err := store.Retry(due[0].ID, 1, "boom", next); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	if err := store.Kill(due[1].ID, 3, "gone"); err != nil {
+		t.Fatalf("Kill failed: %v", err)
+	}
+	if due, _ := store.Due(time.Now(), 10); len(due) != 0 {
+		t.Errorf("Expected no due deliveries, got %d", len(due))
+	}
+	if at, ok, _ := store.NextAttempt(); !ok || at.Sub(next).Abs() > time.Second {
+		t.Errorf("Unexpected next attempt: %v %v", at, ok)
+	}
+
+	dead, err := store.ListDead(10)
+	if err != nil || len(dead) != 1 || dead[0].LastError != "gone" || dead[0].Attempts != 3 {
+		t.Fatalf("Unexpected dead letters: %v %v", dead, err)
+	}
+
+	if err := store.Replay(due[0].ID); err != ErrWebhookDeliveryNotFound {
+		t.Errorf("Expected pending delivery not to be replayable, got %v", err)
+	}
+	if err := store.Replay(dead[0].ID); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if due, _ := store.Due(time.Now(), 10); len(due) != 1 || due[0].Attempts != 0 {
+		t.Errorf("Expected replayed delivery to be due, got %v", due)
+	}
+	if err := store.Complete(dead[0].ID); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if err := store.DeleteDead(dead[0].ID); err != ErrWebhookDeliveryNotFound {
+		t.Errorf("Expected completed delivery to be gone, got %v", err)
+	}
+
+	endpoint := &config.WebhookEndpointConfig{Name: "hook", URL: "https://example.com/hook", Actions: []string{"insert"}}
+	if err := store.SaveEndpoint(endpoint); err != nil {
+		t.Fatalf("SaveEndpoint failed: %v", err)
+	}
+	endpoints, err := store.ListEndpoints()
+	if err != nil || len(endpoints) != 1 || endpoints[0].URL != endpoint.URL || endpoints[0].Actions[0] != "insert" {
+		t.Errorf("Unexpected endpoints: %v %v", endpoints, err)
+	}
+	if err := store.DeleteEndpoint("hook"); err != nil {
+		t.Fatalf("DeleteEndpoint failed: %v", err)
+	}
+	if endpoints, _ := store.ListEndpoints(); len(endpoints) != 0 {
+		t.Errorf("Expected no endpoints, got %v", endpoints)
+	}
+}
@@ -0,0 +1,248 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"sql2api/internal/model"
+	"sql2api/internal/rpc/pb"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ===== 请求转换（protobuf -> model） =====
+
+// sqlRequestFromProto 转换 SQL 请求
+func sqlRequestFromProto(req *pb.SQLRequest) *model.SQLRequest {
+	result := &model.SQLRequest{
+		DatabaseType: req.GetDatabaseType(),
+		SQL:          req.GetSql(),
+		Params:       structToMap(req.GetParams()),
+		SQLDialect:   req.GetSqlDialect(),
+		Debug:        req.GetDebug(),
+	}
+
+	if query := req.GetQuery(); query != nil {
+		result.Query = &model.StructuredQuery{
+			Table:   query.GetTable(),
+			Action:  query.GetAction(),
+			Fields:  query.GetFields(),
+			Where:   structToMap(query.GetWhere()),
+			Data:    structToMap(query.GetData()),
+			GroupBy: query.GetGroupBy(),
+			Having:  structToMap(query.GetHaving()),
+			Limit:   int(query.GetLimit()),
+		}
+		for _, orderBy := range query.GetOrderBy() {
+			result.Query.OrderBy = append(result.Query.OrderBy, model.OrderByClause{
+				Field: orderBy.GetField(),
+				Order: orderBy.GetOrder(),
+			})
+		}
+	}
+
+	if pagination := req.GetPagination(); pagination != nil {
+		result.Pagination = &model.PaginationConfig{
+			Page:     int(pagination.GetPage()),
+			PageSize: int(pagination.GetPageSize()),
+		}
+	}
+
+	if sort := req.GetSort(); sort != nil {
+		result.Sort = &model.SortConfig{
+			SortBy:    sort.GetSortBy(),
+			SortOrder: sort.GetSortOrder(),
+		}
+	}
+
+	return result
+}
+
+// batchRequestFromProto 转换批量 SQL 请求
+func batchRequestFromProto(req *pb.BatchSQLRequest) *model.BatchSQLRequest {
+	result := &model.BatchSQLRequest{
+		DatabaseType:    req.GetDatabaseType(),
+		Transactional:   req.GetTransactional(),
+		ContinueOnError: req.GetContinueOnError(),
+	}
+	for _, operation := range req.GetOperations() {
+		result.Operations = append(result.Operations, *sqlRequestFromProto(operation))
+	}
+	return result
+}
+
+// insertRequestFromProto 转换便捷插入请求
+func insertRequestFromProto(req *pb.InsertRequest) *model.InsertRequest {
+	return &model.InsertRequest{
+		DatabaseType: req.GetDatabaseType(),
+		Table:        req.GetTable(),
+		Data:         structToMap(req.GetData()),
+		OnConflict:   req.GetOnConflict(),
+		ConflictKeys: req.GetConflictKeys(),
+		ReturnFields: req.GetReturnFields(),
+	}
+}
+
+// batchInsertRequestFromProto 转换批量插入请求
+func batchInsertRequestFromProto(req *pb.BatchInsertRequest) *model.BatchInsertRequest {
+	result := &model.BatchInsertRequest{
+		DatabaseType: req.GetDatabaseType(),
+		Table:        req.GetTable(),
+		OnConflict:   req.GetOnConflict(),
+		ConflictKeys: req.GetConflictKeys(),
+		ReturnFields: req.GetReturnFields(),
+	}
+	for _, data := range req.GetData() {
+		result.Data = append(result.Data, structToMap(data))
+	}
+	return result
+}
+
+// structToMap 转换 Struct，数字统一为 float64（与 JSON 请求一致）
+func structToMap(s *structpb.Struct) map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.AsMap()
+}
+
+// ===== 响应转换（model -> protobuf） =====
+
+// sqlResponseToProto 转换 SQL 响应
+func sqlResponseToProto(resp *model.SQLResponse) (*pb.SQLResponse, error) {
+	data, err := rowsToProto(resp.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SQLResponse{
+		Success:       resp.Success,
+		Message:       resp.Message,
+		Data:          data,
+		Timestamp:     timestamppb.New(resp.Timestamp),
+		AffectedRows:  resp.AffectedRows,
+		Total:         resp.Total,
+		Page:          int32(resp.Page),
+		PageSize:      int32(resp.PageSize),
+		Columns:       resp.Columns,
+		LastInsertId:  resp.LastInsertID,
+		ExecutionTime: resp.ExecutionTime,
+		Debug:         debugInfoToProto(resp.Debug),
+	}, nil
+}
+
+// batchResponseToProto 转换批量 SQL 响应
+func batchResponseToProto(resp *model.BatchSQLResponse) (*pb.BatchSQLResponse, error) {
+	result := &pb.BatchSQLResponse{
+		Success:           resp.Success,
+		Message:           resp.Message,
+		Timestamp:         timestamppb.New(resp.Timestamp),
+		TotalAffectedRows: resp.TotalAffectedRows,
+		ExecutedCount:     int32(resp.ExecutedCount),
+		FailedCount:       int32(resp.FailedCount),
+		ExecutionTime:     resp.ExecutionTime,
+	}
+
+	for _, operation := range resp.Results {
+		data, err := rowsToProto(operation.Data)
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, &pb.OperationResult{
+			Index:         int32(operation.Index),
+			Success:       operation.Success,
+			AffectedRows:  operation.AffectedRows,
+			LastInsertId:  operation.LastInsertID,
+			Data:          data,
+			Error:         sqlErrorToProto(operation.Error),
+			ExecutionTime: operation.ExecutionTime,
+			Debug:         debugInfoToProto(operation.Debug),
+		})
+	}
+	return result, nil
+}
+
+// debugInfoToProto 转换调试信息
+func debugInfoToProto(debug *model.SQLDebugInfo) *pb.DebugInfo {
+	if debug == nil {
+		return nil
+	}
+	return &pb.DebugInfo{
+		SqlDialect:    debug.SQLDialect,
+		DatabaseType:  debug.DatabaseType,
+		TranslatedSql: debug.TranslatedSQL,
+	}
+}
+
+// sqlErrorToProto 转换 SQL 错误
+func sqlErrorToProto(sqlError *model.SQLError) *pb.SQLError {
+	if sqlError == nil {
+		return nil
+	}
+	return &pb.SQLError{
+		Code:     int32(sqlError.Code),
+		Message:  sqlError.Message,
+		Details:  sqlError.Details,
+		SqlState: sqlError.SQLState,
+	}
+}
+
+// rowsToProto 转换结果行
+func rowsToProto(rows []map[string]interface{}) ([]*structpb.Struct, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	result := make([]*structpb.Struct, len(rows))
+	for i, row := range rows {
+		fields := make(map[string]*structpb.Value, len(row))
+		for column, value := range row {
+			v, err := toValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", column, err)
+			}
+			fields[column] = v
+		}
+		result[i] = &structpb.Struct{Fields: fields}
+	}
+	return result, nil
+}
+
+// rowToProto 按列顺序转换一行（流式查询）
+func rowToProto(columns []string, row map[string]interface{}) (*pb.Row, error) {
+	values := make([]*structpb.Value, len(columns))
+	for i, column := range columns {
+		v, err := toValue(row[column])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column, err)
+		}
+		values[i] = v
+	}
+	return &pb.Row{Values: values}, nil
+}
+
+// toValue 将数据库值转换为 Value，时间等 structpb 不支持的类型按 JSON 编码结果转换，与 HTTP 响应一致
+func toValue(value interface{}) (*structpb.Value, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return structpb.NewStringValue(v.Format(time.RFC3339Nano)), nil
+	case []byte:
+		return structpb.NewStringValue(string(v)), nil
+	}
+
+	if converted, err := structpb.NewValue(value); err == nil {
+		return converted, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+	return structpb.NewValue(decoded)
+}
@@ -0,0 +1,219 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"sql2api/internal/middleware"
+	"sql2api/internal/model"
+	"sql2api/internal/service"
+	"sql2api/internal/sql"
+	"sql2api/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata 请求 ID 的元数据键
+const requestIDMetadata = "x-request-id"
+
+// apiKeyContextKey 上下文中保存已验证 API Key 的键
+type apiKeyContextKey struct{}
+
+// deniedPermissionContextKey 上下文中保存第一个缺少的权限的键
+type deniedPermissionContextKey struct{}
+
+// interceptors gRPC 拦截器，依次执行请求日志、IP 白名单和 API Key 认证，与 HTTP 中间件行为一致
+type interceptors struct {
+	ipManager     *middleware.IPWhitelistManager
+	apiKeyManager *middleware.APIKeyManager
+	auditService  service.AuditService // 为 nil 时不记录被拒绝的调用
+}
+
+// unary 一元调用拦截器
+func (i *interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	ctx, span := startSpan(ctx, info.FullMethod)
+	ctx, caller := withCaller(ctx, info.FullMethod)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, caller.RequestID))
+
+	ctx, err := i.authorize(ctx, caller)
+	var resp interface{}
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+
+	i.recordDenial(ctx, err)
+	logCall(ctx, info.FullMethod, start, err)
+	tracing.End(span, err)
+	return resp, err
+}
+
+// stream 流式调用拦截器
+func (i *interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	ctx, span := startSpan(ss.Context(), info.FullMethod)
+	ctx, caller := withCaller(ctx, info.FullMethod)
+	ss.SetHeader(metadata.Pairs(requestIDMetadata, caller.RequestID))
+
+	ctx, err := i.authorize(ctx, caller)
+	if err == nil {
+		err = handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+	}
+
+	i.recordDenial(ctx, err)
+	logCall(ss.Context(), info.FullMethod, start, err)
+	tracing.End(span, err)
+	return err
+}
+
+// startSpan 为调用创建服务端 span，元数据中的 W3C traceparent 会被解析
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	return tracing.Start(ctx, strings.TrimPrefix(method, "/"),
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.method", method),
+	)
+}
+
+// metadataCarrier 以 gRPC 元数据实现 propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+// Get 获取键的第一个值
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set 设置键的值
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys 返回所有键
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// withCaller 在上下文中附加调用方信息，供审计日志使用
+func withCaller(ctx context.Context, method string) (context.Context, *sql.Caller) {
+	caller := &sql.Caller{
+		RequestID: middleware.ResolveRequestID(metadataValue(ctx, requestIDMetadata)),
+		ClientIP:  clientIP(ctx),
+		Endpoint:  method,
+	}
+	ctx = context.WithValue(ctx, deniedPermissionContextKey{}, new(string))
+	return sql.WithCaller(ctx, caller), caller
+}
+
+// recordDenial 将被拒绝的调用写入审计日志
+func (i *interceptors) recordDenial(ctx context.Context, err error) {
+	if i.auditService == nil || status.Code(err) != codes.PermissionDenied {
+		return
+	}
+
+	var permission string
+	if denied, ok := ctx.Value(deniedPermissionContextKey{}).(*string); ok {
+		permission = *denied
+	}
+	i.auditService.RecordDenial(ctx, &model.AuditRecord{Permission: permission})
+}
+
+// authorize 检查 IP 白名单和 API Key，成功时返回带 API Key 的上下文
+func (i *interceptors) authorize(ctx context.Context, caller *sql.Caller) (context.Context, error) {
+	if i.ipManager != nil && i.ipManager.IsEnabled() {
+		clientIP := clientIP(ctx)
+		if !i.ipManager.IsAllowed(clientIP) {
+			return ctx, status.Errorf(codes.PermissionDenied, "IP address %s is not in the whitelist", clientIP)
+		}
+	}
+
+	// 与 SimpleAuthMiddleware(required=true) 相同：必须提供有效的 API Key
+	if i.apiKeyManager.IsEnabled() {
+		apiKey := metadataValue(ctx, strings.ToLower(i.apiKeyManager.GetHeaderName()))
+		if apiKey != "" {
+			if keyItem, err := i.apiKeyManager.ValidateAPIKey(apiKey); err == nil {
+				caller.APIKeyName = keyItem.Name
+				return context.WithValue(ctx, apiKeyContextKey{}, apiKey), nil
+			}
+		}
+	}
+
+	return ctx, status.Error(codes.Unauthenticated, "Please provide a valid API Key")
+}
+
+// authorizedStream 替换上下文的服务端流
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回带 API Key 的上下文
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// apiKeyFromContext 获取已验证的 API Key
+func apiKeyFromContext(ctx context.Context) string {
+	apiKey, _ := ctx.Value(apiKeyContextKey{}).(string)
+	return apiKey
+}
+
+// metadataValue 获取请求元数据中的第一个值
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// clientIP 获取客户端 IP，优先级与 HTTP 相同：x-forwarded-for -> x-real-ip -> 连接地址
+func clientIP(ctx context.Context) string {
+	if forwarded := metadataValue(ctx, "x-forwarded-for"); forwarded != "" {
+		ip := strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		if net.ParseIP(ip) != nil {
+			return ip
+		}
+	}
+
+	if realIP := metadataValue(ctx, "x-real-ip"); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+
+	return "unknown"
+}
+
+// logCall 记录请求日志
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	log.Printf("[GRPC] %s | %12v | %15s | %s", status.Code(err), time.Since(start), clientIP(ctx), method)
+}
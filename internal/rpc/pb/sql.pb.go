@@ -0,0 +1,1495 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: sql2api/v1/sql.proto
+
+// SQL2API gRPC 接口，与 HTTP 的 /api/v1/sql 端点一一对应。
+// API Key 通过元数据传递（键为 api_keys.header_name 的小写形式，默认 x-api-key）。
+// 失败时返回 gRPC 状态码，SQL 错误码（4001-4009）放在 google.rpc.ErrorInfo 的 metadata 中。
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SQLRequest SQL 请求，sql 与 query 二选一
+type SQLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseType  string                 `protobuf:"bytes,1,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	Sql           string                 `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`
+	Query         *StructuredQuery       `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	Params        *structpb.Struct       `protobuf:"bytes,4,opt,name=params,proto3" json:"params,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,5,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	Sort          *Sort                  `protobuf:"bytes,6,opt,name=sort,proto3" json:"sort,omitempty"`
+	SqlDialect    string                 `protobuf:"bytes,7,opt,name=sql_dialect,json=sqlDialect,proto3" json:"sql_dialect,omitempty"`
+	Debug         bool                   `protobuf:"varint,8,opt,name=debug,proto3" json:"debug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SQLRequest) Reset() {
+	*x = SQLRequest{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SQLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SQLRequest) ProtoMessage() {}
+
+func (x *SQLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SQLRequest.ProtoReflect.Descriptor instead.
+func (*SQLRequest) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SQLRequest) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *SQLRequest) GetSql() string {
+	if x != nil {
+		return x.Sql
+	}
+	return ""
+}
+
+func (x *SQLRequest) GetQuery() *StructuredQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *SQLRequest) GetParams() *structpb.Struct {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *SQLRequest) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *SQLRequest) GetSort() *Sort {
+	if x != nil {
+		return x.Sort
+	}
+	return nil
+}
+
+func (x *SQLRequest) GetSqlDialect() string {
+	if x != nil {
+		return x.SqlDialect
+	}
+	return ""
+}
+
+func (x *SQLRequest) GetDebug() bool {
+	if x != nil {
+		return x.Debug
+	}
+	return false
+}
+
+// StructuredQuery 结构化查询
+type StructuredQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Table         string                 `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Fields        []string               `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
+	Where         *structpb.Struct       `protobuf:"bytes,4,opt,name=where,proto3" json:"where,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	GroupBy       []string               `protobuf:"bytes,6,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Having        *structpb.Struct       `protobuf:"bytes,7,opt,name=having,proto3" json:"having,omitempty"`
+	OrderBy       []*OrderBy             `protobuf:"bytes,8,rep,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	Limit         int32                  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StructuredQuery) Reset() {
+	*x = StructuredQuery{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StructuredQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructuredQuery) ProtoMessage() {}
+
+func (x *StructuredQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructuredQuery.ProtoReflect.Descriptor instead.
+func (*StructuredQuery) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StructuredQuery) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *StructuredQuery) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *StructuredQuery) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *StructuredQuery) GetWhere() *structpb.Struct {
+	if x != nil {
+		return x.Where
+	}
+	return nil
+}
+
+func (x *StructuredQuery) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *StructuredQuery) GetGroupBy() []string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+func (x *StructuredQuery) GetHaving() *structpb.Struct {
+	if x != nil {
+		return x.Having
+	}
+	return nil
+}
+
+func (x *StructuredQuery) GetOrderBy() []*OrderBy {
+	if x != nil {
+		return x.OrderBy
+	}
+	return nil
+}
+
+func (x *StructuredQuery) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// OrderBy 排序子句
+type OrderBy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Order         string                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBy) Reset() {
+	*x = OrderBy{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBy) ProtoMessage() {}
+
+func (x *OrderBy) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBy.ProtoReflect.Descriptor instead.
+func (*OrderBy) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderBy) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *OrderBy) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+// Pagination 分页配置
+type Pagination struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Pagination) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Pagination) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// Sort 排序配置
+type Sort struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SortBy        string                 `protobuf:"bytes,1,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	SortOrder     string                 `protobuf:"bytes,2,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sort) Reset() {
+	*x = Sort{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sort) ProtoMessage() {}
+
+func (x *Sort) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sort.ProtoReflect.Descriptor instead.
+func (*Sort) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Sort) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *Sort) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+// BatchSQLRequest 批量 SQL 请求
+type BatchSQLRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseType    string                 `protobuf:"bytes,1,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	Operations      []*SQLRequest          `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Transactional   bool                   `protobuf:"varint,3,opt,name=transactional,proto3" json:"transactional,omitempty"`
+	ContinueOnError bool                   `protobuf:"varint,4,opt,name=continue_on_error,json=continueOnError,proto3" json:"continue_on_error,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BatchSQLRequest) Reset() {
+	*x = BatchSQLRequest{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSQLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSQLRequest) ProtoMessage() {}
+
+func (x *BatchSQLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSQLRequest.ProtoReflect.Descriptor instead.
+func (*BatchSQLRequest) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchSQLRequest) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *BatchSQLRequest) GetOperations() []*SQLRequest {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *BatchSQLRequest) GetTransactional() bool {
+	if x != nil {
+		return x.Transactional
+	}
+	return false
+}
+
+func (x *BatchSQLRequest) GetContinueOnError() bool {
+	if x != nil {
+		return x.ContinueOnError
+	}
+	return false
+}
+
+// InsertRequest 便捷插入请求
+type InsertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseType  string                 `protobuf:"bytes,1,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	OnConflict    string                 `protobuf:"bytes,4,opt,name=on_conflict,json=onConflict,proto3" json:"on_conflict,omitempty"`
+	ConflictKeys  []string               `protobuf:"bytes,5,rep,name=conflict_keys,json=conflictKeys,proto3" json:"conflict_keys,omitempty"`
+	ReturnFields  []string               `protobuf:"bytes,6,rep,name=return_fields,json=returnFields,proto3" json:"return_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InsertRequest) Reset() {
+	*x = InsertRequest{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InsertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InsertRequest) ProtoMessage() {}
+
+func (x *InsertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InsertRequest.ProtoReflect.Descriptor instead.
+func (*InsertRequest) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InsertRequest) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *InsertRequest) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *InsertRequest) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *InsertRequest) GetOnConflict() string {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ""
+}
+
+func (x *InsertRequest) GetConflictKeys() []string {
+	if x != nil {
+		return x.ConflictKeys
+	}
+	return nil
+}
+
+func (x *InsertRequest) GetReturnFields() []string {
+	if x != nil {
+		return x.ReturnFields
+	}
+	return nil
+}
+
+// BatchInsertRequest 批量插入请求
+type BatchInsertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DatabaseType  string                 `protobuf:"bytes,1,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	Table         string                 `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	Data          []*structpb.Struct     `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	OnConflict    string                 `protobuf:"bytes,4,opt,name=on_conflict,json=onConflict,proto3" json:"on_conflict,omitempty"`
+	ConflictKeys  []string               `protobuf:"bytes,5,rep,name=conflict_keys,json=conflictKeys,proto3" json:"conflict_keys,omitempty"`
+	ReturnFields  []string               `protobuf:"bytes,6,rep,name=return_fields,json=returnFields,proto3" json:"return_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchInsertRequest) Reset() {
+	*x = BatchInsertRequest{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchInsertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchInsertRequest) ProtoMessage() {}
+
+func (x *BatchInsertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchInsertRequest.ProtoReflect.Descriptor instead.
+func (*BatchInsertRequest) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchInsertRequest) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *BatchInsertRequest) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
+func (x *BatchInsertRequest) GetData() []*structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *BatchInsertRequest) GetOnConflict() string {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ""
+}
+
+func (x *BatchInsertRequest) GetConflictKeys() []string {
+	if x != nil {
+		return x.ConflictKeys
+	}
+	return nil
+}
+
+func (x *BatchInsertRequest) GetReturnFields() []string {
+	if x != nil {
+		return x.ReturnFields
+	}
+	return nil
+}
+
+// SQLResponse SQL 响应
+type SQLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          []*structpb.Struct     `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	AffectedRows  int64                  `protobuf:"varint,5,opt,name=affected_rows,json=affectedRows,proto3" json:"affected_rows,omitempty"`
+	Total         int64                  `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,7,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,8,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Columns       []string               `protobuf:"bytes,9,rep,name=columns,proto3" json:"columns,omitempty"`
+	LastInsertId  int64                  `protobuf:"varint,10,opt,name=last_insert_id,json=lastInsertId,proto3" json:"last_insert_id,omitempty"`
+	ExecutionTime float64                `protobuf:"fixed64,11,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"` // 毫秒
+	Debug         *DebugInfo             `protobuf:"bytes,12,opt,name=debug,proto3" json:"debug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SQLResponse) Reset() {
+	*x = SQLResponse{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SQLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SQLResponse) ProtoMessage() {}
+
+func (x *SQLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SQLResponse.ProtoReflect.Descriptor instead.
+func (*SQLResponse) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SQLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SQLResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SQLResponse) GetData() []*structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SQLResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SQLResponse) GetAffectedRows() int64 {
+	if x != nil {
+		return x.AffectedRows
+	}
+	return 0
+}
+
+func (x *SQLResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SQLResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SQLResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SQLResponse) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *SQLResponse) GetLastInsertId() int64 {
+	if x != nil {
+		return x.LastInsertId
+	}
+	return 0
+}
+
+func (x *SQLResponse) GetExecutionTime() float64 {
+	if x != nil {
+		return x.ExecutionTime
+	}
+	return 0
+}
+
+func (x *SQLResponse) GetDebug() *DebugInfo {
+	if x != nil {
+		return x.Debug
+	}
+	return nil
+}
+
+// StreamQueryResponse 流式查询的一批结果：columns 只在第一条消息中出现，
+// summary 只在最后一条消息中出现
+type StreamQueryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Columns       []string               `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows          []*Row                 `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	Summary       *QuerySummary          `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamQueryResponse) Reset() {
+	*x = StreamQueryResponse{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamQueryResponse) ProtoMessage() {}
+
+func (x *StreamQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamQueryResponse.ProtoReflect.Descriptor instead.
+func (*StreamQueryResponse) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamQueryResponse) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *StreamQueryResponse) GetRows() []*Row {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *StreamQueryResponse) GetSummary() *QuerySummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+// Row 一行数据，values 与 columns 按位置对应
+type Row struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*structpb.Value      `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Row) Reset() {
+	*x = Row{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Row) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Row) ProtoMessage() {}
+
+func (x *Row) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Row.ProtoReflect.Descriptor instead.
+func (*Row) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Row) GetValues() []*structpb.Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// QuerySummary 流式查询的汇总信息
+type QuerySummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	ExecutionTime float64                `protobuf:"fixed64,2,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"` // 毫秒
+	Debug         *DebugInfo             `protobuf:"bytes,3,opt,name=debug,proto3" json:"debug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuerySummary) Reset() {
+	*x = QuerySummary{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuerySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuerySummary) ProtoMessage() {}
+
+func (x *QuerySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuerySummary.ProtoReflect.Descriptor instead.
+func (*QuerySummary) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *QuerySummary) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *QuerySummary) GetExecutionTime() float64 {
+	if x != nil {
+		return x.ExecutionTime
+	}
+	return 0
+}
+
+func (x *QuerySummary) GetDebug() *DebugInfo {
+	if x != nil {
+		return x.Debug
+	}
+	return nil
+}
+
+// DebugInfo 调试信息（请求 debug 为 true 时返回）
+type DebugInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SqlDialect    string                 `protobuf:"bytes,1,opt,name=sql_dialect,json=sqlDialect,proto3" json:"sql_dialect,omitempty"`
+	DatabaseType  string                 `protobuf:"bytes,2,opt,name=database_type,json=databaseType,proto3" json:"database_type,omitempty"`
+	TranslatedSql string                 `protobuf:"bytes,3,opt,name=translated_sql,json=translatedSql,proto3" json:"translated_sql,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DebugInfo) Reset() {
+	*x = DebugInfo{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DebugInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugInfo) ProtoMessage() {}
+
+func (x *DebugInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugInfo.ProtoReflect.Descriptor instead.
+func (*DebugInfo) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DebugInfo) GetSqlDialect() string {
+	if x != nil {
+		return x.SqlDialect
+	}
+	return ""
+}
+
+func (x *DebugInfo) GetDatabaseType() string {
+	if x != nil {
+		return x.DatabaseType
+	}
+	return ""
+}
+
+func (x *DebugInfo) GetTranslatedSql() string {
+	if x != nil {
+		return x.TranslatedSql
+	}
+	return ""
+}
+
+// SQLError SQL 错误（批量操作中单个操作的错误）
+type SQLError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	SqlState      string                 `protobuf:"bytes,4,opt,name=sql_state,json=sqlState,proto3" json:"sql_state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SQLError) Reset() {
+	*x = SQLError{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SQLError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SQLError) ProtoMessage() {}
+
+func (x *SQLError) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SQLError.ProtoReflect.Descriptor instead.
+func (*SQLError) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SQLError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SQLError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SQLError) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *SQLError) GetSqlState() string {
+	if x != nil {
+		return x.SqlState
+	}
+	return ""
+}
+
+// BatchSQLResponse 批量 SQL 响应
+type BatchSQLResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Success           bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message           string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Results           []*OperationResult     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	Timestamp         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TotalAffectedRows int64                  `protobuf:"varint,5,opt,name=total_affected_rows,json=totalAffectedRows,proto3" json:"total_affected_rows,omitempty"`
+	ExecutedCount     int32                  `protobuf:"varint,6,opt,name=executed_count,json=executedCount,proto3" json:"executed_count,omitempty"`
+	FailedCount       int32                  `protobuf:"varint,7,opt,name=failed_count,json=failedCount,proto3" json:"failed_count,omitempty"`
+	ExecutionTime     float64                `protobuf:"fixed64,8,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"` // 毫秒
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *BatchSQLResponse) Reset() {
+	*x = BatchSQLResponse{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSQLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSQLResponse) ProtoMessage() {}
+
+func (x *BatchSQLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSQLResponse.ProtoReflect.Descriptor instead.
+func (*BatchSQLResponse) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchSQLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BatchSQLResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BatchSQLResponse) GetResults() []*OperationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchSQLResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *BatchSQLResponse) GetTotalAffectedRows() int64 {
+	if x != nil {
+		return x.TotalAffectedRows
+	}
+	return 0
+}
+
+func (x *BatchSQLResponse) GetExecutedCount() int32 {
+	if x != nil {
+		return x.ExecutedCount
+	}
+	return 0
+}
+
+func (x *BatchSQLResponse) GetFailedCount() int32 {
+	if x != nil {
+		return x.FailedCount
+	}
+	return 0
+}
+
+func (x *BatchSQLResponse) GetExecutionTime() float64 {
+	if x != nil {
+		return x.ExecutionTime
+	}
+	return 0
+}
+
+// OperationResult 批量操作中单个操作的结果
+type OperationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	AffectedRows  int64                  `protobuf:"varint,3,opt,name=affected_rows,json=affectedRows,proto3" json:"affected_rows,omitempty"`
+	LastInsertId  int64                  `protobuf:"varint,4,opt,name=last_insert_id,json=lastInsertId,proto3" json:"last_insert_id,omitempty"`
+	Data          []*structpb.Struct     `protobuf:"bytes,5,rep,name=data,proto3" json:"data,omitempty"`
+	Error         *SQLError              `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	ExecutionTime float64                `protobuf:"fixed64,7,opt,name=execution_time,json=executionTime,proto3" json:"execution_time,omitempty"` // 毫秒
+	Debug         *DebugInfo             `protobuf:"bytes,8,opt,name=debug,proto3" json:"debug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationResult) Reset() {
+	*x = OperationResult{}
+	mi := &file_sql2api_v1_sql_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationResult) ProtoMessage() {}
+
+func (x *OperationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_sql2api_v1_sql_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationResult.ProtoReflect.Descriptor instead.
+func (*OperationResult) Descriptor() ([]byte, []int) {
+	return file_sql2api_v1_sql_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *OperationResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *OperationResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *OperationResult) GetAffectedRows() int64 {
+	if x != nil {
+		return x.AffectedRows
+	}
+	return 0
+}
+
+func (x *OperationResult) GetLastInsertId() int64 {
+	if x != nil {
+		return x.LastInsertId
+	}
+	return 0
+}
+
+func (x *OperationResult) GetData() []*structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *OperationResult) GetError() *SQLError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *OperationResult) GetExecutionTime() float64 {
+	if x != nil {
+		return x.ExecutionTime
+	}
+	return 0
+}
+
+func (x *OperationResult) GetDebug() *DebugInfo {
+	if x != nil {
+		return x.Debug
+	}
+	return nil
+}
+
+var File_sql2api_v1_sql_proto protoreflect.FileDescriptor
+
+const file_sql2api_v1_sql_proto_rawDesc = "" +
+	"\n" +
+	"\x14sql2api/v1/sql.proto\x12\n" +
+	"sql2api.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbc\x02\n" +
+	"\n" +
+	"SQLRequest\x12#\n" +
+	"\rdatabase_type\x18\x01 \x01(\tR\fdatabaseType\x12\x10\n" +
+	"\x03sql\x18\x02 \x01(\tR\x03sql\x121\n" +
+	"\x05query\x18\x03 \x01(\v2\x1b.sql2api.v1.StructuredQueryR\x05query\x12/\n" +
+	"\x06params\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06params\x126\n" +
+	"\n" +
+	"pagination\x18\x05 \x01(\v2\x16.sql2api.v1.PaginationR\n" +
+	"pagination\x12$\n" +
+	"\x04sort\x18\x06 \x01(\v2\x10.sql2api.v1.SortR\x04sort\x12\x1f\n" +
+	"\vsql_dialect\x18\a \x01(\tR\n" +
+	"sqlDialect\x12\x14\n" +
+	"\x05debug\x18\b \x01(\bR\x05debug\"\xc5\x02\n" +
+	"\x0fStructuredQuery\x12\x14\n" +
+	"\x05table\x18\x01 \x01(\tR\x05table\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06fields\x18\x03 \x03(\tR\x06fields\x12-\n" +
+	"\x05where\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x05where\x12+\n" +
+	"\x04data\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x19\n" +
+	"\bgroup_by\x18\x06 \x03(\tR\agroupBy\x12/\n" +
+	"\x06having\x18\a \x01(\v2\x17.google.protobuf.StructR\x06having\x12.\n" +
+	"\border_by\x18\b \x03(\v2\x13.sql2api.v1.OrderByR\aorderBy\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x05R\x05limit\"5\n" +
+	"\aOrderBy\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x14\n" +
+	"\x05order\x18\x02 \x01(\tR\x05order\"=\n" +
+	"\n" +
+	"Pagination\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\">\n" +
+	"\x04Sort\x12\x17\n" +
+	"\asort_by\x18\x01 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x02 \x01(\tR\tsortOrder\"\xc0\x01\n" +
+	"\x0fBatchSQLRequest\x12#\n" +
+	"\rdatabase_type\x18\x01 \x01(\tR\fdatabaseType\x126\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\v2\x16.sql2api.v1.SQLRequestR\n" +
+	"operations\x12$\n" +
+	"\rtransactional\x18\x03 \x01(\bR\rtransactional\x12*\n" +
+	"\x11continue_on_error\x18\x04 \x01(\bR\x0fcontinueOnError\"\xe2\x01\n" +
+	"\rInsertRequest\x12#\n" +
+	"\rdatabase_type\x18\x01 \x01(\tR\fdatabaseType\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12+\n" +
+	"\x04data\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x1f\n" +
+	"\von_conflict\x18\x04 \x01(\tR\n" +
+	"onConflict\x12#\n" +
+	"\rconflict_keys\x18\x05 \x03(\tR\fconflictKeys\x12#\n" +
+	"\rreturn_fields\x18\x06 \x03(\tR\freturnFields\"\xe7\x01\n" +
+	"\x12BatchInsertRequest\x12#\n" +
+	"\rdatabase_type\x18\x01 \x01(\tR\fdatabaseType\x12\x14\n" +
+	"\x05table\x18\x02 \x01(\tR\x05table\x12+\n" +
+	"\x04data\x18\x03 \x03(\v2\x17.google.protobuf.StructR\x04data\x12\x1f\n" +
+	"\von_conflict\x18\x04 \x01(\tR\n" +
+	"onConflict\x12#\n" +
+	"\rconflict_keys\x18\x05 \x03(\tR\fconflictKeys\x12#\n" +
+	"\rreturn_fields\x18\x06 \x03(\tR\freturnFields\"\xa8\x03\n" +
+	"\vSQLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\x04data\x18\x03 \x03(\v2\x17.google.protobuf.StructR\x04data\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12#\n" +
+	"\raffected_rows\x18\x05 \x01(\x03R\faffectedRows\x12\x14\n" +
+	"\x05total\x18\x06 \x01(\x03R\x05total\x12\x12\n" +
+	"\x04page\x18\a \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\b \x01(\x05R\bpageSize\x12\x18\n" +
+	"\acolumns\x18\t \x03(\tR\acolumns\x12$\n" +
+	"\x0elast_insert_id\x18\n" +
+	" \x01(\x03R\flastInsertId\x12%\n" +
+	"\x0eexecution_time\x18\v \x01(\x01R\rexecutionTime\x12+\n" +
+	"\x05debug\x18\f \x01(\v2\x15.sql2api.v1.DebugInfoR\x05debug\"\x88\x01\n" +
+	"\x13StreamQueryResponse\x12\x18\n" +
+	"\acolumns\x18\x01 \x03(\tR\acolumns\x12#\n" +
+	"\x04rows\x18\x02 \x03(\v2\x0f.sql2api.v1.RowR\x04rows\x122\n" +
+	"\asummary\x18\x03 \x01(\v2\x18.sql2api.v1.QuerySummaryR\asummary\"5\n" +
+	"\x03Row\x12.\n" +
+	"\x06values\x18\x01 \x03(\v2\x16.google.protobuf.ValueR\x06values\"x\n" +
+	"\fQuerySummary\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12%\n" +
+	"\x0eexecution_time\x18\x02 \x01(\x01R\rexecutionTime\x12+\n" +
+	"\x05debug\x18\x03 \x01(\v2\x15.sql2api.v1.DebugInfoR\x05debug\"x\n" +
+	"\tDebugInfo\x12\x1f\n" +
+	"\vsql_dialect\x18\x01 \x01(\tR\n" +
+	"sqlDialect\x12#\n" +
+	"\rdatabase_type\x18\x02 \x01(\tR\fdatabaseType\x12%\n" +
+	"\x0etranslated_sql\x18\x03 \x01(\tR\rtranslatedSql\"o\n" +
+	"\bSQLError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
+	"\adetails\x18\x03 \x01(\tR\adetails\x12\x1b\n" +
+	"\tsql_state\x18\x04 \x01(\tR\bsqlState\"\xd8\x02\n" +
+	"\x10BatchSQLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x125\n" +
+	"\aresults\x18\x03 \x03(\v2\x1b.sql2api.v1.OperationResultR\aresults\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12.\n" +
+	"\x13total_affected_rows\x18\x05 \x01(\x03R\x11totalAffectedRows\x12%\n" +
+	"\x0eexecuted_count\x18\x06 \x01(\x05R\rexecutedCount\x12!\n" +
+	"\ffailed_count\x18\a \x01(\x05R\vfailedCount\x12%\n" +
+	"\x0eexecution_time\x18\b \x01(\x01R\rexecutionTime\"\xb9\x02\n" +
+	"\x0fOperationResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12#\n" +
+	"\raffected_rows\x18\x03 \x01(\x03R\faffectedRows\x12$\n" +
+	"\x0elast_insert_id\x18\x04 \x01(\x03R\flastInsertId\x12+\n" +
+	"\x04data\x18\x05 \x03(\v2\x17.google.protobuf.StructR\x04data\x12*\n" +
+	"\x05error\x18\x06 \x01(\v2\x14.sql2api.v1.SQLErrorR\x05error\x12%\n" +
+	"\x0eexecution_time\x18\a \x01(\x01R\rexecutionTime\x12+\n" +
+	"\x05debug\x18\b \x01(\v2\x15.sql2api.v1.DebugInfoR\x05debug2\xb5\x03\n" +
+	"\n" +
+	"SQLService\x12?\n" +
+	"\fExecuteQuery\x12\x16.sql2api.v1.SQLRequest\x1a\x17.sql2api.v1.SQLResponse\x12H\n" +
+	"\vStreamQuery\x12\x16.sql2api.v1.SQLRequest\x1a\x1f.sql2api.v1.StreamQueryResponse0\x01\x12=\n" +
+	"\n" +
+	"ExecuteSQL\x12\x16.sql2api.v1.SQLRequest\x1a\x17.sql2api.v1.SQLResponse\x12I\n" +
+	"\fExecuteBatch\x12\x1b.sql2api.v1.BatchSQLRequest\x1a\x1c.sql2api.v1.BatchSQLResponse\x12C\n" +
+	"\rExecuteInsert\x12\x19.sql2api.v1.InsertRequest\x1a\x17.sql2api.v1.SQLResponse\x12M\n" +
+	"\x12ExecuteBatchInsert\x12\x1e.sql2api.v1.BatchInsertRequest\x1a\x17.sql2api.v1.SQLResponseB\x1cZ\x1asql2api/internal/rpc/pb;pbb\x06proto3"
+
+var (
+	file_sql2api_v1_sql_proto_rawDescOnce sync.Once
+	file_sql2api_v1_sql_proto_rawDescData []byte
+)
+
+func file_sql2api_v1_sql_proto_rawDescGZIP() []byte {
+	file_sql2api_v1_sql_proto_rawDescOnce.Do(func() {
+		file_sql2api_v1_sql_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sql2api_v1_sql_proto_rawDesc), len(file_sql2api_v1_sql_proto_rawDesc)))
+	})
+	return file_sql2api_v1_sql_proto_rawDescData
+}
+
+var file_sql2api_v1_sql_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_sql2api_v1_sql_proto_goTypes = []any{
+	(*SQLRequest)(nil),            // 0: sql2api.v1.SQLRequest
+	(*StructuredQuery)(nil),       // 1: sql2api.v1.StructuredQuery
+	(*OrderBy)(nil),               // 2: sql2api.v1.OrderBy
+	(*Pagination)(nil),            // 3: sql2api.v1.Pagination
+	(*Sort)(nil),                  // 4: sql2api.v1.Sort
+	(*BatchSQLRequest)(nil),       // 5: sql2api.v1.BatchSQLRequest
+	(*InsertRequest)(nil),         // 6: sql2api.v1.InsertRequest
+	(*BatchInsertRequest)(nil),    // 7: sql2api.v1.BatchInsertRequest
+	(*SQLResponse)(nil),           // 8: sql2api.v1.SQLResponse
+	(*StreamQueryResponse)(nil),   // 9: sql2api.v1.StreamQueryResponse
+	(*Row)(nil),                   // 10: sql2api.v1.Row
+	(*QuerySummary)(nil),          // 11: sql2api.v1.QuerySummary
+	(*DebugInfo)(nil),             // 12: sql2api.v1.DebugInfo
+	(*SQLError)(nil),              // 13: sql2api.v1.SQLError
+	(*BatchSQLResponse)(nil),      // 14: sql2api.v1.BatchSQLResponse
+	(*OperationResult)(nil),       // 15: sql2api.v1.OperationResult
+	(*structpb.Struct)(nil),       // 16: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+	(*structpb.Value)(nil),        // 18: google.protobuf.Value
+}
+var file_sql2api_v1_sql_proto_depIdxs = []int32{
+	1,  // 0: sql2api.v1.SQLRequest.query:type_name -> sql2api.v1.StructuredQuery
+	16, // 1: sql2api.v1.SQLRequest.params:type_name -> google.protobuf.Struct
+	3,  // 2: sql2api.v1.SQLRequest.pagination:type_name -> sql2api.v1.Pagination
+	4,  // 3: sql2api.v1.SQLRequest.sort:type_name -> sql2api.v1.Sort
+	16, // 4: sql2api.v1.StructuredQuery.where:type_name -> google.protobuf.Struct
+	16, // 5: sql2api.v1.StructuredQuery.data:type_name -> google.protobuf.Struct
+	16, // 6: sql2api.v1.StructuredQuery.having:type_name -> google.protobuf.Struct
+	2,  // 7: sql2api.v1.StructuredQuery.order_by:type_name -> sql2api.v1.OrderBy
+	0,  // 8: sql2api.v1.BatchSQLRequest.operations:type_name -> sql2api.v1.SQLRequest
+	16, // 9: sql2api.v1.InsertRequest.data:type_name -> google.protobuf.Struct
+	16, // 10: sql2api.v1.BatchInsertRequest.data:type_name -> google.protobuf.Struct
+	16, // 11: sql2api.v1.SQLResponse.data:type_name -> google.protobuf.Struct
+	17, // 12: sql2api.v1.SQLResponse.timestamp:type_name -> google.protobuf.Timestamp
+	12, // 13: sql2api.v1.SQLResponse.debug:type_name -> sql2api.v1.DebugInfo
+	10, // 14: sql2api.v1.StreamQueryResponse.rows:type_name -> sql2api.v1.Row
+	11, // 15: sql2api.v1.StreamQueryResponse.summary:type_name -> sql2api.v1.QuerySummary
+	18, // 16: sql2api.v1.Row.values:type_name -> google.protobuf.Value
+	12, // 17: sql2api.v1.QuerySummary.debug:type_name -> sql2api.v1.DebugInfo
+	15, // 18: sql2api.v1.BatchSQLResponse.results:type_name -> sql2api.v1.OperationResult
+	17, // 19: sql2api.v1.BatchSQLResponse.timestamp:type_name -> google.protobuf.Timestamp
+	16, // 20: sql2api.v1.OperationResult.data:type_name -> google.protobuf.Struct
+	13, // 21: sql2api.v1.OperationResult.error:type_name -> sql2api.v1.SQLError
+	12, // 22: sql2api.v1.OperationResult.debug:type_name -> sql2api.v1.DebugInfo
+	0,  // 23: sql2api.v1.SQLService.ExecuteQuery:input_type -> sql2api.v1.SQLRequest
+	0,  // 24: sql2api.v1.SQLService.StreamQuery:input_type -> sql2api.v1.SQLRequest
+	0,  // 25: sql2api.v1.SQLService.ExecuteSQL:input_type -> sql2api.v1.SQLRequest
+	5,  // 26: sql2api.v1.SQLService.ExecuteBatch:input_type -> sql2api.v1.BatchSQLRequest
+	6,  // 27: sql2api.v1.SQLService.ExecuteInsert:input_type -> sql2api.v1.InsertRequest
+	7,  // 28: sql2api.v1.SQLService.ExecuteBatchInsert:input_type -> sql2api.v1.BatchInsertRequest
+	8,  // 29: sql2api.v1.SQLService.ExecuteQuery:output_type -> sql2api.v1.SQLResponse
+	9,  // 30: sql2api.v1.SQLService.StreamQuery:output_type -> sql2api.v1.StreamQueryResponse
+	8,  // 31: sql2api.v1.SQLService.ExecuteSQL:output_type -> sql2api.v1.SQLResponse
+	14, // 32: sql2api.v1.SQLService.ExecuteBatch:output_type -> sql2api.v1.BatchSQLResponse
+	8,  // 33: sql2api.v1.SQLService.ExecuteInsert:output_type -> sql2api.v1.SQLResponse
+	8,  // 34: sql2api.v1.SQLService.ExecuteBatchInsert:output_type -> sql2api.v1.SQLResponse
+	29, // [29:35] is the sub-list for method output_type
+	23, // [23:29] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_sql2api_v1_sql_proto_init() }
+func file_sql2api_v1_sql_proto_init() {
+	if File_sql2api_v1_sql_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sql2api_v1_sql_proto_rawDesc), len(file_sql2api_v1_sql_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sql2api_v1_sql_proto_goTypes,
+		DependencyIndexes: file_sql2api_v1_sql_proto_depIdxs,
+		MessageInfos:      file_sql2api_v1_sql_proto_msgTypes,
+	}.Build()
+	File_sql2api_v1_sql_proto = out.File
+	file_sql2api_v1_sql_proto_goTypes = nil
+	file_sql2api_v1_sql_proto_depIdxs = nil
+}
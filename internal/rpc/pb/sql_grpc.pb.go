@@ -0,0 +1,335 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: sql2api/v1/sql.proto
+
+// SQL2API gRPC 接口，与 HTTP 的 /api/v1/sql 端点一一对应。
+// API Key 通过元数据传递（键为 api_keys.header_name 的小写形式，默认 x-api-key）。
+// 失败时返回 gRPC 状态码，SQL 错误码（4001-4009）放在 google.rpc.ErrorInfo 的 metadata 中。
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SQLService_ExecuteQuery_FullMethodName       = "/sql2api.v1.SQLService/ExecuteQuery"
+	SQLService_StreamQuery_FullMethodName        = "/sql2api.v1.SQLService/StreamQuery"
+	SQLService_ExecuteSQL_FullMethodName         = "/sql2api.v1.SQLService/ExecuteSQL"
+	SQLService_ExecuteBatch_FullMethodName       = "/sql2api.v1.SQLService/ExecuteBatch"
+	SQLService_ExecuteInsert_FullMethodName      = "/sql2api.v1.SQLService/ExecuteInsert"
+	SQLService_ExecuteBatchInsert_FullMethodName = "/sql2api.v1.SQLService/ExecuteBatchInsert"
+)
+
+// SQLServiceClient is the client API for SQLService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SQLService SQL 执行服务
+type SQLServiceClient interface {
+	// 执行查询（SELECT），需要 sql.query 权限，结果受 max_result_size 限制
+	ExecuteQuery(ctx context.Context, in *SQLRequest, opts ...grpc.CallOption) (*SQLResponse, error)
+	// 流式执行查询，逐批返回行，不受 max_result_size 限制
+	StreamQuery(ctx context.Context, in *SQLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamQueryResponse], error)
+	// 执行 INSERT、UPDATE、DELETE，需要 sql.<action> 权限
+	ExecuteSQL(ctx context.Context, in *SQLRequest, opts ...grpc.CallOption) (*SQLResponse, error)
+	// 执行批量操作，需要 sql.batch 和每个操作的权限
+	ExecuteBatch(ctx context.Context, in *BatchSQLRequest, opts ...grpc.CallOption) (*BatchSQLResponse, error)
+	// 便捷插入，需要 sql.insert 权限
+	ExecuteInsert(ctx context.Context, in *InsertRequest, opts ...grpc.CallOption) (*SQLResponse, error)
+	// 批量插入，需要 sql.insert 权限
+	ExecuteBatchInsert(ctx context.Context, in *BatchInsertRequest, opts ...grpc.CallOption) (*SQLResponse, error)
+}
+
+type sQLServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSQLServiceClient(cc grpc.ClientConnInterface) SQLServiceClient {
+	return &sQLServiceClient{cc}
+}
+
+func (c *sQLServiceClient) ExecuteQuery(ctx context.Context, in *SQLRequest, opts ...grpc.CallOption) (*SQLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SQLResponse)
+	err := c.cc.Invoke(ctx, SQLService_ExecuteQuery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sQLServiceClient) StreamQuery(ctx context.Context, in *SQLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamQueryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SQLService_ServiceDesc.Streams[0], SQLService_StreamQuery_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SQLRequest, StreamQueryResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SQLService_StreamQueryClient = grpc.ServerStreamingClient[StreamQueryResponse]
+
+func (c *sQLServiceClient) ExecuteSQL(ctx context.Context, in *SQLRequest, opts ...grpc.CallOption) (*SQLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SQLResponse)
+	err := c.cc.Invoke(ctx, SQLService_ExecuteSQL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sQLServiceClient) ExecuteBatch(ctx context.Context, in *BatchSQLRequest, opts ...grpc.CallOption) (*BatchSQLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchSQLResponse)
+	err := c.cc.Invoke(ctx, SQLService_ExecuteBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sQLServiceClient) ExecuteInsert(ctx context.Context, in *InsertRequest, opts ...grpc.CallOption) (*SQLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SQLResponse)
+	err := c.cc.Invoke(ctx, SQLService_ExecuteInsert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sQLServiceClient) ExecuteBatchInsert(ctx context.Context, in *BatchInsertRequest, opts ...grpc.CallOption) (*SQLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SQLResponse)
+	err := c.cc.Invoke(ctx, SQLService_ExecuteBatchInsert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SQLServiceServer is the server API for SQLService service.
+// All implementations must embed UnimplementedSQLServiceServer
+// for forward compatibility.
+//
+// SQLService SQL 执行服务
+type SQLServiceServer interface {
+	// 执行查询（SELECT），需要 sql.query 权限，结果受 max_result_size 限制
+	ExecuteQuery(context.Context, *SQLRequest) (*SQLResponse, error)
+	// 流式执行查询，逐批返回行，不受 max_result_size 限制
+	StreamQuery(*SQLRequest, grpc.ServerStreamingServer[StreamQueryResponse]) error
+	// 执行 INSERT、UPDATE、DELETE，需要 sql.<action> 权限
+	ExecuteSQL(context.Context, *SQLRequest) (*SQLResponse, error)
+	// 执行批量操作，需要 sql.batch 和每个操作的权限
+	ExecuteBatch(context.Context, *BatchSQLRequest) (*BatchSQLResponse, error)
+	// 便捷插入，需要 sql.insert 权限
+	ExecuteInsert(context.Context, *InsertRequest) (*SQLResponse, error)
+	// 批量插入，需要 sql.insert 权限
+	ExecuteBatchInsert(context.Context, *BatchInsertRequest) (*SQLResponse, error)
+	mustEmbedUnimplementedSQLServiceServer()
+}
+
+// UnimplementedSQLServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSQLServiceServer struct{}
+
+func (UnimplementedSQLServiceServer) ExecuteQuery(context.Context, *SQLRequest) (*SQLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteQuery not implemented")
+}
+func (UnimplementedSQLServiceServer) StreamQuery(*SQLRequest, grpc.ServerStreamingServer[StreamQueryResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamQuery not implemented")
+}
+func (UnimplementedSQLServiceServer) ExecuteSQL(context.Context, *SQLRequest) (*SQLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteSQL not implemented")
+}
+func (UnimplementedSQLServiceServer) ExecuteBatch(context.Context, *BatchSQLRequest) (*BatchSQLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteBatch not implemented")
+}
+func (UnimplementedSQLServiceServer) ExecuteInsert(context.Context, *InsertRequest) (*SQLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteInsert not implemented")
+}
+func (UnimplementedSQLServiceServer) ExecuteBatchInsert(context.Context, *BatchInsertRequest) (*SQLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteBatchInsert not implemented")
+}
+func (UnimplementedSQLServiceServer) mustEmbedUnimplementedSQLServiceServer() {}
+func (UnimplementedSQLServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeSQLServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SQLServiceServer will
+// result in compilation errors.
+type UnsafeSQLServiceServer interface {
+	mustEmbedUnimplementedSQLServiceServer()
+}
+
+func RegisterSQLServiceServer(s grpc.ServiceRegistrar, srv SQLServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSQLServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SQLService_ServiceDesc, srv)
+}
+
+func _SQLService_ExecuteQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SQLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SQLServiceServer).ExecuteQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SQLService_ExecuteQuery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SQLServiceServer).ExecuteQuery(ctx, req.(*SQLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SQLService_StreamQuery_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SQLRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SQLServiceServer).StreamQuery(m, &grpc.GenericServerStream[SQLRequest, StreamQueryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SQLService_StreamQueryServer = grpc.ServerStreamingServer[StreamQueryResponse]
+
+func _SQLService_ExecuteSQL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SQLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SQLServiceServer).ExecuteSQL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SQLService_ExecuteSQL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SQLServiceServer).ExecuteSQL(ctx, req.(*SQLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SQLService_ExecuteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchSQLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SQLServiceServer).ExecuteBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SQLService_ExecuteBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SQLServiceServer).ExecuteBatch(ctx, req.(*BatchSQLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SQLService_ExecuteInsert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SQLServiceServer).ExecuteInsert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SQLService_ExecuteInsert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SQLServiceServer).ExecuteInsert(ctx, req.(*InsertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SQLService_ExecuteBatchInsert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchInsertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SQLServiceServer).ExecuteBatchInsert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SQLService_ExecuteBatchInsert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SQLServiceServer).ExecuteBatchInsert(ctx, req.(*BatchInsertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SQLService_ServiceDesc is the grpc.ServiceDesc for SQLService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SQLService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sql2api.v1.SQLService",
+	HandlerType: (*SQLServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExecuteQuery",
+			Handler:    _SQLService_ExecuteQuery_Handler,
+		},
+		{
+			MethodName: "ExecuteSQL",
+			Handler:    _SQLService_ExecuteSQL_Handler,
+		},
+		{
+			MethodName: "ExecuteBatch",
+			Handler:    _SQLService_ExecuteBatch_Handler,
+		},
+		{
+			MethodName: "ExecuteInsert",
+			Handler:    _SQLService_ExecuteInsert_Handler,
+		},
+		{
+			MethodName: "ExecuteBatchInsert",
+			Handler:    _SQLService_ExecuteBatchInsert_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamQuery",
+			Handler:       _SQLService_StreamQuery_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "sql2api/v1/sql.proto",
+}
@@ -0,0 +1,318 @@
+// Package rpc 提供与 HTTP SQL 端点对应的 gRPC 服务
+package rpc
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=sql2api --go-grpc_out=../.. --go-grpc_opt=module=sql2api sql2api/v1/sql.proto
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"sql2api/internal/config"
+	"sql2api/internal/middleware"
+	"sql2api/internal/model"
+	"sql2api/internal/rpc/pb"
+	"sql2api/internal/service"
+
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamBatchSize 流式查询每条消息包含的最大行数
+const streamBatchSize = 100
+
+// errorDomain ErrorInfo 中的错误域
+const errorDomain = "sql2api"
+
+// SQLServer gRPC SQL 服务实现
+type SQLServer struct {
+	pb.UnimplementedSQLServiceServer
+	sqlService    service.SQLService
+	apiKeyManager *middleware.APIKeyManager
+}
+
+// NewServer 创建 gRPC 服务器并注册 SQL 服务
+// auditService 为 nil 时不记录被拒绝的调用
+func NewServer(cfg *config.GRPCConfig, sqlService service.SQLService, auditService service.AuditService, ipManager *middleware.IPWhitelistManager, apiKeyManager *middleware.APIKeyManager) *grpc.Server {
+	chain := &interceptors{
+		ipManager:     ipManager,
+		apiKeyManager: apiKeyManager,
+		auditService:  auditService,
+	}
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(chain.unary),
+		grpc.StreamInterceptor(chain.stream),
+		grpc.MaxRecvMsgSize(cfg.MaxMessageSize),
+		grpc.MaxSendMsgSize(cfg.MaxMessageSize),
+	)
+
+	pb.RegisterSQLServiceServer(server, &SQLServer{
+		sqlService:    sqlService,
+		apiKeyManager: apiKeyManager,
+	})
+	return server
+}
+
+// ExecuteQuery 执行查询操作
+func (s *SQLServer) ExecuteQuery(ctx context.Context, in *pb.SQLRequest) (*pb.SQLResponse, error) {
+	req := sqlRequestFromProto(in)
+	if err := s.checkSQLRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	response, err := s.sqlService.ExecuteQuery(ctx, req)
+	return s.sqlResponse(response, err)
+}
+
+// StreamQuery 流式执行查询操作，按批发送行
+func (s *SQLServer) StreamQuery(in *pb.SQLRequest, stream pb.SQLService_StreamQueryServer) error {
+	ctx := stream.Context()
+	req := sqlRequestFromProto(in)
+	if err := s.checkSQLRequest(ctx, req); err != nil {
+		return err
+	}
+	if service.SQLAction(req) != "query" {
+		return status.Error(codes.InvalidArgument, "StreamQuery only accepts SELECT queries")
+	}
+
+	var columns []string
+	batch := &pb.StreamQueryResponse{}
+	flush := func() error {
+		if err := stream.Send(batch); err != nil {
+			return err
+		}
+		batch = &pb.StreamQueryResponse{}
+		return nil
+	}
+
+	response, err := s.sqlService.StreamQuery(ctx, req, func(rowColumns []string, row map[string]interface{}) error {
+		// 第一条消息携带列名
+		if columns == nil {
+			columns = rowColumns
+			batch.Columns = columns
+		}
+
+		converted, err := rowToProto(columns, row)
+		if err != nil {
+			return err
+		}
+		batch.Rows = append(batch.Rows, converted)
+		if len(batch.Rows) >= streamBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !response.Success {
+		return statusFromSQLError(response.Error)
+	}
+
+	// 最后一条消息携带剩余的行和汇总信息
+	if columns == nil {
+		batch.Columns = response.Columns
+	}
+	batch.Summary = &pb.QuerySummary{
+		Total:         response.Total,
+		ExecutionTime: response.ExecutionTime,
+		Debug:         debugInfoToProto(response.Debug),
+	}
+	return stream.Send(batch)
+}
+
+// ExecuteSQL 执行 SQL 操作（INSERT、UPDATE、DELETE）
+func (s *SQLServer) ExecuteSQL(ctx context.Context, in *pb.SQLRequest) (*pb.SQLResponse, error) {
+	req := sqlRequestFromProto(in)
+	if err := s.checkSQLRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	response, err := s.sqlService.ExecuteSQL(ctx, req)
+	return s.sqlResponse(response, err)
+}
+
+// ExecuteBatch 执行批量 SQL 操作
+func (s *SQLServer) ExecuteBatch(ctx context.Context, in *pb.BatchSQLRequest) (*pb.BatchSQLResponse, error) {
+	req := batchRequestFromProto(in)
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	// 检查批量操作权限和每个操作的权限
+	if !s.hasPermission(ctx, "sql.batch") {
+		return nil, status.Error(codes.PermissionDenied, "Insufficient permissions for batch operations")
+	}
+	for i := range req.Operations {
+		if !s.hasSQLPermission(ctx, &req.Operations[i]) {
+			return nil, status.Errorf(codes.PermissionDenied, "Operation %d permission denied", i)
+		}
+	}
+
+	response, err := s.sqlService.ExecuteBatch(ctx, req)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if response.Error != nil {
+		return nil, statusFromSQLError(response.Error)
+	}
+
+	result, err := batchResponseToProto(response)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return result, nil
+}
+
+// ExecuteInsert 执行便捷插入操作
+func (s *SQLServer) ExecuteInsert(ctx context.Context, in *pb.InsertRequest) (*pb.SQLResponse, error) {
+	req := insertRequestFromProto(in)
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+	if !s.hasPermission(ctx, "sql.insert") {
+		return nil, status.Error(codes.PermissionDenied, "Insufficient permissions for insert operation")
+	}
+
+	response, err := s.sqlService.ExecuteInsert(ctx, req)
+	return s.sqlResponse(response, err)
+}
+
+// ExecuteBatchInsert 执行批量插入操作
+func (s *SQLServer) ExecuteBatchInsert(ctx context.Context, in *pb.BatchInsertRequest) (*pb.SQLResponse, error) {
+	req := batchInsertRequestFromProto(in)
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+	if !s.hasPermission(ctx, "sql.insert") {
+		return nil, status.Error(codes.PermissionDenied, "Insufficient permissions for batch insert operation")
+	}
+
+	response, err := s.sqlService.ExecuteBatchInsert(ctx, req)
+	return s.sqlResponse(response, err)
+}
+
+// ===== 辅助方法 =====
+
+// checkSQLRequest 验证 SQL 请求并检查 sql.<action> 权限
+func (s *SQLServer) checkSQLRequest(ctx context.Context, req *model.SQLRequest) error {
+	if err := validate(req); err != nil {
+		return err
+	}
+	if !s.hasSQLPermission(ctx, req) {
+		return status.Error(codes.PermissionDenied, "Insufficient permissions")
+	}
+	return nil
+}
+
+// hasSQLPermission 检查 SQL 请求对应的 sql.<action> 权限
+func (s *SQLServer) hasSQLPermission(ctx context.Context, req *model.SQLRequest) bool {
+	action := service.SQLAction(req)
+	if action == "" {
+		return false
+	}
+	return s.hasPermission(ctx, "sql."+action)
+}
+
+// hasPermission 检查当前 API Key 是否有指定权限
+func (s *SQLServer) hasPermission(ctx context.Context, permission string) bool {
+	apiKey := apiKeyFromContext(ctx)
+	if apiKey != "" && s.apiKeyManager.HasPermission(apiKey, permission) {
+		return true
+	}
+
+	// 记录第一个缺少的权限，调用被拒绝时写入审计日志
+	if denied, ok := ctx.Value(deniedPermissionContextKey{}).(*string); ok && *denied == "" {
+		*denied = permission
+	}
+	return false
+}
+
+// sqlResponse 将服务结果转换为 gRPC 响应，失败的响应转换为对应的状态码
+func (s *SQLServer) sqlResponse(response *model.SQLResponse, err error) (*pb.SQLResponse, error) {
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !response.Success {
+		return nil, statusFromSQLError(response.Error)
+	}
+
+	result, err := sqlResponseToProto(response)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return result, nil
+}
+
+// validate 按 binding 标签验证请求（与 HTTP 请求绑定时的验证相同）
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return statusFromSQLError(&model.SQLError{
+			Code:    model.SQLErrorParams,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+	}
+	return nil
+}
+
+// statusFromSQLError 根据 SQL 错误码创建 gRPC 状态，错误码和详细信息放在 ErrorInfo 中
+func statusFromSQLError(sqlError *model.SQLError) error {
+	if sqlError == nil {
+		return status.Error(codes.Internal, "SQL execution failed")
+	}
+
+	message := sqlError.Message
+	if sqlError.Details != "" {
+		message = fmt.Sprintf("%s: %s", message, sqlError.Details)
+	}
+	st := status.New(codeFromSQLError(sqlError.Code), message)
+
+	info := &errdetails.ErrorInfo{
+		Reason: "SQL_ERROR_" + strconv.Itoa(sqlError.Code),
+		Domain: errorDomain,
+		Metadata: map[string]string{
+			"code":    strconv.Itoa(sqlError.Code),
+			"message": sqlError.Message,
+		},
+	}
+	if sqlError.Details != "" {
+		info.Metadata["details"] = sqlError.Details
+	}
+	if sqlError.SQLState != "" {
+		info.Metadata["sql_state"] = sqlError.SQLState
+	}
+
+	if detailed, err := st.WithDetails(info); err == nil {
+		return detailed.Err()
+	}
+	return st.Err()
+}
+
+// codeFromSQLError 根据 SQL 错误码获取 gRPC 状态码（与 HTTP 状态码映射对应）
+func codeFromSQLError(code int) codes.Code {
+	switch code {
+	case model.SQLErrorSyntax, model.SQLErrorParams:
+		return codes.InvalidArgument
+	case model.SQLErrorPermission:
+		return codes.PermissionDenied
+	case model.SQLErrorConnection:
+		return codes.Unavailable
+	case model.SQLErrorTimeout:
+		return codes.DeadlineExceeded
+	case model.SQLErrorTransaction:
+		return codes.Aborted
+	case model.SQLErrorResultSize:
+		return codes.ResourceExhausted
+	case model.SQLErrorConflict:
+		return codes.Aborted
+	case model.SQLErrorPrecondition:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,98 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"sql2api/internal/model"
+	"sql2api/internal/rpc/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestStatusFromSQLError(t *testing.T) {
+	err := statusFromSQLError(&model.SQLError{
+		Code:    model.SQLErrorTimeout,
+		Message: "Query timeout",
+		Details: "context deadline exceeded",
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %s", st.Code())
+	}
+	if st.Message() != "Query timeout: context deadline exceeded" {
+		t.Errorf("Unexpected message: %s", st.Message())
+	}
+
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("Expected one detail, got %d", len(details))
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok || info.Metadata["code"] != "4006" || info.Domain != errorDomain {
+		t.Errorf("Unexpected error info: %v", details[0])
+	}
+
+	if code := status.Code(statusFromSQLError(nil)); code != codes.Internal {
+		t.Errorf("Expected Internal for nil error, got %s", code)
+	}
+}
+
+func TestSQLRequestFromProto(t *testing.T) {
+	where, _ := structpb.NewStruct(map[string]interface{}{"price": map[string]interface{}{"gt": 10}})
+	req := sqlRequestFromProto(&pb.SQLRequest{
+		DatabaseType: "sqlite",
+		Query: &pb.StructuredQuery{
+			Table:   "items",
+			Action:  "select",
+			Where:   where,
+			OrderBy: []*pb.OrderBy{{Field: "name", Order: "desc"}},
+		},
+		Pagination: &pb.Pagination{Page: 2, PageSize: 10},
+	})
+
+	if req.Query == nil || req.Query.Table != "items" || len(req.Query.OrderBy) != 1 {
+		t.Fatalf("Unexpected query: %+v", req.Query)
+	}
+	price, ok := req.Query.Where["price"].(map[string]interface{})
+	if !ok || price["gt"] != float64(10) {
+		t.Errorf("Unexpected where: %v", req.Query.Where)
+	}
+	if req.Pagination == nil || req.Pagination.Page != 2 {
+		t.Errorf("Unexpected pagination: %+v", req.Pagination)
+	}
+	if req.Sort != nil || req.Params != nil {
+		t.Errorf("Expected unset sort and params, got %+v, %v", req.Sort, req.Params)
+	}
+}
+
+func TestRowToProto(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	row, err := rowToProto([]string{"id", "name", "created", "note"}, map[string]interface{}{
+		"id":      int64(7),
+		"name":    []byte("widget"),
+		"created": created,
+		"note":    nil,
+	})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	values := row.GetValues()
+	if len(values) != 4 {
+		t.Fatalf("Expected 4 values, got %d", len(values))
+	}
+	if values[0].GetNumberValue() != 7 || values[1].GetStringValue() != "widget" {
+		t.Errorf("Unexpected values: %v", values)
+	}
+	if values[2].GetStringValue() != "2024-01-02T03:04:05Z" {
+		t.Errorf("Unexpected time value: %v", values[2])
+	}
+	if _, ok := values[3].GetKind().(*structpb.Value_NullValue); !ok {
+		t.Errorf("Expected null value, got %v", values[3])
+	}
+}
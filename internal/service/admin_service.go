@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// AdminService 管理 API 服务接口
+type AdminService interface {
+	// 连接池、性能监控器和 Go 运行时统计
+	Stats() (*model.AdminStats, error)
+
+	// 正在执行的查询
+	RunningQueries() []model.RunningQuery
+}
+
+// adminService 管理 API 服务实现
+type adminService struct {
+	repos     *repository.Repositories
+	sqlEngine *sql.SQLEngine // SQL 服务未启用时为 nil
+}
+
+// NewAdminService 创建管理 API 服务
+func NewAdminService(repos *repository.Repositories, cfg *config.Config) (AdminService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	service := &adminService{repos: repos}
+	if cfg.SQL.Enabled {
+		engine, err := sql.NewSQLEngine(repos, &cfg.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+		}
+		service.sqlEngine = engine
+	}
+	return service, nil
+}
+
+// Stats 获取运行状态统计
+func (s *adminService) Stats() (*model.AdminStats, error) {
+	pool, err := s.repos.GetDatabase().GetConnectionStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection stats: %w", err)
+	}
+
+	stats := &model.AdminStats{Pool: pool}
+	if s.sqlEngine != nil {
+		stats.Monitor = s.sqlEngine.GetMetricsSummary()
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats.Runtime = model.RuntimeStats{
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapInuse:    mem.HeapInuse,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		PauseTotalNs: mem.PauseTotalNs,
+	}
+	return stats, nil
+}
+
+// RunningQueries 获取正在执行的查询
+func (s *adminService) RunningQueries() []model.RunningQuery {
+	return sql.RunningQueries()
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// ErrAuditFileNotConfigured 未配置 JSONL 审计文件，无法校验哈希链
+var ErrAuditFileNotConfigured = errors.New("audit file sink is not configured")
+
+// defaultAuditQueryLimit 审计查询默认返回的记录数
+const defaultAuditQueryLimit = 100
+
+// AuditService 审计日志服务接口
+type AuditService interface {
+	// 记录被拒绝的请求（权限不足）
+	RecordDenial(ctx context.Context, record *model.AuditRecord)
+
+	// 按条件查询审计记录（最新的在前），优先查询数据库审计表
+	Query(ctx context.Context, req *model.AuditQueryRequest) (*model.AuditQueryResponse, error)
+
+	// 校验 JSONL 审计文件的哈希链
+	Verify() (*model.AuditVerifyResult, error)
+
+	// 停止记录审计日志并关闭审计文件
+	Close()
+}
+
+// auditQuerier 可查询的审计存储
+type auditQuerier interface {
+	QueryAudit(req *model.AuditQueryRequest, limit int) ([]model.AuditRecord, error)
+}
+
+// auditService 审计日志服务实现
+type auditService struct {
+	trail   *sql.AuditTrail
+	table   *repository.AuditTable
+	file    *repository.AuditFile
+	querier auditQuerier
+	detach  func()
+}
+
+// NewAuditService 创建审计日志服务，并为数据库连接上的所有写操作启用审计
+func NewAuditService(repos *repository.Repositories, cfg *config.SQLConfig) (AuditService, error) {
+	service := &auditService{}
+
+	// JSONL 文件先于数据库写入，数据库中的记录也带有哈希
+	var sinks []sql.AuditSink
+	if cfg.Audit.HasSink(config.AuditSinkFile) {
+		file, err := repository.OpenAuditFile(cfg.Audit.File)
+		if err != nil {
+			return nil, err
+		}
+		service.file = file
+		service.querier = file
+		sinks = append(sinks, file)
+	}
+	if cfg.Audit.HasSink(config.AuditSinkDatabase) {
+		table, err := repository.NewAuditTable(repos.GetDB(), cfg.Audit.Table)
+		if err != nil {
+			service.closeFile()
+			return nil, err
+		}
+		service.table = table
+		service.querier = table
+		sinks = append(sinks, table)
+	}
+
+	trail, err := sql.NewAuditTrail(&cfg.Audit, sinks...)
+	if err != nil {
+		service.closeFile()
+		return nil, err
+	}
+	service.trail = trail
+	service.detach = sql.AttachAuditTrail(repos.GetDB(), trail)
+	return service, nil
+}
+
+// RecordDenial 记录被拒绝的请求，未指定操作类型时取权限名的最后一段（如 sql.update、resource.<name>.delete）
+func (s *auditService) RecordDenial(ctx context.Context, record *model.AuditRecord) {
+	if record.Action == "" {
+		record.Action = record.Permission[strings.LastIndexByte(record.Permission, '.')+1:]
+	}
+	record.Outcome = model.AuditOutcomeDenied
+	s.trail.Record(ctx, record)
+}
+
+// Query 按条件查询审计记录
+func (s *auditService) Query(ctx context.Context, req *model.AuditQueryRequest) (*model.AuditQueryResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultAuditQueryLimit
+	}
+
+	records, err := s.querier.QueryAudit(req, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit records: %w", err)
+	}
+
+	response := &model.AuditQueryResponse{Records: records}
+	if len(records) == limit {
+		response.NextBeforeID = records[len(records)-1].ID
+	}
+	return response, nil
+}
+
+// Verify 校验 JSONL 审计文件的哈希链
+func (s *auditService) Verify() (*model.AuditVerifyResult, error) {
+	if s.file == nil {
+		return nil, ErrAuditFileNotConfigured
+	}
+	return s.file.VerifyAudit()
+}
+
+// Close 停止记录审计日志并关闭审计文件
+func (s *auditService) Close() {
+	s.detach()
+	s.closeFile()
+}
+
+// closeFile 关闭审计文件
+func (s *auditService) closeFile() {
+	if s.file != nil {
+		s.file.Close()
+	}
+}
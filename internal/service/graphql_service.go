@@ -0,0 +1,1065 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"sql2api/internal/config"
+	"sql2api/internal/graphql"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// GraphQLService GraphQL 查询服务接口
+type GraphQLService interface {
+	// 执行 GraphQL 请求，allow 判断调用方是否拥有指定权限
+	Execute(ctx context.Context, req *model.GraphQLRequest, allow func(permission string) bool) *model.GraphQLResponse
+
+	// 生成调用方可用部分的 GraphQL SDL
+	SDL(ctx context.Context, allow func(permission string) bool) (string, error)
+}
+
+// graphQLBatchSize 关联字段批量查询时每条 IN 条件的最大取值数
+const graphQLBatchSize = 50
+
+// graphQLComparisonOperators 过滤条件支持的运算符（与 QueryBuilder 的条件运算符一致）
+var graphQLComparisonOperators = []string{"eq", "neq", "gt", "gte", "lt", "lte", "in", "nin", "is_null"}
+
+// graphQLTextOperators 字符串列额外支持的运算符
+var graphQLTextOperators = []string{"like", "nlike", "ilike"}
+
+// gqlRootField 根字段定义
+type gqlRootField struct {
+	table *apiTable
+	kind  string // list、by_pk、insert、update、delete
+}
+
+// gqlSchema 由表结构生成的 GraphQL 模式
+type gqlSchema struct {
+	tables    []*apiTable
+	queries   map[string]gqlRootField
+	mutations map[string]gqlRootField
+}
+
+// graphQLService GraphQL 查询服务实现
+type graphQLService struct {
+	sqlEngine     *sql.SQLEngine
+	config        *config.SQLConfig
+	builder       *sql.QueryBuilder
+	schemaService SchemaService
+}
+
+// NewGraphQLService 创建 GraphQL 查询服务
+func NewGraphQLService(repos *repository.Repositories, cfg *config.SQLConfig, schemaService SchemaService) (GraphQLService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	if schemaService == nil {
+		return nil, errors.New("schema service cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	return &graphQLService{
+		sqlEngine:     engine,
+		config:        cfg,
+		builder:       newTableQueryBuilder(engine.GetDatabaseType(), cfg),
+		schemaService: schemaService,
+	}, nil
+}
+
+// Execute 执行 GraphQL 请求
+func (s *graphQLService) Execute(ctx context.Context, req *model.GraphQLRequest, allow func(permission string) bool) *model.GraphQLResponse {
+	doc, err := graphql.Parse(req.Query)
+	if err != nil {
+		return requestError(err)
+	}
+
+	op, err := doc.Operation(req.OperationName)
+	if err != nil {
+		return requestError(err)
+	}
+	if op.Type == "subscription" {
+		return requestError(errors.New("subscriptions are not supported"))
+	}
+
+	variables, err := op.CoerceVariables(req.Variables)
+	if err != nil {
+		return requestError(err)
+	}
+
+	schema, err := s.buildSchema(ctx)
+	if err != nil {
+		return requestError(err)
+	}
+
+	exec := &gqlExecution{
+		service:   s,
+		schema:    schema,
+		doc:       doc,
+		variables: variables,
+		allow:     allow,
+	}
+
+	rootType := "Query"
+	if op.Type == "mutation" {
+		rootType = "Mutation"
+	}
+	fields, err := doc.CollectFields(rootType, op.Selections, variables)
+	if err != nil {
+		return requestError(err)
+	}
+
+	// 根字段依次执行，变更操作按请求顺序串行
+	data := graphql.NewObject()
+	for _, field := range fields {
+		key := field.ResponseKey()
+		if field.Name == "__typename" {
+			data.Set(key, rootType)
+			continue
+		}
+
+		value, err := exec.resolveRoot(ctx, op.Type, field)
+		if err != nil {
+			exec.addError(err, field, []interface{}{key})
+			data.Set(key, nil)
+			continue
+		}
+		data.Set(key, value)
+	}
+
+	return &model.GraphQLResponse{Data: data, Errors: exec.errors}
+}
+
+// requestError 请求级错误（解析或验证失败），不返回 data
+func requestError(err error) *model.GraphQLResponse {
+	gqlErr := model.GraphQLError{Message: err.Error()}
+	var syntaxErr *graphql.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		gqlErr.Message = syntaxErr.Message
+		gqlErr.Locations = []model.GraphQLLocation{{Line: syntaxErr.Line, Column: syntaxErr.Column}}
+	}
+	return &model.GraphQLResponse{Errors: []model.GraphQLError{gqlErr}}
+}
+
+// buildSchema 根据允许访问的表结构生成 GraphQL 模式
+func (s *graphQLService) buildSchema(ctx context.Context) (*gqlSchema, error) {
+	tables, err := loadAPITables(ctx, s.schemaService)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &gqlSchema{
+		tables:    tables,
+		queries:   make(map[string]gqlRootField),
+		mutations: make(map[string]gqlRootField),
+	}
+	for _, t := range tables {
+		schema.queries[t.typeName] = gqlRootField{table: t, kind: "list"}
+		if len(t.primaryKey) > 0 {
+			schema.queries[t.typeName+"_by_pk"] = gqlRootField{table: t, kind: "by_pk"}
+		}
+		schema.mutations["insert_"+t.typeName] = gqlRootField{table: t, kind: "insert"}
+		schema.mutations["update_"+t.typeName] = gqlRootField{table: t, kind: "update"}
+		schema.mutations["delete_"+t.typeName] = gqlRootField{table: t, kind: "delete"}
+	}
+
+	return schema, nil
+}
+
+// ===== 执行 =====
+
+// gqlExecution 单次 GraphQL 请求的执行状态
+type gqlExecution struct {
+	service   *graphQLService
+	schema    *gqlSchema
+	doc       *graphql.Document
+	variables map[string]interface{}
+	allow     func(permission string) bool
+	errors    []model.GraphQLError
+}
+
+// addError 记录字段错误
+func (e *gqlExecution) addError(err error, field *graphql.Field, path []interface{}) {
+	e.errors = append(e.errors, model.GraphQLError{
+		Message:   err.Error(),
+		Locations: []model.GraphQLLocation{{Line: field.Line, Column: field.Column}},
+		Path:      path,
+	})
+}
+
+// requirePermission 检查调用方权限（与 /sql 相同的 sql.<action> 权限）
+func (e *gqlExecution) requirePermission(permission string) error {
+	if e.allow == nil || !e.allow(permission) {
+		return fmt.Errorf("insufficient permissions: %s required", permission)
+	}
+	return nil
+}
+
+// resolveRoot 执行根字段
+func (e *gqlExecution) resolveRoot(ctx context.Context, opType string, field *graphql.Field) (interface{}, error) {
+	fields := e.schema.queries
+	if opType == "mutation" {
+		fields = e.schema.mutations
+	}
+	root, ok := fields[field.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %s", field.Name)
+	}
+
+	args, err := field.ArgumentValues(e.variables)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root.kind {
+	case "list":
+		if err := e.requirePermission("sql.query"); err != nil {
+			return nil, err
+		}
+		return e.resolveList(ctx, root.table, field, args)
+
+	case "by_pk":
+		if err := e.requirePermission("sql.query"); err != nil {
+			return nil, err
+		}
+		return e.resolveByPK(ctx, root.table, field, args)
+
+	case "insert":
+		if err := e.requirePermission("sql.insert"); err != nil {
+			return nil, err
+		}
+		return e.resolveInsert(ctx, root.table, field, args)
+
+	case "update":
+		if err := e.requirePermission("sql.update"); err != nil {
+			return nil, err
+		}
+		return e.resolveUpdate(ctx, root.table, field, args)
+
+	case "delete":
+		if err := e.requirePermission("sql.delete"); err != nil {
+			return nil, err
+		}
+		return e.resolveDelete(ctx, root.table, field, args)
+	}
+
+	return nil, fmt.Errorf("unknown field %s", field.Name)
+}
+
+// resolveList 执行列表查询
+func (e *gqlExecution) resolveList(ctx context.Context, t *apiTable, field *graphql.Field, args map[string]interface{}) (interface{}, error) {
+	if err := checkArguments(args, "where", "order_by", "limit", "offset"); err != nil {
+		return nil, err
+	}
+
+	where, err := e.whereConditions(t, args["where"])
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := e.orderByClauses(t, args["order_by"])
+	if err != nil {
+		return nil, err
+	}
+	limit, offset, err := pagingArguments(args)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := e.collectObjectFields(t, field)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := e.fetchRows(ctx, t, e.selectColumns(t, fields), where, orderBy, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := e.resolveObjects(ctx, t, fields, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]interface{}, len(objects))
+	for i, object := range objects {
+		list[i] = object
+	}
+	return list, nil
+}
+
+// resolveByPK 按主键查询单条记录
+func (e *gqlExecution) resolveByPK(ctx context.Context, t *apiTable, field *graphql.Field, args map[string]interface{}) (interface{}, error) {
+	if err := checkArguments(args, t.primaryKey...); err != nil {
+		return nil, err
+	}
+
+	where := make(map[string]interface{}, len(t.primaryKey))
+	for _, column := range t.primaryKey {
+		value, ok := args[column]
+		if !ok || value == nil {
+			return nil, fmt.Errorf("argument %s is required", column)
+		}
+		where[column] = normalizeNumber(value)
+	}
+
+	fields, err := e.collectObjectFields(t, field)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := e.fetchRows(ctx, t, e.selectColumns(t, fields), where, nil, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	objects, err := e.resolveObjects(ctx, t, fields, rows)
+	if err != nil {
+		return nil, err
+	}
+	return objects[0], nil
+}
+
+// collectObjectFields 收集对象字段的子选择
+func (e *gqlExecution) collectObjectFields(t *apiTable, field *graphql.Field) ([]*graphql.Field, error) {
+	if len(field.Selections) == 0 {
+		return nil, fmt.Errorf("field %s of type %s must have a selection of subfields", field.Name, t.typeName)
+	}
+	return e.doc.CollectFields(t.typeName, field.Selections, e.variables)
+}
+
+// selectColumns 计算需要查询的列：选中的标量列和关联字段需要的连接列
+func (e *gqlExecution) selectColumns(t *apiTable, fields []*graphql.Field, extra ...string) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	add := func(column string) {
+		if !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+
+	for _, field := range fields {
+		if _, ok := t.columnMap[field.Name]; ok {
+			add(field.Name)
+		} else if rel, ok := t.relation(field.Name); ok {
+			add(rel.localColumn)
+		}
+	}
+	for _, column := range extra {
+		add(column)
+	}
+
+	// 只选择了 __typename 时仍需要一个列
+	if len(columns) == 0 && len(t.columns) > 0 {
+		add(t.columns[0].Name)
+	}
+	return columns
+}
+
+// fetchRows 通过 QueryBuilder 构建查询并执行
+func (e *gqlExecution) fetchRows(ctx context.Context, t *apiTable, columns []string, where map[string]interface{}, orderBy []model.OrderByClause, limit, offset int) ([]map[string]interface{}, error) {
+	query, params, err := e.service.builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:   t.table,
+		Action:  "select",
+		Fields:  columns,
+		Where:   where,
+		OrderBy: orderBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 || offset > 0 {
+		if limit <= 0 {
+			limit = math.MaxInt32
+		}
+		query = e.service.builder.ApplyPagination(query, offset, limit)
+	}
+
+	result, err := e.service.sqlEngine.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+// resolveObjects 按选择集构建对象，关联字段对所有行批量查询
+func (e *gqlExecution) resolveObjects(ctx context.Context, t *apiTable, fields []*graphql.Field, rows []map[string]interface{}) ([]*graphql.Object, error) {
+	relations := make(map[string]map[string]interface{})
+
+	for _, field := range fields {
+		if field.Name == "__typename" {
+			continue
+		}
+		if _, ok := t.columnMap[field.Name]; ok {
+			if len(field.Selections) > 0 {
+				return nil, fmt.Errorf("field %s of type %s cannot have a selection of subfields", field.Name, t.typeName)
+			}
+			continue
+		}
+
+		rel, ok := t.relation(field.Name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %s on type %s", field.Name, t.typeName)
+		}
+		values, err := e.resolveRelation(ctx, rel, field, rows)
+		if err != nil {
+			return nil, err
+		}
+		relations[field.ResponseKey()] = values
+	}
+
+	objects := make([]*graphql.Object, len(rows))
+	for i, row := range rows {
+		object := graphql.NewObject()
+		for _, field := range fields {
+			key := field.ResponseKey()
+			switch {
+			case field.Name == "__typename":
+				object.Set(key, t.typeName)
+			case relations[key] != nil:
+				rel, _ := t.relation(field.Name)
+				value, ok := relations[key][relationKey(rowValue(row, rel.localColumn))]
+				if !ok && rel.many {
+					value = []interface{}{}
+				}
+				object.Set(key, value)
+			default:
+				object.Set(key, rowValue(row, field.Name))
+			}
+		}
+		objects[i] = object
+	}
+	return objects, nil
+}
+
+// resolveRelation 批量查询所有父行的关联记录，返回连接值到结果（对象或列表）的映射
+func (e *gqlExecution) resolveRelation(ctx context.Context, rel *apiRelation, field *graphql.Field, parents []map[string]interface{}) (map[string]interface{}, error) {
+	args, err := field.ArgumentValues(e.variables)
+	if err != nil {
+		return nil, err
+	}
+	if rel.many {
+		err = checkArguments(args, "where", "order_by", "limit", "offset")
+	} else {
+		err = checkArguments(args)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	where, err := e.whereConditions(rel.target, args["where"])
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := where[rel.remoteColumn]; exists {
+		return nil, fmt.Errorf("cannot filter %s on relationship column %s", field.Name, rel.remoteColumn)
+	}
+	orderBy, err := e.orderByClauses(rel.target, args["order_by"])
+	if err != nil {
+		return nil, err
+	}
+	limit, offset, err := pagingArguments(args)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := e.collectObjectFields(rel.target, field)
+	if err != nil {
+		return nil, err
+	}
+	columns := e.selectColumns(rel.target, fields, rel.remoteColumn)
+
+	// 收集去重后的连接值
+	var keys []interface{}
+	seen := make(map[string]bool)
+	for _, parent := range parents {
+		value := rowValue(parent, rel.localColumn)
+		if value == nil || seen[relationKey(value)] {
+			continue
+		}
+		seen[relationKey(value)] = true
+		keys = append(keys, value)
+	}
+
+	// 按批次使用 IN 条件查询，避免 N+1 查询
+	var rows []map[string]interface{}
+	for start := 0; start < len(keys); start += graphQLBatchSize {
+		end := start + graphQLBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		batchWhere := make(map[string]interface{}, len(where)+1)
+		for column, condition := range where {
+			batchWhere[column] = condition
+		}
+		batchWhere[rel.remoteColumn] = map[string]interface{}{"in": keys[start:end]}
+
+		batch, err := e.fetchRows(ctx, rel.target, columns, batchWhere, orderBy, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, batch...)
+	}
+
+	objects, err := e.resolveObjects(ctx, rel.target, fields, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按连接值分组，分页参数对每个父行分别生效
+	grouped := make(map[string][]interface{})
+	for i, row := range rows {
+		key := relationKey(rowValue(row, rel.remoteColumn))
+		grouped[key] = append(grouped[key], objects[i])
+	}
+
+	values := make(map[string]interface{}, len(grouped))
+	for key, group := range grouped {
+		if !rel.many {
+			values[key] = group[0]
+			continue
+		}
+		if offset >= len(group) {
+			group = []interface{}{}
+		} else {
+			group = group[offset:]
+		}
+		if limit > 0 && limit < len(group) {
+			group = group[:limit]
+		}
+		values[key] = group
+	}
+	return values, nil
+}
+
+// resolveInsert 插入一条或多条记录
+func (e *gqlExecution) resolveInsert(ctx context.Context, t *apiTable, field *graphql.Field, args map[string]interface{}) (interface{}, error) {
+	if err := checkArguments(args, "objects", "object"); err != nil {
+		return nil, err
+	}
+
+	var objects []interface{}
+	if object, ok := args["object"]; ok && object != nil {
+		objects = append(objects, object)
+	}
+	if list, ok := args["objects"].([]interface{}); ok {
+		objects = append(objects, list...)
+	}
+	if len(objects) == 0 {
+		return nil, errors.New("argument objects is required")
+	}
+
+	records := make([]map[string]interface{}, 0, len(objects))
+	for _, object := range objects {
+		record, err := e.columnValues(t, object)
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 {
+			return nil, errors.New("insert objects cannot be empty")
+		}
+		records = append(records, record)
+	}
+
+	var query string
+	var params map[string]interface{}
+	var err error
+	if len(records) == 1 {
+		query, params, err = e.service.builder.BuildStructuredQuery(&model.StructuredQuery{
+			Table:  t.table,
+			Action: "insert",
+			Data:   records[0],
+		})
+	} else {
+		// 多条记录使用单条多行 INSERT，要求字段一致
+		if err := sameColumns(records); err != nil {
+			return nil, err
+		}
+		query, params, err = e.service.builder.BuildBatchInsertQuery(&model.BatchInsertRequest{
+			Table: t.table,
+			Data:  records,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.service.sqlEngine.ExecuteSQL(sql.WithChangeRows(ctx, records...), query, params)
+	if err != nil {
+		return nil, err
+	}
+	return e.mutationResponse(field, result)
+}
+
+// resolveUpdate 按条件更新记录
+func (e *gqlExecution) resolveUpdate(ctx context.Context, t *apiTable, field *graphql.Field, args map[string]interface{}) (interface{}, error) {
+	if err := checkArguments(args, "where", "_set"); err != nil {
+		return nil, err
+	}
+
+	where, err := e.whereConditions(t, args["where"])
+	if err != nil {
+		return nil, err
+	}
+	if len(where) == 0 {
+		return nil, errors.New("argument where is required for update")
+	}
+	data, err := e.columnValues(t, args["_set"])
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("argument _set is required for update")
+	}
+
+	query, params, err := e.service.builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  t.table,
+		Action: "update",
+		Data:   data,
+		Where:  where,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.service.sqlEngine.ExecuteSQL(sql.WithChangeKeys(ctx, sql.EqualityKeys(where)...), query, params)
+	if err != nil {
+		return nil, err
+	}
+	return e.mutationResponse(field, result)
+}
+
+// resolveDelete 按条件删除记录
+func (e *gqlExecution) resolveDelete(ctx context.Context, t *apiTable, field *graphql.Field, args map[string]interface{}) (interface{}, error) {
+	if err := checkArguments(args, "where"); err != nil {
+		return nil, err
+	}
+
+	where, err := e.whereConditions(t, args["where"])
+	if err != nil {
+		return nil, err
+	}
+	if len(where) == 0 {
+		return nil, errors.New("argument where is required for delete")
+	}
+
+	query, params, err := e.service.builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  t.table,
+		Action: "delete",
+		Where:  where,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = softDeleteContext(sql.WithChangeKeys(ctx, sql.EqualityKeys(where)...), e.service.config, t.table)
+	result, err := e.service.sqlEngine.ExecuteSQL(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return e.mutationResponse(field, result)
+}
+
+// mutationResponse 构建 mutation_response 对象
+func (e *gqlExecution) mutationResponse(field *graphql.Field, result *sql.ExecuteResult) (interface{}, error) {
+	if len(field.Selections) == 0 {
+		return nil, fmt.Errorf("field %s of type mutation_response must have a selection of subfields", field.Name)
+	}
+	fields, err := e.doc.CollectFields("mutation_response", field.Selections, e.variables)
+	if err != nil {
+		return nil, err
+	}
+
+	object := graphql.NewObject()
+	for _, f := range fields {
+		switch f.Name {
+		case "affected_rows":
+			object.Set(f.ResponseKey(), result.AffectedRows)
+		case "last_insert_id":
+			if result.LastInsertID != 0 {
+				object.Set(f.ResponseKey(), result.LastInsertID)
+			} else {
+				object.Set(f.ResponseKey(), nil)
+			}
+		case "__typename":
+			object.Set(f.ResponseKey(), "mutation_response")
+		default:
+			return nil, fmt.Errorf("unknown field %s on type mutation_response", f.Name)
+		}
+	}
+	return object, nil
+}
+
+// whereConditions 将过滤参数转换为 StructuredQuery 的运算符条件
+func (e *gqlExecution) whereConditions(t *apiTable, value interface{}) (map[string]interface{}, error) {
+	where := make(map[string]interface{})
+	if value == nil {
+		return where, nil
+	}
+
+	filter, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("argument where must be an object")
+	}
+
+	for name, condition := range filter {
+		column, ok := t.columnMap[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s in where on type %s", name, t.typeName)
+		}
+		operators, ok := condition.(map[string]interface{})
+		if !ok || len(operators) == 0 {
+			return nil, fmt.Errorf("where condition for %s must be an object of operators", name)
+		}
+
+		allowed := comparisonOperators(column)
+		converted := make(map[string]interface{}, len(operators))
+		for op, operand := range operators {
+			if !containsString(allowed, op) {
+				return nil, fmt.Errorf("unsupported operator %s for %s", op, name)
+			}
+			if list, ok := operand.([]interface{}); ok {
+				items := make([]interface{}, len(list))
+				for i, item := range list {
+					items[i] = normalizeNumber(item)
+				}
+				operand = items
+			} else {
+				operand = normalizeNumber(operand)
+			}
+			converted[op] = operand
+		}
+		where[column.Name] = converted
+	}
+	return where, nil
+}
+
+// orderByClauses 解析排序参数，支持单个对象或对象列表（如 [{name: asc}, {id: desc}]）
+func (e *gqlExecution) orderByClauses(t *apiTable, value interface{}) ([]model.OrderByClause, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		items = []interface{}{v}
+	default:
+		return nil, errors.New("argument order_by must be an object or a list of objects")
+	}
+
+	var clauses []model.OrderByClause
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("argument order_by must be an object or a list of objects")
+		}
+
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if _, ok := t.columnMap[name]; !ok {
+				return nil, fmt.Errorf("unknown column %s in order_by on type %s", name, t.typeName)
+			}
+			direction, _ := object[name].(string)
+			if direction != "asc" && direction != "desc" {
+				return nil, fmt.Errorf("order_by direction for %s must be asc or desc", name)
+			}
+			clauses = append(clauses, model.OrderByClause{Field: name, Order: direction})
+		}
+	}
+	return clauses, nil
+}
+
+// columnValues 将输入对象转换为列值，只允许公开的列
+func (e *gqlExecution) columnValues(t *apiTable, value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("input must be an object")
+	}
+
+	data := make(map[string]interface{}, len(object))
+	for name, item := range object {
+		if _, ok := t.columnMap[name]; !ok {
+			return nil, fmt.Errorf("unknown column %s on type %s", name, t.typeName)
+		}
+		data[name] = normalizeNumber(item)
+	}
+	return data, nil
+}
+
+// ===== 辅助函数 =====
+
+// checkArguments 检查是否有未定义的参数
+func checkArguments(args map[string]interface{}, allowed ...string) error {
+	for name := range args {
+		if !containsString(allowed, name) {
+			return fmt.Errorf("unknown argument %s", name)
+		}
+	}
+	return nil
+}
+
+// pagingArguments 解析 limit 和 offset 参数
+func pagingArguments(args map[string]interface{}) (int, int, error) {
+	limit, err := intArgument(args, "limit")
+	if err != nil {
+		return 0, 0, err
+	}
+	offset, err := intArgument(args, "offset")
+	if err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
+
+// intArgument 解析非负整数参数（JSON 变量中的数字为 float64）
+func intArgument(args map[string]interface{}, name string) (int, error) {
+	value, ok := args[name]
+	if !ok || value == nil {
+		return 0, nil
+	}
+
+	var n int64
+	switch v := value.(type) {
+	case int64:
+		n = v
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("argument %s must be an integer", name)
+		}
+		n = int64(v)
+	default:
+		return 0, fmt.Errorf("argument %s must be an integer", name)
+	}
+	if n < 0 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("argument %s is out of range", name)
+	}
+	return int(n), nil
+}
+
+// normalizeNumber 将 JSON 变量中的整数值 float64 转为 int64
+func normalizeNumber(value interface{}) interface{} {
+	if f, ok := value.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return value
+}
+
+// sameColumns 检查多条插入记录的字段是否一致
+func sameColumns(records []map[string]interface{}) error {
+	for _, record := range records[1:] {
+		if len(record) != len(records[0]) {
+			return errors.New("all insert objects must have the same fields")
+		}
+		for column := range records[0] {
+			if _, ok := record[column]; !ok {
+				return errors.New("all insert objects must have the same fields")
+			}
+		}
+	}
+	return nil
+}
+
+// comparisonOperators 列支持的过滤运算符
+func comparisonOperators(column sql.ColumnSchema) []string {
+	if graphQLScalar(column) == "String" {
+		return append(append([]string{}, graphQLComparisonOperators...), graphQLTextOperators...)
+	}
+	return graphQLComparisonOperators
+}
+
+// graphQLScalar 将数据库列类型映射为 GraphQL 标量类型
+func graphQLScalar(column sql.ColumnSchema) string {
+	schema := columnTypeSchema(column)
+	switch schema["type"] {
+	case "integer":
+		return "Int"
+	case "number":
+		return "Float"
+	case "boolean":
+		return "Boolean"
+	}
+	return "String"
+}
+
+// rowValue 读取行中的列值（忽略大小写，兼容 Oracle 大写列名）
+func rowValue(row map[string]interface{}, column string) interface{} {
+	if value, ok := row[column]; ok {
+		return value
+	}
+	for key, value := range row {
+		if strings.EqualFold(key, column) {
+			return value
+		}
+	}
+	return nil
+}
+
+// relationKey 连接值的比较键（不同驱动返回的整数类型可能不同）
+func relationKey(value interface{}) string {
+	return fmt.Sprint(normalizeNumber(value))
+}
+
+// containsString 检查字符串是否在列表中
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ===== SDL =====
+
+// SDL 生成调用方可用部分的 GraphQL SDL（代替内省查询）
+func (s *graphQLService) SDL(ctx context.Context, allow func(permission string) bool) (string, error) {
+	schema, err := s.buildSchema(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	can := func(permission string) bool {
+		return allow != nil && allow(permission)
+	}
+
+	var sb strings.Builder
+
+	// 过滤条件输入类型
+	for _, scalar := range []string{"Boolean", "Float", "Int", "String"} {
+		fmt.Fprintf(&sb, "input %s_comparison_exp {\n", scalar)
+		operators := graphQLComparisonOperators
+		if scalar == "String" {
+			operators = comparisonOperators(sql.ColumnSchema{Type: "text"})
+		}
+		for _, op := range operators {
+			switch op {
+			case "in", "nin":
+				fmt.Fprintf(&sb, "  %s: [%s!]\n", op, scalar)
+			case "is_null":
+				fmt.Fprintf(&sb, "  %s: Boolean\n", op)
+			default:
+				fmt.Fprintf(&sb, "  %s: %s\n", op, scalar)
+			}
+		}
+		sb.WriteString("}\n\n")
+	}
+	sb.WriteString("enum order_by {\n  asc\n  desc\n}\n\n")
+	sb.WriteString("type mutation_response {\n  affected_rows: Int!\n  last_insert_id: Int\n}\n\n")
+
+	var queries, mutations []string
+	for _, t := range schema.tables {
+		// 对象类型
+		fmt.Fprintf(&sb, "type %s {\n", t.typeName)
+		for _, column := range t.columns {
+			fmt.Fprintf(&sb, "  %s: %s\n", column.Name, sdlColumnType(column))
+		}
+		for _, rel := range t.relations {
+			if rel.many {
+				fmt.Fprintf(&sb, "  %s(where: %s_bool_exp, order_by: [%s_order_by!], limit: Int, offset: Int): [%s!]!\n",
+					rel.name, rel.target.typeName, rel.target.typeName, rel.target.typeName)
+			} else {
+				fmt.Fprintf(&sb, "  %s: %s\n", rel.name, rel.target.typeName)
+			}
+		}
+		sb.WriteString("}\n\n")
+
+		// 输入类型
+		fmt.Fprintf(&sb, "input %s_bool_exp {\n", t.typeName)
+		for _, column := range t.columns {
+			fmt.Fprintf(&sb, "  %s: %s_comparison_exp\n", column.Name, graphQLScalar(column))
+		}
+		sb.WriteString("}\n\n")
+
+		fmt.Fprintf(&sb, "input %s_order_by {\n", t.typeName)
+		for _, column := range t.columns {
+			fmt.Fprintf(&sb, "  %s: order_by\n", column.Name)
+		}
+		sb.WriteString("}\n\n")
+
+		for _, suffix := range []string{"insert_input", "set_input"} {
+			fmt.Fprintf(&sb, "input %s_%s {\n", t.typeName, suffix)
+			for _, column := range t.columns {
+				fmt.Fprintf(&sb, "  %s: %s\n", column.Name, graphQLScalar(column))
+			}
+			sb.WriteString("}\n\n")
+		}
+
+		// 根字段
+		if can("sql.query") {
+			queries = append(queries, fmt.Sprintf("  %s(where: %s_bool_exp, order_by: [%s_order_by!], limit: Int, offset: Int): [%s!]!",
+				t.typeName, t.typeName, t.typeName, t.typeName))
+			if len(t.primaryKey) > 0 {
+				var keys []string
+				for _, column := range t.primaryKey {
+					scalar := "String"
+					if schemaColumn, ok := t.columnMap[column]; ok {
+						scalar = graphQLScalar(schemaColumn)
+					}
+					keys = append(keys, fmt.Sprintf("%s: %s!", column, scalar))
+				}
+				queries = append(queries, fmt.Sprintf("  %s_by_pk(%s): %s", t.typeName, strings.Join(keys, ", "), t.typeName))
+			}
+		}
+		if can("sql.insert") {
+			mutations = append(mutations, fmt.Sprintf("  insert_%s(objects: [%s_insert_input!], object: %s_insert_input): mutation_response",
+				t.typeName, t.typeName, t.typeName))
+		}
+		if can("sql.update") {
+			mutations = append(mutations, fmt.Sprintf("  update_%s(where: %s_bool_exp!, _set: %s_set_input!): mutation_response",
+				t.typeName, t.typeName, t.typeName))
+		}
+		if can("sql.delete") {
+			mutations = append(mutations, fmt.Sprintf("  delete_%s(where: %s_bool_exp!): mutation_response", t.typeName, t.typeName))
+		}
+	}
+
+	// Query 类型不能为空
+	if len(queries) == 0 {
+		queries = append(queries, "  _empty: Boolean")
+	}
+	fmt.Fprintf(&sb, "type Query {\n%s\n}\n", strings.Join(queries, "\n"))
+	if len(mutations) > 0 {
+		fmt.Fprintf(&sb, "\ntype Mutation {\n%s\n}\n", strings.Join(mutations, "\n"))
+	}
+
+	return sb.String(), nil
+}
+
+// sdlColumnType 列在 SDL 中的类型，非空列带 !
+func sdlColumnType(column sql.ColumnSchema) string {
+	if column.Nullable {
+		return graphQLScalar(column)
+	}
+	return graphQLScalar(column) + "!"
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"sql2api/internal/model"
+)
+
+func TestGraphQLService_TimestampColumns(t *testing.T) {
+	repos := newTestRepositories(t,
+		`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, created_at TEXT, updated_at TEXT)`,
+		`CREATE TABLE logs (id INTEGER PRIMARY KEY, item_id INTEGER REFERENCES items(id), message TEXT, created_at TEXT)`,
+		`INSERT INTO items (id, name, created_at) VALUES (1, 'widget', '2024-01-01')`,
+		`INSERT INTO logs (id, item_id, message, created_at) VALUES (10, 1, 'created', '2024-01-02')`,
+	)
+	cfg := newTestSQLConfig("items", "logs")
+	schemaService, err := NewSchemaService(repos, cfg)
+	if err != nil {
+		t.Fatalf("NewSchemaService failed: %v", err)
+	}
+	service, err := NewGraphQLService(repos, cfg, schemaService)
+	if err != nil {
+		t.Fatalf("NewGraphQLService failed: %v", err)
+	}
+	allowAll := func(string) bool { return true }
+
+	execute := func(query string) map[string]interface{} {
+		t.Helper()
+		response := service.Execute(context.Background(), &model.GraphQLRequest{Query: query}, allowAll)
+		if len(response.Errors) > 0 {
+			t.Fatalf("Execute %q failed: %v", query, response.Errors)
+		}
+		encoded, err := json.Marshal(response.Data)
+		if err != nil {
+			t.Fatalf("Failed to encode data: %v", err)
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal(encoded, &data); err != nil {
+			t.Fatalf("Failed to decode data: %v", err)
+		}
+		return data
+	}
+
+	// 时间戳列与正反向关联
+	data := execute(`{ items { id created_at updated_at logs { created_at } } logs { item { name created_at } } }`)
+	items := data["items"].([]interface{})
+	item := items[0].(map[string]interface{})
+	if len(items) != 1 || item["created_at"] != "2024-01-01" {
+		t.Errorf("Unexpected items: %v", items)
+	}
+	if logs := item["logs"].([]interface{}); len(logs) != 1 || logs[0].(map[string]interface{})["created_at"] != "2024-01-02" {
+		t.Errorf("Unexpected nested logs: %v", item["logs"])
+	}
+	parent := data["logs"].([]interface{})[0].(map[string]interface{})["item"].(map[string]interface{})
+	if parent["name"] != "widget" || parent["created_at"] != "2024-01-01" {
+		t.Errorf("Unexpected nested item: %v", parent)
+	}
+
+	// 变更写入时间戳列
+	data = execute(`mutation { insert_items(object: {name: "gadget", created_at: "2024-02-01"}) { affected_rows } }`)
+	if affected := data["insert_items"].(map[string]interface{})["affected_rows"]; affected != float64(1) {
+		t.Errorf("Expected 1 affected row, got %v", affected)
+	}
+	data = execute(`{ items_by_pk(id: 2) { created_at } }`)
+	if created := data["items_by_pk"].(map[string]interface{})["created_at"]; created != "2024-02-01" {
+		t.Errorf("Expected inserted created_at, got %v", created)
+	}
+}
@@ -0,0 +1,607 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"sql2api/internal/config"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// ErrInvalidImportRequest 导入参数无效（格式、列映射、分隔符等）
+var ErrInvalidImportRequest = errors.New("invalid import request")
+
+// 数据导入格式
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// 导入过程中报告的事件类型
+const (
+	ImportEventProgress = "progress" // 一批数据已提交
+	ImportEventRejected = "rejected" // 一行数据被拒绝
+	ImportEventSummary  = "summary"  // 导入结束
+)
+
+// 导入结果状态
+const (
+	ImportStatusCompleted = "completed" // 所有数据已读取，有效的行均已提交
+	ImportStatusAborted   = "aborted"   // 被拒绝的行数超过错误预算
+	ImportStatusFailed    = "failed"    // 读取上传数据或写入数据库失败
+)
+
+// ImportColumn 源字段到表列的映射
+type ImportColumn struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// ImportRequest 数据导入请求
+type ImportRequest struct {
+	Table     string
+	Format    string         // csv 或 ndjson
+	Columns   []ImportColumn // 列映射，为空时按字段名导入所有字段
+	Header    bool           // CSV 第一行是否为表头
+	Delimiter string         // CSV 分隔符，默认为逗号
+	NullValue string         // CSV 中表示 NULL 的值，默认为空字符串
+	ChunkSize int            // 每次提交的行数，0 使用配置的默认值
+	MaxErrors *int           // 允许拒绝的行数，nil 使用配置的默认值，-1 表示不限制
+}
+
+// ImportStats 导入统计
+type ImportStats struct {
+	Rows     int64 `json:"rows"`     // 已读取的数据行数
+	Inserted int64 `json:"inserted"` // 已提交的行数
+	Rejected int64 `json:"rejected"` // 被拒绝的行数
+	Chunks   int64 `json:"chunks"`   // 已提交的批次数
+}
+
+// ImportEvent 导入进度、被拒绝的行和最终结果
+type ImportEvent struct {
+	Type   string `json:"type"`
+	Line   int64  `json:"line,omitempty"`   // 被拒绝的行在上传数据中的行号（从 1 开始）
+	Reason string `json:"reason,omitempty"` // 行被拒绝或导入中止、失败的原因
+	Status string `json:"status,omitempty"` // 导入结果（仅 summary）
+	*ImportStats
+	DurationMs int64 `json:"duration_ms,omitempty"`
+}
+
+// ImportReporter 接收导入进度和被拒绝的行
+type ImportReporter func(event *ImportEvent)
+
+// ImportService 数据导入服务接口
+type ImportService interface {
+	// 导入上传的数据；返回错误时没有读取任何数据行，也没有报告任何事件
+	Import(ctx context.Context, req *ImportRequest, body io.Reader, report ImportReporter) (*ImportEvent, error)
+}
+
+// importService 数据导入服务实现
+type importService struct {
+	sqlEngine     *sql.SQLEngine
+	schemaService SchemaService
+	config        *config.ImportConfig
+}
+
+// NewImportService 创建数据导入服务
+func NewImportService(repos *repository.Repositories, cfg *config.SQLConfig, schemaService SchemaService) (ImportService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	if schemaService == nil {
+		return nil, errors.New("schema service cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	return &importService{
+		sqlEngine:     engine,
+		schemaService: schemaService,
+		config:        &cfg.Import,
+	}, nil
+}
+
+// ParseImportColumns 解析列映射，格式为 "source:target,source"，省略 target 时与 source 相同
+// 无表头的 CSV 按位置对应，空项表示跳过该位置的字段
+func ParseImportColumns(spec string) ([]ImportColumn, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(spec, ",")
+	columns := make([]ImportColumn, 0, len(parts))
+	for _, part := range parts {
+		source, target, found := strings.Cut(part, ":")
+		source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+		if !found {
+			target = source
+		}
+		if found && (source == "" || target == "") {
+			return nil, fmt.Errorf("%w: invalid column mapping %q", ErrInvalidImportRequest, part)
+		}
+		columns = append(columns, ImportColumn{Source: source, Target: target})
+	}
+	return columns, nil
+}
+
+// importRow 一行待写入的数据
+type importRow struct {
+	line    int64
+	columns []string
+	values  []interface{}
+}
+
+// importRowError 无法读取或转换的行
+type importRowError struct {
+	line   int64
+	reason string
+}
+
+// Error 实现 error 接口
+func (e *importRowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.reason)
+}
+
+// importReader 按行读取上传数据，返回目标列名和原始值；io.EOF 表示数据结束，*importRowError 表示该行被拒绝
+type importReader interface {
+	next() (line int64, columns []string, values []interface{}, err error)
+}
+
+// importJob 一次导入的状态
+type importJob struct {
+	service   *importService
+	schema    *sql.TableSchema
+	columns   map[string]*sql.ColumnSchema
+	reader    importReader
+	chunkSize int
+	maxErrors int
+	report    ImportReporter
+
+	stats   ImportStats
+	pending []importRow
+}
+
+// Import 读取上传的数据（可为 gzip 压缩），转换类型后分批写入，每批一个事务
+// 无法解析、类型不符或写入失败的行被拒绝并报告原因，拒绝的行数超过错误预算时中止导入，已提交的批次保留
+func (s *importService) Import(ctx context.Context, req *ImportRequest, body io.Reader, report ImportReporter) (*ImportEvent, error) {
+	chunkSize := req.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = s.config.ChunkSize
+	}
+	if chunkSize < 0 || chunkSize > s.config.MaxChunkSize {
+		return nil, fmt.Errorf("%w: chunk_size must be between 1 and %d", ErrInvalidImportRequest, s.config.MaxChunkSize)
+	}
+
+	maxErrors := s.config.MaxErrors
+	if req.MaxErrors != nil {
+		maxErrors = *req.MaxErrors
+	}
+	if maxErrors < -1 {
+		return nil, fmt.Errorf("%w: max_errors must be -1 or greater", ErrInvalidImportRequest)
+	}
+
+	schema, err := s.schemaService.GetTable(ctx, req.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]*sql.ColumnSchema, len(schema.Columns))
+	for i := range schema.Columns {
+		columns[strings.ToLower(schema.Columns[i].Name)] = &schema.Columns[i]
+	}
+
+	// 映射的目标列必须存在
+	for _, column := range req.Columns {
+		if column.Target != "" && columns[strings.ToLower(column.Target)] == nil {
+			return nil, fmt.Errorf("%w: unknown column %s", ErrInvalidImportRequest, column.Target)
+		}
+	}
+
+	input, err := decompress(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader importReader
+	switch req.Format {
+	case ImportFormatCSV:
+		reader, err = newCSVImportReader(input, req)
+	case ImportFormatNDJSON:
+		reader = newNDJSONImportReader(input, req.Columns)
+	default:
+		err = fmt.Errorf("%w: unsupported format %q", ErrInvalidImportRequest, req.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// CSV 的列在读取表头后即可确定，提前检查
+	if csvReader, ok := reader.(*csvImportReader); ok {
+		for _, target := range csvReader.targets {
+			if target != "" && columns[strings.ToLower(target)] == nil {
+				return nil, fmt.Errorf("%w: unknown column %s", ErrInvalidImportRequest, target)
+			}
+		}
+	}
+
+	if report == nil {
+		report = func(*ImportEvent) {}
+	}
+
+	job := &importJob{
+		service:   s,
+		schema:    schema,
+		columns:   columns,
+		reader:    reader,
+		chunkSize: chunkSize,
+		maxErrors: maxErrors,
+		report:    report,
+	}
+
+	start := time.Now()
+	summary := job.run(ctx)
+	summary.DurationMs = time.Since(start).Milliseconds()
+	return summary, nil
+}
+
+// run 读取并写入所有数据，返回最终结果
+func (j *importJob) run(ctx context.Context) *ImportEvent {
+	for {
+		line, columns, values, err := j.reader.next()
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *importRowError
+		if errors.As(err, &rowErr) {
+			j.stats.Rows++
+			if j.reject(rowErr.line, rowErr.reason) {
+				return j.summary(ImportStatusAborted, j.budgetReason())
+			}
+			continue
+		}
+		if err != nil {
+			return j.summary(ImportStatusFailed, fmt.Sprintf("failed to read data: %v", err))
+		}
+
+		j.stats.Rows++
+		row, reason := j.convert(line, columns, values)
+		if reason != "" {
+			if j.reject(line, reason) {
+				return j.summary(ImportStatusAborted, j.budgetReason())
+			}
+			continue
+		}
+
+		j.pending = append(j.pending, *row)
+		if len(j.pending) >= j.chunkSize {
+			if status, reason := j.flush(ctx); status != "" {
+				return j.summary(status, reason)
+			}
+		}
+	}
+
+	if status, reason := j.flush(ctx); status != "" {
+		return j.summary(status, reason)
+	}
+	return j.summary(ImportStatusCompleted, "")
+}
+
+// convert 将一行原始值转换为表列的值，失败时返回拒绝原因
+func (j *importJob) convert(line int64, columns []string, values []interface{}) (*importRow, string) {
+	row := &importRow{
+		line:    line,
+		columns: make([]string, 0, len(columns)),
+		values:  make([]interface{}, 0, len(values)),
+	}
+
+	seen := make(map[string]bool, len(columns))
+	for i, name := range columns {
+		column := j.columns[strings.ToLower(name)]
+		if column == nil {
+			return nil, fmt.Sprintf("unknown column %s", name)
+		}
+		if seen[column.Name] {
+			return nil, fmt.Sprintf("duplicate column %s", column.Name)
+		}
+		seen[column.Name] = true
+
+		value, err := coerceColumnValue(column, values[i], j.service.sqlEngine.GetDatabaseType())
+		if err != nil {
+			return nil, fmt.Sprintf("column %s: %v", column.Name, err)
+		}
+		row.columns = append(row.columns, column.Name)
+		row.values = append(row.values, value)
+	}
+
+	if len(row.columns) == 0 {
+		return nil, "no columns to import"
+	}
+	return row, ""
+}
+
+// flush 写入待提交的行；列相同的行一起写入，写入失败时逐行重试以找出被拒绝的行
+// 返回非空状态表示导入应当结束
+func (j *importJob) flush(ctx context.Context) (string, string) {
+	if len(j.pending) == 0 {
+		return "", ""
+	}
+	rows := j.pending
+	j.pending = j.pending[:0]
+
+	// 按列分组，保持首次出现的顺序（NDJSON 的各行可能包含不同的字段）
+	var order []string
+	groups := make(map[string][]importRow)
+	for _, row := range rows {
+		key := strings.Join(row.columns, "\x00")
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		values := make([][]interface{}, len(group))
+		for i, row := range group {
+			values[i] = row.values
+		}
+
+		result, err := j.service.sqlEngine.BulkInsert(ctx, j.schema.Name, group[0].columns, values)
+		if err == nil {
+			j.stats.Inserted += result.AffectedRows
+			continue
+		}
+		if ctx.Err() != nil {
+			return ImportStatusFailed, fmt.Sprintf("failed to insert rows: %v", err)
+		}
+
+		// 逐行写入，找出导致失败的行
+		for _, row := range group {
+			result, err := j.service.sqlEngine.BulkInsert(ctx, j.schema.Name, row.columns, [][]interface{}{row.values})
+			if err == nil {
+				j.stats.Inserted += result.AffectedRows
+				continue
+			}
+			if ctx.Err() != nil {
+				return ImportStatusFailed, fmt.Sprintf("failed to insert rows: %v", err)
+			}
+			if j.reject(row.line, err.Error()) {
+				return ImportStatusAborted, j.budgetReason()
+			}
+		}
+	}
+
+	j.stats.Chunks++
+	j.report(&ImportEvent{Type: ImportEventProgress, ImportStats: j.snapshot()})
+	return "", ""
+}
+
+// reject 报告被拒绝的行，超过错误预算时返回 true
+func (j *importJob) reject(line int64, reason string) bool {
+	j.stats.Rejected++
+	j.report(&ImportEvent{Type: ImportEventRejected, Line: line, Reason: reason})
+	return j.maxErrors >= 0 && j.stats.Rejected > int64(j.maxErrors)
+}
+
+// budgetReason 超过错误预算时的中止原因
+func (j *importJob) budgetReason() string {
+	return fmt.Sprintf("error budget exceeded: %d rows rejected, max_errors is %d", j.stats.Rejected, j.maxErrors)
+}
+
+// snapshot 复制当前统计
+func (j *importJob) snapshot() *ImportStats {
+	stats := j.stats
+	return &stats
+}
+
+// summary 生成最终结果
+func (j *importJob) summary(status, reason string) *ImportEvent {
+	return &ImportEvent{
+		Type:        ImportEventSummary,
+		Status:      status,
+		Reason:      reason,
+		ImportStats: j.snapshot(),
+	}
+}
+
+// decompress 检测 gzip 魔数，压缩数据透明解压
+func decompress(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+	magic, err := buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		reader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid gzip data: %v", ErrInvalidImportRequest, err)
+		}
+		return reader, nil
+	}
+	return buffered, nil
+}
+
+// csvImportReader CSV 数据读取器
+type csvImportReader struct {
+	reader    *csv.Reader
+	targets   []string // 每个位置的目标列，空字符串表示跳过
+	nullValue string
+}
+
+// newCSVImportReader 创建 CSV 读取器，有表头时读取表头并按列映射确定每个位置的目标列
+func newCSVImportReader(input io.Reader, req *ImportRequest) (*csvImportReader, error) {
+	reader := csv.NewReader(input)
+	reader.ReuseRecord = true
+	if req.Delimiter != "" {
+		delimiter, size := utf8.DecodeRuneInString(req.Delimiter)
+		if size != len(req.Delimiter) || delimiter == utf8.RuneError || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+			return nil, fmt.Errorf("%w: invalid delimiter %q", ErrInvalidImportRequest, req.Delimiter)
+		}
+		reader.Comma = delimiter
+	}
+
+	r := &csvImportReader{reader: reader, nullValue: req.NullValue}
+
+	if !req.Header {
+		if len(req.Columns) == 0 {
+			return nil, fmt.Errorf("%w: columns are required for CSV without header", ErrInvalidImportRequest)
+		}
+		for _, column := range req.Columns {
+			r.targets = append(r.targets, column.Target)
+		}
+		reader.FieldsPerRecord = len(r.targets)
+		return r, nil
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: missing CSV header", ErrInvalidImportRequest)
+		}
+		return nil, fmt.Errorf("%w: invalid CSV header: %v", ErrInvalidImportRequest, err)
+	}
+	if len(header) > 0 {
+		// 去掉表格软件导出时附带的 UTF-8 BOM
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	mapping := make(map[string]string, len(req.Columns))
+	for _, column := range req.Columns {
+		mapping[column.Source] = column.Target
+	}
+
+	found := make(map[string]bool, len(header))
+	for _, field := range header {
+		field = strings.TrimSpace(field)
+		found[field] = true
+		if len(mapping) == 0 {
+			r.targets = append(r.targets, field)
+		} else {
+			r.targets = append(r.targets, mapping[field])
+		}
+	}
+	for _, column := range req.Columns {
+		if !found[column.Source] {
+			return nil, fmt.Errorf("%w: column %s not found in CSV header", ErrInvalidImportRequest, column.Source)
+		}
+	}
+
+	reader.FieldsPerRecord = len(r.targets)
+	return r, nil
+}
+
+// next 读取下一条 CSV 记录
+func (r *csvImportReader) next() (int64, []string, []interface{}, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return int64(parseErr.StartLine), nil, nil, &importRowError{line: int64(parseErr.StartLine), reason: parseErr.Err.Error()}
+		}
+		return 0, nil, nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	columns := make([]string, 0, len(record))
+	values := make([]interface{}, 0, len(record))
+	for i, field := range record {
+		if r.targets[i] == "" {
+			continue
+		}
+		columns = append(columns, r.targets[i])
+		if field == r.nullValue {
+			values = append(values, nil)
+		} else {
+			values = append(values, field)
+		}
+	}
+	return int64(line), columns, values, nil
+}
+
+// ndjsonImportReader NDJSON 数据读取器，每行一个 JSON 对象
+type ndjsonImportReader struct {
+	reader  *bufio.Reader
+	mapping map[string]string
+	line    int64
+}
+
+// newNDJSONImportReader 创建 NDJSON 读取器，有列映射时只导入映射的字段
+func newNDJSONImportReader(input io.Reader, columns []ImportColumn) *ndjsonImportReader {
+	mapping := make(map[string]string, len(columns))
+	for _, column := range columns {
+		if column.Target != "" {
+			mapping[column.Source] = column.Target
+		}
+	}
+	return &ndjsonImportReader{reader: bufio.NewReader(input), mapping: mapping}
+}
+
+// next 读取下一个 JSON 对象，跳过空行
+func (r *ndjsonImportReader) next() (int64, []string, []interface{}, error) {
+	for {
+		data, err := r.reader.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(data) == 0) {
+			return 0, nil, nil, err
+		}
+		r.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		var record map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&record); err != nil || decoder.More() || record == nil {
+			reason := "line is not a JSON object"
+			if err != nil {
+				reason = fmt.Sprintf("invalid JSON: %v", err)
+			}
+			return r.line, nil, nil, &importRowError{line: r.line, reason: reason}
+		}
+
+		// 字段按名称排序，相同字段的行可以一起写入
+		keys := make([]string, 0, len(record))
+		for key := range record {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		columns := make([]string, 0, len(keys))
+		values := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			target := key
+			if len(r.mapping) > 0 {
+				if target = r.mapping[key]; target == "" {
+					continue
+				}
+			}
+			columns = append(columns, target)
+			values = append(values, record[key])
+		}
+		return r.line, columns, values, nil
+	}
+}
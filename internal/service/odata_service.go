@@ -0,0 +1,516 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/odata"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+var (
+	// ErrEntitySetNotFound 实体集不存在
+	ErrEntitySetNotFound = errors.New("entity set not found")
+	// ErrEntityNotFound 实体不存在
+	ErrEntityNotFound = errors.New("entity not found")
+	// ErrInvalidODataQuery 查询选项无效
+	ErrInvalidODataQuery = errors.New("invalid OData query")
+)
+
+// odataExpandBatchSize 展开导航属性时每条 IN 条件的最大取值数
+const odataExpandBatchSize = 50
+
+// ODataService OData 查询服务接口
+type ODataService interface {
+	// 列出实体集名称
+	EntitySets(ctx context.Context) ([]string, error)
+
+	// 生成 $metadata 文档
+	Metadata(ctx context.Context) ([]byte, error)
+
+	// 查询实体集
+	Query(ctx context.Context, set string, opts *odata.Options) (*ODataResult, error)
+
+	// 按键获取单个实体
+	Get(ctx context.Context, set, key string, opts *odata.Options) (map[string]interface{}, error)
+
+	// 统计实体集中满足 $filter 的实体数
+	Count(ctx context.Context, set string, opts *odata.Options) (int64, error)
+}
+
+// ODataResult 实体集查询结果
+type ODataResult struct {
+	Rows     []map[string]interface{}
+	Count    *int64 // $count=true 时返回
+	NextSkip int    // 大于 0 时还有下一页，下一页从该偏移开始
+	NextTop  int    // 下一页剩余的 $top，-1 表示未指定
+}
+
+// odataPlan 单个实体集的查询计划
+type odataPlan struct {
+	where    map[string]interface{}
+	orderBy  []model.OrderByClause
+	selected []string // 返回的列
+	columns  []string // 查询的列（包括展开需要的连接列）
+}
+
+// odataService OData 查询服务实现
+type odataService struct {
+	sqlEngine     *sql.SQLEngine
+	builder       *sql.QueryBuilder
+	schemaService SchemaService
+	pageSize      int
+}
+
+// NewODataService 创建 OData 查询服务
+func NewODataService(repos *repository.Repositories, cfg *config.SQLConfig, schemaService SchemaService) (ODataService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	if schemaService == nil {
+		return nil, errors.New("schema service cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	return &odataService{
+		sqlEngine:     engine,
+		builder:       newTableQueryBuilder(engine.GetDatabaseType(), cfg),
+		schemaService: schemaService,
+		pageSize:      cfg.MaxResultSize,
+	}, nil
+}
+
+// entityTables 返回可作为实体集的表（OData 实体必须有键，没有主键的表不公开）
+func (s *odataService) entityTables(ctx context.Context) ([]*apiTable, error) {
+	tables, err := loadAPITables(ctx, s.schemaService)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []*apiTable
+	for _, t := range tables {
+		if len(t.primaryKey) == 0 {
+			continue
+		}
+		keyed := true
+		for _, key := range t.primaryKey {
+			if _, ok := t.columnMap[key]; !ok {
+				keyed = false
+			}
+		}
+		if keyed {
+			entities = append(entities, t)
+		}
+	}
+	return entities, nil
+}
+
+// entityTable 按实体集名称查找表
+func (s *odataService) entityTable(ctx context.Context, set string) (*apiTable, error) {
+	tables, err := s.entityTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		if t.typeName == set {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrEntitySetNotFound, set)
+}
+
+// EntitySets 列出实体集名称
+func (s *odataService) EntitySets(ctx context.Context) ([]string, error) {
+	tables, err := s.entityTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]string, len(tables))
+	for i, t := range tables {
+		sets[i] = t.typeName
+	}
+	return sets, nil
+}
+
+// Metadata 生成 $metadata 文档
+func (s *odataService) Metadata(ctx context.Context) ([]byte, error) {
+	tables, err := s.entityTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exposed := make(map[*apiTable]bool, len(tables))
+	for _, t := range tables {
+		exposed[t] = true
+	}
+
+	types := make([]odata.EntityType, 0, len(tables))
+	for _, t := range tables {
+		entityType := odata.EntityType{Name: t.typeName, Key: t.primaryKey}
+		for _, column := range t.columns {
+			// 键属性不能为空
+			nullable := column.Nullable && !containsString(t.primaryKey, column.Name)
+			entityType.Properties = append(entityType.Properties, odata.Property{
+				Name:     column.Name,
+				Type:     edmType(column),
+				Nullable: nullable,
+			})
+		}
+		for _, rel := range t.relations {
+			if !exposed[rel.target] {
+				continue
+			}
+			entityType.Navigation = append(entityType.Navigation, odata.NavigationProperty{
+				Name:       rel.name,
+				Target:     rel.target.typeName,
+				Collection: rel.many,
+			})
+		}
+		types = append(types, entityType)
+	}
+
+	return odata.Metadata(types)
+}
+
+// Query 查询实体集，未指定 $top 或 $top 超过 max_result_size 时分页返回
+func (s *odataService) Query(ctx context.Context, set string, opts *odata.Options) (*ODataResult, error) {
+	t, err := s.entityTable(ctx, set)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.plan(t, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ODataResult{Rows: []map[string]interface{}{}, NextTop: -1}
+
+	limit := s.pageSize
+	if opts.Top >= 0 && opts.Top < limit {
+		limit = opts.Top
+	}
+
+	if limit > 0 {
+		rows, err := s.fetch(ctx, t, plan, opts.Skip, limit)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.expand(ctx, t, rows, opts.Expand); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			result.Rows = append(result.Rows, project(plan, opts.Expand, row))
+		}
+
+		// 服务端分页：本页已满且 $top 未取完时返回下一页位置
+		if len(rows) == s.pageSize && (opts.Top < 0 || opts.Top > len(rows)) {
+			result.NextSkip = opts.Skip + len(rows)
+			if opts.Top >= 0 {
+				result.NextTop = opts.Top - len(rows)
+			}
+		}
+	}
+
+	if opts.Count {
+		count, err := countRows(ctx, s.sqlEngine, s.builder, &model.StructuredQuery{Table: t.table, Where: plan.where})
+		if err != nil {
+			return nil, err
+		}
+		result.Count = &count
+	}
+
+	return result, nil
+}
+
+// Get 按键获取单个实体
+func (s *odataService) Get(ctx context.Context, set, key string, opts *odata.Options) (map[string]interface{}, error) {
+	t, err := s.entityTable(ctx, set)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Filter != "" || len(opts.OrderBy) > 0 || opts.Top >= 0 || opts.Skip > 0 || opts.Count {
+		return nil, fmt.Errorf("%w: only $select and $expand apply to a single entity", ErrInvalidODataQuery)
+	}
+
+	values, err := odata.ParseKey(key, t.primaryKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidODataQuery, err)
+	}
+
+	plan, err := s.plan(t, opts)
+	if err != nil {
+		return nil, err
+	}
+	plan.where = make(map[string]interface{}, len(values))
+	for column, value := range values {
+		plan.where[column] = value
+	}
+	plan.orderBy = nil
+
+	rows, err := s.fetch(ctx, t, plan, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: %s(%s)", ErrEntityNotFound, set, key)
+	}
+	if err := s.expand(ctx, t, rows, opts.Expand); err != nil {
+		return nil, err
+	}
+	return project(plan, opts.Expand, rows[0]), nil
+}
+
+// Count 统计实体集中满足 $filter 的实体数
+func (s *odataService) Count(ctx context.Context, set string, opts *odata.Options) (int64, error) {
+	t, err := s.entityTable(ctx, set)
+	if err != nil {
+		return 0, err
+	}
+
+	where, err := s.filter(t, opts.Filter)
+	if err != nil {
+		return 0, err
+	}
+	return countRows(ctx, s.sqlEngine, s.builder, &model.StructuredQuery{Table: t.table, Where: where})
+}
+
+// plan 校验查询选项并生成查询计划
+func (s *odataService) plan(t *apiTable, opts *odata.Options) (*odataPlan, error) {
+	where, err := s.filter(t, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &odataPlan{where: where}
+
+	for _, item := range opts.OrderBy {
+		if _, ok := t.columnMap[item.Property]; !ok {
+			return nil, fmt.Errorf("%w: unknown property %s in $orderby", ErrInvalidODataQuery, item.Property)
+		}
+		order := "asc"
+		if item.Desc {
+			order = "desc"
+		}
+		plan.orderBy = append(plan.orderBy, model.OrderByClause{Field: item.Property, Order: order})
+	}
+	// 默认按键排序，保证分页结果稳定
+	if len(plan.orderBy) == 0 {
+		for _, key := range t.primaryKey {
+			plan.orderBy = append(plan.orderBy, model.OrderByClause{Field: key, Order: "asc"})
+		}
+	}
+
+	if len(opts.Select) > 0 {
+		for _, property := range opts.Select {
+			if _, ok := t.columnMap[property]; !ok {
+				return nil, fmt.Errorf("%w: unknown property %s in $select", ErrInvalidODataQuery, property)
+			}
+			if !containsString(plan.selected, property) {
+				plan.selected = append(plan.selected, property)
+			}
+		}
+	} else {
+		for _, column := range t.columns {
+			plan.selected = append(plan.selected, column.Name)
+		}
+	}
+
+	plan.columns = append(plan.columns, plan.selected...)
+	for _, expand := range opts.Expand {
+		rel, ok := t.relation(expand.Property)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown navigation property %s in $expand", ErrInvalidODataQuery, expand.Property)
+		}
+		if expand.Options.Count {
+			return nil, fmt.Errorf("%w: $count is not supported in $expand", ErrInvalidODataQuery)
+		}
+		if !containsString(plan.columns, rel.localColumn) {
+			plan.columns = append(plan.columns, rel.localColumn)
+		}
+	}
+
+	return plan, nil
+}
+
+// filter 将 $filter 转换为查询条件，属性名区分大小写
+func (s *odataService) filter(t *apiTable, expr string) (map[string]interface{}, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	where, err := odata.ParseFilter(expr, func(property string) (string, bool) {
+		_, ok := t.columnMap[property]
+		return property, ok
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: $filter: %v", ErrInvalidODataQuery, err)
+	}
+	return where, nil
+}
+
+// fetch 通过 QueryBuilder 构建查询并执行
+func (s *odataService) fetch(ctx context.Context, t *apiTable, plan *odataPlan, offset, limit int) ([]map[string]interface{}, error) {
+	query, params, err := s.builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:   t.table,
+		Action:  "select",
+		Fields:  plan.columns,
+		Where:   plan.where,
+		OrderBy: plan.orderBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidODataQuery, err)
+	}
+
+	if limit > 0 || offset > 0 {
+		if limit <= 0 {
+			limit = math.MaxInt32
+		}
+		query = s.builder.ApplyPagination(query, offset, limit)
+	}
+
+	result, err := s.sqlEngine.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+// expand 批量加载导航属性并写入行中（以导航属性名为键）
+func (s *odataService) expand(ctx context.Context, t *apiTable, rows []map[string]interface{}, expands []*odata.Expand) error {
+	for _, expand := range expands {
+		rel, _ := t.relation(expand.Property)
+		opts := expand.Options
+
+		plan, err := s.plan(rel.target, opts)
+		if err != nil {
+			return err
+		}
+		if !containsString(plan.columns, rel.remoteColumn) {
+			plan.columns = append(plan.columns, rel.remoteColumn)
+		}
+
+		// 收集去重后的连接值
+		var keys []interface{}
+		seen := make(map[string]bool)
+		for _, row := range rows {
+			value := rowValue(row, rel.localColumn)
+			if value == nil || seen[relationKey(value)] {
+				continue
+			}
+			seen[relationKey(value)] = true
+			keys = append(keys, value)
+		}
+
+		// 按批次使用 IN 条件查询，避免每行一次查询
+		var children []map[string]interface{}
+		for start := 0; start < len(keys); start += odataExpandBatchSize {
+			end := start + odataExpandBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+
+			batchPlan := *plan
+			batchPlan.where = map[string]interface{}{rel.remoteColumn: map[string]interface{}{"in": keys[start:end]}}
+			if len(plan.where) > 0 {
+				batchPlan.where = map[string]interface{}{"$and": []interface{}{plan.where, batchPlan.where}}
+			}
+
+			batch, err := s.fetch(ctx, rel.target, &batchPlan, 0, 0)
+			if err != nil {
+				return err
+			}
+			children = append(children, batch...)
+		}
+
+		if err := s.expand(ctx, rel.target, children, opts.Expand); err != nil {
+			return err
+		}
+
+		// 按连接值分组，$skip/$top 对每个父实体分别生效
+		grouped := make(map[string][]map[string]interface{})
+		for _, child := range children {
+			key := relationKey(rowValue(child, rel.remoteColumn))
+			grouped[key] = append(grouped[key], project(plan, opts.Expand, child))
+		}
+
+		for _, row := range rows {
+			group := grouped[relationKey(rowValue(row, rel.localColumn))]
+			if !rel.many {
+				if len(group) > 0 {
+					row[rel.name] = group[0]
+				} else {
+					row[rel.name] = nil
+				}
+				continue
+			}
+
+			if opts.Skip >= len(group) {
+				group = nil
+			} else {
+				group = group[opts.Skip:]
+			}
+			if opts.Top >= 0 && opts.Top < len(group) {
+				group = group[:opts.Top]
+			}
+			if group == nil {
+				group = []map[string]interface{}{}
+			}
+			row[rel.name] = group
+		}
+	}
+	return nil
+}
+
+// project 按 $select 和 $expand 生成返回的实体
+func project(plan *odataPlan, expands []*odata.Expand, row map[string]interface{}) map[string]interface{} {
+	entity := make(map[string]interface{}, len(plan.selected)+len(expands))
+	for _, column := range plan.selected {
+		entity[column] = rowValue(row, column)
+	}
+	for _, expand := range expands {
+		entity[expand.Property] = row[expand.Property]
+	}
+	return entity
+}
+
+// edmType 将数据库列类型映射为 Edm 类型
+func edmType(column sql.ColumnSchema) string {
+	schema := columnTypeSchema(column)
+	switch schema["type"] {
+	case "integer":
+		return "Edm.Int64"
+	case "number":
+		return "Edm.Decimal"
+	case "boolean":
+		return "Edm.Boolean"
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			return "Edm.DateTimeOffset"
+		case "date":
+			return "Edm.Date"
+		case "byte":
+			return "Edm.Binary"
+		}
+	}
+	return "Edm.String"
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"sql2api/internal/odata"
+)
+
+func TestODataService_ExpandTimestampColumns(t *testing.T) {
+	repos := newTestRepositories(t,
+		`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, created_at TEXT)`,
+		`CREATE TABLE logs (id INTEGER PRIMARY KEY, item_id INTEGER REFERENCES items(id), message TEXT, created_at TEXT)`,
+		`INSERT INTO items (id, name, created_at) VALUES (1, 'widget', '2024-01-01')`,
+		`INSERT INTO logs (id, item_id, message, created_at) VALUES (10, 1, 'created', '2024-01-02')`,
+	)
+	cfg := newTestSQLConfig("items", "logs")
+	schemaService, err := NewSchemaService(repos, cfg)
+	if err != nil {
+		t.Fatalf("NewSchemaService failed: %v", err)
+	}
+	service, err := NewODataService(repos, cfg, schemaService)
+	if err != nil {
+		t.Fatalf("NewODataService failed: %v", err)
+	}
+	ctx := context.Background()
+
+	// 查询所有列（包括 created_at）
+	result, err := service.Query(ctx, "items", &odata.Options{Top: -1})
+	if err != nil {
+		t.Fatalf("Query items failed: %v", err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0]["created_at"] != "2024-01-01" {
+		t.Errorf("Unexpected items: %v", result.Rows)
+	}
+
+	// 从 logs 展开到 items，两侧都有 created_at 列
+	result, err = service.Query(ctx, "logs", &odata.Options{
+		Top:     -1,
+		Select:  []string{"id", "created_at"},
+		OrderBy: []odata.OrderItem{{Property: "created_at", Desc: true}},
+		Expand:  []*odata.Expand{{Property: "item", Options: &odata.Options{Top: -1, Select: []string{"name", "created_at"}}}},
+	})
+	if err != nil {
+		t.Fatalf("Query logs with $expand failed: %v", err)
+	}
+	if len(result.Rows) != 1 {
+		t.Fatalf("Expected 1 log, got %v", result.Rows)
+	}
+	item, ok := result.Rows[0]["item"].(map[string]interface{})
+	if !ok || item["name"] != "widget" || item["created_at"] != "2024-01-01" {
+		t.Errorf("Unexpected expanded item: %v", result.Rows[0])
+	}
+}
@@ -0,0 +1,612 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"sql2api/internal/config"
+	"sql2api/internal/sql"
+)
+
+// OpenAPIService OpenAPI 文档生成服务接口
+type OpenAPIService interface {
+	// 生成只包含调用方可用操作的 OpenAPI 文档
+	Generate(ctx context.Context, opts OpenAPIOptions) (map[string]interface{}, error)
+}
+
+// OpenAPIOptions OpenAPI 文档生成选项
+type OpenAPIOptions struct {
+	// 未配置 swagger.servers 时使用的服务器地址
+	DefaultServer string
+	// 判断调用方是否拥有指定权限
+	Allow func(permission string) bool
+}
+
+// openAPIService OpenAPI 文档生成服务实现
+type openAPIService struct {
+	config        *config.Config
+	schemaService SchemaService
+	queryService  QueryService
+}
+
+// NewOpenAPIService 创建 OpenAPI 文档生成服务
+func NewOpenAPIService(cfg *config.Config, schemaService SchemaService, queryService QueryService) (OpenAPIService, error) {
+	if cfg == nil {
+		return nil, errors.New("configuration cannot be nil")
+	}
+
+	return &openAPIService{
+		config:        cfg,
+		schemaService: schemaService,
+		queryService:  queryService,
+	}, nil
+}
+
+// Generate 根据资源、允许访问的表、命名查询和表结构生成 OpenAPI 3.0 文档
+func (s *openAPIService) Generate(ctx context.Context, opts OpenAPIOptions) (map[string]interface{}, error) {
+	allow := opts.Allow
+	if allow == nil {
+		allow = func(string) bool { return false }
+	}
+
+	paths := make(map[string]interface{})
+	schemas := map[string]interface{}{
+		"SQLError": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":      map[string]interface{}{"type": "integer"},
+				"message":   map[string]interface{}{"type": "string"},
+				"details":   map[string]interface{}{"type": "string"},
+				"sql_state": map[string]interface{}{"type": "string"},
+			},
+		},
+		"ErrorResponse": sqlResponseSchema(nil),
+	}
+
+	// REST 资源
+	for i := range s.config.SQL.Resources {
+		resource := &s.config.SQL.Resources[i]
+		if !s.allowsAnyResourceOperation(resource, allow) {
+			continue
+		}
+
+		record, err := s.resourceRecordSchema(ctx, resource)
+		if err != nil {
+			return nil, err
+		}
+		recordName := "Resource_" + resource.Name
+		schemas[recordName] = record
+		s.addResourcePaths(paths, resource, "#/components/schemas/"+recordName, allow)
+	}
+
+	// 允许访问的表：查询和导入操作按路由检查的 sql.query、sql.insert 权限生成；
+	// 表结构操作和按列生成的记录结构需要 schema.<table> 权限，没有时记录为任意对象
+	if s.schemaService != nil {
+		for _, name := range s.schemaService.ListTables() {
+			canSchema := allow(schemaPermission(name))
+			if !canSchema && !allow("sql.query") && !allow("sql.insert") {
+				continue
+			}
+
+			record := map[string]interface{}{"type": "object", "additionalProperties": true}
+			if canSchema {
+				table, err := s.schemaService.GetTable(ctx, name)
+				if err != nil {
+					// 配置中存在但数据库中不存在的表不生成
+					if errors.Is(err, sql.ErrTableNotFound) {
+						continue
+					}
+					return nil, err
+				}
+				recordName := "Table_" + name
+				schemas[recordName] = tableRecordSchema(table)
+				record = map[string]interface{}{"$ref": "#/components/schemas/" + recordName}
+			}
+			s.addTablePaths(paths, name, record, canSchema, allow)
+		}
+	}
+
+	// 命名查询
+	if s.queryService != nil {
+		for _, query := range s.queryService.ListQueries() {
+			if !allow(query.GetPermission()) {
+				continue
+			}
+			s.addQueryPaths(paths, query)
+		}
+	}
+
+	document := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       s.config.Swagger.Title,
+			"version":     s.config.Swagger.Version,
+			"description": s.config.Swagger.Description,
+		},
+		"servers": s.servers(opts.DefaultServer),
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"responses": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"description": "请求失败",
+					"content":     jsonContent(map[string]interface{}{"$ref": "#/components/schemas/ErrorResponse"}),
+				},
+			},
+		},
+	}
+
+	// 安全方案来自 API Key 配置
+	if s.config.APIKeys.Enabled {
+		securitySchemes := make(map[string]interface{})
+		var security []interface{}
+		if s.config.APIKeys.HeaderName != "" {
+			securitySchemes["ApiKeyHeader"] = map[string]interface{}{
+				"type": "apiKey",
+				"in":   "header",
+				"name": s.config.APIKeys.HeaderName,
+			}
+			security = append(security, map[string]interface{}{"ApiKeyHeader": []string{}})
+		}
+		if s.config.APIKeys.QueryParam != "" {
+			securitySchemes["ApiKeyQuery"] = map[string]interface{}{
+				"type": "apiKey",
+				"in":   "query",
+				"name": s.config.APIKeys.QueryParam,
+			}
+			security = append(security, map[string]interface{}{"ApiKeyQuery": []string{}})
+		}
+		document["components"].(map[string]interface{})["securitySchemes"] = securitySchemes
+		document["security"] = security
+	}
+
+	return document, nil
+}
+
+// servers 获取服务器地址列表
+func (s *openAPIService) servers(defaultServer string) []interface{} {
+	urls := s.config.Swagger.Servers
+	if len(urls) == 0 && defaultServer != "" {
+		urls = []string{defaultServer}
+	}
+
+	servers := make([]interface{}, 0, len(urls))
+	for _, url := range urls {
+		servers = append(servers, map[string]interface{}{"url": strings.TrimSuffix(url, "/")})
+	}
+	return servers
+}
+
+// allowsAnyResourceOperation 检查调用方是否可以使用资源的任一操作
+func (s *openAPIService) allowsAnyResourceOperation(resource *config.ResourceConfig, allow func(string) bool) bool {
+	for _, op := range config.ResourceOperations {
+		if resource.AllowsOperation(op) && allow(resourcePermission(resource, op)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceRecordSchema 根据表结构生成资源记录的 JSON Schema
+// 表结构不可用时（例如表尚未创建）只列出字段名
+func (s *openAPIService) resourceRecordSchema(ctx context.Context, resource *config.ResourceConfig) (map[string]interface{}, error) {
+	var table *sql.TableSchema
+	if s.schemaService != nil {
+		var err error
+		table, err = s.schemaService.GetTable(ctx, resource.Table)
+		if err != nil && !errors.Is(err, sql.ErrTableNotFound) {
+			return nil, err
+		}
+	}
+
+	fields := resource.Fields
+	if len(fields) == 0 {
+		if table == nil {
+			return map[string]interface{}{"type": "object", "additionalProperties": true}, nil
+		}
+		for _, column := range table.Columns {
+			fields = append(fields, config.ResourceFieldConfig{Column: column.Name})
+		}
+	}
+
+	properties := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		property := map[string]interface{}{}
+		if table != nil {
+			for _, column := range table.Columns {
+				if strings.EqualFold(column.Name, field.Column) {
+					property = columnTypeSchema(column)
+					break
+				}
+			}
+		}
+		if field.ReadOnly {
+			property["readOnly"] = true
+		}
+		properties[field.FieldName()] = property
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}, nil
+}
+
+// addResourcePaths 添加调用方有权限的资源操作
+func (s *openAPIService) addResourcePaths(paths map[string]interface{}, resource *config.ResourceConfig, recordRef string, allow func(string) bool) {
+	record := map[string]interface{}{"$ref": recordRef}
+	allowed := func(op string) bool {
+		return resource.AllowsOperation(op) && allow(resourcePermission(resource, op))
+	}
+
+	keySchema := map[string]interface{}{"type": "integer", "format": "int64"}
+	if resource.KeyType == "string" {
+		keySchema = map[string]interface{}{"type": "string"}
+	}
+	idParam := map[string]interface{}{
+		"name": "id", "in": "path", "required": true, "schema": keySchema,
+	}
+	body := map[string]interface{}{
+		"required": true,
+		"content":  jsonContent(record),
+	}
+	tags := []string{resource.Name}
+
+	collection := make(map[string]interface{})
+	if allowed("list") {
+		collection["get"] = map[string]interface{}{
+			"operationId": "list_" + resource.Name,
+			"tags":        tags,
+			"summary":     "列出 " + resource.Name,
+			"parameters": []interface{}{
+				queryParameter("page", map[string]interface{}{"type": "integer", "minimum": 1}),
+				queryParameter("page_size", map[string]interface{}{"type": "integer", "minimum": 1, "maximum": resource.GetMaxPageSize()}),
+				queryParameter("sort", map[string]interface{}{"type": "string"}),
+			},
+			"responses": operationResponses("200", sqlResponseSchema(record)),
+		}
+	}
+	if allowed("create") {
+		collection["post"] = map[string]interface{}{
+			"operationId": "create_" + resource.Name,
+			"tags":        tags,
+			"summary":     "创建 " + resource.Name,
+			"requestBody": body,
+			"responses":   operationResponses("201", sqlResponseSchema(record)),
+		}
+	}
+	if len(collection) > 0 {
+		paths["/api/v1/resources/"+resource.Name] = collection
+	}
+
+	item := make(map[string]interface{})
+	if allowed("get") {
+		item["get"] = map[string]interface{}{
+			"operationId": "get_" + resource.Name,
+			"tags":        tags,
+			"summary":     "获取 " + resource.Name,
+			"responses":   operationResponses("200", sqlResponseSchema(record)),
+		}
+	}
+	if allowed("update") {
+		item["put"] = map[string]interface{}{
+			"operationId": "replace_" + resource.Name,
+			"tags":        tags,
+			"summary":     "替换 " + resource.Name,
+			"requestBody": body,
+			"responses":   operationResponses("200", sqlResponseSchema(record)),
+		}
+		item["patch"] = map[string]interface{}{
+			"operationId": "patch_" + resource.Name,
+			"tags":        tags,
+			"summary":     "部分更新 " + resource.Name,
+			"requestBody": body,
+			"responses":   operationResponses("200", sqlResponseSchema(record)),
+		}
+	}
+	if allowed("delete") {
+		item["delete"] = map[string]interface{}{
+			"operationId": "delete_" + resource.Name,
+			"tags":        tags,
+			"summary":     "删除 " + resource.Name,
+			"responses":   operationResponses("200", sqlResponseSchema(nil)),
+		}
+	}
+	if len(item) > 0 {
+		item["parameters"] = []interface{}{idParam}
+		paths["/api/v1/resources/"+resource.Name+"/{id}"] = item
+	}
+}
+
+// tableRecordSchema 根据表结构生成表记录的 JSON Schema
+// 数据库生成的列为只读，没有默认值的 NOT NULL 列为必填
+func tableRecordSchema(table *sql.TableSchema) map[string]interface{} {
+	properties := make(map[string]interface{}, len(table.Columns))
+	required := make([]string, 0)
+	for _, column := range table.Columns {
+		property := columnTypeSchema(column)
+		if column.Generated {
+			property["readOnly"] = true
+		} else if !column.Nullable && column.Default == nil {
+			required = append(required, column.Name)
+		}
+		properties[column.Name] = property
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if table.Comment != "" {
+		schema["description"] = table.Comment
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addTablePaths 添加调用方有权限的表操作：表结构（schema.<table>）、URL 查询（sql.query）和数据导入（sql.insert）
+// record 为表记录的 JSON Schema
+func (s *openAPIService) addTablePaths(paths map[string]interface{}, table string, record map[string]interface{}, canSchema bool, allow func(string) bool) {
+	tags := []string{"tables"}
+
+	if canSchema {
+		paths["/api/v1/schema/"+table] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "schema_" + table,
+				"tags":        tags,
+				"summary":     "获取 " + table + " 的表结构",
+				"responses":   operationResponses("200", map[string]interface{}{"type": "object", "additionalProperties": true}),
+			},
+		}
+	}
+
+	if allow("sql.query") {
+		responses := operationResponses("200", map[string]interface{}{"type": "array", "items": record})
+		responses["206"] = map[string]interface{}{
+			"description": "部分结果",
+			"content":     jsonContent(map[string]interface{}{"type": "array", "items": record}),
+		}
+		paths["/api/v1/tables/"+table] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "query_table_" + table,
+				"tags":        tags,
+				"summary":     "查询 " + table,
+				"parameters": []interface{}{
+					queryParameter("select", map[string]interface{}{"type": "string"}),
+					queryParameter("order", map[string]interface{}{"type": "string"}),
+					queryParameter("limit", map[string]interface{}{"type": "integer", "minimum": 0}),
+					queryParameter("offset", map[string]interface{}{"type": "integer", "minimum": 0}),
+				},
+				"responses": responses,
+			},
+		}
+	}
+
+	if allow("sql.insert") {
+		responses := operationResponses("200", nil)
+		responses["200"] = map[string]interface{}{
+			"description": "导入事件流",
+			"content": map[string]interface{}{
+				"application/x-ndjson": map[string]interface{}{"schema": map[string]interface{}{"type": "object", "additionalProperties": true}},
+			},
+		}
+		paths["/api/v1/import/"+table] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": "import_" + table,
+				"tags":        tags,
+				"summary":     "导入数据到 " + table,
+				"requestBody": map[string]interface{}{
+					"required": true,
+					"content": map[string]interface{}{
+						"text/csv":             map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+						"application/x-ndjson": map[string]interface{}{"schema": record},
+					},
+				},
+				"responses": responses,
+			},
+		}
+	}
+}
+
+// addQueryPaths 添加命名查询的 GET 和 POST 操作
+func (s *openAPIService) addQueryPaths(paths map[string]interface{}, query config.SavedQueryConfig) {
+	parameters := make([]interface{}, 0, len(query.Params))
+	properties := make(map[string]interface{}, len(query.Params))
+	required := make([]string, 0)
+	for _, param := range query.Params {
+		schema := queryParamSchema(param)
+		parameter := queryParameter(param.Name, schema)
+		parameter["required"] = param.Required
+		parameters = append(parameters, parameter)
+
+		properties[param.Name] = schema
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+
+	paramsSchema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		paramsSchema["required"] = required
+	}
+
+	tags := []string{"queries"}
+	paths["/api/v1/queries/"+query.Name] = map[string]interface{}{
+		"get": map[string]interface{}{
+			"operationId": "query_" + query.Name,
+			"tags":        tags,
+			"summary":     query.Description,
+			"parameters":  parameters,
+			"responses":   operationResponses("200", sqlResponseSchema(nil)),
+		},
+		"post": map[string]interface{}{
+			"operationId": "query_" + query.Name + "_post",
+			"tags":        tags,
+			"summary":     query.Description,
+			"requestBody": map[string]interface{}{
+				"content": jsonContent(map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"params": paramsSchema},
+				}),
+			},
+			"responses": operationResponses("200", sqlResponseSchema(nil)),
+		},
+	}
+}
+
+// ===== 辅助函数 =====
+
+// resourcePermission 资源操作权限：resource.<name>.<operation>
+func resourcePermission(resource *config.ResourceConfig, operation string) string {
+	return "resource." + resource.Name + "." + operation
+}
+
+// schemaPermission 表结构查看权限：schema.<table>
+func schemaPermission(table string) string {
+	return "schema." + strings.ToLower(table)
+}
+
+// columnTypeSchema 将数据库列类型转换为 JSON Schema
+func columnTypeSchema(column sql.ColumnSchema) map[string]interface{} {
+	dataType := strings.ToLower(column.Type)
+
+	var schema map[string]interface{}
+	switch {
+	case strings.Contains(dataType, "bool") || dataType == "bit":
+		schema = map[string]interface{}{"type": "boolean"}
+	case strings.Contains(dataType, "int") && !strings.Contains(dataType, "interval") && !strings.Contains(dataType, "point"):
+		schema = map[string]interface{}{"type": "integer", "format": "int64"}
+	case strings.Contains(dataType, "numeric"), strings.Contains(dataType, "decimal"),
+		strings.Contains(dataType, "number"), strings.Contains(dataType, "float"),
+		strings.Contains(dataType, "double"), strings.Contains(dataType, "real"),
+		strings.Contains(dataType, "money"):
+		schema = map[string]interface{}{"type": "number"}
+	case strings.Contains(dataType, "timestamp"), strings.Contains(dataType, "datetime"):
+		schema = map[string]interface{}{"type": "string", "format": "date-time"}
+	case strings.HasPrefix(dataType, "date"):
+		schema = map[string]interface{}{"type": "string", "format": "date"}
+	case strings.Contains(dataType, "uuid"), dataType == "uniqueidentifier":
+		schema = map[string]interface{}{"type": "string", "format": "uuid"}
+	case strings.Contains(dataType, "json"):
+		schema = map[string]interface{}{}
+	case strings.Contains(dataType, "blob"), strings.Contains(dataType, "bytea"), strings.Contains(dataType, "binary"):
+		schema = map[string]interface{}{"type": "string", "format": "byte"}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+		if column.Length != nil {
+			schema["maxLength"] = *column.Length
+		}
+	}
+
+	if len(column.Enum) > 0 {
+		schema["enum"] = column.Enum
+	}
+	if column.Nullable {
+		schema["nullable"] = true
+	}
+	if column.Comment != "" {
+		schema["description"] = column.Comment
+	}
+	return schema
+}
+
+// queryParamSchema 将命名查询参数定义转换为 JSON Schema
+func queryParamSchema(param config.QueryParamConfig) map[string]interface{} {
+	var schema map[string]interface{}
+	switch param.Type {
+	case "integer":
+		schema = map[string]interface{}{"type": "integer", "format": "int64"}
+	case "number":
+		schema = map[string]interface{}{"type": "number"}
+	case "boolean":
+		schema = map[string]interface{}{"type": "boolean"}
+	case "date":
+		schema = map[string]interface{}{"type": "string", "format": "date"}
+	case "timestamp":
+		schema = map[string]interface{}{"type": "string", "format": "date-time"}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+	}
+
+	if param.Min != nil {
+		schema["minimum"] = *param.Min
+	}
+	if param.Max != nil {
+		schema["maximum"] = *param.Max
+	}
+	if param.MaxLength > 0 {
+		schema["maxLength"] = param.MaxLength
+	}
+	if param.Pattern != "" {
+		schema["pattern"] = param.Pattern
+	}
+	if len(param.Enum) > 0 {
+		schema["enum"] = param.Enum
+	}
+	if param.Default != nil {
+		schema["default"] = param.Default
+	}
+	return schema
+}
+
+// sqlResponseSchema 生成 SQL 响应结构的 JSON Schema，item 为 data 中元素的结构
+func sqlResponseSchema(item map[string]interface{}) map[string]interface{} {
+	if item == nil {
+		item = map[string]interface{}{"type": "object", "additionalProperties": true}
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"success":        map[string]interface{}{"type": "boolean"},
+			"message":        map[string]interface{}{"type": "string"},
+			"data":           map[string]interface{}{"type": "array", "items": item},
+			"error":          map[string]interface{}{"$ref": "#/components/schemas/SQLError"},
+			"timestamp":      map[string]interface{}{"type": "string", "format": "date-time"},
+			"affected_rows":  map[string]interface{}{"type": "integer", "format": "int64"},
+			"total":          map[string]interface{}{"type": "integer", "format": "int64"},
+			"page":           map[string]interface{}{"type": "integer"},
+			"page_size":      map[string]interface{}{"type": "integer"},
+			"columns":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"last_insert_id": map[string]interface{}{"type": "integer", "format": "int64"},
+			"execution_time": map[string]interface{}{"type": "number"},
+		},
+	}
+}
+
+// operationResponses 生成操作的响应定义
+func operationResponses(status string, schema map[string]interface{}) map[string]interface{} {
+	errorRef := map[string]interface{}{"$ref": "#/components/responses/Error"}
+	return map[string]interface{}{
+		status: map[string]interface{}{
+			"description": "成功",
+			"content":     jsonContent(schema),
+		},
+		"400": errorRef,
+		"403": errorRef,
+		"404": errorRef,
+	}
+}
+
+// queryParameter 生成查询字符串参数定义
+func queryParameter(name string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":   name,
+		"in":     "query",
+		"schema": schema,
+	}
+}
+
+// jsonContent 生成 application/json 内容定义
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sql2api/internal/config"
+	"sql2api/internal/sql"
+)
+
+// fakeSchemaService 返回固定表结构的 SchemaService
+type fakeSchemaService struct {
+	tables map[string]*sql.TableSchema
+	listed []string
+}
+
+func (f *fakeSchemaService) ListTables() []string {
+	return f.listed
+}
+
+func (f *fakeSchemaService) GetTable(ctx context.Context, table string) (*sql.TableSchema, error) {
+	if schema, ok := f.tables[strings.ToLower(table)]; ok {
+		return schema, nil
+	}
+	return nil, sql.ErrTableNotFound
+}
+
+func (f *fakeSchemaService) Refresh(ctx context.Context) (*SchemaRefreshResult, error) {
+	return &SchemaRefreshResult{}, nil
+}
+
+func TestOpenAPIService_Tables(t *testing.T) {
+	schemas := &fakeSchemaService{
+		listed: []string{"orders", "secrets", "missing"},
+		tables: map[string]*sql.TableSchema{
+			"orders": {Name: "orders", Columns: []sql.ColumnSchema{
+				{Name: "id", Type: "integer", Generated: true},
+				{Name: "status", Type: "varchar", Enum: []string{"new", "paid"}},
+				{Name: "note", Type: "text", Nullable: true},
+			}},
+			"secrets": {Name: "secrets", Columns: []sql.ColumnSchema{{Name: "value", Type: "text"}}},
+		},
+	}
+	cfg := &config.Config{SQL: config.SQLConfig{AllowedTables: schemas.listed}}
+	service, err := NewOpenAPIService(cfg, schemas, nil)
+	if err != nil {
+		t.Fatalf("NewOpenAPIService failed: %v", err)
+	}
+
+	generate := func(permissions ...string) (map[string]interface{}, map[string]interface{}) {
+		t.Helper()
+		document, err := service.Generate(context.Background(), OpenAPIOptions{Allow: func(permission string) bool {
+			for _, p := range permissions {
+				if p == permission {
+					return true
+				}
+			}
+			return false
+		}})
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		return document["paths"].(map[string]interface{}), document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	}
+
+	paths, components := generate("schema.orders", "sql.query")
+	if pathOperation(paths, "/api/v1/tables/orders", "get") == nil {
+		t.Error("Expected table query operation for orders")
+	}
+	if _, ok := paths["/api/v1/schema/orders"]; !ok {
+		t.Error("Expected table schema operation for orders")
+	}
+
+	record, ok := components["Table_orders"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected Table_orders schema, got %v", components)
+	}
+	properties := record["properties"].(map[string]interface{})
+	if properties["id"].(map[string]interface{})["readOnly"] != true {
+		t.Errorf("Expected generated column to be read-only: %v", properties["id"])
+	}
+	if !reflect.DeepEqual(properties["status"].(map[string]interface{})["enum"], []string{"new", "paid"}) {
+		t.Errorf("Expected enum values for status: %v", properties["status"])
+	}
+	if !reflect.DeepEqual(record["required"], []string{"status"}) {
+		t.Errorf("Expected status to be required, got %v", record["required"])
+	}
+
+	// 没有 sql.insert 权限时不生成导入操作
+	if _, ok := paths["/api/v1/import/orders"]; ok {
+		t.Error("Expected import operation to be omitted without sql.insert")
+	}
+
+	// 没有 schema.<table> 权限的表仍有查询操作，但没有表结构操作和按列生成的记录结构
+	if _, ok := paths["/api/v1/tables/secrets"]; !ok {
+		t.Error("Expected table query operation for secrets with sql.query")
+	}
+	if _, ok := paths["/api/v1/schema/secrets"]; ok {
+		t.Error("Expected table schema operation to be omitted without schema.secrets")
+	}
+	if _, ok := components["Table_secrets"]; ok {
+		t.Error("Expected Table_secrets schema to be omitted without schema.secrets")
+	}
+
+	// 只有 sql.insert 权限时只生成导入操作
+	paths, _ = generate("schema.orders", "sql.insert")
+	if _, ok := paths["/api/v1/tables/orders"]; ok {
+		t.Error("Expected table query operation to be omitted without sql.query")
+	}
+	if pathOperation(paths, "/api/v1/import/orders", "post") == nil {
+		t.Error("Expected import operation for orders")
+	}
+
+	// 只有 sql.query 权限的 Key 可以看到所有允许访问的表的查询操作，记录为任意对象
+	paths, components = generate("sql.query")
+	for _, table := range schemas.listed {
+		operation := pathOperation(paths, "/api/v1/tables/"+table, "get")
+		if operation == nil {
+			t.Errorf("Expected table query operation for %s", table)
+			continue
+		}
+		items := operation["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["items"]
+		if !reflect.DeepEqual(items, map[string]interface{}{"type": "object", "additionalProperties": true}) {
+			t.Errorf("Expected generic record schema for %s, got %v", table, items)
+		}
+	}
+	if len(paths) != len(schemas.listed) || components["Table_orders"] != nil {
+		t.Errorf("Expected only table query operations, got %v", paths)
+	}
+
+	// 没有任何权限时不生成表操作
+	if paths, _ = generate(); len(paths) != 0 {
+		t.Errorf("Expected no operations without permissions, got %v", paths)
+	}
+}
+
+// pathOperation 获取文档中的操作，不存在时返回 nil
+func pathOperation(paths map[string]interface{}, path, method string) map[string]interface{} {
+	item, _ := paths[path].(map[string]interface{})
+	operation, _ := item[method].(map[string]interface{})
+	return operation
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// ErrSavedQueryNotFound 命名查询不存在
+var ErrSavedQueryNotFound = errors.New("saved query not found")
+
+// QueryService 命名查询业务服务接口
+type QueryService interface {
+	// 获取命名查询定义
+	GetQuery(name string) (*config.SavedQueryConfig, bool)
+
+	// 列出所有命名查询
+	ListQueries() []config.SavedQueryConfig
+
+	// 新增或替换命名查询
+	SaveQuery(query *config.SavedQueryConfig) error
+
+	// 删除命名查询
+	DeleteQuery(name string) error
+
+	// 执行命名查询
+	ExecuteQuery(ctx context.Context, name string, params map[string]interface{}) (*model.SQLResponse, error)
+}
+
+// queryCacheEntry 命名查询结果缓存项
+type queryCacheEntry struct {
+	response  model.SQLResponse
+	expiresAt time.Time
+}
+
+// queryService 命名查询业务服务实现
+type queryService struct {
+	sqlEngine *sql.SQLEngine
+	config    *config.SQLConfig
+
+	mu      sync.RWMutex
+	queries map[string]config.SavedQueryConfig
+	cache   map[string]queryCacheEntry
+}
+
+// NewQueryService 创建命名查询业务服务，初始定义来自配置
+func NewQueryService(repos *repository.Repositories, cfg *config.SQLConfig) (QueryService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	s := &queryService{
+		sqlEngine: engine,
+		config:    cfg,
+		queries:   make(map[string]config.SavedQueryConfig),
+		cache:     make(map[string]queryCacheEntry),
+	}
+
+	for i := range cfg.SavedQueries {
+		if err := s.SaveQuery(&cfg.SavedQueries[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// GetQuery 获取命名查询定义
+func (s *queryService) GetQuery(name string) (*config.SavedQueryConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query, ok := s.queries[name]
+	if !ok {
+		return nil, false
+	}
+	return &query, true
+}
+
+// ListQueries 列出所有命名查询（按名称排序）
+func (s *queryService) ListQueries() []config.SavedQueryConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queries := make([]config.SavedQueryConfig, 0, len(s.queries))
+	for _, query := range s.queries {
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+	return queries
+}
+
+// SaveQuery 新增或替换命名查询（仅保存在内存中，重启后以配置文件为准）
+func (s *queryService) SaveQuery(query *config.SavedQueryConfig) error {
+	if err := config.ValidateSavedQuery(query); err != nil {
+		return err
+	}
+
+	// 预编译 SQL，提前发现未声明的参数
+	placeholders := make(map[string]interface{}, len(query.Params))
+	for _, param := range query.Params {
+		placeholders[param.Name] = nil
+	}
+	if _, _, err := sql.CompileNamedQuery(query.SQL, s.sqlEngine.GetDatabaseType(), placeholders); err != nil {
+		return fmt.Errorf("saved query %s: %w", query.Name, err)
+	}
+
+	// 与原生 SQL 相同，命名查询不能访问软删除的表
+	if err := checkSoftDeleteTables(s.sqlEngine, s.config, query.SQL); err != nil {
+		return fmt.Errorf("saved query %s: %w", query.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queries[query.Name] = *query
+	s.invalidateLocked(query.Name)
+	return nil
+}
+
+// DeleteQuery 删除命名查询
+func (s *queryService) DeleteQuery(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.queries[name]; !ok {
+		return ErrSavedQueryNotFound
+	}
+	delete(s.queries, name)
+	s.invalidateLocked(name)
+	return nil
+}
+
+// ExecuteQuery 执行命名查询，查询结果按 cache_ttl 缓存
+func (s *queryService) ExecuteQuery(ctx context.Context, name string, params map[string]interface{}) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	query, ok := s.GetQuery(name)
+	if !ok {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Saved query not found", name)
+		return &response, nil
+	}
+
+	// 执行前再次检查，保证任何来源的定义都不会读取或修改已删除的行
+	if err := checkSoftDeleteTables(s.sqlEngine, s.config, query.SQL); err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorPermission, "Saved query cannot access soft-delete table", err.Error())
+		return &response, nil
+	}
+
+	cacheKey := ""
+	if query.CacheTTL > 0 {
+		cacheKey = s.cacheKey(name, params)
+		if response, ok := s.getCached(cacheKey); ok {
+			return response, nil
+		}
+	}
+
+	result, err := s.sqlEngine.ExecuteSavedQuery(ctx, query, params)
+	if err != nil {
+		return s.handleQueryError(err), nil
+	}
+
+	var response model.SQLResponse
+	if result.Query != nil {
+		response = model.NewSQLSuccessResponse(result.Query.Rows, 0, "Query executed successfully")
+		response.Columns = result.Query.Columns
+		response.Total = result.Query.Total
+	} else {
+		response = model.NewSQLSuccessResponse(nil, result.Exec.AffectedRows, "SQL executed successfully")
+		response.LastInsertID = result.Exec.LastInsertID
+	}
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+
+	// 只缓存查询结果
+	if cacheKey != "" && result.Query != nil {
+		s.mu.Lock()
+		s.evictExpiredLocked()
+		s.cache[cacheKey] = queryCacheEntry{
+			response:  response,
+			expiresAt: time.Now().Add(time.Duration(query.CacheTTL) * time.Second),
+		}
+		s.mu.Unlock()
+	}
+
+	return &response, nil
+}
+
+// ===== 辅助方法 =====
+
+// cacheKey 生成缓存键（JSON 编码时映射键有序）
+func (s *queryService) cacheKey(name string, params map[string]interface{}) string {
+	encoded, _ := json.Marshal(params)
+	return name + "\x00" + string(encoded)
+}
+
+// getCached 获取未过期的缓存结果
+func (s *queryService) getCached(key string) (*model.SQLResponse, bool) {
+	s.mu.RLock()
+	entry, ok := s.cache[key]
+	s.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	response := entry.response
+	return &response, true
+}
+
+// invalidateLocked 清除指定查询的缓存（调用方需持有写锁）
+func (s *queryService) invalidateLocked(name string) {
+	prefix := name + "\x00"
+	for key := range s.cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.cache, key)
+		}
+	}
+}
+
+// evictExpiredLocked 清除过期的缓存（调用方需持有写锁）
+func (s *queryService) evictExpiredLocked() {
+	now := time.Now()
+	for key, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, key)
+		}
+	}
+}
+
+// handleQueryError 处理命名查询执行错误
+func (s *queryService) handleQueryError(err error) *model.SQLResponse {
+	var sqlErr *model.SQLError
+
+	switch {
+	case errors.Is(err, sql.ErrInvalidQueryParams):
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid query parameters", err.Error())
+		return &response
+	case errors.As(err, &sqlErr):
+		response := model.NewSQLErrorResponse(sqlErr.Code, sqlErr.Message, sqlErr.Details)
+		return &response
+	}
+
+	// 与 SQL 服务使用相同的错误分类
+	return executionErrorResponse(err)
+}
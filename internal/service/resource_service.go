@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+var (
+	// ErrResourceNotFound 资源未注册
+	ErrResourceNotFound = errors.New("resource not found")
+	// ErrRecordNotFound 记录不存在
+	ErrRecordNotFound = errors.New("record not found")
+	// ErrInvalidResourceData 资源数据无效
+	ErrInvalidResourceData = errors.New("invalid resource data")
+)
+
+// ResourceService 资源业务服务接口
+type ResourceService interface {
+	// 获取资源配置
+	GetResource(name string) (*config.ResourceConfig, bool)
+
+	// 列出资源记录
+	List(ctx context.Context, name string, req *model.ResourceListRequest) (*model.SQLResponse, error)
+
+	// 获取单条记录，includeDeleted 为 true 时包含软删除的记录
+	Get(ctx context.Context, name, id string, includeDeleted bool) (*model.SQLResponse, error)
+
+	// 创建记录
+	Create(ctx context.Context, name string, data map[string]interface{}) (*model.SQLResponse, error)
+
+	// 更新记录，replace 为 true 时未提供的字段置为 NULL（PUT 语义），ifMatch 不为空时按版本做乐观并发检查
+	Update(ctx context.Context, name, id string, data map[string]interface{}, replace bool, ifMatch string) (*model.SQLResponse, error)
+
+	// 删除记录，ifMatch 不为空时按版本做乐观并发检查
+	Delete(ctx context.Context, name, id, ifMatch string) (*model.SQLResponse, error)
+
+	// 恢复软删除的记录，ifMatch 不为空时按版本做乐观并发检查
+	Restore(ctx context.Context, name, id, ifMatch string) (*model.SQLResponse, error)
+}
+
+// resourceService 资源业务服务实现
+type resourceService struct {
+	sqlEngine *sql.SQLEngine
+	config    *config.SQLConfig
+	builder   *QueryBuilder
+}
+
+// NewResourceService 创建资源业务服务
+func NewResourceService(repos *repository.Repositories, cfg *config.SQLConfig) (ResourceService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	return &resourceService{
+		sqlEngine: engine,
+		config:    cfg,
+		builder:   newTableQueryBuilder(engine.GetDatabaseType(), cfg),
+	}, nil
+}
+
+// GetResource 获取资源配置
+func (s *resourceService) GetResource(name string) (*config.ResourceConfig, bool) {
+	return s.config.GetResource(name)
+}
+
+// List 列出资源记录
+func (s *resourceService) List(ctx context.Context, name string, req *model.ResourceListRequest) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	resource, ok := s.config.GetResource(name)
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	query, err := sql.ResourceListQuery(resource, req.Filters, req.Sort, req.IncludeDeleted)
+	if err != nil {
+		return s.invalidData(err.Error()), nil
+	}
+
+	sqlQuery, params, err := s.builder.BuildStructuredQuery(query)
+	if err != nil {
+		return s.buildError(err), nil
+	}
+
+	// 分页
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > resource.GetMaxPageSize() {
+		pageSize = resource.GetMaxPageSize()
+	}
+	sqlQuery = s.builder.ApplyPagination(sqlQuery, (page-1)*pageSize, pageSize)
+
+	result, err := s.sqlEngine.ExecuteQuery(ctx, sqlQuery, params)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+
+	// 总行数使用相同的过滤条件单独统计
+	total, err := countRows(ctx, s.sqlEngine, s.builder, query)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+
+	response := model.NewSQLSuccessResponse(sql.ResourceRows(resource, result.Rows), 0, "Resources retrieved successfully")
+	response.Total = total
+	response.Page = page
+	response.PageSize = pageSize
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+
+	return &response, nil
+}
+
+// Get 获取单条记录
+func (s *resourceService) Get(ctx context.Context, name, id string, includeDeleted bool) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	resource, ok := s.config.GetResource(name)
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	key, err := s.parseKey(resource, id)
+	if err != nil {
+		return s.invalidData(err.Error()), nil
+	}
+
+	response, err := s.fetchRecord(ctx, resource, key, includeDeleted)
+	if err != nil || !response.Success {
+		return response, err
+	}
+
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+	return response, nil
+}
+
+// Create 创建记录
+func (s *resourceService) Create(ctx context.Context, name string, data map[string]interface{}) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	resource, ok := s.config.GetResource(name)
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	columns, err := s.toColumns(resource, data, false)
+	if err != nil {
+		return s.invalidData(err.Error()), nil
+	}
+	if len(columns) == 0 {
+		return s.invalidData("no data provided"), nil
+	}
+
+	query, params, err := s.builder.BuildInsertQuery(&model.InsertRequest{
+		Table: resource.Table,
+		Data:  columns,
+	})
+	if err != nil {
+		return s.buildError(err), nil
+	}
+
+	result, err := s.sqlEngine.ExecuteStatement(sql.WithChangeRows(ctx, columns), query, params)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+
+	// 主键来自请求数据或自增主键，可确定时返回新记录
+	var key interface{} = columns[resource.GetPrimaryKey()]
+	if key == nil && result.LastInsertID > 0 {
+		key = result.LastInsertID
+	}
+
+	response := model.NewSQLSuccessResponse(nil, result.AffectedRows, "Resource created successfully")
+	if key != nil {
+		if record, err := s.fetchRecord(ctx, resource, key, false); err == nil && record.Success {
+			response.Data = record.Data
+			response.Columns = record.Columns
+			response.ETag = record.ETag
+		}
+	}
+	response.LastInsertID = result.LastInsertID
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+
+	return &response, nil
+}
+
+// Update 更新记录
+func (s *resourceService) Update(ctx context.Context, name, id string, data map[string]interface{}, replace bool, ifMatch string) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	resource, ok := s.config.GetResource(name)
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	key, err := s.parseKey(resource, id)
+	if err != nil {
+		return s.invalidData(err.Error()), nil
+	}
+
+	columns, err := s.toColumns(resource, data, true)
+	if err != nil {
+		return s.invalidData(err.Error()), nil
+	}
+
+	// PUT 语义：未提供的可写字段置为 NULL（仅在声明了字段列表时适用，版本列由更新语句维护）
+	if replace {
+		version, versioned := s.config.GetVersion(resource.Table)
+		for _, field := range resource.Fields {
+			if field.ReadOnly || field.Column == resource.GetPrimaryKey() || versioned && strings.EqualFold(field.Column, version.Column) {
+				continue
+			}
+			if _, ok := columns[field.Column]; !ok {
+				columns[field.Column] = nil
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return s.invalidData("no data provided"), nil
+	}
+
+	structured := &model.StructuredQuery{
+		Table:   resource.Table,
+		Action:  "update",
+		Data:    columns,
+		Where:   map[string]interface{}{resource.GetPrimaryKey(): key},
+		IfMatch: ifMatch,
+	}
+	if response := resolveIfMatch(structured); response != nil {
+		return response, nil
+	}
+
+	query, params, err := s.builder.BuildStructuredQuery(structured)
+	if err != nil {
+		return s.buildError(err), nil
+	}
+
+	ctx = sql.WithChangeKeys(ctx, map[string]interface{}{resource.GetPrimaryKey(): key})
+	result, err := s.sqlEngine.ExecuteStatement(ctx, query, params)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+	if result.AffectedRows == 0 {
+		return s.notMatched(ctx, resource, key, structured)
+	}
+
+	response := model.NewSQLSuccessResponse(nil, result.AffectedRows, "Resource updated successfully")
+	if record, err := s.fetchRecord(ctx, resource, key, false); err == nil && record.Success {
+		response.Data = record.Data
+		response.Columns = record.Columns
+		response.ETag = record.ETag
+	}
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+
+	return &response, nil
+}
+
+// Delete 删除记录
+func (s *resourceService) Delete(ctx context.Context, name, id, ifMatch string) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	resource, ok := s.config.GetResource(name)
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+
+	key, err := s.parseKey(resource, id)
+	if err != nil {
+		return s.invalidData(err.Error()), nil
+	}
+
+	structured := &model.StructuredQuery{
+		Table:   resource.Table,
+		Action:  "delete",
+		Where:   map[string]interface{}{resource.GetPrimaryKey(): key},
+		IfMatch: ifMatch,
+	}
+	if response := resolveIfMatch(structured); response != nil {
+		return response, nil
+	}
+
+	query, params, err := s.builder.BuildStructuredQuery(structured)
+	if err != nil {
+		return s.buildError(err), nil
+	}
+
+	ctx = sql.WithChangeKeys(ctx, map[string]interface{}{resource.GetPrimaryKey(): key})
+	result, err := s.sqlEngine.ExecuteStatement(softDeleteContext(ctx, s.config, resource.Table), query, params)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+	if result.AffectedRows == 0 {
+		return s.notMatched(ctx, resource, key, structured)
+	}
+
+	response := model.NewSQLSuccessResponse(nil, result.AffectedRows, "Resource deleted successfully")
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+
+	return &response, nil
+}
+
+// Restore 恢复软删除的记录
+func (s *resourceService) Restore(ctx context.Context, name, id, ifMatch string) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	resource, ok := s.config.GetResource(name)
+	if !ok {
+		return nil, ErrResourceNotFound
+	}
+	if _, ok := s.config.GetSoftDelete(resource.Table); !ok {
+		return s.invalidData("resource does not use soft delete"), nil
+	}
+
+	key, err := s.parseKey(resource, id)
+	if err != nil {
+		return s.invalidData(err.Error()), nil
+	}
+
+	structured := &model.StructuredQuery{
+		Table:   resource.Table,
+		Action:  "restore",
+		Where:   map[string]interface{}{resource.GetPrimaryKey(): key},
+		IfMatch: ifMatch,
+	}
+	if response := resolveIfMatch(structured); response != nil {
+		return response, nil
+	}
+
+	query, params, err := s.builder.BuildStructuredQuery(structured)
+	if err != nil {
+		return s.buildError(err), nil
+	}
+
+	ctx = sql.WithChangeKeys(ctx, map[string]interface{}{resource.GetPrimaryKey(): key})
+	result, err := s.sqlEngine.ExecuteStatement(ctx, query, params)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+	if result.AffectedRows == 0 {
+		// 记录不存在或未被删除
+		if structured.ExpectedVersion != nil {
+			if _, err := s.fetchRecord(ctx, resource, key, true); err == nil {
+				return versionMismatchResponse(structured), nil
+			}
+		}
+		return nil, ErrRecordNotFound
+	}
+
+	response := model.NewSQLSuccessResponse(nil, result.AffectedRows, "Resource restored successfully")
+	if record, err := s.fetchRecord(ctx, resource, key, false); err == nil && record.Success {
+		response.Data = record.Data
+		response.Columns = record.Columns
+		response.ETag = record.ETag
+	}
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+
+	return &response, nil
+}
+
+// ===== 辅助方法 =====
+
+// notMatched 更新、删除没有匹配的行时区分记录不存在（404）和版本不匹配（409/412）
+func (s *resourceService) notMatched(ctx context.Context, resource *config.ResourceConfig, key interface{}, query *model.StructuredQuery) (*model.SQLResponse, error) {
+	if query.ExpectedVersion == nil {
+		return nil, ErrRecordNotFound
+	}
+	if _, err := s.fetchRecord(ctx, resource, key, false); errors.Is(err, ErrRecordNotFound) {
+		return nil, ErrRecordNotFound
+	}
+	return versionMismatchResponse(query), nil
+}
+
+// fetchRecord 按主键读取单条记录，表配置了版本列时在响应中设置 ETag
+// includeDeleted 为 true 时包含软删除的记录
+func (s *resourceService) fetchRecord(ctx context.Context, resource *config.ResourceConfig, key interface{}, includeDeleted bool) (*model.SQLResponse, error) {
+	// 声明了字段列表时额外读取版本列（不会出现在 API 字段中）
+	fields := sql.ResourceColumns(resource)
+	version, versioned := s.config.GetVersion(resource.Table)
+	if versioned && len(fields) > 0 && !containsFold(fields, version.Column) {
+		fields = append(fields, version.Column)
+	}
+
+	query, params, err := s.builder.BuildStructuredQuery(sql.ResourceKeyQuery(resource, key, fields, includeDeleted))
+	if err != nil {
+		return s.buildError(err), nil
+	}
+
+	result, err := s.sqlEngine.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+	if len(result.Rows) == 0 {
+		return nil, ErrRecordNotFound
+	}
+
+	rows := sql.ResourceRows(resource, result.Rows[:1])
+	response := model.NewSQLSuccessResponse(rows, 0, "Resource retrieved successfully")
+	response.Total = 1
+	if versioned {
+		response.ETag = sql.VersionTag(versionValue(result.Rows[0], version.Column))
+	}
+
+	return &response, nil
+}
+
+// toColumns 将请求数据的字段名转换为列名，并检查只读字段
+func (s *resourceService) toColumns(resource *config.ResourceConfig, data map[string]interface{}, update bool) (map[string]interface{}, error) {
+	columns := make(map[string]interface{}, len(data))
+	var problems []string
+
+	for field, value := range data {
+		column, ok := sql.ResourceColumn(resource, field)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: unknown field", field))
+			continue
+		}
+		if update && column == resource.GetPrimaryKey() {
+			problems = append(problems, fmt.Sprintf("%s: primary key cannot be updated", field))
+			continue
+		}
+		for _, f := range resource.Fields {
+			if f.Column == column && f.ReadOnly {
+				problems = append(problems, fmt.Sprintf("%s: field is read-only", field))
+			}
+		}
+		columns[column] = value
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidResourceData, strings.Join(problems, "; "))
+	}
+	return columns, nil
+}
+
+// parseKey 按主键类型解析路径中的 ID
+func (s *resourceService) parseKey(resource *config.ResourceConfig, id string) (interface{}, error) {
+	key, err := sql.ParseResourceKey(resource, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResourceData, err)
+	}
+	return key, nil
+}
+
+// invalidData 创建数据无效响应
+func (s *resourceService) invalidData(details string) *model.SQLResponse {
+	response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid resource request", details)
+	return &response
+}
+
+// buildError 创建查询构建失败响应
+func (s *resourceService) buildError(err error) *model.SQLResponse {
+	response := model.NewSQLErrorResponse(model.SQLErrorSyntax, "Query building failed", err.Error())
+	return &response
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// SchemaService 表结构查询服务接口
+type SchemaService interface {
+	// 列出允许访问的表名
+	ListTables() []string
+
+	// 获取表结构（优先使用缓存）
+	GetTable(ctx context.Context, table string) (*sql.TableSchema, error)
+
+	// 清空缓存并重新读取所有表结构
+	Refresh(ctx context.Context) (*SchemaRefreshResult, error)
+}
+
+// SchemaRefreshResult 表结构刷新结果
+type SchemaRefreshResult struct {
+	Tables      int               `json:"tables"`
+	Errors      map[string]string `json:"errors,omitempty"`
+	RefreshedAt time.Time         `json:"refreshed_at"`
+}
+
+// schemaService 表结构查询服务实现
+type schemaService struct {
+	sqlEngine *sql.SQLEngine
+	tables    []string
+
+	mu    sync.RWMutex
+	cache map[string]*sql.TableSchema
+}
+
+// NewSchemaService 创建表结构查询服务
+func NewSchemaService(repos *repository.Repositories, cfg *config.SQLConfig) (SchemaService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	return &schemaService{
+		sqlEngine: engine,
+		tables:    cfg.AllowedTables,
+		cache:     make(map[string]*sql.TableSchema),
+	}, nil
+}
+
+// ListTables 列出允许访问的表名（按配置顺序）
+func (s *schemaService) ListTables() []string {
+	tables := make([]string, len(s.tables))
+	copy(tables, s.tables)
+	return tables
+}
+
+// GetTable 获取表结构，首次访问时从数据库目录读取并缓存
+func (s *schemaService) GetTable(ctx context.Context, table string) (*sql.TableSchema, error) {
+	key := strings.ToLower(table)
+
+	s.mu.RLock()
+	cached, ok := s.cache[key]
+	s.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	schema, err := s.sqlEngine.DescribeTable(ctx, s.configuredName(table))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[key] = schema
+	s.mu.Unlock()
+
+	return schema, nil
+}
+
+// Refresh 清空缓存并重新读取所有允许访问的表
+func (s *schemaService) Refresh(ctx context.Context) (*SchemaRefreshResult, error) {
+	cache := make(map[string]*sql.TableSchema, len(s.tables))
+	result := &SchemaRefreshResult{}
+
+	for _, table := range s.tables {
+		schema, err := s.sqlEngine.DescribeTable(ctx, table)
+		if err != nil {
+			// 上下文取消时放弃刷新，保留原缓存
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if result.Errors == nil {
+				result.Errors = make(map[string]string)
+			}
+			result.Errors[table] = err.Error()
+			continue
+		}
+		cache[strings.ToLower(table)] = schema
+	}
+
+	s.mu.Lock()
+	s.cache = cache
+	s.mu.Unlock()
+
+	result.Tables = len(cache)
+	result.RefreshedAt = time.Now()
+	return result, nil
+}
+
+// configuredName 返回配置中的表名写法（表名匹配不区分大小写）
+func (s *schemaService) configuredName(table string) string {
+	for _, allowed := range s.tables {
+		if strings.EqualFold(allowed, table) {
+			return allowed
+		}
+	}
+	return table
+}
@@ -9,29 +9,190 @@ import (
 
 // Services 服务集合
 type Services struct {
-	SQL SQLService
+	SQL           SQLService
+	Queries       QueryService
+	Resources     ResourceService
+	Schema        SchemaService
+	OpenAPI       OpenAPIService
+	GraphQL       GraphQLService
+	Tables        TableService
+	OData         ODataService
+	Subscriptions SubscriptionService
+	Webhooks      WebhookService
+	Import        ImportService
+	Audit         AuditService
+	Statements    StatementService
+	Admin         AdminService
 }
 
 // NewServices 创建服务集合
 func NewServices(repos *repository.Repositories, cfg *config.Config) (*Services, error) {
 	// 创建 SQL 服务
 	var sqlService SQLService
+	var queryService QueryService
+	var resourceService ResourceService
+	var schemaService SchemaService
+	var openAPIService OpenAPIService
+	var graphQLService GraphQLService
+	var tableService TableService
+	var odataService ODataService
+	var subscriptionService SubscriptionService
+	var webhookService WebhookService
+	var importService ImportService
+	var auditService AuditService
+	var statementService StatementService
+	var adminService AdminService
 	var err error
 	if cfg.SQL.Enabled {
-		sqlService, err = NewSQLService(repos, &cfg.SQL)
+		// 创建审计日志服务（需在其他服务执行写操作前启用）
+		if cfg.SQL.Audit.Enabled {
+			auditService, err = NewAuditService(repos, &cfg.SQL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create audit service: %w", err)
+			}
+		}
+
+		// 创建语句统计服务
+		if cfg.SQL.StatementStats.Enabled {
+			statementService, err = NewStatementService(repos, &cfg.SQL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create statement service: %w", err)
+			}
+		}
+
+		// 创建表结构服务（SQL 服务按表结构校验写入的数据）
+		schemaService, err = NewSchemaService(repos, &cfg.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create schema service: %w", err)
+		}
+
+		sqlService, err = NewSQLService(repos, &cfg.SQL, schemaService)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create SQL service: %w", err)
 		}
+
+		// 创建命名查询服务
+		queryService, err = NewQueryService(repos, &cfg.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create query service: %w", err)
+		}
+
+		// 创建资源服务
+		resourceService, err = NewResourceService(repos, &cfg.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource service: %w", err)
+		}
+
+		// 创建 OpenAPI 文档服务
+		openAPIService, err = NewOpenAPIService(cfg, schemaService, queryService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OpenAPI service: %w", err)
+		}
+
+		// 创建 GraphQL 服务
+		graphQLService, err = NewGraphQLService(repos, &cfg.SQL, schemaService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GraphQL service: %w", err)
+		}
+
+		// 创建表查询服务
+		tableService, err = NewTableService(repos, &cfg.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create table service: %w", err)
+		}
+
+		// 创建 OData 服务
+		odataService, err = NewODataService(repos, &cfg.SQL, schemaService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OData service: %w", err)
+		}
+
+		// 创建表变更订阅服务
+		if cfg.SQL.Subscriptions.Enabled {
+			subscriptionService, err = NewSubscriptionService(repos, &cfg.SQL, schemaService)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create subscription service: %w", err)
+			}
+		}
+
+		// 创建 webhook 服务
+		if cfg.SQL.Webhooks.Enabled {
+			webhookService, err = NewWebhookService(repos, &cfg.SQL, schemaService)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create webhook service: %w", err)
+			}
+		}
+
+		// 创建数据导入服务
+		if cfg.SQL.Import.Enabled {
+			importService, err = NewImportService(repos, &cfg.SQL, schemaService)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create import service: %w", err)
+			}
+		}
+	}
+
+	// 创建管理 API 服务（不依赖 SQL 服务）
+	adminService, err = NewAdminService(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin service: %w", err)
 	}
 
 	return &Services{
-		SQL: sqlService,
+		SQL:           sqlService,
+		Queries:       queryService,
+		Resources:     resourceService,
+		Schema:        schemaService,
+		OpenAPI:       openAPIService,
+		GraphQL:       graphQLService,
+		Tables:        tableService,
+		OData:         odataService,
+		Subscriptions: subscriptionService,
+		Webhooks:      webhookService,
+		Import:        importService,
+		Audit:         auditService,
+		Statements:    statementService,
+		Admin:         adminService,
 	}, nil
 }
 
+// CloseSubscriptions 结束所有变更订阅的长连接，以便 HTTP 服务器优雅关闭
+func (s *Services) CloseSubscriptions() {
+	if s.Subscriptions != nil {
+		s.Subscriptions.Close()
+	}
+}
+
+// Close 关闭需要释放的服务，应在 HTTP 服务器关闭后调用，以免丢失关闭期间完成的写操作的 webhook
+func (s *Services) Close() {
+	s.CloseSubscriptions()
+	if s.Webhooks != nil {
+		s.Webhooks.Close()
+	}
+	if s.Audit != nil {
+		s.Audit.Close()
+	}
+	if s.Statements != nil {
+		s.Statements.Close()
+	}
+}
+
 // ServiceManager 服务管理器接口
 type ServiceManager interface {
 	GetSQLService() SQLService
+	GetQueryService() QueryService
+	GetResourceService() ResourceService
+	GetSchemaService() SchemaService
+	GetOpenAPIService() OpenAPIService
+	GetGraphQLService() GraphQLService
+	GetTableService() TableService
+	GetODataService() ODataService
+	GetSubscriptionService() SubscriptionService
+	GetWebhookService() WebhookService
+	GetImportService() ImportService
+	GetAuditService() AuditService
+	GetStatementService() StatementService
+	GetAdminService() AdminService
 }
 
 // serviceManager 服务管理器实现
@@ -55,3 +216,68 @@ func NewServiceManager(repos *repository.Repositories, cfg *config.Config) (Serv
 func (sm *serviceManager) GetSQLService() SQLService {
 	return sm.services.SQL
 }
+
+// GetQueryService 获取命名查询服务
+func (sm *serviceManager) GetQueryService() QueryService {
+	return sm.services.Queries
+}
+
+// GetResourceService 获取资源服务
+func (sm *serviceManager) GetResourceService() ResourceService {
+	return sm.services.Resources
+}
+
+// GetSchemaService 获取表结构服务
+func (sm *serviceManager) GetSchemaService() SchemaService {
+	return sm.services.Schema
+}
+
+// GetOpenAPIService 获取 OpenAPI 文档服务
+func (sm *serviceManager) GetOpenAPIService() OpenAPIService {
+	return sm.services.OpenAPI
+}
+
+// GetGraphQLService 获取 GraphQL 服务
+func (sm *serviceManager) GetGraphQLService() GraphQLService {
+	return sm.services.GraphQL
+}
+
+// GetTableService 获取表查询服务
+func (sm *serviceManager) GetTableService() TableService {
+	return sm.services.Tables
+}
+
+// GetODataService 获取 OData 服务
+func (sm *serviceManager) GetODataService() ODataService {
+	return sm.services.OData
+}
+
+// GetSubscriptionService 获取表变更订阅服务
+func (sm *serviceManager) GetSubscriptionService() SubscriptionService {
+	return sm.services.Subscriptions
+}
+
+// GetWebhookService 获取 webhook 服务
+func (sm *serviceManager) GetWebhookService() WebhookService {
+	return sm.services.Webhooks
+}
+
+// GetImportService 获取数据导入服务
+func (sm *serviceManager) GetImportService() ImportService {
+	return sm.services.Import
+}
+
+// GetAuditService 获取审计日志服务
+func (sm *serviceManager) GetAuditService() AuditService {
+	return sm.services.Audit
+}
+
+// GetStatementService 获取语句统计服务
+func (sm *serviceManager) GetStatementService() StatementService {
+	return sm.services.Statements
+}
+
+// GetAdminService 获取管理 API 服务
+func (sm *serviceManager) GetAdminService() AdminService {
+	return sm.services.Admin
+}
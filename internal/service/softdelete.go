@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"sql2api/internal/config"
+	"sql2api/internal/sql"
+)
+
+// softDeleteContext 软删除的表执行的是 UPDATE 语句，变更事件仍以 delete 发出
+func softDeleteContext(ctx context.Context, cfg *config.SQLConfig, table string) context.Context {
+	if _, ok := cfg.GetSoftDelete(table); ok {
+		return sql.WithChangeOperation(ctx, sql.ChangeDelete)
+	}
+	return ctx
+}
+
+// checkSoftDeleteTables 原生 SQL 无法可靠地改写为排除已删除的行，拒绝访问软删除的表
+func checkSoftDeleteTables(engine *sql.SQLEngine, cfg *config.SQLConfig, query string) error {
+	if len(cfg.SoftDelete) == 0 {
+		return nil
+	}
+
+	tables, err := engine.QueryTables(query)
+	if err != nil {
+		return fmt.Errorf("failed to parse SQL: %w", err)
+	}
+	for _, table := range tables {
+		if _, ok := cfg.GetSoftDelete(table); ok {
+			return fmt.Errorf("raw SQL cannot access soft-delete table %s, use a structured query instead", table)
+		}
+	}
+	return nil
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"sql2api/internal/config"
 	"sql2api/internal/model"
 	"sql2api/internal/repository"
 	"sql2api/internal/sql"
+	"sql2api/internal/tracing"
 )
 
 // QueryBuilder 查询构建器类型别名
@@ -20,11 +22,19 @@ func NewQueryBuilder(dbType string) *QueryBuilder {
 	return sql.NewQueryBuilder(dbType)
 }
 
+// newTableQueryBuilder 创建按配置处理版本列和软删除的查询构建器
+func newTableQueryBuilder(dbType string, cfg *config.SQLConfig) *QueryBuilder {
+	return sql.NewQueryBuilder(dbType).WithVersioning(cfg.Versioning).WithSoftDelete(cfg.SoftDelete)
+}
+
 // SQLService SQL 业务服务接口
 type SQLService interface {
 	// 执行查询操作
 	ExecuteQuery(ctx context.Context, req *model.SQLRequest) (*model.SQLResponse, error)
 	
+	// 流式执行查询操作，逐行交给 handler 处理，响应中不包含行数据
+	StreamQuery(ctx context.Context, req *model.SQLRequest, handler sql.RowHandler) (*model.SQLResponse, error)
+	
 	// 执行 SQL 操作（INSERT、UPDATE、DELETE）
 	ExecuteSQL(ctx context.Context, req *model.SQLRequest) (*model.SQLResponse, error)
 	
@@ -37,19 +47,24 @@ type SQLService interface {
 	// 执行批量插入操作
 	ExecuteBatchInsert(ctx context.Context, req *model.BatchInsertRequest) (*model.SQLResponse, error)
 	
+	// 调用存储过程或函数
+	CallRoutine(ctx context.Context, req *model.CallRequest) (*model.CallResponse, error)
+	
 	// 健康检查
 	HealthCheck() error
 }
 
 // sqlService SQL 业务服务实现
 type sqlService struct {
-	sqlEngine *sql.SQLEngine
-	config    *config.SQLConfig
-	builder   *QueryBuilder
+	sqlEngine  *sql.SQLEngine
+	config     *config.SQLConfig
+	builder    *QueryBuilder
+	translator *sql.SQLTranslator
+	schema     SchemaService // 写入数据校验使用的表结构，可为 nil
 }
 
 // NewSQLService 创建 SQL 业务服务
-func NewSQLService(repos *repository.Repositories, cfg *config.SQLConfig) (SQLService, error) {
+func NewSQLService(repos *repository.Repositories, cfg *config.SQLConfig, schemaService SchemaService) (SQLService, error) {
 	if repos == nil {
 		return nil, errors.New("repositories cannot be nil")
 	}
@@ -69,18 +84,22 @@ func NewSQLService(repos *repository.Repositories, cfg *config.SQLConfig) (SQLSe
 	}
 	
 	// 创建查询构建器
-	builder := NewQueryBuilder(engine.GetDatabaseType())
+	builder := newTableQueryBuilder(engine.GetDatabaseType(), cfg)
 	
 	return &sqlService{
-		sqlEngine: engine,
-		config:    cfg,
-		builder:   builder,
+		sqlEngine:  engine,
+		config:     cfg,
+		builder:    builder,
+		translator: sql.NewSQLTranslator(engine.GetDatabaseType()),
+		schema:     schemaService,
 	}, nil
 }
 
 // ExecuteQuery 执行查询操作
 func (s *sqlService) ExecuteQuery(ctx context.Context, req *model.SQLRequest) (*model.SQLResponse, error) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlService.ExecuteQuery")
+	defer span.End()
 	
 	// 验证请求
 	if err := s.validateSQLRequest(req); err != nil {
@@ -88,7 +107,7 @@ func (s *sqlService) ExecuteQuery(ctx context.Context, req *model.SQLRequest) (*
 	}
 	
 	// 构建查询
-	query, params, err := s.buildQuery(req)
+	query, params, err := s.buildQuery(ctx, req)
 	if err != nil {
 		return s.createErrorResponse(model.SQLErrorSyntax, "Query building failed", err.Error()), nil
 	}
@@ -99,40 +118,128 @@ func (s *sqlService) ExecuteQuery(ctx context.Context, req *model.SQLRequest) (*
 	// 执行查询
 	result, err := s.sqlEngine.ExecuteQuery(ctx, query, params)
 	if err != nil {
-		return s.handleExecutionError(err), nil
+		response := s.handleExecutionError(err)
+		response.Debug = s.buildDebugInfo(req, query)
+		return response, nil
 	}
 	
 	// 构建响应
 	response := s.buildQueryResponse(result, req)
 	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6 // 转换为毫秒
+	response.Debug = s.buildDebugInfo(req, query)
 	
 	return response, nil
 }
 
+// StreamQuery 流式执行查询操作
+func (s *sqlService) StreamQuery(ctx context.Context, req *model.SQLRequest, handler sql.RowHandler) (*model.SQLResponse, error) {
+	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlService.StreamQuery")
+	defer span.End()
+
+	// 验证请求
+	if err := s.validateSQLRequest(req); err != nil {
+		return s.createErrorResponse(model.SQLErrorParams, "Request validation failed", err.Error()), nil
+	}
+
+	// 构建查询
+	query, params, err := s.buildQuery(ctx, req)
+	if err != nil {
+		return s.createErrorResponse(model.SQLErrorSyntax, "Query building failed", err.Error()), nil
+	}
+
+	// 应用分页和排序
+	query = s.applyPaginationAndSort(query, req)
+
+	// 执行查询
+	result, err := s.sqlEngine.StreamQuery(ctx, query, params, handler)
+	if err != nil {
+		response := s.handleExecutionError(err)
+		response.Debug = s.buildDebugInfo(req, query)
+		return response, nil
+	}
+
+	// 构建响应
+	response := s.buildQueryResponse(result, req)
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+	response.Debug = s.buildDebugInfo(req, query)
+
+	return response, nil
+}
+
 // ExecuteSQL 执行 SQL 操作
 func (s *sqlService) ExecuteSQL(ctx context.Context, req *model.SQLRequest) (*model.SQLResponse, error) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlService.ExecuteSQL")
+	defer span.End()
 	
 	// 验证请求
 	if err := s.validateSQLRequest(req); err != nil {
 		return s.createErrorResponse(model.SQLErrorParams, "Request validation failed", err.Error()), nil
 	}
 	
+	// 按表结构校验并转换结构化插入、更新的数据
+	if req.Query != nil && (strings.EqualFold(req.Query.Action, "insert") || strings.EqualFold(req.Query.Action, "update")) {
+		records, errorResponse := s.validateWriteRecords(ctx, req.Query.Table, []map[string]interface{}{req.Query.Data}, strings.EqualFold(req.Query.Action, "insert"), false)
+		if errorResponse != nil {
+			return errorResponse, nil
+		}
+		structured := *req.Query
+		structured.Data = records[0]
+		validated := *req
+		validated.Query = &structured
+		req = &validated
+	}
+
+	// If-Match 转换为期望版本
+	if req.Query != nil && req.Query.IfMatch != "" {
+		structured := *req.Query
+		if response := resolveIfMatch(&structured); response != nil {
+			return response, nil
+		}
+		resolved := *req
+		resolved.Query = &structured
+		req = &resolved
+	}
+
 	// 构建查询
-	query, params, err := s.buildQuery(req)
+	query, params, err := s.buildQuery(ctx, req)
 	if err != nil {
 		return s.createErrorResponse(model.SQLErrorSyntax, "Query building failed", err.Error()), nil
 	}
 	
+	// 结构化插入的行数据随变更事件发出，更新、删除的等值条件作为受影响行的键发出
+	if req.Query != nil {
+		if strings.EqualFold(req.Query.Action, "insert") {
+			ctx = sql.WithChangeRows(ctx, req.Query.Data)
+		} else {
+			ctx = sql.WithChangeKeys(ctx, sql.EqualityKeys(req.Query.Where)...)
+		}
+		if strings.EqualFold(req.Query.Action, "delete") {
+			ctx = softDeleteContext(ctx, s.config, req.Query.Table)
+		}
+	}
+
 	// 执行 SQL
 	result, err := s.sqlEngine.ExecuteSQL(ctx, query, params)
 	if err != nil {
-		return s.handleExecutionError(err), nil
+		response := s.handleExecutionError(err)
+		response.Debug = s.buildDebugInfo(req, query)
+		return response, nil
 	}
 	
+	// 指定了期望版本却没有行匹配，说明记录已被其他请求修改或删除
+	if req.Query != nil && req.Query.ExpectedVersion != nil && result.AffectedRows == 0 {
+		response := versionMismatchResponse(req.Query)
+		response.Debug = s.buildDebugInfo(req, query)
+		return response, nil
+	}
+
 	// 构建响应
 	response := model.NewSQLSuccessResponse(nil, result.AffectedRows, "SQL executed successfully")
+	response.LastInsertID = result.LastInsertID
 	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+	response.Debug = s.buildDebugInfo(req, query)
 	
 	return &response, nil
 }
@@ -140,6 +247,8 @@ func (s *sqlService) ExecuteSQL(ctx context.Context, req *model.SQLRequest) (*mo
 // ExecuteBatch 执行批量 SQL 操作
 func (s *sqlService) ExecuteBatch(ctx context.Context, req *model.BatchSQLRequest) (*model.BatchSQLResponse, error) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlService.ExecuteBatch")
+	defer span.End()
 	
 	// 验证批量请求
 	if err := s.validateBatchRequest(req); err != nil {
@@ -149,7 +258,7 @@ func (s *sqlService) ExecuteBatch(ctx context.Context, req *model.BatchSQLReques
 	// 构建批量查询
 	var batchQueries []sql.BatchQuery
 	for _, sqlReq := range req.Operations {
-		query, params, err := s.buildQuery(&sqlReq)
+		query, params, err := s.buildQuery(ctx, &sqlReq)
 		if err != nil {
 			return s.createBatchErrorResponse(model.SQLErrorSyntax, "Query building failed", err.Error()), nil
 		}
@@ -167,7 +276,7 @@ func (s *sqlService) ExecuteBatch(ctx context.Context, req *model.BatchSQLReques
 	}
 	
 	// 构建响应
-	response := s.buildBatchResponse(result, req)
+	response := s.buildBatchResponse(result, req, batchQueries)
 	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
 	
 	return response, nil
@@ -176,26 +285,40 @@ func (s *sqlService) ExecuteBatch(ctx context.Context, req *model.BatchSQLReques
 // ExecuteInsert 执行便捷插入操作
 func (s *sqlService) ExecuteInsert(ctx context.Context, req *model.InsertRequest) (*model.SQLResponse, error) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlService.ExecuteInsert")
+	defer span.End()
 	
 	// 验证插入请求
 	if err := s.validateInsertRequest(req); err != nil {
 		return s.createErrorResponse(model.SQLErrorParams, "Insert request validation failed", err.Error()), nil
 	}
+
+	// 按表结构校验并转换插入的数据
+	records, errorResponse := s.validateWriteRecords(ctx, req.Table, []map[string]interface{}{req.Data}, true, false)
+	if errorResponse != nil {
+		return errorResponse, nil
+	}
+	validated := *req
+	validated.Data = records[0]
+	req = &validated
 	
 	// 构建插入查询
+	_, buildSpan := tracing.Start(ctx, "QueryBuilder.BuildInsertQuery")
 	query, params, err := s.builder.BuildInsertQuery(req)
+	tracing.End(buildSpan, err)
 	if err != nil {
 		return s.createErrorResponse(model.SQLErrorSyntax, "Insert query building failed", err.Error()), nil
 	}
 	
 	// 执行插入
-	result, err := s.sqlEngine.ExecuteSQL(ctx, query, params)
+	result, err := s.sqlEngine.ExecuteSQL(sql.WithChangeRows(ctx, req.Data), query, params)
 	if err != nil {
 		return s.handleExecutionError(err), nil
 	}
 	
 	// 构建响应
 	response := model.NewSQLSuccessResponse(nil, result.AffectedRows, "Insert executed successfully")
+	response.LastInsertID = result.LastInsertID
 	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
 	
 	return &response, nil
@@ -204,31 +327,115 @@ func (s *sqlService) ExecuteInsert(ctx context.Context, req *model.InsertRequest
 // ExecuteBatchInsert 执行批量插入操作
 func (s *sqlService) ExecuteBatchInsert(ctx context.Context, req *model.BatchInsertRequest) (*model.SQLResponse, error) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlService.ExecuteBatchInsert")
+	defer span.End()
 	
 	// 验证批量插入请求
 	if err := s.validateBatchInsertRequest(req); err != nil {
 		return s.createErrorResponse(model.SQLErrorParams, "Batch insert request validation failed", err.Error()), nil
 	}
+
+	// 缺少的字段写入 NULL 时，按显式的 null 校验
+	records := req.Data
+	if req.MissingFields == model.MissingFieldsNull {
+		records = fillMissingFields(records)
+	}
+
+	// 按表结构校验并转换插入的数据
+	records, errorResponse := s.validateWriteRecords(ctx, req.Table, records, true, true)
+	if errorResponse != nil {
+		return errorResponse, nil
+	}
+	validated := *req
+	validated.Data = records
+	req = &validated
 	
-	// 构建批量插入查询
-	query, params, err := s.builder.BuildBatchInsertQuery(req)
+	// 构建批量插入语句（按数据库的参数上限拆分）
+	_, buildSpan := tracing.Start(ctx, "QueryBuilder.BuildBatchInsertChunks")
+	chunks, err := s.builder.BuildBatchInsertChunks(req)
+	tracing.End(buildSpan, err)
 	if err != nil {
 		return s.createErrorResponse(model.SQLErrorSyntax, "Batch insert query building failed", err.Error()), nil
 	}
-	
+
 	// 执行批量插入
-	result, err := s.sqlEngine.ExecuteSQL(ctx, query, params)
+	results, err := s.sqlEngine.ExecuteInsertChunks(ctx, chunks, req.Transactional)
 	if err != nil {
 		return s.handleExecutionError(err), nil
 	}
-	
+
+	// 汇总各分批语句的结果，未使用事务时部分语句可能已提交
+	var affectedRows int64
+	var firstErr error
+	chunkResults := make([]model.BatchInsertChunkResult, len(results))
+	for i, result := range results {
+		chunkResults[i] = model.BatchInsertChunkResult{
+			Index:        i,
+			Rows:         result.Rows,
+			AffectedRows: result.AffectedRows,
+			LastInsertID: result.LastInsertID,
+			Success:      result.Err == nil,
+		}
+		if result.Err != nil {
+			chunkResults[i].Error = result.Err.Error()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to execute chunk %d: %w", i, result.Err)
+			}
+			continue
+		}
+		affectedRows += result.AffectedRows
+	}
+
 	// 构建响应
-	response := model.NewSQLSuccessResponse(nil, result.AffectedRows, "Batch insert executed successfully")
+	response := model.NewSQLSuccessResponse(nil, affectedRows, "Batch insert executed successfully")
+	if firstErr != nil {
+		response = *s.handleExecutionError(firstErr)
+		response.AffectedRows = affectedRows
+	}
+	response.Chunks = chunkResults
 	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
 	
 	return &response, nil
 }
 
+// CallRoutine 调用存储过程或函数
+func (s *sqlService) CallRoutine(ctx context.Context, req *model.CallRequest) (*model.CallResponse, error) {
+	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlService.CallRoutine")
+	defer span.End()
+
+	if req == nil {
+		response := model.NewCallErrorResponse("", model.SQLErrorParams, "Request validation failed", "request cannot be nil")
+		return &response, nil
+	}
+
+	// 验证数据库类型
+	if req.DatabaseType != s.sqlEngine.GetDatabaseType() {
+		response := model.NewCallErrorResponse(req.Routine, model.SQLErrorParams, "Request validation failed",
+			fmt.Sprintf("database type mismatch: expected %s, got %s", s.sqlEngine.GetDatabaseType(), req.DatabaseType))
+		return &response, nil
+	}
+
+	result, err := s.sqlEngine.CallRoutine(ctx, req.Routine, req.Params)
+	if err != nil {
+		return s.handleCallError(req.Routine, err), nil
+	}
+
+	response := &model.CallResponse{
+		Success:       true,
+		Message:       "Routine called successfully",
+		Routine:       req.Routine,
+		ReturnValue:   result.ReturnValue,
+		OutParams:     result.OutParams,
+		Data:          result.Rows,
+		Columns:       result.Columns,
+		Timestamp:     time.Now(),
+		ExecutionTime: float64(time.Since(startTime).Nanoseconds()) / 1e6,
+	}
+
+	return response, nil
+}
+
 // HealthCheck 健康检查
 func (s *sqlService) HealthCheck() error {
 	if s.sqlEngine == nil {
@@ -260,10 +467,27 @@ func (s *sqlService) validateSQLRequest(req *model.SQLRequest) error {
 		return errors.New("either SQL or Query must be provided")
 	}
 
+	// 方言转换仅适用于原生 SQL
+	if req.SQLDialect != "" {
+		if req.SQL == "" {
+			return errors.New("sql_dialect can only be used with SQL")
+		}
+		if sql.NormalizeSQLDialect(req.SQLDialect) == "" {
+			return fmt.Errorf("unsupported sql dialect: %s", req.SQLDialect)
+		}
+	}
+
 	if req.SQL != "" && req.Query != nil {
 		return errors.New("cannot provide both SQL and Query")
 	}
 
+	// 软删除的表只能通过结构化查询访问
+	if req.SQL != "" {
+		if err := checkSoftDeleteTables(s.sqlEngine, s.config, req.SQL); err != nil {
+			return err
+		}
+	}
+
 	// 验证结构化查询
 	if req.Query != nil {
 		if err := s.validateStructuredQuery(req.Query); err != nil {
@@ -300,6 +524,28 @@ func (s *sqlService) validateStructuredQuery(query *model.StructuredQuery) error
 		if len(query.Where) == 0 {
 			return errors.New("where condition is required for delete operation")
 		}
+	case "restore":
+		if len(query.Where) == 0 {
+			return errors.New("where condition is required for restore operation")
+		}
+		if _, ok := s.config.GetSoftDelete(query.Table); !ok {
+			return fmt.Errorf("table %s has no soft delete configured", query.Table)
+		}
+	}
+
+	// 包含已删除的行只用于查询和更新
+	if query.IncludeDeleted && query.Action != "select" && query.Action != "update" {
+		return errors.New("include_deleted is only supported for select and update operations")
+	}
+
+	// 期望版本只用于更新、删除和恢复
+	if query.ExpectedVersion != nil || query.IfMatch != "" {
+		if query.Action != "update" && query.Action != "delete" && query.Action != "restore" {
+			return errors.New("expected_version and If-Match are only supported for update, delete and restore operations")
+		}
+		if query.ExpectedVersion != nil && query.IfMatch != "" {
+			return errors.New("cannot provide both expected_version and If-Match")
+		}
 	}
 
 	return nil
@@ -389,19 +635,75 @@ func (s *sqlService) validateBatchInsertRequest(req *model.BatchInsertRequest) e
 		return fmt.Errorf("invalid on_conflict action: %s", req.OnConflict)
 	}
 
+	// 验证缺失字段的处理方式
+	switch req.MissingFields {
+	case "", model.MissingFieldsDefault, model.MissingFieldsNull:
+	default:
+		return fmt.Errorf("invalid missing_fields: %s", req.MissingFields)
+	}
+
 	return nil
 }
 
+// validateWriteRecords 按表结构校验并转换写入的数据，校验失败时返回包含所有字段错误的 4002 响应
+// 未开启 validate_writes 或表结构不可用（如目录查询失败）时原样返回，由数据库报告错误
+func (s *sqlService) validateWriteRecords(ctx context.Context, table string, records []map[string]interface{}, insert, batch bool) ([]map[string]interface{}, *model.SQLResponse) {
+	if !s.config.ValidateWrites || s.schema == nil {
+		return records, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "sqlService.validateWriteRecords")
+	defer span.End()
+
+	schema, err := s.schema.GetTable(ctx, table)
+	if err != nil {
+		return records, nil
+	}
+
+	return validateWriteBatch(schema, records, insert, batch, s.sqlEngine.GetDatabaseType())
+}
+
+// fillMissingFields 为每条记录补齐所有记录字段的并集，缺少的字段为 null
+func fillMissingFields(records []map[string]interface{}) []map[string]interface{} {
+	fields := make(map[string]bool)
+	for _, record := range records {
+		for field := range record {
+			fields[field] = true
+		}
+	}
+
+	filled := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		filled[i] = make(map[string]interface{}, len(fields))
+		for field := range fields {
+			filled[i][field] = record[field]
+		}
+	}
+	return filled
+}
+
 // buildQuery 构建查询
-func (s *sqlService) buildQuery(req *model.SQLRequest) (string, map[string]interface{}, error) {
+func (s *sqlService) buildQuery(ctx context.Context, req *model.SQLRequest) (string, map[string]interface{}, error) {
 	if req.SQL != "" {
-		// 使用原生 SQL
+		// 使用原生 SQL，指定源方言时转换为目标数据库的 SQL
+		if req.SQLDialect != "" {
+			_, span := tracing.Start(ctx, "SQLTranslator.Translate")
+			query, err := s.translator.Translate(req.SQL, req.SQLDialect)
+			tracing.End(span, err)
+			if err != nil {
+				return "", nil, fmt.Errorf("SQL translation failed: %w", err)
+			}
+			return query, req.Params, nil
+		}
 		return req.SQL, req.Params, nil
 	}
 
 	if req.Query != nil {
 		// 使用结构化查询
-		return s.builder.BuildStructuredQuery(req.Query)
+		_, span := tracing.Start(ctx, "QueryBuilder.BuildStructuredQuery")
+		query, params, err := s.builder.BuildStructuredQuery(req.Query)
+		tracing.End(span, err)
+		return query, params, err
 	}
 
 	return "", nil, errors.New("no query provided")
@@ -441,8 +743,21 @@ func (s *sqlService) buildQueryResponse(result *sql.QueryResult, req *model.SQLR
 	return &response
 }
 
+// buildDebugInfo 构建调试信息，仅在请求开启 debug 时返回
+func (s *sqlService) buildDebugInfo(req *model.SQLRequest, query string) *model.SQLDebugInfo {
+	if !req.Debug {
+		return nil
+	}
+
+	return &model.SQLDebugInfo{
+		SQLDialect:    req.SQLDialect,
+		DatabaseType:  s.sqlEngine.GetDatabaseType(),
+		TranslatedSQL: query,
+	}
+}
+
 // buildBatchResponse 构建批量响应
-func (s *sqlService) buildBatchResponse(result *sql.BatchResult, req *model.BatchSQLRequest) *model.BatchSQLResponse {
+func (s *sqlService) buildBatchResponse(result *sql.BatchResult, req *model.BatchSQLRequest, queries []sql.BatchQuery) *model.BatchSQLResponse {
 	var results []model.SQLOperationResult
 
 	for i, sqlResult := range result.Results {
@@ -450,6 +765,10 @@ func (s *sqlService) buildBatchResponse(result *sql.BatchResult, req *model.Batc
 			Index:        i,
 			Success:      true,
 			AffectedRows: sqlResult.AffectedRows,
+			LastInsertID: sqlResult.LastInsertID,
+		}
+		if i < len(req.Operations) && i < len(queries) {
+			opResult.Debug = s.buildDebugInfo(&req.Operations[i], queries[i].SQL)
 		}
 		results = append(results, opResult)
 	}
@@ -479,31 +798,60 @@ func (s *sqlService) createBatchErrorResponse(code int, message, details string)
 
 // handleExecutionError 处理执行错误
 func (s *sqlService) handleExecutionError(err error) *model.SQLResponse {
+	return executionErrorResponse(err)
+}
+
+// executionErrorResponse 根据执行错误创建错误响应
+func executionErrorResponse(err error) *model.SQLResponse {
 	// 根据错误类型返回相应的错误码
 	errMsg := err.Error()
+	createErrorResponse := func(code int, message, details string) *model.SQLResponse {
+		response := model.NewSQLErrorResponse(code, message, details)
+		return &response
+	}
 
 	if contains(errMsg, "timeout") || contains(errMsg, "context deadline exceeded") {
-		return s.createErrorResponse(model.SQLErrorTimeout, "Query timeout", errMsg)
+		return createErrorResponse(model.SQLErrorTimeout, "Query timeout", errMsg)
 	}
 
 	if contains(errMsg, "connection") || contains(errMsg, "connect") {
-		return s.createErrorResponse(model.SQLErrorConnection, "Database connection error", errMsg)
+		return createErrorResponse(model.SQLErrorConnection, "Database connection error", errMsg)
 	}
 
 	if contains(errMsg, "permission") || contains(errMsg, "access denied") {
-		return s.createErrorResponse(model.SQLErrorPermission, "Permission denied", errMsg)
+		return createErrorResponse(model.SQLErrorPermission, "Permission denied", errMsg)
 	}
 
 	if contains(errMsg, "syntax") || contains(errMsg, "invalid") {
-		return s.createErrorResponse(model.SQLErrorSyntax, "SQL syntax error", errMsg)
+		return createErrorResponse(model.SQLErrorSyntax, "SQL syntax error", errMsg)
 	}
 
 	if contains(errMsg, "result set too large") {
-		return s.createErrorResponse(model.SQLErrorResultSize, "Result set too large", errMsg)
+		return createErrorResponse(model.SQLErrorResultSize, "Result set too large", errMsg)
 	}
 
 	// 默认为语法错误
-	return s.createErrorResponse(model.SQLErrorSyntax, "SQL execution error", errMsg)
+	return createErrorResponse(model.SQLErrorSyntax, "SQL execution error", errMsg)
+}
+
+// handleCallError 处理例程调用错误
+func (s *sqlService) handleCallError(routine string, err error) *model.CallResponse {
+	var response model.CallResponse
+	var sqlErr *model.SQLError
+
+	switch {
+	case errors.Is(err, sql.ErrRoutineNotAllowed):
+		response = model.NewCallErrorResponse(routine, model.SQLErrorPermission, "Routine not allowed", err.Error())
+	case errors.Is(err, sql.ErrInvalidRoutineParams):
+		response = model.NewCallErrorResponse(routine, model.SQLErrorParams, "Invalid routine parameters", err.Error())
+	case errors.As(err, &sqlErr):
+		response = model.NewCallErrorResponse(routine, sqlErr.Code, sqlErr.Message, sqlErr.Details)
+	default:
+		fallback := s.handleExecutionError(err)
+		response = model.NewCallErrorResponse(routine, fallback.Error.Code, fallback.Error.Message, fallback.Error.Details)
+	}
+
+	return &response
 }
 
 // handleBatchExecutionError 处理批量执行错误
@@ -535,3 +883,39 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// ExecutionError 将执行错误转换为 SQL 错误，供不返回 SQLResponse 的接口映射状态码
+func ExecutionError(err error) *model.SQLError {
+	return executionErrorResponse(err).Error
+}
+
+// SQLAction 获取 SQL 请求的操作类型（query、insert、update、delete），用于检查 sql.<action> 权限，无法识别时返回空
+func SQLAction(req *model.SQLRequest) string {
+	if req.SQL != "" {
+		// 从原生 SQL 中提取操作类型
+		words := strings.Fields(strings.ToLower(req.SQL))
+		if len(words) > 0 {
+			switch words[0] {
+			case "select", "with":
+				return "query"
+			case "insert":
+				return "insert"
+			case "update":
+				return "update"
+			case "delete":
+				return "delete"
+			}
+		}
+	}
+
+	if req.Query != nil {
+		// 从结构化查询中获取操作类型
+		action := strings.ToLower(req.Query.Action)
+		if action == "select" {
+			return "query"
+		}
+		return action
+	}
+
+	return ""
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// StatementService 语句统计服务接口
+type StatementService interface {
+	// 按语句指纹汇总的执行统计
+	List(req *model.StatementStatsRequest) *model.StatementStatsResponse
+
+	// 保留时间内最慢的查询样本
+	SlowQueries() []model.SlowQuerySample
+
+	// 清空语句统计和慢查询日志
+	Reset()
+
+	// 停止统计
+	Close()
+}
+
+// statementService 语句统计服务实现
+type statementService struct {
+	stats  *sql.StatementStats
+	detach func()
+}
+
+// NewStatementService 创建语句统计服务，并为数据库连接上的所有 SQLEngine 启用统计
+func NewStatementService(repos *repository.Repositories, cfg *config.SQLConfig) (StatementService, error) {
+	stats := sql.NewStatementStats(&cfg.StatementStats)
+	return &statementService{
+		stats:  stats,
+		detach: sql.AttachStatementStats(repos.GetDB(), stats),
+	}, nil
+}
+
+// List 返回语句统计
+func (s *statementService) List(req *model.StatementStatsRequest) *model.StatementStatsResponse {
+	return s.stats.List(req)
+}
+
+// SlowQueries 返回慢查询样本
+func (s *statementService) SlowQueries() []model.SlowQuerySample {
+	return s.stats.SlowQueries()
+}
+
+// Reset 清空统计
+func (s *statementService) Reset() {
+	s.stats.Reset()
+}
+
+// Close 停止统计
+func (s *statementService) Close() {
+	s.detach()
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+var (
+	// ErrInvalidSubscriptionFilter 订阅过滤条件无效
+	ErrInvalidSubscriptionFilter = errors.New("invalid subscription filter")
+	// ErrSubscriptionsClosed 服务器正在关闭，不再接受订阅
+	ErrSubscriptionsClosed = errors.New("subscriptions are closed")
+)
+
+// SubscriptionService 表变更订阅服务接口
+type SubscriptionService interface {
+	// 订阅允许访问的表的变更事件，where 为结构化过滤条件（可为空）
+	Subscribe(ctx context.Context, table string, where map[string]interface{}) (*sql.Subscription, error)
+
+	// 心跳间隔
+	HeartbeatInterval() time.Duration
+
+	// 关闭所有订阅并停止数据库监听
+	Close()
+}
+
+// subscriptionService 表变更订阅服务实现
+type subscriptionService struct {
+	sqlEngine     *sql.SQLEngine
+	schemaService SchemaService
+	config        *config.SubscriptionConfig
+	listener      *sql.ChangeListener
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewSubscriptionService 创建表变更订阅服务，notify 模式下安装触发器并开始监听
+func NewSubscriptionService(repos *repository.Repositories, cfg *config.SQLConfig, schemaService SchemaService) (SubscriptionService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	if schemaService == nil {
+		return nil, errors.New("schema service cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	service := &subscriptionService{
+		sqlEngine:     engine,
+		schemaService: schemaService,
+		config:        &cfg.Subscriptions,
+	}
+
+	if cfg.Subscriptions.Source == config.SubscriptionSourceNotify {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.MaxQueryTime)*time.Second)
+		defer cancel()
+
+		service.listener, err = engine.StartChangeListener(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return service, nil
+}
+
+// Subscribe 订阅表的变更事件
+// 过滤条件与结构化查询的 where 格式相同，只能引用表中存在的列
+func (s *subscriptionService) Subscribe(ctx context.Context, table string, where map[string]interface{}) (*sql.Subscription, error) {
+	schema, err := s.schemaService.GetTable(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter *sql.RowFilter
+	if len(where) > 0 {
+		filter, err = sql.NewRowFilter(where)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSubscriptionFilter, err)
+		}
+
+		columns := make(map[string]bool, len(schema.Columns))
+		for _, column := range schema.Columns {
+			columns[column.Name] = true
+		}
+		for _, column := range filter.Columns() {
+			if !columns[column] {
+				return nil, fmt.Errorf("%w: unknown column %s", ErrInvalidSubscriptionFilter, column)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrSubscriptionsClosed
+	}
+
+	return s.sqlEngine.Changes().Subscribe(schema.Name, filter, s.config.BufferSize), nil
+}
+
+// HeartbeatInterval 获取心跳间隔
+func (s *subscriptionService) HeartbeatInterval() time.Duration {
+	return time.Duration(s.config.HeartbeatInterval) * time.Second
+}
+
+// Close 关闭所有订阅并停止数据库监听
+func (s *subscriptionService) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	s.sqlEngine.Changes().Close()
+}
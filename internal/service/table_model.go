@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"sql2api/internal/sql"
+)
+
+// apiNamePattern GraphQL 和 OData 共用的名称格式
+var apiNamePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// apiTable 对外公开的表（GraphQL 对象类型、OData 实体类型）
+type apiTable struct {
+	typeName   string
+	table      string
+	columns    []sql.ColumnSchema
+	columnMap  map[string]sql.ColumnSchema
+	primaryKey []string
+	relations  []*apiRelation
+}
+
+// apiRelation 由外键推导的关联（GraphQL 关联字段、OData 导航属性）
+type apiRelation struct {
+	name         string
+	target       *apiTable
+	localColumn  string
+	remoteColumn string
+	many         bool // 反向关联（一对多）返回列表
+}
+
+// loadAPITables 读取允许访问的表结构，并由单列外键推导双向关联
+func loadAPITables(ctx context.Context, schemaService SchemaService) ([]*apiTable, error) {
+	var tables []*apiTable
+	byTable := make(map[string]*apiTable)
+	schemas := make(map[*apiTable]*sql.TableSchema)
+
+	for _, name := range schemaService.ListTables() {
+		tableSchema, err := schemaService.GetTable(ctx, name)
+		if err != nil {
+			// 配置中存在但数据库中不存在的表不公开
+			if errors.Is(err, sql.ErrTableNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		t := &apiTable{
+			typeName:  apiName(name),
+			table:     name,
+			columnMap: make(map[string]sql.ColumnSchema),
+		}
+		for _, column := range tableSchema.Columns {
+			// 不是合法名称的列不公开
+			if !apiNamePattern.MatchString(column.Name) {
+				continue
+			}
+			t.columns = append(t.columns, column)
+			t.columnMap[column.Name] = column
+		}
+		t.primaryKey = tableSchema.PrimaryKey
+
+		tables = append(tables, t)
+		schemas[t] = tableSchema
+		byTable[strings.ToLower(tableName(name))] = t
+	}
+
+	// 正向关联为单个对象，反向关联为列表
+	for _, t := range tables {
+		for _, fk := range schemas[t].ForeignKeys {
+			if len(fk.Columns) != 1 || len(fk.ReferencedColumns) != 1 {
+				continue
+			}
+			target, ok := byTable[strings.ToLower(fk.ReferencedTable)]
+			if !ok {
+				continue
+			}
+			local, okLocal := t.column(fk.Columns[0])
+			remote, okRemote := target.column(fk.ReferencedColumns[0])
+			if !okLocal || !okRemote {
+				continue
+			}
+
+			forwardName := target.typeName
+			if trimmed := strings.TrimSuffix(strings.ToLower(local), "_id"); trimmed != strings.ToLower(local) && trimmed != "" {
+				forwardName = local[:len(trimmed)]
+			}
+			t.addRelation(&apiRelation{
+				name:         uniqueFieldName(t, forwardName, target.typeName+"_by_"+local),
+				target:       target,
+				localColumn:  local,
+				remoteColumn: remote,
+			})
+			target.addRelation(&apiRelation{
+				name:         uniqueFieldName(target, t.typeName, t.typeName+"_by_"+local),
+				target:       t,
+				localColumn:  remote,
+				remoteColumn: local,
+				many:         true,
+			})
+		}
+	}
+
+	return tables, nil
+}
+
+// column 按名称（忽略大小写）查找公开的列
+func (t *apiTable) column(name string) (string, bool) {
+	if _, ok := t.columnMap[name]; ok {
+		return name, true
+	}
+	for _, column := range t.columns {
+		if strings.EqualFold(column.Name, name) {
+			return column.Name, true
+		}
+	}
+	return "", false
+}
+
+// relation 按名称查找关联
+func (t *apiTable) relation(name string) (*apiRelation, bool) {
+	for _, rel := range t.relations {
+		if rel.name == name {
+			return rel, true
+		}
+	}
+	return nil, false
+}
+
+// addRelation 添加关联
+func (t *apiTable) addRelation(rel *apiRelation) {
+	if rel.name != "" {
+		t.relations = append(t.relations, rel)
+	}
+}
+
+// uniqueFieldName 返回不与列和已有关联冲突的名称，两个候选都冲突时返回空
+func uniqueFieldName(t *apiTable, preferred, fallback string) string {
+	for _, name := range []string{preferred, fallback} {
+		if _, isColumn := t.columnMap[name]; isColumn {
+			continue
+		}
+		if _, isRelation := t.relation(name); isRelation {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
+// apiName 将表名转换为合法名称（schema.table 转为 schema_table）
+func apiName(name string) string {
+	var sb strings.Builder
+	for i, ch := range name {
+		switch {
+		case ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z'):
+			sb.WriteRune(ch)
+		case ch >= '0' && ch <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(ch)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+// tableName 去掉表名中的 schema 前缀
+func tableName(name string) string {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// TableService 表查询服务接口（PostgREST 风格 URL 查询）
+type TableService interface {
+	// 执行 URL 查询，Prefer: count=exact 时在 Total 中返回满足条件的总行数
+	Query(ctx context.Context, query *sql.URLQuery) (*model.SQLResponse, error)
+}
+
+// tableService 表查询服务实现
+type tableService struct {
+	sqlEngine *sql.SQLEngine
+	builder   *sql.QueryBuilder
+}
+
+// NewTableService 创建表查询服务
+func NewTableService(repos *repository.Repositories, cfg *config.SQLConfig) (TableService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	return &tableService{
+		sqlEngine: engine,
+		builder:   newTableQueryBuilder(engine.GetDatabaseType(), cfg),
+	}, nil
+}
+
+// Query 执行 URL 查询
+func (s *tableService) Query(ctx context.Context, query *sql.URLQuery) (*model.SQLResponse, error) {
+	startTime := time.Now()
+
+	if !s.sqlEngine.IsTableAllowed(query.Query.Table) {
+		return nil, fmt.Errorf("%w: %s", sql.ErrTableNotAllowed, query.Query.Table)
+	}
+
+	sqlQuery, params, err := s.builder.BuildStructuredQuery(query.Query)
+	if err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid query", err.Error())
+		return &response, nil
+	}
+
+	// 分页：只指定偏移量时不限制行数（仍受 max_result_size 限制）
+	if query.Limit > 0 || query.Offset > 0 {
+		limit := query.Limit
+		if limit == 0 {
+			limit = math.MaxInt32
+		}
+		sqlQuery = s.builder.ApplyPagination(sqlQuery, query.Offset, limit)
+	}
+
+	result, err := s.sqlEngine.ExecuteQuery(ctx, sqlQuery, params)
+	if err != nil {
+		return executionErrorResponse(err), nil
+	}
+
+	response := model.NewSQLSuccessResponse(result.Rows, 0, "Query executed successfully")
+	response.Columns = result.Columns
+	response.Total = result.Total
+
+	// 总行数使用相同的过滤条件单独统计
+	if query.Count {
+		total, err := s.count(ctx, query.Query)
+		if err != nil {
+			return executionErrorResponse(err), nil
+		}
+		response.Total = total
+	}
+
+	response.ExecutionTime = float64(time.Since(startTime).Nanoseconds()) / 1e6
+	return &response, nil
+}
+
+// count 统计满足过滤条件的总行数
+func (s *tableService) count(ctx context.Context, query *model.StructuredQuery) (int64, error) {
+	return countRows(ctx, s.sqlEngine, s.builder, query)
+}
+
+// countRows 使用 COUNT(*) 统计满足 filter 的表、过滤条件和软删除选项的行数
+func countRows(ctx context.Context, engine *sql.SQLEngine, builder *sql.QueryBuilder, filter *model.StructuredQuery) (int64, error) {
+	sqlQuery, params, err := builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:          filter.Table,
+		Action:         "select",
+		Fields:         []string{"COUNT(*) AS total"},
+		Where:          filter.Where,
+		IncludeDeleted: filter.IncludeDeleted,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := engine.ExecuteQuery(ctx, sqlQuery, params)
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Rows) != 1 {
+		return 0, errors.New("count query returned no rows")
+	}
+
+	for _, value := range result.Rows[0] {
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case int32:
+			return int64(v), nil
+		case int:
+			return int64(v), nil
+		case float64:
+			return int64(v), nil
+		case []byte:
+			if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+				return n, nil
+			}
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+		return 0, fmt.Errorf("unexpected count value: %v", value)
+	}
+	return 0, errors.New("count query returned no columns")
+}
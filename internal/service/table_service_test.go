@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"sql2api/internal/config"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+// newTestRepositories 创建内存 SQLite 数据库并执行建表语句
+func newTestRepositories(t *testing.T, statements ...string) *repository.Repositories {
+	t.Helper()
+
+	repos, err := repository.NewRepositories(&config.DatabaseConfig{
+		Type:         "sqlite",
+		Path:         ":memory:",
+		MaxOpenConns: 1, // 内存数据库每个连接相互独立
+		MaxIdleConns: 1,
+		MaxLifetime:  60,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	for _, statement := range statements {
+		if err := repos.GetDB().Exec(statement).Error; err != nil {
+			t.Fatalf("Failed to execute %q: %v", statement, err)
+		}
+	}
+	return repos
+}
+
+// newTestSQLConfig 创建只允许访问指定表的 SQL 配置
+func newTestSQLConfig(tables ...string) *config.SQLConfig {
+	return &config.SQLConfig{
+		Enabled:        true,
+		AllowedTables:  tables,
+		AllowedActions: []string{"select", "insert", "update", "delete"},
+		MaxQueryTime:   5,
+		MaxResultSize:  100,
+		EnableRawSQL:   true,
+	}
+}
+
+func TestTableService_TimestampColumns(t *testing.T) {
+	repos := newTestRepositories(t,
+		`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, created_at TEXT, updated_at TEXT, deleted_by TEXT)`,
+		`INSERT INTO items (id, name, created_at) VALUES (1, 'old', '2024-01-01'), (2, 'new', '2024-06-01')`,
+	)
+	service, err := NewTableService(repos, newTestSQLConfig("items"))
+	if err != nil {
+		t.Fatalf("NewTableService failed: %v", err)
+	}
+
+	values, _ := url.ParseQuery("select=id,created_at,updated_at,deleted_by&order=created_at.desc")
+	query, err := sql.ParseURLQuery("items", values, http.Header{})
+	if err != nil {
+		t.Fatalf("ParseURLQuery failed: %v", err)
+	}
+
+	response, err := service.Query(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !response.Success {
+		t.Fatalf("Expected success, got %+v", response.Error)
+	}
+	if len(response.Data) != 2 || response.Data[0]["created_at"] != "2024-06-01" {
+		t.Errorf("Expected rows ordered by created_at desc, got %v", response.Data)
+	}
+}
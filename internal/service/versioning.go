@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strings"
+
+	"sql2api/internal/model"
+	"sql2api/internal/sql"
+)
+
+// resolveIfMatch 将结构化查询的 If-Match 实体标签转换为期望版本
+// 标签无效时返回参数错误，弱标签按强比较规则永远不匹配，返回前置条件失败；返回 nil 时可继续执行
+func resolveIfMatch(query *model.StructuredQuery) *model.SQLResponse {
+	if query.IfMatch == "" {
+		return nil
+	}
+
+	tag, weak, err := sql.ParseVersionTag(query.IfMatch)
+	if err != nil {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Invalid If-Match header", err.Error())
+		return &response
+	}
+	if weak {
+		return versionMismatchResponse(query)
+	}
+	if tag != "" {
+		query.ExpectedVersion = tag
+	}
+	return nil
+}
+
+// versionMismatchResponse 创建版本不匹配响应：来自 If-Match 时为前置条件失败（412），否则为版本冲突（409）
+func versionMismatchResponse(query *model.StructuredQuery) *model.SQLResponse {
+	if query.IfMatch != "" {
+		response := model.NewSQLErrorResponse(model.SQLErrorPrecondition, "Precondition failed", "If-Match does not match the current version")
+		return &response
+	}
+	response := model.NewSQLErrorResponse(model.SQLErrorConflict, "Version conflict", "no rows matched the expected version")
+	return &response
+}
+
+// versionValue 获取行数据中版本列的取值（忽略大小写，兼容 Oracle 大写列名）
+func versionValue(row map[string]interface{}, column string) interface{} {
+	for name, value := range row {
+		if strings.EqualFold(name, column) {
+			return value
+		}
+	}
+	return nil
+}
+
+// containsFold 检查列表中是否包含指定列名（忽略大小写）
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
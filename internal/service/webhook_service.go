@@ -0,0 +1,509 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+	"sql2api/internal/sql"
+)
+
+var (
+	// ErrWebhookEndpointNotFound webhook 端点不存在
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	// ErrWebhookEndpointReadOnly 配置文件中定义的端点不能通过 API 修改
+	ErrWebhookEndpointReadOnly = errors.New("webhook endpoint is defined in the configuration file")
+)
+
+// webhook 端点来源
+const (
+	WebhookSourceConfig = "config" // 配置文件
+	WebhookSourceAPI    = "api"    // 通过管理 API 注册，保存在本地存储中
+)
+
+const (
+	// webhookBatchSize 每轮投递的最大记录数
+	webhookBatchSize = 100
+	// webhookConcurrency 同时进行的投递数
+	webhookConcurrency = 4
+	// webhookIdleInterval 队列为空时的轮询间隔
+	webhookIdleInterval = time.Minute
+	// webhookMaxErrorLength 记录的错误信息最大长度
+	webhookMaxErrorLength = 1024
+	// webhookDeadLetterLimit 死信列表返回的最大记录数
+	webhookDeadLetterLimit = 1000
+)
+
+// WebhookEndpoint webhook 端点及其来源
+type WebhookEndpoint struct {
+	config.WebhookEndpointConfig
+	Source string `json:"source"`
+}
+
+// WebhookPayload webhook 请求体
+type WebhookPayload struct {
+	Webhook      string                   `json:"webhook"`
+	EventID      uint64                   `json:"event_id"`
+	Table        string                   `json:"table"`
+	Action       string                   `json:"action"`
+	Keys         []map[string]interface{} `json:"keys,omitempty"`
+	AffectedRows int64                    `json:"affected_rows"`
+	Rows         []map[string]interface{} `json:"rows,omitempty"`
+	Timestamp    time.Time                `json:"timestamp"`
+}
+
+// WebhookService 出站 webhook 服务接口
+type WebhookService interface {
+	// 列出所有端点（按名称排序）
+	ListEndpoints() []WebhookEndpoint
+
+	// 新增或替换通过 API 注册的端点
+	SaveEndpoint(endpoint *config.WebhookEndpointConfig) error
+
+	// 删除通过 API 注册的端点
+	DeleteEndpoint(name string) error
+
+	// 列出死信记录
+	ListDeadLetters() ([]model.WebhookDelivery, error)
+
+	// 将死信记录重新加入投递队列
+	ReplayDeadLetter(id uint64) error
+
+	// 删除死信记录
+	DeleteDeadLetter(id uint64) error
+
+	// 停止接收变更并等待进行中的投递结束
+	Close()
+}
+
+// webhookService 出站 webhook 服务实现
+type webhookService struct {
+	sqlEngine     *sql.SQLEngine
+	schemaService SchemaService
+	config        *config.WebhookConfig
+	allowedTables []string
+	store         *repository.WebhookStore
+	client        *http.Client
+
+	mu        sync.RWMutex
+	endpoints map[string]WebhookEndpoint
+	closed    bool
+
+	unregister func()
+	wake       chan struct{}
+	cancel     context.CancelFunc
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewWebhookService 创建出站 webhook 服务，注册网关写操作的变更处理函数并启动投递
+func NewWebhookService(repos *repository.Repositories, cfg *config.SQLConfig, schemaService SchemaService) (WebhookService, error) {
+	if repos == nil {
+		return nil, errors.New("repositories cannot be nil")
+	}
+
+	if cfg == nil {
+		return nil, errors.New("SQL configuration cannot be nil")
+	}
+
+	if schemaService == nil {
+		return nil, errors.New("schema service cannot be nil")
+	}
+
+	// 创建 SQL 查询引擎
+	engine, err := sql.NewSQLEngine(repos, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL engine: %w", err)
+	}
+
+	store, err := repository.NewWebhookStore(cfg.Webhooks.QueuePath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &webhookService{
+		sqlEngine:     engine,
+		schemaService: schemaService,
+		config:        &cfg.Webhooks,
+		allowedTables: cfg.AllowedTables,
+		store:         store,
+		client:        &http.Client{Timeout: time.Duration(cfg.Webhooks.Timeout) * time.Second},
+		endpoints:     make(map[string]WebhookEndpoint),
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+
+	// 配置文件中的端点优先于 API 注册的同名端点
+	registered, err := store.ListEndpoints()
+	if err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to load webhook endpoints: %w", err)
+	}
+	for _, endpoint := range registered {
+		s.endpoints[endpoint.Name] = WebhookEndpoint{WebhookEndpointConfig: endpoint, Source: WebhookSourceAPI}
+	}
+	for _, endpoint := range cfg.Webhooks.Endpoints {
+		s.endpoints[endpoint.Name] = WebhookEndpoint{WebhookEndpointConfig: endpoint, Source: WebhookSourceConfig}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.unregister = engine.Changes().OnChange(s.enqueue)
+	go s.run(ctx)
+
+	return s, nil
+}
+
+// ListEndpoints 列出所有端点（按名称排序）
+func (s *webhookService) ListEndpoints() []WebhookEndpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	endpoints := make([]WebhookEndpoint, 0, len(s.endpoints))
+	for _, endpoint := range s.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+	return endpoints
+}
+
+// SaveEndpoint 新增或替换通过 API 注册的端点，保存在本地存储中，重启后仍然有效
+func (s *webhookService) SaveEndpoint(endpoint *config.WebhookEndpointConfig) error {
+	if err := config.ValidateWebhookEndpoint(endpoint, s.allowedTables); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.endpoints[endpoint.Name]; ok && existing.Source == WebhookSourceConfig {
+		return ErrWebhookEndpointReadOnly
+	}
+	if err := s.store.SaveEndpoint(endpoint); err != nil {
+		return fmt.Errorf("failed to save webhook endpoint: %w", err)
+	}
+	s.endpoints[endpoint.Name] = WebhookEndpoint{WebhookEndpointConfig: *endpoint, Source: WebhookSourceAPI}
+	return nil
+}
+
+// DeleteEndpoint 删除通过 API 注册的端点，队列中尚未投递的记录会在投递时移入死信列表
+func (s *webhookService) DeleteEndpoint(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.endpoints[name]
+	if !ok {
+		return ErrWebhookEndpointNotFound
+	}
+	if existing.Source == WebhookSourceConfig {
+		return ErrWebhookEndpointReadOnly
+	}
+	if err := s.store.DeleteEndpoint(name); err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	delete(s.endpoints, name)
+	return nil
+}
+
+// ListDeadLetters 列出死信记录（最新的在前）
+func (s *webhookService) ListDeadLetters() ([]model.WebhookDelivery, error) {
+	return s.store.ListDead(webhookDeadLetterLimit)
+}
+
+// ReplayDeadLetter 将死信记录重新加入投递队列
+func (s *webhookService) ReplayDeadLetter(id uint64) error {
+	if err := s.store.Replay(id); err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// DeleteDeadLetter 删除死信记录
+func (s *webhookService) DeleteDeadLetter(id uint64) error {
+	return s.store.DeleteDead(id)
+}
+
+// Close 停止接收变更并等待进行中的投递结束，未投递的记录保留在队列中，下次启动时继续投递
+func (s *webhookService) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+
+		s.unregister()
+		s.cancel()
+		<-s.done
+		if err := s.store.Close(); err != nil {
+			log.Printf("Failed to close webhook store: %v", err)
+		}
+	})
+}
+
+// enqueue 将网关写操作的变更事件写入订阅端点的投递队列
+// 在写操作提交后同步调用，写入失败只记录日志，不影响已完成的写操作
+func (s *webhookService) enqueue(ctx context.Context, event *sql.ChangeEvent) {
+	table := event.Table
+	var primaryKey []string
+	if schema, err := s.schemaService.GetTable(ctx, event.Table); err == nil {
+		table = schema.Name
+		primaryKey = schema.PrimaryKey
+	}
+
+	// 持有读锁直到写入队列，关闭时等待写入完成
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	var deliveries []*model.WebhookDelivery
+	for _, endpoint := range s.endpoints {
+		if !endpoint.Matches(table, event.Operation) {
+			continue
+		}
+
+		payload := WebhookPayload{
+			Webhook:      endpoint.Name,
+			EventID:      event.ID,
+			Table:        table,
+			Action:       event.Operation,
+			Keys:         eventKeys(event, primaryKey),
+			AffectedRows: event.AffectedRows,
+			Timestamp:    event.Timestamp,
+		}
+		if event.Row != nil {
+			payload.AffectedRows = 1
+			if endpoint.IncludeRows {
+				payload.Rows = []map[string]interface{}{event.Row}
+			}
+		}
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			log.Printf("Failed to encode webhook payload for %s: %v", endpoint.Name, err)
+			continue
+		}
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			Endpoint: endpoint.Name,
+			Table:    table,
+			Action:   event.Operation,
+			Payload:  body,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return
+	}
+	if err := s.store.Enqueue(deliveries); err != nil {
+		log.Printf("Failed to enqueue webhook deliveries for %s %s: %v", event.Operation, table, err)
+		return
+	}
+	s.notify()
+}
+
+// eventKeys 按主键提取受影响行的键，主键未知或键不完整时返回空
+func eventKeys(event *sql.ChangeEvent, primaryKey []string) []map[string]interface{} {
+	if len(primaryKey) == 0 {
+		return nil
+	}
+
+	candidates := event.Keys
+	if event.Row != nil {
+		candidates = []map[string]interface{}{event.Row}
+	}
+
+	keys := make([]map[string]interface{}, 0, len(candidates))
+	for _, candidate := range candidates {
+		key := make(map[string]interface{}, len(primaryKey))
+		for _, column := range primaryKey {
+			value, ok := candidate[column]
+			if !ok || value == nil {
+				key = nil
+				break
+			}
+			key[column] = value
+		}
+		if key == nil {
+			keys = nil
+			break
+		}
+		keys = append(keys, key)
+	}
+
+	// 自增主键未包含在插入的数据中时使用 LastInsertID
+	if len(keys) == 0 && event.Operation == sql.ChangeInsert && event.LastInsertID != 0 && len(primaryKey) == 1 {
+		keys = []map[string]interface{}{{primaryKey[0]: event.LastInsertID}}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys
+}
+
+// notify 唤醒投递循环
+func (s *webhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run 投递循环：投递到期的记录，然后等待下一条记录到期或新记录入队
+func (s *webhookService) run(ctx context.Context) {
+	defer close(s.done)
+
+	for {
+		deliveries, err := s.store.Due(time.Now(), webhookBatchSize)
+		if err != nil {
+			log.Printf("Failed to read webhook queue: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, webhookConcurrency)
+		for i := range deliveries {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(delivery *model.WebhookDelivery) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				s.deliver(ctx, delivery)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// 本轮已满时立即继续
+		if len(deliveries) == webhookBatchSize {
+			continue
+		}
+
+		wait := webhookIdleInterval
+		if next, ok, err := s.store.NextAttempt(); err == nil && ok {
+			wait = min(max(time.Until(next), 0), webhookIdleInterval)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// deliver 投递一条记录并更新队列
+func (s *webhookService) deliver(ctx context.Context, delivery *model.WebhookDelivery) {
+	s.mu.RLock()
+	endpoint, ok := s.endpoints[delivery.Endpoint]
+	s.mu.RUnlock()
+
+	attempts := delivery.Attempts + 1
+	if !ok {
+		s.record(s.store.Kill(delivery.ID, attempts, ErrWebhookEndpointNotFound.Error()))
+		return
+	}
+
+	err := s.post(ctx, &endpoint.WebhookEndpointConfig, delivery)
+	if err == nil {
+		s.record(s.store.Complete(delivery.ID))
+		return
+	}
+
+	// 服务关闭导致的失败不计入投递次数，下次启动时重新投递
+	if ctx.Err() != nil {
+		return
+	}
+
+	message := err.Error()
+	if len(message) > webhookMaxErrorLength {
+		message = message[:webhookMaxErrorLength]
+	}
+	if attempts >= s.config.MaxAttempts {
+		log.Printf("Webhook delivery %d to %s failed after %d attempts: %s", delivery.ID, endpoint.Name, attempts, message)
+		s.record(s.store.Kill(delivery.ID, attempts, message))
+		return
+	}
+	s.record(s.store.Retry(delivery.ID, attempts, message, time.Now().Add(s.backoff(attempts))))
+}
+
+// backoff 计算第 attempts 次失败后的重试间隔（指数增长，不超过 max_backoff）
+func (s *webhookService) backoff(attempts int) time.Duration {
+	delay := time.Duration(s.config.InitialBackoff) * time.Second
+	limit := time.Duration(s.config.MaxBackoff) * time.Second
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// post 发送 webhook 请求，2xx 响应视为成功
+// 配置了密钥时，X-Webhook-Signature 为 "sha256=" 加上以密钥对 "<时间戳>.<请求体>" 计算的 HMAC-SHA256（十六进制）
+func (s *webhookService) post(ctx context.Context, endpoint *config.WebhookEndpointConfig, delivery *model.WebhookDelivery) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "sql2api-webhook/1.0")
+	request.Header.Set("X-Webhook-ID", strconv.FormatUint(delivery.ID, 10))
+	request.Header.Set("X-Webhook-Event", delivery.Table+"."+delivery.Action)
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	if endpoint.Secret != "" {
+		request.Header.Set("X-Webhook-Signature", "sha256="+signWebhook(endpoint.Secret, timestamp, delivery.Payload))
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 256))
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, bytes.TrimSpace(body))
+	}
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+	return nil
+}
+
+// signWebhook 计算 webhook 签名
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// record 记录队列更新失败
+func (s *webhookService) record(err error) {
+	if err != nil {
+		log.Printf("Failed to update webhook queue: %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"sql2api/internal/model"
+	"sql2api/internal/sql"
+)
+
+// columnTimeLayouts 日期和时间列接受的格式
+var columnTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// decimalPattern 十进制数值文本（不接受 Inf、NaN 和十六进制）
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// columnValueError 列值校验错误
+type columnValueError struct {
+	code    string
+	message string
+}
+
+// Error 实现 error 接口
+func (e *columnValueError) Error() string {
+	return e.message
+}
+
+// newColumnValueError 创建列值校验错误
+func newColumnValueError(code, format string, args ...interface{}) error {
+	return &columnValueError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// validateWriteBatch 按表结构校验并转换多条写入数据，校验失败时返回包含所有字段错误的 4002 响应
+// batch 为 true 时错误中的字段名带 data[i]. 前缀
+func validateWriteBatch(schema *sql.TableSchema, records []map[string]interface{}, insert, batch bool, dbType string) ([]map[string]interface{}, *model.SQLResponse) {
+	validated := make([]map[string]interface{}, len(records))
+	var fieldErrors []model.FieldError
+	for i, record := range records {
+		prefix := ""
+		if batch {
+			prefix = fmt.Sprintf("data[%d].", i)
+		}
+
+		var errs []model.FieldError
+		validated[i], errs = validateWriteData(schema, record, insert, dbType, prefix)
+		fieldErrors = append(fieldErrors, errs...)
+	}
+
+	if len(fieldErrors) > 0 {
+		response := model.NewSQLErrorResponse(model.SQLErrorParams, "Data validation failed", fmt.Sprintf("%d invalid field(s)", len(fieldErrors)))
+		response.Error.Fields = fieldErrors
+		return nil, &response
+	}
+	return validated, nil
+}
+
+// validateWriteData 按表结构校验并转换一条写入数据，返回转换后的数据和所有字段错误
+// insert 为 true 时检查缺少的 NOT NULL 列（没有默认值且不由数据库生成）；prefix 为错误中字段名的前缀
+func validateWriteData(schema *sql.TableSchema, data map[string]interface{}, insert bool, dbType, prefix string) (map[string]interface{}, []model.FieldError) {
+	columns := make(map[string]*sql.ColumnSchema, len(schema.Columns))
+	for i := range schema.Columns {
+		columns[strings.ToLower(schema.Columns[i].Name)] = &schema.Columns[i]
+	}
+
+	fields := make([]string, 0, len(data))
+	for field := range data {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	result := make(map[string]interface{}, len(data))
+	present := make(map[string]bool, len(data))
+	var fieldErrors []model.FieldError
+	for _, field := range fields {
+		column := columns[strings.ToLower(field)]
+		if column == nil {
+			fieldErrors = append(fieldErrors, model.FieldError{
+				Field:   prefix + field,
+				Code:    model.FieldErrorUnknownColumn,
+				Message: fmt.Sprintf("column %s does not exist in table %s", field, schema.Name),
+			})
+			continue
+		}
+		present[column.Name] = true
+
+		value, err := coerceColumnValue(column, data[field], dbType)
+		if err != nil {
+			code := model.FieldErrorInvalidType
+			var valueErr *columnValueError
+			if errors.As(err, &valueErr) {
+				code = valueErr.code
+			}
+			fieldErrors = append(fieldErrors, model.FieldError{Field: prefix + field, Code: code, Message: err.Error()})
+			continue
+		}
+		result[field] = value
+	}
+
+	if insert {
+		for _, column := range schema.Columns {
+			if present[column.Name] || column.Nullable || column.Default != nil || column.Generated {
+				continue
+			}
+			fieldErrors = append(fieldErrors, model.FieldError{
+				Field:   prefix + column.Name,
+				Code:    model.FieldErrorRequired,
+				Message: fmt.Sprintf("column %s is required", column.Name),
+			})
+		}
+	}
+
+	return result, fieldErrors
+}
+
+// coerceColumnValue 按列类型校验并转换写入的值
+// 值可以是 JSON 解码的值（float64 或 json.Number）或 CSV 字段的字符串
+func coerceColumnValue(column *sql.ColumnSchema, value interface{}, dbType string) (interface{}, error) {
+	if value == nil {
+		if !column.Nullable {
+			return nil, newColumnValueError(model.FieldErrorNotNull, "value cannot be null")
+		}
+		return nil, nil
+	}
+
+	// 数组类型（PostgreSQL）由驱动处理，未声明类型的列（SQLite）不做转换
+	if column.Type == "" || strings.HasSuffix(column.Type, "[]") {
+		return value, nil
+	}
+
+	schema := columnTypeSchema(*column)
+	switch schema["type"] {
+	case "integer":
+		return coerceInteger(column, value, dbType)
+	case "number":
+		return coerceNumber(column, value)
+	case "boolean":
+		return coerceBoolean(value)
+	case "string":
+		return coerceString(column, schema, value, dbType)
+	}
+
+	// JSON 列：字符串须为 JSON 文本，其他 JSON 值序列化后写入
+	if text, ok := value.(string); ok {
+		if !json.Valid([]byte(text)) {
+			return nil, newColumnValueError(model.FieldErrorInvalidFormat, "invalid JSON")
+		}
+		return text, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, newColumnValueError(model.FieldErrorInvalidType, "value cannot be encoded as JSON: %v", err)
+	}
+	return string(data), nil
+}
+
+// coerceInteger 转换整数列的值并检查类型范围；数值列也接受布尔值（写入 1 或 0）
+func coerceInteger(column *sql.ColumnSchema, value interface{}, dbType string) (interface{}, error) {
+	var v int64
+	switch x := value.(type) {
+	case bool:
+		if x {
+			v = 1
+		}
+	case int:
+		v = int64(x)
+	case int64:
+		v = x
+	case float64:
+		if x != math.Trunc(x) || x < math.MinInt64 || x >= math.MaxInt64 {
+			return nil, newColumnValueError(model.FieldErrorInvalidType, "expected integer, got %v", x)
+		}
+		v = int64(x)
+	default:
+		text, ok := valueText(value)
+		if !ok {
+			return nil, newColumnValueError(model.FieldErrorInvalidType, "expected integer, got %s", jsonTypeName(value))
+		}
+		parsed, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return nil, newColumnValueError(model.FieldErrorInvalidFormat, "invalid integer: %s", text)
+		}
+		v = parsed
+	}
+
+	if min, max := integerRange(column.Type, dbType); v < min || v > max {
+		return nil, newColumnValueError(model.FieldErrorOutOfRange, "value %d is out of range [%d, %d]", v, min, max)
+	}
+	return v, nil
+}
+
+// integerRange 整数列类型的取值范围（SQLite 的整数均为 64 位）
+func integerRange(dataType, dbType string) (int64, int64) {
+	dataType = strings.ToLower(dataType)
+	unsigned := strings.Contains(dataType, "unsigned")
+	if dbType == "sqlite" || dbType == "oracle" {
+		return math.MinInt64, math.MaxInt64
+	}
+
+	switch {
+	case strings.Contains(dataType, "bigint"), strings.Contains(dataType, "int8"), strings.Contains(dataType, "bigserial"):
+		if unsigned {
+			return 0, math.MaxInt64
+		}
+		return math.MinInt64, math.MaxInt64
+	case strings.Contains(dataType, "smallint"), strings.Contains(dataType, "int2"), strings.Contains(dataType, "smallserial"):
+		if unsigned {
+			return 0, math.MaxUint16
+		}
+		return math.MinInt16, math.MaxInt16
+	case strings.Contains(dataType, "mediumint"):
+		if unsigned {
+			return 0, 1<<24 - 1
+		}
+		return -1 << 23, 1<<23 - 1
+	case strings.Contains(dataType, "tinyint"):
+		// SQL Server 的 tinyint 为无符号
+		if unsigned || dbType == "sqlserver" {
+			return 0, math.MaxUint8
+		}
+		return math.MinInt8, math.MaxInt8
+	}
+
+	if unsigned {
+		return 0, math.MaxUint32
+	}
+	return math.MinInt32, math.MaxInt32
+}
+
+// coerceNumber 校验数值列的值并检查精确数值类型的精度；十进制文本原样保留，避免丢失精度
+func coerceNumber(column *sql.ColumnSchema, value interface{}) (interface{}, error) {
+	var text string
+	switch x := value.(type) {
+	case bool:
+		if x {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case int:
+		text = strconv.Itoa(x)
+	case int64:
+		text = strconv.FormatInt(x, 10)
+	case float64:
+		text = strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		s, ok := valueText(value)
+		if !ok {
+			return nil, newColumnValueError(model.FieldErrorInvalidType, "expected number, got %s", jsonTypeName(value))
+		}
+		text = strings.TrimSpace(s)
+		if !decimalPattern.MatchString(text) {
+			return nil, newColumnValueError(model.FieldErrorInvalidFormat, "invalid number: %s", s)
+		}
+	}
+
+	// 整数部分的位数不能超过 precision - scale；小数部分由数据库按 scale 舍入
+	if column.Precision != nil {
+		precision, scale := *column.Precision, int64(0)
+		if column.Scale != nil {
+			scale = *column.Scale
+		}
+		if scale >= 0 && scale <= precision {
+			rat, ok := new(big.Rat).SetString(text)
+			if !ok {
+				return nil, newColumnValueError(model.FieldErrorInvalidFormat, "invalid number: %s", text)
+			}
+			integer := new(big.Int).Quo(new(big.Int).Abs(rat.Num()), rat.Denom())
+			digits := int64(0)
+			if integer.Sign() > 0 {
+				digits = int64(len(integer.String()))
+			}
+			if digits > precision-scale {
+				return nil, newColumnValueError(model.FieldErrorOutOfRange, "value %s exceeds precision (%d, %d)", text, precision, scale)
+			}
+		}
+	}
+
+	switch value.(type) {
+	case float64, int, int64:
+		return value, nil
+	}
+	return text, nil
+}
+
+// coerceBoolean 转换布尔列的值，接受 true/false、1/0 及其文本
+func coerceBoolean(value interface{}) (interface{}, error) {
+	switch x := value.(type) {
+	case bool:
+		return x, nil
+	case float64:
+		if x == 0 || x == 1 {
+			return x == 1, nil
+		}
+	default:
+		if text, ok := valueText(value); ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(text))
+			if err != nil {
+				return nil, newColumnValueError(model.FieldErrorInvalidFormat, "invalid boolean: %s", text)
+			}
+			return b, nil
+		}
+	}
+	return nil, newColumnValueError(model.FieldErrorInvalidType, "expected boolean, got %s", jsonTypeName(value))
+}
+
+// coerceString 转换字符串类列（日期时间、二进制、文本）的值
+// 日期时间转换为 time.Time（SQLite 以文本存储日期，保留原文本）；二进制须为 base64 编码
+func coerceString(column *sql.ColumnSchema, schema map[string]interface{}, value interface{}, dbType string) (interface{}, error) {
+	switch schema["format"] {
+	case "date-time", "date":
+		if t, ok := value.(time.Time); ok {
+			return t, nil
+		}
+		text, ok := value.(string)
+		if !ok {
+			return nil, newColumnValueError(model.FieldErrorInvalidType, "expected %s, got %s", schema["format"], jsonTypeName(value))
+		}
+		text = strings.TrimSpace(text)
+		for _, layout := range columnTimeLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				if dbType == "sqlite" {
+					return text, nil
+				}
+				return t, nil
+			}
+		}
+		return nil, newColumnValueError(model.FieldErrorInvalidFormat, "invalid %s: %s", schema["format"], text)
+
+	case "byte":
+		text, ok := value.(string)
+		if !ok {
+			return nil, newColumnValueError(model.FieldErrorInvalidType, "expected base64 string, got %s", jsonTypeName(value))
+		}
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, newColumnValueError(model.FieldErrorInvalidFormat, "invalid base64: %v", err)
+		}
+		return data, nil
+	}
+
+	var text string
+	switch x := value.(type) {
+	case string:
+		text = x
+	case json.Number:
+		text = x.String()
+	case float64:
+		text = strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		text = strconv.FormatBool(x)
+	default:
+		return nil, newColumnValueError(model.FieldErrorInvalidType, "expected string, got %s", jsonTypeName(value))
+	}
+
+	if maxLength, ok := schema["maxLength"].(int64); ok && int64(utf8.RuneCountInString(text)) > maxLength {
+		return nil, newColumnValueError(model.FieldErrorMaxLength, "value exceeds maximum length %d", maxLength)
+	}
+	if len(column.Enum) > 0 && !containsString(column.Enum, text) {
+		return nil, newColumnValueError(model.FieldErrorEnum, "value must be one of: %s", strings.Join(column.Enum, ", "))
+	}
+	return text, nil
+}
+
+// valueText 获取字符串或 JSON 数字的文本
+func valueText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// jsonTypeName JSON 值的类型名，用于错误信息
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case json.Number, float64, int, int64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
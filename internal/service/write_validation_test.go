@@ -0,0 +1,228 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"sql2api/internal/model"
+	"sql2api/internal/sql"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestCoerceColumnValue_IntegerRange(t *testing.T) {
+	tests := []struct {
+		dbType   string
+		dataType string
+		value    interface{}
+		want     interface{}
+		code     string
+	}{
+		{"mysql", "tinyint", float64(127), int64(127), ""},
+		{"mysql", "tinyint", float64(128), nil, model.FieldErrorOutOfRange},
+		{"mysql", "tinyint", float64(-128), int64(-128), ""},
+		{"mysql", "tinyint unsigned", float64(255), int64(255), ""},
+		{"mysql", "tinyint unsigned", float64(-1), nil, model.FieldErrorOutOfRange},
+		{"sqlserver", "tinyint", float64(255), int64(255), ""},
+		{"sqlserver", "tinyint", float64(-1), nil, model.FieldErrorOutOfRange},
+		{"postgres", "smallint", float64(32767), int64(32767), ""},
+		{"postgres", "int2", float64(32768), nil, model.FieldErrorOutOfRange},
+		{"mysql", "mediumint", float64(8388608), nil, model.FieldErrorOutOfRange},
+		{"mysql", "int unsigned", float64(4294967295), int64(4294967295), ""},
+		{"postgres", "integer", float64(2147483648), nil, model.FieldErrorOutOfRange},
+		{"postgres", "bigint", json.Number("9223372036854775807"), int64(9223372036854775807), ""},
+		{"sqlite", "integer", float64(2147483648), int64(2147483648), ""},
+		{"oracle", "integer", float64(2147483648), int64(2147483648), ""},
+		{"postgres", "integer", "42", int64(42), ""},
+		{"postgres", "integer", true, int64(1), ""},
+		{"postgres", "integer", float64(1.5), nil, model.FieldErrorInvalidType},
+		{"postgres", "integer", "4x", nil, model.FieldErrorInvalidFormat},
+		{"postgres", "integer", []interface{}{1}, nil, model.FieldErrorInvalidType},
+	}
+
+	for _, test := range tests {
+		column := &sql.ColumnSchema{Name: "n", Type: test.dataType}
+		got, err := coerceColumnValue(column, test.value, test.dbType)
+		assertCoerced(t, test.dbType+" "+test.dataType, test.value, got, err, test.want, test.code)
+	}
+}
+
+func TestCoerceColumnValue_NumericPrecision(t *testing.T) {
+	tests := []struct {
+		precision *int64
+		scale     *int64
+		value     interface{}
+		want      interface{}
+		code      string
+	}{
+		{int64Ptr(5), int64Ptr(2), float64(999.99), float64(999.99), ""},
+		{int64Ptr(5), int64Ptr(2), float64(1000), nil, model.FieldErrorOutOfRange},
+		{int64Ptr(5), int64Ptr(2), float64(-999.999), float64(-999.999), ""}, // 小数部分由数据库舍入
+		{int64Ptr(5), int64Ptr(2), "123.456", "123.456", ""},
+		{int64Ptr(5), int64Ptr(2), json.Number("12345"), nil, model.FieldErrorOutOfRange},
+		{int64Ptr(3), int64Ptr(3), "0.5", "0.5", ""},
+		{int64Ptr(3), int64Ptr(3), "1.5", nil, model.FieldErrorOutOfRange},
+		{int64Ptr(10), nil, "1e9", "1e9", ""},
+		{int64Ptr(10), nil, "1e10", nil, model.FieldErrorOutOfRange},
+		{nil, nil, "12345678901234567890.123", "12345678901234567890.123", ""},
+		{nil, nil, "NaN", nil, model.FieldErrorInvalidFormat},
+		{nil, nil, "0x10", nil, model.FieldErrorInvalidFormat},
+		{nil, nil, false, int64(0), ""},
+		{nil, nil, map[string]interface{}{}, nil, model.FieldErrorInvalidType},
+	}
+
+	for _, test := range tests {
+		column := &sql.ColumnSchema{Name: "amount", Type: "numeric", Precision: test.precision, Scale: test.scale}
+		got, err := coerceColumnValue(column, test.value, "postgres")
+		assertCoerced(t, "numeric", test.value, got, err, test.want, test.code)
+	}
+}
+
+func TestCoerceColumnValue_String(t *testing.T) {
+	tests := []struct {
+		column sql.ColumnSchema
+		value  interface{}
+		want   interface{}
+		code   string
+	}{
+		{sql.ColumnSchema{Type: "varchar", Length: int64Ptr(5)}, "hello", "hello", ""},
+		{sql.ColumnSchema{Type: "varchar", Length: int64Ptr(5)}, "héllo", "héllo", ""}, // 按字符计算长度
+		{sql.ColumnSchema{Type: "varchar", Length: int64Ptr(5)}, "hello!", nil, model.FieldErrorMaxLength},
+		{sql.ColumnSchema{Type: "varchar", Length: int64Ptr(5)}, float64(12), "12", ""},
+		{sql.ColumnSchema{Type: "text"}, []interface{}{"a"}, nil, model.FieldErrorInvalidType},
+		{sql.ColumnSchema{Type: "enum", Enum: []string{"new", "paid"}}, "paid", "paid", ""},
+		{sql.ColumnSchema{Type: "enum", Enum: []string{"new", "paid"}}, "PAID", nil, model.FieldErrorEnum},
+		{sql.ColumnSchema{Type: "varchar", Enum: []string{"S", "M", "L"}}, "XL", nil, model.FieldErrorEnum}, // CHECK 约束
+		{sql.ColumnSchema{Type: "bytea"}, "aGk=", []byte("hi"), ""},
+		{sql.ColumnSchema{Type: "bytea"}, "not base64!", nil, model.FieldErrorInvalidFormat},
+		{sql.ColumnSchema{Type: "jsonb"}, `{"a":1}`, `{"a":1}`, ""},
+		{sql.ColumnSchema{Type: "jsonb"}, map[string]interface{}{"a": float64(1)}, `{"a":1}`, ""},
+		{sql.ColumnSchema{Type: "jsonb"}, "{broken", nil, model.FieldErrorInvalidFormat},
+		{sql.ColumnSchema{Type: "boolean"}, "true", true, ""},
+		{sql.ColumnSchema{Type: "boolean"}, float64(2), nil, model.FieldErrorInvalidType},
+		{sql.ColumnSchema{Type: "text"}, nil, nil, model.FieldErrorNotNull},
+		{sql.ColumnSchema{Type: "text", Nullable: true}, nil, nil, ""},
+	}
+
+	for _, test := range tests {
+		got, err := coerceColumnValue(&test.column, test.value, "postgres")
+		assertCoerced(t, test.column.Type, test.value, got, err, test.want, test.code)
+	}
+}
+
+func TestCoerceColumnValue_Dates(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	moment := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		dbType   string
+		dataType string
+		value    interface{}
+		want     interface{}
+		code     string
+	}{
+		{"oracle", "DATE", "2024-03-01", day, ""},
+		{"oracle", "TIMESTAMP(6)", "2024-03-01T12:30:00Z", moment, ""},
+		{"oracle", "TIMESTAMP(6)", "2024-03-01 12:30:00", moment, ""},
+		{"oracle", "DATE", "01/03/2024", nil, model.FieldErrorInvalidFormat},
+		{"oracle", "DATE", float64(20240301), nil, model.FieldErrorInvalidType},
+		{"postgres", "timestamp with time zone", "2024-03-01T12:30:00Z", moment, ""},
+		{"sqlite", "datetime", "2024-03-01 12:30:00", "2024-03-01 12:30:00", ""}, // SQLite 以文本存储日期
+	}
+
+	for _, test := range tests {
+		column := &sql.ColumnSchema{Name: "created_at", Type: test.dataType}
+		got, err := coerceColumnValue(column, test.value, test.dbType)
+		if test.code == "" {
+			if value, ok := got.(time.Time); ok {
+				got = value.UTC()
+			}
+		}
+		assertCoerced(t, test.dbType+" "+test.dataType, test.value, got, err, test.want, test.code)
+	}
+}
+
+func TestValidateWriteBatch(t *testing.T) {
+	schema := &sql.TableSchema{
+		Name: "orders",
+		Columns: []sql.ColumnSchema{
+			{Name: "id", Type: "integer", Generated: true},
+			{Name: "status", Type: "varchar", Enum: []string{"new", "paid"}},
+			{Name: "quantity", Type: "smallint"},
+			{Name: "note", Type: "varchar", Length: int64Ptr(3), Nullable: true},
+			{Name: "created_at", Type: "timestamp", Default: new(string)},
+		},
+	}
+
+	records := []map[string]interface{}{
+		{"status": "new", "quantity": float64(2)},
+		{"status": "lost", "quantity": float64(70000), "note": "long", "color": "red"},
+		{"note": "ok"},
+	}
+
+	validated, response := validateWriteBatch(schema, records, true, true, "postgres")
+	if validated != nil || response == nil {
+		t.Fatalf("Expected validation failure, got %v", validated)
+	}
+	if response.Success || response.Error.Code != model.SQLErrorParams {
+		t.Fatalf("Expected %d error response, got %+v", model.SQLErrorParams, response.Error)
+	}
+
+	var got []string
+	for _, fieldError := range response.Error.Fields {
+		got = append(got, fieldError.Field+":"+fieldError.Code)
+	}
+	expected := []string{
+		"data[1].color:" + model.FieldErrorUnknownColumn,
+		"data[1].note:" + model.FieldErrorMaxLength,
+		"data[1].quantity:" + model.FieldErrorOutOfRange,
+		"data[1].status:" + model.FieldErrorEnum,
+		"data[2].status:" + model.FieldErrorRequired,
+		"data[2].quantity:" + model.FieldErrorRequired,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected field errors %v, got %v", expected, got)
+	}
+	if response.Error.Details != "6 invalid field(s)" {
+		t.Errorf("Unexpected details: %q", response.Error.Details)
+	}
+
+	// 校验通过时返回转换后的数据；更新时不检查缺少的列
+	validated, response = validateWriteBatch(schema, []map[string]interface{}{{"quantity": "7"}}, false, false, "postgres")
+	if response != nil {
+		t.Fatalf("Expected validation success, got %+v", response.Error)
+	}
+	if validated[0]["quantity"] != int64(7) {
+		t.Errorf("Expected quantity converted to int64, got %#v", validated[0]["quantity"])
+	}
+
+	// 单条写入的字段名不带前缀
+	_, response = validateWriteBatch(schema, []map[string]interface{}{{"quantity": "x"}}, false, false, "postgres")
+	if response == nil || len(response.Error.Fields) != 1 || response.Error.Fields[0].Field != "quantity" {
+		t.Errorf("Expected one unprefixed field error, got %+v", response)
+	}
+}
+
+// assertCoerced 检查列值转换的结果或错误码
+func assertCoerced(t *testing.T, name string, value, got interface{}, err error, want interface{}, code string) {
+	t.Helper()
+
+	if code != "" {
+		valueErr, ok := err.(*columnValueError)
+		if !ok || valueErr.code != code {
+			t.Errorf("%s: coerce(%#v) error = %v, want code %s", name, value, err, code)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("%s: coerce(%#v) unexpected error: %v", name, value, err)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: coerce(%#v) = %#v, want %#v", name, value, got, want)
+	}
+}
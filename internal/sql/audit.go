@@ -0,0 +1,635 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// redactedValue 脱敏后的取值
+const redactedValue = "[REDACTED]"
+
+// maxAuditSQLLength 审计记录中 SQL 的最大长度
+const maxAuditSQLLength = 8192
+
+// Caller 发起请求的调用方，由 HTTP 中间件和 gRPC 拦截器写入上下文，API Key 认证通过后补充名称
+type Caller struct {
+	RequestID  string
+	ClientIP   string
+	APIKeyName string
+	Endpoint   string // HTTP 方法和路径，或 gRPC 方法名
+}
+
+// callerContextKey 上下文中保存调用方的键
+type callerContextKey struct{}
+
+// WithCaller 在上下文中附加调用方信息
+func WithCaller(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// CallerFrom 获取上下文中的调用方信息，没有时返回 nil
+func CallerFrom(ctx context.Context) *Caller {
+	caller, _ := ctx.Value(callerContextKey{}).(*Caller)
+	return caller
+}
+
+// AuditSink 审计记录的存储（数据库审计表或 JSONL 文件）
+type AuditSink interface {
+	WriteAudit(record *model.AuditRecord) error
+	LastAuditID() (uint64, error)
+}
+
+// AuditTrail 审计日志，记录同一数据库连接上所有 SQLEngine 执行的写操作
+type AuditTrail struct {
+	config      *config.AuditConfig
+	sinks       []AuditSink
+	mu          sync.Mutex
+	lastID      uint64
+	primaryKeys sync.Map // 表名（小写）-> 主键列，记录修改后的行时使用
+}
+
+// NewAuditTrail 创建审计日志，记录 ID 从所有存储中最大的 ID 继续递增
+// 按顺序写入各个存储，JSONL 文件应排在数据库之前，以便数据库中的记录也带有哈希
+func NewAuditTrail(cfg *config.AuditConfig, sinks ...AuditSink) (*AuditTrail, error) {
+	trail := &AuditTrail{config: cfg, sinks: sinks}
+	for _, sink := range sinks {
+		lastID, err := sink.LastAuditID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read last audit record: %w", err)
+		}
+		if lastID > trail.lastID {
+			trail.lastID = lastID
+		}
+	}
+	return trail, nil
+}
+
+// auditTrails 按数据库连接保存的审计日志
+var auditTrails sync.Map
+
+// AttachAuditTrail 为数据库连接上的所有 SQLEngine 启用审计日志，返回停用的函数
+func AttachAuditTrail(db *gorm.DB, trail *AuditTrail) func() {
+	auditTrails.Store(db, trail)
+	return func() {
+		auditTrails.CompareAndDelete(db, trail)
+	}
+}
+
+// Record 写入审计记录，补充 ID、时间和上下文中的调用方信息
+// 写入失败只记录日志：此时写操作已经完成，不能再让请求失败
+func (t *AuditTrail) Record(ctx context.Context, record *model.AuditRecord) {
+	if caller := CallerFrom(ctx); caller != nil {
+		record.RequestID = caller.RequestID
+		record.ClientIP = caller.ClientIP
+		record.APIKeyName = caller.APIKeyName
+		record.Endpoint = caller.Endpoint
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastID++
+	record.ID = t.lastID
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now().UTC()
+	}
+	for _, sink := range t.sinks {
+		if err := sink.WriteAudit(record); err != nil {
+			log.Printf("[AUDIT] Failed to write audit record %d: %v", record.ID, err)
+		}
+	}
+}
+
+// redacts 检查列名或参数名是否需要脱敏
+func (t *AuditTrail) redacts(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range t.config.Redact {
+		if matched, _ := path.Match(strings.ToLower(pattern), name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// redactParams 复制参数并脱敏：参数名匹配脱敏规则，或从 SQL 推断出参数对应的列名匹配脱敏规则
+func (t *AuditTrail) redactParams(query, dbType string, params map[string]interface{}) map[string]interface{} {
+	if len(params) == 0 {
+		return nil
+	}
+
+	columns := paramColumns(query, dbType, params)
+	redacted := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if t.redacts(key) || (columns[key] != "" && t.redacts(columns[key])) {
+			value = redactedValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// redactRows 复制行数据并脱敏
+func (t *AuditTrail) redactRows(rows []map[string]interface{}) []map[string]interface{} {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	redacted := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		copied := make(map[string]interface{}, len(row))
+		for column, value := range row {
+			if t.redacts(column) {
+				value = redactedValue
+			}
+			copied[column] = value
+		}
+		redacted = append(redacted, copied)
+	}
+	return redacted
+}
+
+// ===== SQLEngine 的审计记录 =====
+
+// rowImages 写语句修改前后的行
+type rowImages struct {
+	before []map[string]interface{}
+	after  []map[string]interface{}
+}
+
+// auditTrail 获取引擎所在数据库连接的审计日志，未启用时返回 nil
+func (e *SQLEngine) auditTrail() *AuditTrail {
+	trail, ok := auditTrails.Load(e.db)
+	if !ok {
+		return nil
+	}
+	return trail.(*AuditTrail)
+}
+
+// execWrite 执行单条写语句；表配置了 row_images 时在同一事务中读取修改前后的行
+func (e *SQLEngine) execWrite(ctx context.Context, query string, params map[string]interface{}) (*ExecuteResult, *rowImages, error) {
+	trail := e.auditTrail()
+	if trail == nil {
+		result, err := e.execStatement(ctx, e.db, query, params)
+		return result, nil, err
+	}
+
+	operation, table := writeTarget(query)
+	if (operation != ChangeUpdate && operation != ChangeDelete) || !trail.config.CapturesRowImages(table) {
+		result, err := e.execStatement(ctx, e.db, query, params)
+		return result, nil, err
+	}
+
+	selectQuery, selectArgs, ok := e.imageQuery(query, table, params)
+	if !ok {
+		result, err := e.execStatement(ctx, e.db, query, params)
+		return result, nil, err
+	}
+
+	// 在事务外读取主键，SQLite 等单连接的连接池在事务中无法再获取连接
+	var primaryKey []string
+	if operation == ChangeUpdate {
+		primaryKey = e.imagePrimaryKey(ctx, trail, table)
+	}
+
+	tx := e.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+
+	images := &rowImages{}
+	before, complete, err := e.readImage(ctx, tx, selectQuery, selectArgs, trail.config.MaxImageRows)
+	if err != nil {
+		// 无法读取修改前的行（如语句关联了其他表）时不记录行数据
+		tx.Rollback()
+		log.Printf("[AUDIT] Skipping row images for %s: %v", table, err)
+		result, err := e.execStatement(ctx, e.db, query, params)
+		return result, nil, err
+	}
+
+	result, err := e.execStatement(ctx, tx, query, params)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+
+	if complete {
+		images.before = before
+		if len(primaryKey) > 0 && len(before) > 0 {
+			keyQuery, keyArgs := e.keyImageQuery(table, primaryKey, before)
+			if images.after, _, err = e.readImage(ctx, tx, keyQuery, keyArgs, len(before)); err != nil {
+				tx.Rollback()
+				return nil, nil, fmt.Errorf("failed to read updated rows: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, nil, err
+	}
+	if !complete {
+		images = nil
+	}
+	return result, images, nil
+}
+
+// imageQuery 根据写语句的 WHERE 子句构建读取受影响行的查询，参数按出现顺序重新编号
+// 语句包含 UPDATE ... FROM、USING、JOIN 等关联其他表的结构时返回 false
+func (e *SQLEngine) imageQuery(query, table string, params map[string]interface{}) (string, []interface{}, bool) {
+	tokens, err := auditTokens(query, e.dbType)
+	if err != nil || len(tokens) == 0 {
+		return "", nil, false
+	}
+	keys := resolvePlaceholders(tokens, params)
+
+	whereStart, whereEnd := -1, len(tokens)
+	depth := 0
+	for i := 0; i < len(tokens) && whereEnd == len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		case depth != 0 || tok.kind != tokWord:
+		case whereStart < 0 && (tok.is("from") && tokens[0].is("update") || tok.is("using") || tok.is("join")):
+			return "", nil, false
+		case whereStart < 0 && tok.is("where"):
+			whereStart = i + 1
+		case whereStart >= 0 && (tok.is("returning") || tok.is("order") || tok.is("limit")):
+			whereEnd = i
+		}
+	}
+
+	selectQuery := "SELECT * FROM " + table
+	if whereStart < 0 || whereStart == whereEnd {
+		return selectQuery, nil, true
+	}
+
+	var args []interface{}
+	conditions := make([]string, 0, whereEnd-whereStart)
+	for i := whereStart; i < whereEnd; i++ {
+		if tokens[i].kind != tokParam {
+			conditions = append(conditions, tokens[i].text)
+			continue
+		}
+		value, ok := params[keys[i]]
+		if !ok {
+			return "", nil, false
+		}
+		args = append(args, e.dialect.ConvertDataType(value))
+		conditions = append(conditions, bindPlaceholder(e.dbType, len(args)))
+	}
+	return selectQuery + " WHERE " + strings.Join(conditions, " "), args, true
+}
+
+// keyImageQuery 构建按主键读取行的查询
+func (e *SQLEngine) keyImageQuery(table string, primaryKey []string, rows []map[string]interface{}) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	for _, row := range rows {
+		var parts []string
+		for _, column := range primaryKey {
+			args = append(args, lookupColumn(row, column))
+			parts = append(parts, fmt.Sprintf("%s = %s", column, bindPlaceholder(e.dbType, len(args))))
+		}
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s", table, strings.Join(conditions, " OR ")), args
+}
+
+// readImage 在事务中读取行，超过 maxRows 行时停止读取并返回 complete 为 false
+func (e *SQLEngine) readImage(ctx context.Context, tx *gorm.DB, query string, args []interface{}, maxRows int) ([]map[string]interface{}, bool, error) {
+	rows, err := tx.Statement.ConnPool.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result []map[string]interface{}
+	scanner := newRowScanner(columns)
+	for rows.Next() {
+		if len(result) == maxRows {
+			return nil, false, nil
+		}
+		row, err := scanner.scan(rows)
+		if err != nil {
+			return nil, false, err
+		}
+		result = append(result, row)
+	}
+	return result, true, rows.Err()
+}
+
+// imagePrimaryKey 获取表的主键列（缓存），无法获取时只记录修改前的行
+func (e *SQLEngine) imagePrimaryKey(ctx context.Context, trail *AuditTrail, table string) []string {
+	key := strings.ToLower(table)
+	if primaryKey, ok := trail.primaryKeys.Load(key); ok {
+		return primaryKey.([]string)
+	}
+
+	schema, err := e.DescribeTable(ctx, table)
+	if err != nil {
+		log.Printf("[AUDIT] Failed to read primary key of %s: %v", table, err)
+		return nil
+	}
+	trail.primaryKeys.Store(key, schema.PrimaryKey)
+	return schema.PrimaryKey
+}
+
+// auditWrite 记录单条写语句
+func (e *SQLEngine) auditWrite(ctx context.Context, query string, params map[string]interface{}, result *ExecuteResult, images *rowImages, err error) {
+	trail := e.auditTrail()
+	if trail == nil {
+		return
+	}
+
+	operation, table := writeTarget(query)
+	if operation == "" {
+		operation = e.security.extractSQLAction(strings.ToLower(query))
+		// 批量操作中的查询语句不记录
+		if operation == "select" {
+			return
+		}
+	}
+	// 软删除等以 UPDATE 实现的操作按调用方指定的操作类型记录
+	if override, ok := ctx.Value(changeOperationContextKey{}).(string); ok && override != "" {
+		operation = override
+	}
+
+	record := &model.AuditRecord{
+		Table:  table,
+		Action: operation,
+		SQL:    sanitizeAuditSQL(query, e.dbType),
+		Params: trail.redactParams(query, e.dbType, params),
+	}
+	if err != nil {
+		record.Outcome = model.AuditOutcomeFailed
+		record.Error = err.Error()
+	} else {
+		record.Outcome = model.AuditOutcomeSuccess
+		record.AffectedRows = result.AffectedRows
+		if images != nil {
+			record.Before = trail.redactRows(images.before)
+			record.After = trail.redactRows(images.after)
+		} else if operation == ChangeInsert && trail.config.CapturesRowImages(table) {
+			// 插入的行由调用方通过上下文提供
+			rows, _ := ctx.Value(changeRowsContextKey{}).([]map[string]interface{})
+			record.After = trail.redactRows(rows)
+		}
+	}
+	trail.Record(ctx, record)
+}
+
+// auditOperation 记录不是单条 SQL 语句的写操作（批量导入、存储过程调用）
+func (e *SQLEngine) auditOperation(ctx context.Context, record *model.AuditRecord, err error) {
+	trail := e.auditTrail()
+	if trail == nil {
+		return
+	}
+
+	if record.Params != nil {
+		redacted := make(map[string]interface{}, len(record.Params))
+		for key, value := range record.Params {
+			if trail.redacts(key) {
+				value = redactedValue
+			}
+			redacted[key] = value
+		}
+		record.Params = redacted
+	}
+	if err != nil {
+		record.Outcome = model.AuditOutcomeFailed
+		record.Error = err.Error()
+		record.AffectedRows = 0
+	} else {
+		record.Outcome = model.AuditOutcomeSuccess
+	}
+	trail.Record(ctx, record)
+}
+
+// ===== 审计使用的 SQL 解析 =====
+
+// auditTokens 解析 SQL 并去除空白和注释，Oracle 的 :name 命名占位符合并为一个参数词法单元
+func auditTokens(query, dbType string) ([]sqlToken, error) {
+	tokens, err := lexSQL(query, dbType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]sqlToken, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.isTrivia() {
+			continue
+		}
+		if dbType == "oracle" && tok.is(":") && i+1 < len(tokens) && tokens[i+1].kind == tokWord {
+			tok = sqlToken{kind: tokParam, text: ":" + tokens[i+1].text}
+			i++
+		}
+		result = append(result, tok)
+	}
+	return result, nil
+}
+
+// resolvePlaceholders 确定每个占位符绑定的参数名，返回词法单元下标到参数名的映射
+// 参数按 convertParams 的顺序绑定：$N、:N 和 @pN 为第 N 个参数，? 按出现顺序，Oracle 的 :name 优先按名称匹配
+func resolvePlaceholders(tokens []sqlToken, params map[string]interface{}) map[int]string {
+	keys := orderedParamKeys(params)
+	resolved := make(map[int]string)
+	sequence := 0
+	for i, tok := range tokens {
+		if tok.kind != tokParam {
+			continue
+		}
+		sequence++
+
+		index := tok.index
+		if index == 0 {
+			if _, ok := params[tok.text[1:]]; ok {
+				resolved[i] = tok.text[1:]
+				continue
+			}
+			index = sequence
+		}
+		if index <= len(keys) {
+			resolved[i] = keys[index-1]
+		}
+	}
+	return resolved
+}
+
+// paramColumns 推断每个参数对应的列名，用于按列名脱敏；无法确定的参数不包含在结果中
+// 支持 INSERT 列名列表与 VALUES（含 MERGE ... USING (VALUES ...) AS source (列)）、
+// col <op> 参数、col IN (参数, ...)、col BETWEEN 参数 AND 参数 和 参数 AS col
+func paramColumns(query, dbType string, params map[string]interface{}) map[string]string {
+	tokens, err := auditTokens(query, dbType)
+	if err != nil {
+		return nil
+	}
+	keys := resolvePlaceholders(tokens, params)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	type tupleParam struct {
+		key      string
+		position int
+	}
+	var insertColumns []string
+	var tupleParams []tupleParam
+	columns := make(map[string]string, len(keys))
+	depth, tupleDepth, tuplePosition, inDepth := 0, -1, 0, -1
+	inColumn := ""
+	afterTuple := false
+
+	for i, tok := range tokens {
+		closedTuple := false
+		switch {
+		case tok.is("("):
+			depth++
+			// INTO table (列, ...) 或 AS alias (列, ...) 形式的列名列表
+			if i >= 2 && isName(tokens[i-1]) && (tokens[i-2].is("into") || tokens[i-2].is("as") || tokens[i-2].is(".")) {
+				if list := identifierList(tokens[i+1:]); list != nil {
+					insertColumns = list
+				}
+			}
+			// VALUES 后的每一组值
+			if i >= 1 && (tokens[i-1].is("values") || tokens[i-1].is(",") && afterTuple) {
+				tupleDepth, tuplePosition = depth, 0
+			}
+			if i >= 1 && tokens[i-1].is("in") {
+				inColumn, inDepth = columnBefore(tokens, i-1), depth
+			}
+		case tok.is(")"):
+			if depth == tupleDepth {
+				tupleDepth, closedTuple = -1, true
+			}
+			if depth == inDepth {
+				inColumn, inDepth = "", -1
+			}
+			depth--
+		case tok.is(",") && depth == tupleDepth:
+			tuplePosition++
+		case tok.kind == tokParam:
+			key, ok := keys[i]
+			if !ok {
+				break
+			}
+			switch {
+			case depth == tupleDepth:
+				tupleParams = append(tupleParams, tupleParam{key, tuplePosition})
+			case depth == inDepth:
+				columns[key] = inColumn
+			case i+2 < len(tokens) && tokens[i+1].is("as") && isName(tokens[i+2]):
+				columns[key] = identName(tokens[i+2])
+			case i >= 1 && (isComparison(tokens[i-1]) || tokens[i-1].is("between")):
+				columns[key] = columnBefore(tokens, i-1)
+			case i >= 3 && tokens[i-1].is("and") && tokens[i-2].kind == tokParam && tokens[i-3].is("between"):
+				columns[key] = columnBefore(tokens, i-3)
+			}
+		}
+		// 逗号之后的左括号仍属于 VALUES
+		afterTuple = closedTuple || afterTuple && tok.is(",")
+	}
+
+	for _, param := range tupleParams {
+		if param.position < len(insertColumns) {
+			columns[param.key] = insertColumns[param.position]
+		}
+	}
+	for key, column := range columns {
+		if column == "" {
+			delete(columns, key)
+		}
+	}
+	return columns
+}
+
+// isName 检查词法单元是否为标识符（含带引号的标识符）
+func isName(tok sqlToken) bool {
+	return tok.kind == tokWord || tok.kind == tokIdent
+}
+
+// identName 获取去除引号的标识符
+func identName(tok sqlToken) string {
+	if tok.kind == tokIdent && len(tok.text) >= 2 {
+		return tok.text[1 : len(tok.text)-1]
+	}
+	return tok.text
+}
+
+// identifierList 读取以 ) 结束、逗号分隔的标识符列表，包含其他内容时返回 nil
+func identifierList(tokens []sqlToken) []string {
+	var list []string
+	for i, tok := range tokens {
+		switch {
+		case tok.is(")"):
+			return list
+		case i%2 == 0 && isName(tok):
+			list = append(list, identName(tok))
+		case i%2 == 1 && tok.is(","):
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// isComparison 检查是否为比较运算符或 LIKE
+func isComparison(tok sqlToken) bool {
+	switch {
+	case tok.kind == tokSymbol:
+		switch tok.text {
+		case "=", "<>", "!=", "<", ">", "<=", ">=":
+			return true
+		}
+	case tok.is("like"), tok.is("ilike"):
+		return true
+	}
+	return false
+}
+
+// columnBefore 获取运算符前的列名（跳过 NOT，去除表名前缀），不是列名时返回空
+func columnBefore(tokens []sqlToken, operator int) string {
+	i := operator - 1
+	if i >= 0 && tokens[i].is("not") {
+		i--
+	}
+	if i < 0 || !isName(tokens[i]) {
+		return ""
+	}
+	return identName(tokens[i])
+}
+
+// sanitizeAuditSQL 将 SQL 中的字符串常量替换为 '?'，并限制长度
+func sanitizeAuditSQL(query, dbType string) string {
+	tokens, err := lexSQL(query, dbType)
+	if err == nil {
+		for i := range tokens {
+			if tokens[i].kind == tokString {
+				tokens[i].text = "'?'"
+			}
+		}
+		query = renderTokens(tokens)
+	}
+
+	if len(query) > maxAuditSQLLength {
+		query = query[:maxAuditSQLLength] + "..."
+	}
+	return query
+}
@@ -0,0 +1,150 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+)
+
+// memoryAuditSink 保存在内存中的审计存储
+type memoryAuditSink struct {
+	records []model.AuditRecord
+}
+
+func (s *memoryAuditSink) WriteAudit(record *model.AuditRecord) error {
+	s.records = append(s.records, *record)
+	return nil
+}
+
+func (s *memoryAuditSink) LastAuditID() (uint64, error) {
+	return 41, nil
+}
+
+func TestParamColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		query    string
+		params   map[string]interface{}
+		expected map[string]string
+	}{
+		{
+			name:     "postgres insert",
+			dbType:   "postgres",
+			query:    `INSERT INTO users (name, "password") VALUES ($1, $2), ($3, $4)`,
+			params:   map[string]interface{}{"param_1": "a", "param_2": "x", "param_3": "b", "param_4": "y"},
+			expected: map[string]string{"param_1": "name", "param_2": "password", "param_3": "name", "param_4": "password"},
+		},
+		{
+			name:     "sqlite update with comparisons",
+			dbType:   "sqlite",
+			query:    "UPDATE users SET password = ? WHERE u.name LIKE ? AND id NOT IN (?, ?) -- ?",
+			params:   map[string]interface{}{"param_1": "x", "param_2": "a%", "param_3": 1, "param_4": 2},
+			expected: map[string]string{"param_1": "password", "param_2": "name", "param_3": "id", "param_4": "id"},
+		},
+		{
+			name:     "mysql between and alias",
+			dbType:   "mysql",
+			query:    "SELECT ? AS token FROM users WHERE age BETWEEN ? AND ? AND note = 'a = ?'",
+			params:   map[string]interface{}{"param_1": "t", "param_2": 1, "param_3": 9},
+			expected: map[string]string{"param_1": "token", "param_2": "age", "param_3": "age"},
+		},
+		{
+			name:     "sqlserver merge",
+			dbType:   "sqlserver",
+			query:    "MERGE INTO [users] AS target USING (VALUES (@p1, @p2)) AS source ([id], [password]) ON target.id = source.id WHEN NOT MATCHED THEN INSERT (id, password) VALUES (source.id, source.password);",
+			params:   map[string]interface{}{"param_1": 1, "param_2": "x"},
+			expected: map[string]string{"param_1": "id", "param_2": "password"},
+		},
+		{
+			name:     "oracle named placeholders",
+			dbType:   "oracle",
+			query:    "UPDATE users SET password = :secret WHERE id = :param_1",
+			params:   map[string]interface{}{"param_1": 1, "secret": "x"},
+			expected: map[string]string{"secret": "password", "param_1": "id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := paramColumns(tt.query, tt.dbType, tt.params)
+			if !reflect.DeepEqual(columns, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, columns)
+			}
+		})
+	}
+}
+
+func TestSanitizeAuditSQL(t *testing.T) {
+	query := "UPDATE users SET password = 'it''s secret', note = ? WHERE name = 'bob' -- 'comment'"
+	expected := "UPDATE users SET password = '?', note = ? WHERE name = '?' -- 'comment'"
+	if sanitized := sanitizeAuditSQL(query, "sqlite"); sanitized != expected {
+		t.Errorf("Expected %q, got %q", expected, sanitized)
+	}
+}
+
+func TestSQLEngine_AuditTrail(t *testing.T) {
+	engine, repos := newTestEngine(t)
+	sink := &memoryAuditSink{}
+	trail, err := NewAuditTrail(&config.AuditConfig{
+		Redact:       []string{"category", "*token*"},
+		RowImages:    []string{"items"},
+		MaxImageRows: 2,
+	}, sink)
+	if err != nil {
+		t.Fatalf("NewAuditTrail failed: %v", err)
+	}
+	detach := AttachAuditTrail(repos.GetDB(), trail)
+	defer detach()
+
+	ctx := WithCaller(context.Background(), &Caller{RequestID: "req-1", ClientIP: "10.0.0.1", APIKeyName: "writer"})
+	exec := func(query string, params map[string]interface{}) {
+		t.Helper()
+		if _, err := engine.ExecuteSQL(ctx, query, params); err != nil {
+			t.Fatalf("ExecuteSQL %q failed: %v", query, err)
+		}
+	}
+	exec("INSERT INTO items (name, category, value) VALUES (?, ?, ?)", map[string]interface{}{"param_1": "a", "param_2": "tools", "param_3": 1})
+	exec("INSERT INTO items (name, category, value) VALUES ('b', 'tools', 2), ('c', 'toys', 3)", nil)
+	exec("UPDATE items SET value = value + ? WHERE category = ? AND value < ?", map[string]interface{}{"param_1": 10, "param_2": "tools", "param_3": 5})
+	exec("DELETE FROM items WHERE value > 0", nil)
+	if _, err := engine.ExecuteSQL(ctx, "INSERT INTO items (name) VALUES (NULL)", nil); err == nil {
+		t.Fatal("Expected NOT NULL violation")
+	}
+
+	if len(sink.records) != 5 {
+		t.Fatalf("Expected 5 audit records, got %d", len(sink.records))
+	}
+
+	insert := sink.records[0]
+	if insert.ID != 42 || insert.RequestID != "req-1" || insert.APIKeyName != "writer" || insert.Table != "items" ||
+		insert.Action != "insert" || insert.Outcome != model.AuditOutcomeSuccess || insert.AffectedRows != 1 {
+		t.Errorf("Unexpected insert record: %+v", insert)
+	}
+	if insert.Params["param_1"] != "a" || insert.Params["param_2"] != redactedValue {
+		t.Errorf("Expected category parameter to be redacted, got %v", insert.Params)
+	}
+	if sink.records[1].SQL != "INSERT INTO items (name, category, value) VALUES ('?', '?', 2), ('?', '?', 3)" {
+		t.Errorf("Expected literals to be removed, got %q", sink.records[1].SQL)
+	}
+
+	update := sink.records[2]
+	if update.AffectedRows != 2 || len(update.Before) != 2 || len(update.After) != 2 {
+		t.Fatalf("Expected before and after images of 2 rows, got %+v", update)
+	}
+	if update.Before[0]["value"] != int64(1) || update.After[0]["value"] != int64(11) || update.After[0]["category"] != redactedValue {
+		t.Errorf("Unexpected row images: %v -> %v", update.Before, update.After)
+	}
+
+	// 超过 max_image_rows 时不记录行数据
+	if deleted := sink.records[3]; deleted.AffectedRows != 3 || deleted.Before != nil {
+		t.Errorf("Expected delete without row images, got %+v", deleted)
+	}
+
+	if failed := sink.records[4]; failed.Outcome != model.AuditOutcomeFailed || failed.Error == "" {
+		t.Errorf("Expected failed record, got %+v", failed)
+	}
+}
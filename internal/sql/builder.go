@@ -2,15 +2,19 @@ package sql
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"sql2api/internal/config"
 	"sql2api/internal/model"
 )
 
 // QueryBuilder 查询构建器
 type QueryBuilder struct {
-	dbType  string
-	dialect DatabaseDialect
+	dbType      string
+	dialect     DatabaseDialect
+	versions    map[string]config.VersionConfig    // 按表名（小写）索引的版本列
+	softDeletes map[string]config.SoftDeleteConfig // 按表名（小写）索引的软删除配置
 }
 
 // NewQueryBuilder 创建查询构建器
@@ -35,6 +39,8 @@ func (b *QueryBuilder) BuildStructuredQuery(query *model.StructuredQuery) (strin
 		return b.buildUpdateQuery(query)
 	case "delete":
 		return b.buildDeleteQuery(query)
+	case "restore":
+		return b.buildRestoreQuery(query)
 	default:
 		return "", nil, fmt.Errorf("unsupported action: %s", query.Action)
 	}
@@ -48,6 +54,11 @@ func (b *QueryBuilder) buildSelectQuery(query *model.StructuredQuery) (string, m
 	
 	// SELECT 子句
 	sql.WriteString("SELECT ")
+	if query.Limit > 0 && b.dbType == "sqlserver" {
+		// SQL Server 的 TOP 位于字段列表之前
+		sql.WriteString(b.dialect.GetLimitQuery(query.Limit))
+		sql.WriteString(" ")
+	}
 	if len(query.Fields) > 0 {
 		sql.WriteString(strings.Join(query.Fields, ", "))
 	} else {
@@ -58,21 +69,16 @@ func (b *QueryBuilder) buildSelectQuery(query *model.StructuredQuery) (string, m
 	sql.WriteString(" FROM ")
 	sql.WriteString(query.Table)
 	
-	// WHERE 子句
-	if len(query.Where) > 0 {
-		whereClause, whereParams, err := b.buildWhereClause(query.Where, paramIndex)
-		if err != nil {
-			return "", nil, fmt.Errorf("failed to build WHERE clause: %w", err)
-		}
+	// WHERE 子句（软删除的表排除已删除的行）
+	whereClause, err := b.buildFilteredWhere(query, params, paramIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	if whereClause != "" {
 		sql.WriteString(" WHERE ")
 		sql.WriteString(whereClause)
-		
-		// 合并参数
-		for k, v := range whereParams {
-			params[k] = v
-		}
-		paramIndex += len(whereParams)
 	}
+	paramIndex = len(params) + 1
 	
 	// GROUP BY 子句
 	if len(query.GroupBy) > 0 {
@@ -111,7 +117,7 @@ func (b *QueryBuilder) buildSelectQuery(query *model.StructuredQuery) (string, m
 	}
 	
 	// LIMIT 子句
-	if query.Limit > 0 {
+	if query.Limit > 0 && b.dbType != "sqlserver" {
 		sql.WriteString(" ")
 		sql.WriteString(b.dialect.GetLimitQuery(query.Limit))
 	}
@@ -155,6 +161,7 @@ func (b *QueryBuilder) buildInsertQuery(query *model.StructuredQuery) (string, m
 }
 
 // buildUpdateQuery 构建 UPDATE 查询
+// 表配置了版本列时自动递增版本列，指定期望版本时追加版本条件
 func (b *QueryBuilder) buildUpdateQuery(query *model.StructuredQuery) (string, map[string]interface{}, error) {
 	if len(query.Data) == 0 {
 		return "", nil, fmt.Errorf("no data provided for update")
@@ -163,6 +170,7 @@ func (b *QueryBuilder) buildUpdateQuery(query *model.StructuredQuery) (string, m
 	var sql strings.Builder
 	params := make(map[string]interface{})
 	paramIndex := 1
+	version, versioned := b.versionColumn(query.Table)
 	
 	// UPDATE 子句
 	sql.WriteString("UPDATE ")
@@ -172,33 +180,42 @@ func (b *QueryBuilder) buildUpdateQuery(query *model.StructuredQuery) (string, m
 	// SET 子句
 	var setClauses []string
 	for field, value := range query.Data {
+		if versioned && strings.EqualFold(field, version.Column) {
+			return "", nil, fmt.Errorf("version column %s is managed automatically", field)
+		}
 		placeholder := b.getParameterPlaceholder(paramIndex)
 		setClauses = append(setClauses, fmt.Sprintf("%s = %s", field, placeholder))
 		params[fmt.Sprintf("param_%d", paramIndex)] = value
 		paramIndex++
 	}
+	if versioned {
+		setClauses = append(setClauses, b.versionIncrement(version))
+	}
 	sql.WriteString(strings.Join(setClauses, ", "))
 	
 	// WHERE 子句
-	if len(query.Where) > 0 {
-		whereClause, whereParams, err := b.buildWhereClause(query.Where, paramIndex)
-		if err != nil {
-			return "", nil, fmt.Errorf("failed to build WHERE clause: %w", err)
-		}
+	whereClause, err := b.buildFilteredWhere(query, params, paramIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	if whereClause != "" {
 		sql.WriteString(" WHERE ")
 		sql.WriteString(whereClause)
-		
-		// 合并参数
-		for k, v := range whereParams {
-			params[k] = v
-		}
 	}
 	
 	return sql.String(), params, nil
 }
 
-// buildDeleteQuery 构建 DELETE 查询
+// buildDeleteQuery 构建 DELETE 查询，指定期望版本时追加版本条件
+// 软删除的表改为设置删除标记的 UPDATE 语句
 func (b *QueryBuilder) buildDeleteQuery(query *model.StructuredQuery) (string, map[string]interface{}, error) {
+	if len(query.Where) == 0 {
+		return "", nil, fmt.Errorf("WHERE clause is required for DELETE operation")
+	}
+	if softDelete, ok := b.softDeleteColumn(query.Table); ok {
+		return b.buildSoftDeleteQuery(query, softDelete)
+	}
+	
 	var sql strings.Builder
 	params := make(map[string]interface{})
 	
@@ -207,26 +224,86 @@ func (b *QueryBuilder) buildDeleteQuery(query *model.StructuredQuery) (string, m
 	sql.WriteString(query.Table)
 	
 	// WHERE 子句
+	whereClause, err := b.buildFilteredWhere(query, params, 1)
+	if err != nil {
+		return "", nil, err
+	}
+	sql.WriteString(" WHERE ")
+	sql.WriteString(whereClause)
+	
+	return sql.String(), params, nil
+}
+
+// buildFilteredWhere 构建 WHERE 条件（不含 WHERE 关键字），参数合并到 params
+// 指定期望版本时追加版本条件，软删除的表追加删除标记条件，均以 AND 连接
+func (b *QueryBuilder) buildFilteredWhere(query *model.StructuredQuery, params map[string]interface{}, paramIndex int) (string, error) {
+	var conditions []string
 	if len(query.Where) > 0 {
-		whereClause, whereParams, err := b.buildWhereClause(query.Where, 1)
+		clause, whereParams, err := b.buildWhereClause(query.Where, paramIndex)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to build WHERE clause: %w", err)
+			return "", fmt.Errorf("failed to build WHERE clause: %w", err)
 		}
-		sql.WriteString(" WHERE ")
-		sql.WriteString(whereClause)
+		conditions = append(conditions, clause)
 		
 		// 合并参数
 		for k, v := range whereParams {
 			params[k] = v
 		}
-	} else {
-		return "", nil, fmt.Errorf("WHERE clause is required for DELETE operation")
+		paramIndex += len(whereParams)
 	}
 	
-	return sql.String(), params, nil
+	condition, value, err := b.versionCondition(query, paramIndex)
+	if err != nil {
+		return "", err
+	}
+	if condition != "" {
+		conditions = append(conditions, condition)
+		params[fmt.Sprintf("param_%d", paramIndex)] = value
+		paramIndex++
+	}
+	
+	condition, values := b.softDeleteCondition(query, paramIndex)
+	if condition != "" {
+		conditions = append(conditions, condition)
+		for _, v := range values {
+			params[fmt.Sprintf("param_%d", paramIndex)] = v
+			paramIndex++
+		}
+	}
+	
+	if len(conditions) > 1 && len(query.Where) > 0 {
+		conditions[0] = "(" + conditions[0] + ")"
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// whereOperators 条件中支持的比较运算符，条件值为 {"运算符": 值} 形式时使用
+var whereOperators = map[string]string{
+	"eq":      "=",
+	"neq":     "<>",
+	"gt":      ">",
+	"gte":     ">=",
+	"lt":      "<",
+	"lte":     "<=",
+	"like":    "LIKE",
+	"nlike":   "NOT LIKE",
+	"ilike":   "ILIKE",
+	"in":      "IN",
+	"nin":     "NOT IN",
+	"is_null": "IS NULL",
+
+	// 子串匹配，参数值中的通配符会被转义
+	"contains":   "LIKE",
+	"startswith": "LIKE",
+	"endswith":   "LIKE",
 }
 
+// likeEscapeChar LIKE 转义字符（所有支持的数据库都可用 ESCAPE 指定）
+const likeEscapeChar = "!"
+
 // buildWhereClause 构建 WHERE 子句
+// 条件值为普通值时生成等值比较；为运算符映射（如 {"gt": 10, "lt": 20}）时按运算符生成，多个条件以 AND 连接
+// 逻辑组合使用 "$and"、"$or"（条件列表）和 "$not"（单个条件）键，如 {"$or": [{"a": 1}, {"b": {"gt": 2}}]}
 func (b *QueryBuilder) buildWhereClause(conditions map[string]interface{}, startIndex int) (string, map[string]interface{}, error) {
 	if len(conditions) == 0 {
 		return "", nil, nil
@@ -235,17 +312,222 @@ func (b *QueryBuilder) buildWhereClause(conditions map[string]interface{}, start
 	var clauses []string
 	params := make(map[string]interface{})
 	paramIndex := startIndex
+
+	// 按字段名排序，保证生成的 SQL 稳定
+	fields := make([]string, 0, len(conditions))
+	for field := range conditions {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
 	
-	for field, value := range conditions {
-		placeholder := b.getParameterPlaceholder(paramIndex)
-		clauses = append(clauses, fmt.Sprintf("%s = %s", field, placeholder))
-		params[fmt.Sprintf("param_%d", paramIndex)] = value
-		paramIndex++
+	for _, field := range fields {
+		value := conditions[field]
+
+		if strings.HasPrefix(field, "$") {
+			clause, logicalParams, err := b.buildLogicalClause(field, value, paramIndex)
+			if err != nil {
+				return "", nil, err
+			}
+			clauses = append(clauses, clause)
+			for k, v := range logicalParams {
+				params[k] = v
+			}
+			paramIndex += len(logicalParams)
+			continue
+		}
+
+		operators, ok := asOperatorMap(value)
+		if !ok {
+			placeholder := b.getParameterPlaceholder(paramIndex)
+			clauses = append(clauses, fmt.Sprintf("%s = %s", field, placeholder))
+			params[fmt.Sprintf("param_%d", paramIndex)] = value
+			paramIndex++
+			continue
+		}
+
+		names := make([]string, 0, len(operators))
+		for name := range operators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			clause, values, err := b.buildOperatorClause(field, name, operators[name], paramIndex)
+			if err != nil {
+				return "", nil, err
+			}
+			clauses = append(clauses, clause)
+			for _, v := range values {
+				params[fmt.Sprintf("param_%d", paramIndex)] = v
+				paramIndex++
+			}
+		}
 	}
 	
 	return strings.Join(clauses, " AND "), params, nil
 }
 
+// buildLogicalClause 构建 $and、$or、$not 逻辑组合条件
+func (b *QueryBuilder) buildLogicalClause(key string, value interface{}, startIndex int) (string, map[string]interface{}, error) {
+	if key == "$not" {
+		condition, ok := value.(map[string]interface{})
+		if !ok || len(condition) == 0 {
+			return "", nil, fmt.Errorf("%s requires a non-empty condition object", key)
+		}
+		clause, params, err := b.buildWhereClause(condition, startIndex)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + clause + ")", params, nil
+	}
+
+	var separator string
+	switch key {
+	case "$and":
+		separator = " AND "
+	case "$or":
+		separator = " OR "
+	default:
+		return "", nil, fmt.Errorf("unsupported logical operator %s", key)
+	}
+
+	var conditions []map[string]interface{}
+	switch v := value.(type) {
+	case []map[string]interface{}:
+		conditions = v
+	case []interface{}:
+		for _, item := range v {
+			condition, ok := item.(map[string]interface{})
+			if !ok {
+				return "", nil, fmt.Errorf("%s requires a list of condition objects", key)
+			}
+			conditions = append(conditions, condition)
+		}
+	default:
+		return "", nil, fmt.Errorf("%s requires a list of condition objects", key)
+	}
+	if len(conditions) == 0 {
+		return "", nil, fmt.Errorf("%s requires at least one condition", key)
+	}
+
+	var clauses []string
+	params := make(map[string]interface{})
+	paramIndex := startIndex
+	for _, condition := range conditions {
+		if len(condition) == 0 {
+			return "", nil, fmt.Errorf("%s conditions cannot be empty", key)
+		}
+		clause, conditionParams, err := b.buildWhereClause(condition, paramIndex)
+		if err != nil {
+			return "", nil, err
+		}
+		clauses = append(clauses, "("+clause+")")
+		for k, v := range conditionParams {
+			params[k] = v
+		}
+		paramIndex += len(conditionParams)
+	}
+
+	return "(" + strings.Join(clauses, separator) + ")", params, nil
+}
+
+// buildOperatorClause 构建单个运算符条件，返回条件文本和按顺序绑定的参数值
+func (b *QueryBuilder) buildOperatorClause(field, operator string, value interface{}, startIndex int) (string, []interface{}, error) {
+	switch operator {
+	case "is_null":
+		isNull, ok := value.(bool)
+		if !ok {
+			return "", nil, fmt.Errorf("operator is_null on %s requires a boolean", field)
+		}
+		if isNull {
+			return field + " IS NULL", nil, nil
+		}
+		return field + " IS NOT NULL", nil, nil
+
+	case "in", "nin":
+		values, ok := asValueList(value)
+		if !ok || len(values) == 0 {
+			return "", nil, fmt.Errorf("operator %s on %s requires a non-empty list", operator, field)
+		}
+		placeholders := make([]string, len(values))
+		for i := range values {
+			placeholders[i] = b.getParameterPlaceholder(startIndex + i)
+		}
+		return fmt.Sprintf("%s %s (%s)", field, whereOperators[operator], strings.Join(placeholders, ", ")), values, nil
+
+	case "contains", "startswith", "endswith":
+		text, ok := value.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("operator %s on %s requires a string", operator, field)
+		}
+		pattern := escapeLikePattern(text)
+		switch operator {
+		case "contains":
+			pattern = "%" + pattern + "%"
+		case "startswith":
+			pattern = pattern + "%"
+		case "endswith":
+			pattern = "%" + pattern
+		}
+		placeholder := b.getParameterPlaceholder(startIndex)
+		return fmt.Sprintf("%s LIKE %s ESCAPE '%s'", field, placeholder, likeEscapeChar), []interface{}{pattern}, nil
+
+	case "ilike":
+		// 只有 PostgreSQL 支持 ILIKE，其他数据库统一转为小写比较
+		placeholder := b.getParameterPlaceholder(startIndex)
+		if b.dbType == "postgres" {
+			return fmt.Sprintf("%s ILIKE %s", field, placeholder), []interface{}{value}, nil
+		}
+		return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", field, placeholder), []interface{}{value}, nil
+	}
+
+	sqlOperator, ok := whereOperators[operator]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported operator %s on %s", operator, field)
+	}
+	if value == nil {
+		return "", nil, fmt.Errorf("operator %s on %s requires a value", operator, field)
+	}
+	return fmt.Sprintf("%s %s %s", field, sqlOperator, b.getParameterPlaceholder(startIndex)), []interface{}{value}, nil
+}
+
+// escapeLikePattern 转义 LIKE 通配符（SQL Server 的 [ 也是通配符）
+func escapeLikePattern(value string) string {
+	var sb strings.Builder
+	for _, ch := range value {
+		switch ch {
+		case '%', '_', '[', '!':
+			sb.WriteString(likeEscapeChar)
+		}
+		sb.WriteRune(ch)
+	}
+	return sb.String()
+}
+
+// asOperatorMap 检查条件值是否为运算符映射，不支持的运算符在构建条件时报错
+func asOperatorMap(value interface{}) (map[string]interface{}, bool) {
+	operators, ok := value.(map[string]interface{})
+	if !ok || len(operators) == 0 {
+		return nil, false
+	}
+	return operators, true
+}
+
+// asValueList 将 IN 条件的值转换为列表
+func asValueList(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []string:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = item
+		}
+		return values, true
+	}
+	return nil, false
+}
+
 // getParameterPlaceholder 获取参数占位符
 func (b *QueryBuilder) getParameterPlaceholder(index int) string {
 	switch b.dbType {
@@ -253,6 +535,10 @@ func (b *QueryBuilder) getParameterPlaceholder(index int) string {
 		return fmt.Sprintf("$%d", index)
 	case "oracle":
 		return fmt.Sprintf(":param_%d", index)
+	case "sqlite", "mysql":
+		return "?"
+	case "sqlserver":
+		return fmt.Sprintf("@p%d", index)
 	default:
 		return "?"
 	}
@@ -294,6 +580,15 @@ func (b *QueryBuilder) BuildInsertQuery(req *model.InsertRequest) (string, map[s
 		paramIndex++
 	}
 
+	// SQL Server 的 OUTPUT 与 MERGE 语法结构不同，单独构建
+	if b.dbType == "sqlserver" {
+		query, err := b.buildSQLServerInsertQuery(req.Table, fields, [][]string{placeholders}, req.OnConflict, req.ConflictKeys, req.ReturnFields)
+		if err != nil {
+			return "", nil, err
+		}
+		return query, params, nil
+	}
+
 	sql.WriteString(" (")
 	sql.WriteString(strings.Join(fields, ", "))
 	sql.WriteString(") VALUES (")
@@ -302,7 +597,7 @@ func (b *QueryBuilder) BuildInsertQuery(req *model.InsertRequest) (string, map[s
 
 	// 处理冲突
 	if req.OnConflict != "" {
-		conflictClause := b.buildOnConflictClause(req.OnConflict, fields)
+		conflictClause := b.buildOnConflictClause(req.OnConflict, fields, req.ConflictKeys)
 		sql.WriteString(conflictClause)
 	}
 
@@ -315,33 +610,88 @@ func (b *QueryBuilder) BuildInsertQuery(req *model.InsertRequest) (string, map[s
 	return sql.String(), params, nil
 }
 
-// BuildBatchInsertQuery 构建批量插入查询
+// InsertChunk 批量插入拆分后的一条语句
+type InsertChunk struct {
+	SQL     string
+	Params  map[string]interface{}
+	Records []map[string]interface{} // 语句写入的记录，用于发出变更事件
+}
+
+// BuildBatchInsertQuery 构建单条多行批量插入查询
+// 字段取所有记录字段的并集，记录中缺少的字段写入 NULL
 func (b *QueryBuilder) BuildBatchInsertQuery(req *model.BatchInsertRequest) (string, map[string]interface{}, error) {
 	if len(req.Data) == 0 {
 		return "", nil, fmt.Errorf("no data provided for batch insert")
 	}
 
-	// 获取所有字段名（从第一条记录）
-	var fields []string
-	for field := range req.Data[0] {
-		fields = append(fields, field)
+	fields := batchInsertFields(req.Data)
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("no fields provided for batch insert")
+	}
+
+	return b.buildMultiRowInsertQuery(req, fields, req.Data)
+}
+
+// BuildBatchInsertChunks 构建批量插入语句，按数据库的绑定参数上限自动拆分
+// missing_fields 为 default（默认）时按记录的字段组合分组，缺少的列由数据库使用默认值；
+// 为 null 时所有记录使用字段并集，缺少的列写入 NULL。显式的 null 值始终写入 NULL
+// Oracle 每组生成一条数组绑定语句，其他数据库生成多行 INSERT
+func (b *QueryBuilder) BuildBatchInsertChunks(req *model.BatchInsertRequest) ([]InsertChunk, error) {
+	if len(req.Data) == 0 {
+		return nil, fmt.Errorf("no data provided for batch insert")
+	}
+
+	// Oracle 的数组绑定不支持 RETURNING INTO 和冲突处理
+	if b.dbType == "oracle" {
+		if req.OnConflict != "" {
+			return nil, fmt.Errorf("on_conflict is not supported for oracle batch insert")
+		}
+		if len(req.ReturnFields) > 0 {
+			return nil, fmt.Errorf("return_fields is not supported for oracle batch insert")
+		}
+	}
+
+	var chunks []InsertChunk
+	for _, group := range groupBatchInsertRecords(req.Data, req.MissingFields) {
+		if len(group.fields) == 0 {
+			return nil, fmt.Errorf("no fields provided for batch insert")
+		}
+
+		if b.dbType == "oracle" {
+			chunks = append(chunks, b.buildArrayInsertChunk(req.Table, group.fields, group.records))
+			continue
+		}
+
+		step := insertChunkRows(b.dbType, len(group.fields))
+		for start := 0; start < len(group.records); start += step {
+			end := start + step
+			if end > len(group.records) {
+				end = len(group.records)
+			}
+
+			records := group.records[start:end]
+			query, params, err := b.buildMultiRowInsertQuery(req, group.fields, records)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, InsertChunk{SQL: query, Params: params, Records: records})
+		}
 	}
 
+	return chunks, nil
+}
+
+// buildMultiRowInsertQuery 构建多行 INSERT（SQL Server 为 INSERT 或 MERGE），记录中缺少的字段写入 NULL
+func (b *QueryBuilder) buildMultiRowInsertQuery(req *model.BatchInsertRequest, fields []string, records []map[string]interface{}) (string, map[string]interface{}, error) {
 	var sql strings.Builder
 	params := make(map[string]interface{})
 
-	// INSERT INTO 子句
-	sql.WriteString("INSERT INTO ")
-	sql.WriteString(req.Table)
-	sql.WriteString(" (")
-	sql.WriteString(strings.Join(fields, ", "))
-	sql.WriteString(") VALUES ")
-
 	// VALUES 子句
+	var rows [][]string
 	var valuesClauses []string
 	paramIndex := 1
 
-	for _, record := range req.Data {
+	for _, record := range records {
 		var placeholders []string
 		for _, field := range fields {
 			placeholder := b.getParameterPlaceholder(paramIndex)
@@ -349,14 +699,30 @@ func (b *QueryBuilder) BuildBatchInsertQuery(req *model.BatchInsertRequest) (str
 			params[fmt.Sprintf("param_%d", paramIndex)] = record[field]
 			paramIndex++
 		}
+		rows = append(rows, placeholders)
 		valuesClauses = append(valuesClauses, "("+strings.Join(placeholders, ", ")+")")
 	}
 
+	// SQL Server 的 OUTPUT 与 MERGE 语法结构不同，单独构建
+	if b.dbType == "sqlserver" {
+		query, err := b.buildSQLServerInsertQuery(req.Table, fields, rows, req.OnConflict, req.ConflictKeys, req.ReturnFields)
+		if err != nil {
+			return "", nil, err
+		}
+		return query, params, nil
+	}
+
+	// INSERT INTO 子句
+	sql.WriteString("INSERT INTO ")
+	sql.WriteString(req.Table)
+	sql.WriteString(" (")
+	sql.WriteString(strings.Join(fields, ", "))
+	sql.WriteString(") VALUES ")
 	sql.WriteString(strings.Join(valuesClauses, ", "))
 
 	// 处理冲突
 	if req.OnConflict != "" {
-		conflictClause := b.buildOnConflictClause(req.OnConflict, fields)
+		conflictClause := b.buildOnConflictClause(req.OnConflict, fields, req.ConflictKeys)
 		sql.WriteString(conflictClause)
 	}
 
@@ -369,19 +735,114 @@ func (b *QueryBuilder) BuildBatchInsertQuery(req *model.BatchInsertRequest) (str
 	return sql.String(), params, nil
 }
 
+// buildArrayInsertChunk 构建 Oracle 数组绑定的插入语句：每列绑定一个包含所有记录值的切片
+func (b *QueryBuilder) buildArrayInsertChunk(table string, fields []string, records []map[string]interface{}) InsertChunk {
+	placeholders := make([]string, len(fields))
+	params := make(map[string]interface{}, len(fields))
+	for i, field := range fields {
+		placeholders[i] = b.getParameterPlaceholder(i + 1)
+		values := make([]interface{}, len(records))
+		for j, record := range records {
+			values[j] = record[field]
+		}
+		params[fmt.Sprintf("param_%d", i+1)] = values
+	}
+
+	query := "INSERT INTO " + table + " (" + strings.Join(fields, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+	return InsertChunk{SQL: query, Params: params, Records: records}
+}
+
+// batchInsertGroup 字段组合相同的一组插入记录
+type batchInsertGroup struct {
+	fields  []string
+	records []map[string]interface{}
+}
+
+// groupBatchInsertRecords 按缺失字段的处理方式对记录分组
+// null 模式下只有一组（字段并集）；default 模式下按字段组合分组，组的顺序为首次出现的顺序
+func groupBatchInsertRecords(records []map[string]interface{}, missingFields string) []batchInsertGroup {
+	if missingFields == model.MissingFieldsNull {
+		return []batchInsertGroup{{fields: batchInsertFields(records), records: records}}
+	}
+
+	var groups []batchInsertGroup
+	index := make(map[string]int)
+	for _, record := range records {
+		fields := batchInsertFields([]map[string]interface{}{record})
+		key := strings.Join(fields, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, batchInsertGroup{fields: fields})
+		}
+		groups[i].records = append(groups[i].records, record)
+	}
+	return groups
+}
+
+// batchInsertFields 获取所有记录字段的并集（按名称排序）
+func batchInsertFields(records []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, record := range records {
+		for field := range record {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 // buildOnConflictClause 构建冲突处理子句
-func (b *QueryBuilder) buildOnConflictClause(onConflict string, fields []string) string {
+func (b *QueryBuilder) buildOnConflictClause(onConflict string, fields, conflictKeys []string) string {
+	// 冲突目标（PostgreSQL 的 DO UPDATE 必须指定）
+	conflictTarget := ""
+	if len(conflictKeys) > 0 {
+		conflictTarget = " (" + strings.Join(conflictKeys, ", ") + ")"
+	}
+
 	switch b.dbType {
 	case "postgres":
 		switch onConflict {
 		case "ignore":
-			return " ON CONFLICT DO NOTHING"
+			return " ON CONFLICT" + conflictTarget + " DO NOTHING"
 		case "update":
 			var updateClauses []string
 			for _, field := range fields {
 				updateClauses = append(updateClauses, fmt.Sprintf("%s = EXCLUDED.%s", field, field))
 			}
-			return " ON CONFLICT DO UPDATE SET " + strings.Join(updateClauses, ", ")
+			return " ON CONFLICT" + conflictTarget + " DO UPDATE SET " + strings.Join(updateClauses, ", ")
+		}
+	case "sqlite":
+		switch onConflict {
+		case "ignore":
+			return " ON CONFLICT" + conflictTarget + " DO NOTHING"
+		case "update":
+			var updateClauses []string
+			for _, field := range fields {
+				updateClauses = append(updateClauses, fmt.Sprintf("%s = excluded.%s", field, field))
+			}
+			// SQLite 3.35+ 允许最后一个 ON CONFLICT 子句省略冲突目标
+			return " ON CONFLICT" + conflictTarget + " DO UPDATE SET " + strings.Join(updateClauses, ", ")
+		}
+	case "mysql":
+		if len(fields) == 0 {
+			return ""
+		}
+		switch onConflict {
+		case "ignore":
+			// 使用无副作用的自赋值代替 INSERT IGNORE，避免吞掉其他类型的错误
+			return fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s = %s", fields[0], fields[0])
+		case "update":
+			var updateClauses []string
+			for _, field := range fields {
+				updateClauses = append(updateClauses, fmt.Sprintf("%s = VALUES(%s)", field, field))
+			}
+			return " ON DUPLICATE KEY UPDATE " + strings.Join(updateClauses, ", ")
 		}
 	case "oracle":
 		// Oracle 使用 MERGE 语句处理冲突，这里简化处理
@@ -393,10 +854,95 @@ func (b *QueryBuilder) buildOnConflictClause(onConflict string, fields []string)
 // buildReturningClause 构建返回字段子句
 func (b *QueryBuilder) buildReturningClause(returnFields []string) string {
 	switch b.dbType {
-	case "postgres":
+	case "postgres", "sqlite":
 		return " RETURNING " + strings.Join(returnFields, ", ")
 	case "oracle":
 		return " RETURNING " + strings.Join(returnFields, ", ") + " INTO " + strings.Repeat(":out, ", len(returnFields)-1) + ":out"
+	case "mysql":
+		// MySQL 不支持 RETURNING，自增主键通过 LastInsertId 返回
+		return ""
+	case "sqlserver":
+		var outputFields []string
+		for _, field := range returnFields {
+			outputFields = append(outputFields, "INSERTED."+field)
+		}
+		return " OUTPUT " + strings.Join(outputFields, ", ")
 	}
 	return ""
 }
+
+// buildSQLServerInsertQuery 构建 SQL Server 插入语句
+// 无冲突处理时使用 INSERT ... OUTPUT ... VALUES，否则使用 MERGE
+func (b *QueryBuilder) buildSQLServerInsertQuery(table string, fields []string, rows [][]string, onConflict string, conflictKeys, returnFields []string) (string, error) {
+	var valuesClauses []string
+	for _, row := range rows {
+		valuesClauses = append(valuesClauses, "("+strings.Join(row, ", ")+")")
+	}
+
+	outputClause := ""
+	if len(returnFields) > 0 {
+		outputClause = b.buildReturningClause(returnFields)
+	}
+
+	var sql strings.Builder
+
+	if onConflict == "" {
+		sql.WriteString("INSERT INTO ")
+		sql.WriteString(table)
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(fields, ", "))
+		sql.WriteString(")")
+		sql.WriteString(outputClause)
+		sql.WriteString(" VALUES ")
+		sql.WriteString(strings.Join(valuesClauses, ", "))
+		return sql.String(), nil
+	}
+
+	// MERGE 需要明确的匹配列
+	if len(conflictKeys) == 0 {
+		return "", fmt.Errorf("conflict_keys is required for on_conflict with sqlserver")
+	}
+
+	isKey := make(map[string]bool, len(conflictKeys))
+	var matchClauses []string
+	for _, key := range conflictKeys {
+		isKey[key] = true
+		matchClauses = append(matchClauses, fmt.Sprintf("target.%s = source.%s", key, key))
+	}
+
+	var sourceFields []string
+	var updateClauses []string
+	for _, field := range fields {
+		sourceFields = append(sourceFields, "source."+field)
+		if !isKey[field] {
+			updateClauses = append(updateClauses, fmt.Sprintf("target.%s = source.%s", field, field))
+		}
+	}
+
+	// HOLDLOCK 避免并发 MERGE 时的竞态条件
+	sql.WriteString("MERGE INTO ")
+	sql.WriteString(table)
+	sql.WriteString(" WITH (HOLDLOCK) AS target USING (VALUES ")
+	sql.WriteString(strings.Join(valuesClauses, ", "))
+	sql.WriteString(") AS source (")
+	sql.WriteString(strings.Join(fields, ", "))
+	sql.WriteString(") ON ")
+	sql.WriteString(strings.Join(matchClauses, " AND "))
+
+	if onConflict == "update" && len(updateClauses) > 0 {
+		sql.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		sql.WriteString(strings.Join(updateClauses, ", "))
+	}
+
+	sql.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	sql.WriteString(strings.Join(fields, ", "))
+	sql.WriteString(") VALUES (")
+	sql.WriteString(strings.Join(sourceFields, ", "))
+	sql.WriteString(")")
+	sql.WriteString(outputClause)
+
+	// MERGE 语句必须以分号结尾
+	sql.WriteString(";")
+
+	return sql.String(), nil
+}
@@ -0,0 +1,510 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+)
+
+func TestQueryBuilder_MySQLInsert(t *testing.T) {
+	builder := NewQueryBuilder("mysql")
+
+	query, params, err := builder.BuildInsertQuery(&model.InsertRequest{
+		Table:        "items",
+		Data:         map[string]interface{}{"name": "widget"},
+		OnConflict:   "update",
+		ReturnFields: []string{"id"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build insert: %v", err)
+	}
+
+	expected := "INSERT INTO items (name) VALUES (?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+	if len(params) != 1 {
+		t.Errorf("Expected 1 parameter, got %d", len(params))
+	}
+
+	query, _, _ = builder.BuildInsertQuery(&model.InsertRequest{
+		Table:      "items",
+		Data:       map[string]interface{}{"name": "widget"},
+		OnConflict: "ignore",
+	})
+	if !strings.HasSuffix(query, "ON DUPLICATE KEY UPDATE name = name") {
+		t.Errorf("Expected no-op duplicate key clause, got '%s'", query)
+	}
+}
+
+func TestMySQLDialect_ApplySort(t *testing.T) {
+	dialect := NewMySQLDialect()
+
+	query := dialect.ApplyPagination(dialect.ApplySort("SELECT * FROM items", "order", "DESC"), 20, 10)
+	expected := "SELECT * FROM items ORDER BY `order` DESC LIMIT 10 OFFSET 20"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+}
+
+func TestDatabaseErrorMapper_MySQL(t *testing.T) {
+	mapper := NewDatabaseErrorMapper("mysql")
+
+	cases := map[string]int{
+		"Error 1064 (42000): You have an error in your SQL syntax":      model.SQLErrorSyntax,
+		"Error 1062 (23000): Duplicate entry 'widget' for key 'name'":   model.SQLErrorParams,
+		"Error 1142 (42000): INSERT command denied to user":             model.SQLErrorPermission,
+		"Error 1213 (40001): Deadlock found when trying to get lock":    model.SQLErrorTransaction,
+		"Error 3024 (HY000): Query execution was interrupted, max time": model.SQLErrorTimeout,
+		"dial tcp 127.0.0.1:3306: connect: connection refused":          model.SQLErrorConnection,
+	}
+
+	for message, expectedCode := range cases {
+		if sqlErr := mapper.MapError(errorString(message)); sqlErr.Code != expectedCode {
+			t.Errorf("Expected code %d for '%s', got %d", expectedCode, message, sqlErr.Code)
+		}
+	}
+}
+
+// errorString 测试用错误类型
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+func TestQueryBuilder_SQLServerMerge(t *testing.T) {
+	builder := NewQueryBuilder("sqlserver")
+
+	query, params, err := builder.BuildInsertQuery(&model.InsertRequest{
+		Table:        "items",
+		Data:         map[string]interface{}{"name": "widget"},
+		OnConflict:   "update",
+		ConflictKeys: []string{"name"},
+		ReturnFields: []string{"id"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build merge: %v", err)
+	}
+
+	expected := "MERGE INTO items WITH (HOLDLOCK) AS target USING (VALUES (@p1)) AS source (name) ON target.name = source.name " +
+		"WHEN NOT MATCHED THEN INSERT (name) VALUES (source.name) OUTPUT INSERTED.id;"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+	if params["param_1"] != "widget" {
+		t.Errorf("Expected param_1 'widget', got %v", params["param_1"])
+	}
+
+	validator := NewQueryValidator()
+	if err := validator.ValidateQueryStructure(query); err != nil {
+		t.Errorf("Expected merge query to pass validation, got %v", err)
+	}
+
+	if _, _, err := builder.BuildInsertQuery(&model.InsertRequest{
+		Table:      "items",
+		Data:       map[string]interface{}{"name": "widget"},
+		OnConflict: "ignore",
+	}); err == nil {
+		t.Error("Expected error when conflict_keys is missing")
+	}
+}
+
+func TestSQLServerDialect_Pagination(t *testing.T) {
+	builder := NewQueryBuilder("sqlserver")
+
+	query, _, err := builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "select",
+		Fields: []string{"name"},
+		Limit:  5,
+	})
+	if err != nil {
+		t.Fatalf("Failed to build select: %v", err)
+	}
+	if query != "SELECT TOP (5) name FROM items" {
+		t.Errorf("Expected TOP clause, got '%s'", query)
+	}
+
+	dialect := NewSQLServerDialect()
+	query = dialect.ApplyPagination("SELECT * FROM items", 20, 10)
+	expected := "SELECT * FROM items ORDER BY (SELECT NULL) OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+
+	// 只有最外层的 ORDER BY 满足 OFFSET FETCH 的要求，关键字之间可以有任意空白
+	withOrder := []string{
+		"SELECT * FROM items ORDER BY name",
+		"SELECT * FROM items ORDER\nBY name",
+		"SELECT * FROM items order  by name",
+		"SELECT * FROM items ORDER /* x */ BY name;",
+		"SELECT * FROM (SELECT id FROM items ORDER BY id OFFSET 0 ROWS) t ORDER BY id",
+	}
+	for _, sql := range withOrder {
+		query := dialect.ApplyPagination(sql, 0, 10)
+		if strings.Contains(query, "(SELECT NULL)") {
+			t.Errorf("Expected existing ORDER BY to be used for %q, got '%s'", sql, query)
+		}
+	}
+
+	withoutOrder := []string{
+		"SELECT * FROM (SELECT TOP 5 id FROM items ORDER BY id) t",
+		"SELECT id, ROW_NUMBER() OVER (ORDER BY name) AS rn FROM items",
+		"SELECT * FROM items WHERE note = 'order by'",
+		"SELECT * FROM items -- order by name",
+		"SELECT * FROM [order by]",
+	}
+	for _, sql := range withoutOrder {
+		query := dialect.ApplyPagination(sql, 0, 10)
+		if !strings.HasSuffix(query, "ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY") {
+			t.Errorf("Expected ORDER BY to be injected for %q, got '%s'", sql, query)
+		}
+	}
+
+	query = dialect.ApplySort("SELECT id, ROW_NUMBER() OVER (ORDER BY name) AS rn FROM items", "id", "DESC")
+	if expected := "SELECT id, ROW_NUMBER() OVER (ORDER BY name) AS rn FROM items ORDER BY id DESC"; query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+}
+
+func TestQueryBuilder_WhereOperators(t *testing.T) {
+	builder := NewQueryBuilder("sqlite")
+
+	query, params, err := builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "select",
+		Where: map[string]interface{}{
+			"value":    map[string]interface{}{"gt": 1, "lte": 5},
+			"category": map[string]interface{}{"in": []interface{}{"a", "b"}},
+			"name":     map[string]interface{}{"is_null": false},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build query: %v", err)
+	}
+
+	expected := "SELECT * FROM items WHERE category IN (?, ?) AND name IS NOT NULL AND value > ? AND value <= ?"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+	if len(params) != 4 {
+		t.Errorf("Expected 4 parameters, got %d", len(params))
+	}
+
+	_, _, err = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "select",
+		Where:  map[string]interface{}{"value": map[string]interface{}{"between": 1}},
+	})
+	if err == nil {
+		t.Error("Expected unknown operator to fail")
+	}
+}
+
+func TestQueryBuilder_LogicalWhere(t *testing.T) {
+	builder := NewQueryBuilder("postgres")
+
+	query, params, err := builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "select",
+		Where: map[string]interface{}{
+			"$or": []interface{}{
+				map[string]interface{}{"name": map[string]interface{}{"contains": "50%"}},
+				map[string]interface{}{"$not": map[string]interface{}{"value": 1}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build query: %v", err)
+	}
+
+	expected := "SELECT * FROM items WHERE ((name LIKE $1 ESCAPE '!') OR (NOT (value = $2)))"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+	if len(params) != 2 || params["param_1"] != "%50!%%" {
+		t.Errorf("Unexpected parameters: %v", params)
+	}
+
+	_, _, err = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "select",
+		Where:  map[string]interface{}{"$xor": []interface{}{}},
+	})
+	if err == nil {
+		t.Error("Expected unknown logical operator to fail")
+	}
+}
+
+func TestQueryBuilder_BatchInsertChunks(t *testing.T) {
+	data := []map[string]interface{}{
+		{"name": "a", "category": "tools"},
+		{"name": "b"},
+		{"name": "c", "category": nil},
+	}
+
+	// 默认按字段组合分组，缺少的列使用默认值
+	chunks, err := NewQueryBuilder("postgres").BuildBatchInsertChunks(&model.BatchInsertRequest{Table: "items", Data: data})
+	if err != nil {
+		t.Fatalf("Failed to build chunks: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(chunks))
+	}
+	if expected := "INSERT INTO items (category, name) VALUES ($1, $2), ($3, $4)"; chunks[0].SQL != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, chunks[0].SQL)
+	}
+	if chunks[0].Params["param_3"] != nil || len(chunks[0].Records) != 2 {
+		t.Errorf("Expected explicit null in first group, got %v", chunks[0].Params)
+	}
+	if expected := "INSERT INTO items (name) VALUES ($1)"; chunks[1].SQL != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, chunks[1].SQL)
+	}
+
+	// null 模式使用字段并集
+	chunks, err = NewQueryBuilder("postgres").BuildBatchInsertChunks(&model.BatchInsertRequest{Table: "items", Data: data, MissingFields: model.MissingFieldsNull})
+	if err != nil {
+		t.Fatalf("Failed to build chunks: %v", err)
+	}
+	if len(chunks) != 1 || len(chunks[0].Params) != 6 {
+		t.Fatalf("Expected 1 chunk with 6 parameters, got %+v", chunks)
+	}
+
+	// 按参数上限拆分
+	many := make([]map[string]interface{}, 1000)
+	for i := range many {
+		many[i] = map[string]interface{}{"name": i, "category": "tools", "value": i}
+	}
+	chunks, err = NewQueryBuilder("sqlite").BuildBatchInsertChunks(&model.BatchInsertRequest{Table: "items", Data: many})
+	if err != nil {
+		t.Fatalf("Failed to build chunks: %v", err)
+	}
+	if len(chunks) != 4 || len(chunks[0].Records) != 333 || len(chunks[3].Records) != 1 {
+		t.Errorf("Expected 4 chunks of at most 333 rows, got %d", len(chunks))
+	}
+
+	// Oracle 每组一条数组绑定语句
+	chunks, err = NewQueryBuilder("oracle").BuildBatchInsertChunks(&model.BatchInsertRequest{Table: "items", Data: many})
+	if err != nil {
+		t.Fatalf("Failed to build chunks: %v", err)
+	}
+	if expected := "INSERT INTO items (category, name, value) VALUES (:param_1, :param_2, :param_3)"; len(chunks) != 1 || chunks[0].SQL != expected {
+		t.Fatalf("Expected array insert '%s', got %+v", expected, chunks)
+	}
+	if values, ok := chunks[0].Params["param_2"].([]interface{}); !ok || len(values) != 1000 {
+		t.Errorf("Expected 1000 array-bound values, got %T", chunks[0].Params["param_2"])
+	}
+	if _, err := NewQueryBuilder("oracle").BuildBatchInsertChunks(&model.BatchInsertRequest{Table: "items", Data: many, OnConflict: "ignore"}); err == nil {
+		t.Error("Expected on_conflict to be rejected for oracle")
+	}
+}
+
+func TestQueryBuilder_Versioning(t *testing.T) {
+	versions := []config.VersionConfig{
+		{Table: "items", Column: "version"},
+		{Table: "events", Column: "updated_at", Type: config.VersionTypeTimestamp},
+	}
+	builder := NewQueryBuilder("postgres").WithVersioning(versions)
+
+	query, params, err := builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:           "items",
+		Action:          "update",
+		Data:            map[string]interface{}{"name": "widget"},
+		Where:           map[string]interface{}{"id": 1},
+		ExpectedVersion: float64(3),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build update: %v", err)
+	}
+	expected := "UPDATE items SET name = $1, version = version + 1 WHERE (id = $2) AND version = $3"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+	if params["param_3"] != int64(3) {
+		t.Errorf("Expected version parameter 3, got %v", params["param_3"])
+	}
+
+	// 未指定期望版本时仍递增版本列
+	query, _, _ = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "events",
+		Action: "update",
+		Data:   map[string]interface{}{"name": "deploy"},
+		Where:  map[string]interface{}{"id": 1},
+	})
+	expected = "UPDATE events SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+
+	query, params, err = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:           "items",
+		Action:          "delete",
+		Where:           map[string]interface{}{"id": 1},
+		ExpectedVersion: "7",
+	})
+	if err != nil {
+		t.Fatalf("Failed to build delete: %v", err)
+	}
+	expected = "DELETE FROM items WHERE (id = $1) AND version = $2"
+	if query != expected || params["param_2"] != int64(7) {
+		t.Errorf("Expected query '%s' with version 7, got '%s' with %v", expected, query, params["param_2"])
+	}
+
+	failures := []*model.StructuredQuery{
+		{Table: "items", Action: "update", Data: map[string]interface{}{"version": 5}, Where: map[string]interface{}{"id": 1}},
+		{Table: "items", Action: "delete", Where: map[string]interface{}{"id": 1}, ExpectedVersion: 1.5},
+		{Table: "events", Action: "delete", Where: map[string]interface{}{"id": 1}, ExpectedVersion: "yesterday"},
+		{Table: "orders", Action: "delete", Where: map[string]interface{}{"id": 1}, ExpectedVersion: 1},
+	}
+	for _, failure := range failures {
+		if _, _, err := builder.BuildStructuredQuery(failure); err == nil {
+			t.Errorf("Expected error for %+v", failure)
+		}
+	}
+
+	// SQLite 的时间文本按时间值比较
+	query, params, err = NewQueryBuilder("sqlite").WithVersioning(versions).BuildStructuredQuery(&model.StructuredQuery{
+		Table:           "events",
+		Action:          "delete",
+		Where:           map[string]interface{}{"id": 1},
+		ExpectedVersion: "2026-10-18T08:30:00.25+08:00",
+	})
+	if err != nil {
+		t.Fatalf("Failed to build delete: %v", err)
+	}
+	expected = "DELETE FROM events WHERE (id = ?) AND julianday(updated_at) = julianday(?)"
+	if query != expected || params["param_2"] != "2026-10-18 00:30:00.25" {
+		t.Errorf("Expected query '%s' with UTC text, got '%s' with %v", expected, query, params["param_2"])
+	}
+}
+
+func TestVersionTag(t *testing.T) {
+	if tag := VersionTag(int64(3)); tag != `"3"` {
+		t.Errorf("Expected \"3\", got %s", tag)
+	}
+	stamp := time.Date(2026, 10, 18, 8, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	if tag := VersionTag(stamp); tag != `"2026-10-18T00:30:00Z"` {
+		t.Errorf("Expected UTC timestamp tag, got %s", tag)
+	}
+	if tag := VersionTag("2026-10-18 00:30:00.5"); tag != `"2026-10-18T00:30:00.5Z"` {
+		t.Errorf("Expected normalized timestamp tag, got %s", tag)
+	}
+
+	cases := []struct {
+		header string
+		tag    string
+		weak   bool
+		err    bool
+	}{
+		{`"3"`, "3", false, false},
+		{`*`, "", false, false},
+		{`W/"3"`, "", true, false},
+		{`"3", "4"`, "", false, true},
+		{`3`, "", false, true},
+	}
+	for _, c := range cases {
+		tag, weak, err := ParseVersionTag(c.header)
+		if tag != c.tag || weak != c.weak || (err != nil) != c.err {
+			t.Errorf("ParseVersionTag(%s) = %q, %v, %v", c.header, tag, weak, err)
+		}
+	}
+}
+
+func TestQueryBuilder_SoftDelete(t *testing.T) {
+	softDeletes := []config.SoftDeleteConfig{
+		{Table: "items", Column: "deleted_at"},
+		{Table: "users", Column: "status", Type: config.SoftDeleteTypeValue, DeletedValue: "deleted", ActiveValue: "active"},
+	}
+	builder := NewQueryBuilder("postgres").
+		WithVersioning([]config.VersionConfig{{Table: "items", Column: "version"}}).
+		WithSoftDelete(softDeletes)
+
+	query, _, err := builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "select",
+		Where:  map[string]interface{}{"name": "widget"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build select: %v", err)
+	}
+	expected := "SELECT * FROM items WHERE (name = $1) AND deleted_at IS NULL"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+
+	// include_deleted 时不追加过滤条件
+	query, _, _ = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:          "items",
+		Action:         "select",
+		IncludeDeleted: true,
+	})
+	if query != "SELECT * FROM items" {
+		t.Errorf("Expected unfiltered select, got '%s'", query)
+	}
+
+	// 删除改为设置删除标记并递增版本
+	query, params, err := builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:           "items",
+		Action:          "delete",
+		Where:           map[string]interface{}{"id": 1},
+		ExpectedVersion: 2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to build delete: %v", err)
+	}
+	expected = "UPDATE items SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE (id = $1) AND version = $2 AND deleted_at IS NULL"
+	if query != expected || params["param_2"] != int64(2) {
+		t.Errorf("Expected query '%s' with version 2, got '%s' with %v", expected, query, params)
+	}
+
+	query, _, err = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "restore",
+		Where:  map[string]interface{}{"id": 1},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build restore: %v", err)
+	}
+	expected = "UPDATE items SET deleted_at = NULL, version = version + 1 WHERE (id = $1) AND deleted_at IS NOT NULL"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+
+	// 取值型删除标记
+	query, params, _ = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "users",
+		Action: "delete",
+		Where:  map[string]interface{}{"id": 5},
+	})
+	expected = "UPDATE users SET status = $1 WHERE (id = $2) AND (status IS NULL OR status <> $3)"
+	if query != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, query)
+	}
+	if params["param_1"] != "deleted" || params["param_3"] != "deleted" {
+		t.Errorf("Unexpected parameters: %v", params)
+	}
+
+	query, params, _ = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "users",
+		Action: "restore",
+		Where:  map[string]interface{}{"id": 5},
+	})
+	expected = "UPDATE users SET status = $1 WHERE (id = $2) AND status = $3"
+	if query != expected || params["param_1"] != "active" {
+		t.Errorf("Expected query '%s' restoring to active, got '%s' with %v", expected, query, params)
+	}
+
+	failures := []*model.StructuredQuery{
+		{Table: "items", Action: "delete"},
+		{Table: "orders", Action: "restore", Where: map[string]interface{}{"id": 1}},
+	}
+	for _, failure := range failures {
+		if _, _, err := builder.BuildStructuredQuery(failure); err == nil {
+			t.Errorf("Expected error for %+v", failure)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sql2api/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// maxBindParams 各数据库单条语句允许的绑定参数数量上限
+var maxBindParams = map[string]int{
+	"postgres":  65535,
+	"oracle":    65535,
+	"sqlite":    999, // SQLITE_MAX_VARIABLE_NUMBER 在旧版本中的默认值
+	"mysql":     65535,
+	"sqlserver": 2000, // 上限为 2100，预留部分给驱动
+}
+
+// maxInsertRows 各数据库单条 INSERT ... VALUES 允许的最大行数（0 表示不限制）
+var maxInsertRows = map[string]int{
+	"sqlserver": 1000,
+}
+
+// BulkInsert 批量写入多行数据（数据导入），所有行在一个事务中提交
+// PostgreSQL 使用 COPY FROM STDIN，Oracle 使用数组绑定，其他数据库按参数上限拆分为多行 INSERT
+// 值均以参数绑定，不经过字符串参数的注入检测；表必须在允许列表中且允许 insert 操作
+func (e *SQLEngine) BulkInsert(ctx context.Context, table string, columns []string, rows [][]interface{}) (*ExecuteResult, error) {
+	if !e.security.IsTableAllowed(table) {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotAllowed, table)
+	}
+	if !e.security.allowedActions["insert"] {
+		return nil, errors.New("action 'insert' is not allowed")
+	}
+	if len(columns) == 0 {
+		return nil, errors.New("no columns provided for bulk insert")
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("row %d has %d values, expected %d", i, len(row), len(columns))
+		}
+	}
+	if len(rows) == 0 {
+		return &ExecuteResult{}, nil
+	}
+
+	// 开始监控
+	queryCtx := e.monitor.StartQuery(ctx, "insert", e.dbType, "BULK INSERT INTO "+table)
+
+	// 创建带超时的上下文
+	execCtx, cancel := context.WithTimeout(queryCtx.Context, time.Duration(e.config.MaxQueryTime)*time.Second)
+	defer cancel()
+
+	var affected int64
+	var err error
+	if e.dbType == "postgres" {
+		affected, err = e.copyFrom(execCtx, table, columns, rows)
+	} else {
+		affected, err = e.insertRows(execCtx, table, columns, rows)
+	}
+	// 导入的行数可能很大，审计日志只记录列名和行数
+	e.auditOperation(ctx, &model.AuditRecord{
+		Table:        table,
+		Action:       ChangeInsert,
+		SQL:          fmt.Sprintf("BULK INSERT INTO %s (%s)", table, strings.Join(columns, ", ")),
+		AffectedRows: affected,
+	}, err)
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to insert rows: %w", e.errorMapper.MapError(err))
+	}
+	queryCtx.Finish(true, affected, 0, nil)
+
+	// 大批量写入只发出一个汇总事件，不附带行数据
+	if affected > 0 && e.publishesChanges() {
+		e.emitChange(ctx, &ChangeEvent{
+			Table:        table,
+			Operation:    ChangeInsert,
+			AffectedRows: affected,
+			Source:       ChangeSourceGateway,
+		})
+	}
+
+	return &ExecuteResult{AffectedRows: affected}, nil
+}
+
+// InsertChunkResult 批量插入单条语句的执行结果
+type InsertChunkResult struct {
+	Rows         int   // 语句包含的记录数
+	AffectedRows int64 // 影响行数
+	LastInsertID int64 // 自增主键（MySQL、SQLite）
+	Err          error // 执行错误，为 nil 表示成功
+}
+
+// ExecuteInsertChunks 执行 BuildBatchInsertChunks 拆分的批量插入语句
+// transactional 为 true 时所有语句在一个事务中执行，任一失败则全部回滚并返回错误；
+// 否则逐条提交，失败的语句记录在对应结果中并继续执行后续语句
+// 与 ExecuteSQL 相同地进行结构和安全验证并要求开启原生 SQL，参数数量不受单条查询的 100 个限制
+func (e *SQLEngine) ExecuteInsertChunks(ctx context.Context, chunks []InsertChunk, transactional bool) ([]InsertChunkResult, error) {
+	if len(chunks) == 0 {
+		return nil, errors.New("no statements provided for batch insert")
+	}
+
+	for i, chunk := range chunks {
+		if err := e.validateInsertChunk(ctx, chunk); err != nil {
+			return nil, fmt.Errorf("validation failed for chunk %d: %w", i, err)
+		}
+	}
+
+	// 检查是否允许原生 SQL
+	if !e.config.EnableRawSQL {
+		return nil, errors.New("raw SQL execution is disabled")
+	}
+
+	if transactional && !e.config.EnableTransactions {
+		return nil, errors.New("transactions are disabled")
+	}
+
+	// 创建带超时的上下文
+	execCtx, cancel := context.WithTimeout(ctx, time.Duration(e.config.MaxQueryTime)*time.Second)
+	defer cancel()
+
+	results := make([]InsertChunkResult, len(chunks))
+	if !transactional {
+		for i, chunk := range chunks {
+			results[i] = e.execInsertChunk(execCtx, e.db, chunk)
+			e.auditInsertChunk(ctx, chunk, results[i])
+			if results[i].Err == nil {
+				e.publishChange(WithChangeRows(ctx, chunk.Records...), chunk.SQL, &ExecuteResult{
+					AffectedRows: results[i].AffectedRows,
+					LastInsertID: results[i].LastInsertID,
+				})
+			}
+		}
+		return results, nil
+	}
+
+	err := e.db.WithContext(execCtx).Transaction(func(tx *gorm.DB) error {
+		for i, chunk := range chunks {
+			results[i] = e.execInsertChunk(execCtx, tx, chunk)
+			if results[i].Err != nil {
+				e.auditInsertChunk(ctx, chunk, results[i])
+				return fmt.Errorf("failed to execute chunk %d: %w", i, results[i].Err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 提交后才记录审计日志和发出变更事件
+	for i, chunk := range chunks {
+		e.auditInsertChunk(ctx, chunk, results[i])
+		e.publishChange(WithChangeRows(ctx, chunk.Records...), chunk.SQL, &ExecuteResult{
+			AffectedRows: results[i].AffectedRows,
+			LastInsertID: results[i].LastInsertID,
+		})
+	}
+	return results, nil
+}
+
+// auditInsertChunk 记录一条批量插入语句，插入的记录作为修改后的行
+func (e *SQLEngine) auditInsertChunk(ctx context.Context, chunk InsertChunk, result InsertChunkResult) {
+	if result.Err != nil {
+		e.auditWrite(ctx, chunk.SQL, chunk.Params, nil, nil, result.Err)
+		return
+	}
+	e.auditWrite(WithChangeRows(ctx, chunk.Records...), chunk.SQL, chunk.Params, &ExecuteResult{
+		AffectedRows: result.AffectedRows,
+		LastInsertID: result.LastInsertID,
+	}, nil, nil)
+}
+
+// validateInsertChunk 验证批量插入语句：语句按单条查询验证，参数值逐个验证（Oracle 数组绑定的切片逐元素验证）
+func (e *SQLEngine) validateInsertChunk(ctx context.Context, chunk InsertChunk) error {
+	if err := e.validator.ValidateQueryStructure(chunk.SQL); err != nil {
+		return fmt.Errorf("query structure validation failed: %w", err)
+	}
+	if err := e.checkSecurity(ctx, chunk.SQL, nil); err != nil {
+		return fmt.Errorf("security validation failed: %w", err)
+	}
+
+	for key, value := range chunk.Params {
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, v := range values {
+			if err := e.security.validateParameterValue(key, v); err != nil {
+				return fmt.Errorf("security validation failed: invalid parameter '%s': %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// execInsertChunk 执行一条批量插入语句并记录监控信息
+func (e *SQLEngine) execInsertChunk(ctx context.Context, db *gorm.DB, chunk InsertChunk) InsertChunkResult {
+	queryCtx := e.monitor.StartQuery(ctx, "insert", e.dbType, chunk.SQL)
+	queryCtx.SetParams(chunk.Params)
+
+	result, err := e.execStatement(queryCtx.Context, db, chunk.SQL, chunk.Params)
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return InsertChunkResult{Rows: len(chunk.Records), Err: e.errorMapper.MapError(err)}
+	}
+
+	queryCtx.Finish(true, result.AffectedRows, 0, nil)
+	return InsertChunkResult{
+		Rows:         len(chunk.Records),
+		AffectedRows: result.AffectedRows,
+		LastInsertID: result.LastInsertID,
+	}
+}
+
+// copyFrom 通过 COPY FROM STDIN 写入（仅 PostgreSQL），值由 pgx 按列类型编码
+func (e *SQLEngine) copyFrom(ctx context.Context, table string, columns []string, rows [][]interface{}) (int64, error) {
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var affected int64
+	err = conn.Raw(func(driverConn interface{}) error {
+		pgConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unsupported postgres driver connection: %T", driverConn)
+		}
+
+		var copyErr error
+		affected, copyErr = pgConn.Conn().CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), columns, pgx.CopyFromRows(rows))
+		return copyErr
+	})
+	return affected, err
+}
+
+// insertRows 在一个事务中通过 INSERT 写入：Oracle 使用数组绑定一次执行，其他数据库按参数上限拆分为多行 INSERT
+func (e *SQLEngine) insertRows(ctx context.Context, table string, columns []string, rows [][]interface{}) (int64, error) {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = e.dialect.QuoteIdentifier(column)
+	}
+	prefix := "INSERT INTO " + e.dialect.QuoteIdentifier(table) + " (" + strings.Join(quoted, ", ") + ") VALUES "
+
+	var affected int64
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if e.dbType == "oracle" {
+			// 每列绑定一个切片，go-ora 以数组绑定方式一次写入所有行
+			placeholders := make([]string, len(columns))
+			args := make([]interface{}, len(columns))
+			for i := range columns {
+				placeholders[i] = fmt.Sprintf(":%d", i+1)
+				values := make([]interface{}, len(rows))
+				for j, row := range rows {
+					values[j] = e.dialect.ConvertDataType(row[i])
+				}
+				args[i] = values
+			}
+
+			result, err := tx.Statement.ConnPool.ExecContext(ctx, prefix+"("+strings.Join(placeholders, ", ")+")", args...)
+			if err != nil {
+				return err
+			}
+			affected, err = result.RowsAffected()
+			return err
+		}
+
+		step := insertChunkRows(e.dbType, len(columns))
+		for start := 0; start < len(rows); start += step {
+			end := start + step
+			if end > len(rows) {
+				end = len(rows)
+			}
+
+			query, args := e.buildMultiRowInsert(prefix, len(columns), rows[start:end])
+			result, err := tx.Statement.ConnPool.ExecContext(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+			count, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			affected += count
+		}
+		return nil
+	})
+	return affected, err
+}
+
+// buildMultiRowInsert 构建多行 INSERT 语句及其位置参数
+func (e *SQLEngine) buildMultiRowInsert(prefix string, columnCount int, rows [][]interface{}) (string, []interface{}) {
+	var query strings.Builder
+	query.WriteString(prefix)
+
+	args := make([]interface{}, 0, len(rows)*columnCount)
+	for i, row := range rows {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for j, value := range row {
+			if j > 0 {
+				query.WriteString(", ")
+			}
+			args = append(args, e.dialect.ConvertDataType(value))
+			query.WriteString(bindPlaceholder(e.dbType, len(args)))
+		}
+		query.WriteString(")")
+	}
+	return query.String(), args
+}
+
+// insertChunkRows 计算单条多行 INSERT 可容纳的行数
+func insertChunkRows(dbType string, columnCount int) int {
+	limit, ok := maxBindParams[dbType]
+	if !ok {
+		limit = maxBindParams["sqlite"]
+	}
+
+	rows := limit / columnCount
+	if maxRows := maxInsertRows[dbType]; maxRows > 0 && rows > maxRows {
+		rows = maxRows
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// bindPlaceholder 获取位置参数的占位符（序号从 1 开始）
+func bindPlaceholder(dbType string, index int) string {
+	switch dbType {
+	case "postgres":
+		return fmt.Sprintf("$%d", index)
+	case "oracle":
+		return fmt.Sprintf(":%d", index)
+	case "sqlserver":
+		return fmt.Sprintf("@p%d", index)
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,452 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sql2api/internal/config"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSubscriptionOverflow 订阅者处理过慢，事件缓冲已满
+	ErrSubscriptionOverflow = errors.New("subscription buffer overflow")
+	// ErrSubscriptionClosed 订阅已被服务器关闭
+	ErrSubscriptionClosed = errors.New("subscription closed")
+)
+
+// 变更操作类型
+const (
+	ChangeInsert = "insert"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// 变更事件来源
+const (
+	ChangeSourceGateway  = "gateway"  // 通过网关执行的写操作
+	ChangeSourceDatabase = "database" // 数据库触发器（LISTEN/NOTIFY）
+)
+
+// ChangeEvent 表数据变更事件
+// Row 为新行（删除时为被删除的行），Old 为更新前的行；
+// 行数据未知时（如通过网关执行的 UPDATE、DELETE）两者为空，客户端可按需重新查询。
+// Keys 为调用方已知的受影响行的键（如资源操作的主键、结构化更新的等值条件）
+type ChangeEvent struct {
+	ID           uint64                   `json:"id"`
+	Table        string                   `json:"table"`
+	Operation    string                   `json:"operation"`
+	Row          map[string]interface{}   `json:"row,omitempty"`
+	Old          map[string]interface{}   `json:"old,omitempty"`
+	Keys         []map[string]interface{} `json:"keys,omitempty"`
+	AffectedRows int64                    `json:"affected_rows,omitempty"`
+	LastInsertID int64                    `json:"last_insert_id,omitempty"`
+	Source       string                   `json:"source"`
+	Timestamp    time.Time                `json:"timestamp"`
+}
+
+// ChangeHandler 网关写操作的变更处理函数，在写操作成功（事务提交）后同步调用，不能修改事件
+type ChangeHandler func(ctx context.Context, event *ChangeEvent)
+
+// ChangeHub 变更事件分发器，同一数据库连接上的所有 SQLEngine 共享一个分发器
+type ChangeHub struct {
+	mu            sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+	handlers      map[uint64]ChangeHandler
+	handlerSeq    uint64
+	sequence      atomic.Uint64
+}
+
+// changeHubs 按数据库连接保存的分发器
+var changeHubs sync.Map
+
+// changeHubFor 获取数据库连接对应的分发器
+func changeHubFor(db *gorm.DB) *ChangeHub {
+	hub, _ := changeHubs.LoadOrStore(db, NewChangeHub())
+	return hub.(*ChangeHub)
+}
+
+// NewChangeHub 创建变更事件分发器
+func NewChangeHub() *ChangeHub {
+	return &ChangeHub{
+		subscriptions: make(map[*Subscription]struct{}),
+		handlers:      make(map[uint64]ChangeHandler),
+	}
+}
+
+// OnChange 注册网关写操作的变更处理函数（如 webhook），返回取消注册的函数
+// 处理函数只接收网关发出的事件，与订阅的事件来源配置无关
+func (h *ChangeHub) OnChange(handler ChangeHandler) func() {
+	h.mu.Lock()
+	h.handlerSeq++
+	id := h.handlerSeq
+	h.handlers[id] = handler
+	h.mu.Unlock()
+
+	return func() {
+		h.mu.Lock()
+		delete(h.handlers, id)
+		h.mu.Unlock()
+	}
+}
+
+// hasHandlers 检查是否注册了变更处理函数
+func (h *ChangeHub) hasHandlers() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.handlers) > 0
+}
+
+// handle 调用所有变更处理函数
+func (h *ChangeHub) handle(ctx context.Context, event *ChangeEvent) {
+	h.mu.RLock()
+	handlers := make([]ChangeHandler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler)
+	}
+	h.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
+
+// Subscribe 订阅表的变更事件，filter 为空时接收所有事件
+func (h *ChangeHub) Subscribe(table string, filter *RowFilter, bufferSize int) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		table:  strings.ToLower(table),
+		filter: filter,
+		events: make(chan *ChangeEvent, bufferSize),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.subscriptions[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Publish 分发事件，缓冲已满的订阅会被关闭
+func (h *ChangeHub) Publish(event *ChangeEvent) {
+	event.ID = h.sequence.Add(1)
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	table := strings.ToLower(event.Table)
+
+	var overflowed []*Subscription
+	h.mu.RLock()
+	for sub := range h.subscriptions {
+		if sub.table != table {
+			continue
+		}
+		delivered, ok := sub.accept(event)
+		if !ok {
+			continue
+		}
+		select {
+		case sub.events <- delivered:
+		default:
+			overflowed = append(overflowed, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range overflowed {
+		sub.close(ErrSubscriptionOverflow)
+	}
+}
+
+// Close 关闭所有订阅
+func (h *ChangeHub) Close() {
+	h.mu.RLock()
+	subscriptions := make([]*Subscription, 0, len(h.subscriptions))
+	for sub := range h.subscriptions {
+		subscriptions = append(subscriptions, sub)
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		sub.close(ErrSubscriptionClosed)
+	}
+}
+
+// Subscription 表变更订阅
+type Subscription struct {
+	hub    *ChangeHub
+	table  string
+	filter *RowFilter
+	events chan *ChangeEvent
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// Events 获取事件通道
+func (s *Subscription) Events() <-chan *ChangeEvent {
+	return s.events
+}
+
+// Done 订阅结束时关闭的通道
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err 获取订阅被服务器结束的原因，订阅者主动关闭时为 nil
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.close(nil)
+}
+
+// close 从分发器移除订阅并记录结束原因
+func (s *Subscription) close(err error) {
+	s.once.Do(func() {
+		s.hub.mu.Lock()
+		delete(s.hub.subscriptions, s)
+		s.hub.mu.Unlock()
+
+		s.err = err
+		close(s.done)
+	})
+}
+
+// accept 按订阅条件过滤事件
+// 更新前或更新后的行满足条件时投递；行数据不包含条件引用的列时无法判断，
+// 只投递不含行数据的事件，以免把不满足条件的行发给订阅者
+func (s *Subscription) accept(event *ChangeEvent) (*ChangeEvent, bool) {
+	if s.filter == nil {
+		return event, true
+	}
+
+	rows := make([]map[string]interface{}, 0, 2)
+	for _, row := range []map[string]interface{}{event.Row, event.Old} {
+		if s.filter.Covers(row) {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		stripped := *event
+		stripped.Row = nil
+		stripped.Old = nil
+		return &stripped, true
+	}
+
+	for _, row := range rows {
+		if s.filter.Match(row) {
+			return event, true
+		}
+	}
+	return nil, false
+}
+
+// ===== 网关写操作的变更事件 =====
+
+// changeRowsContextKey 上下文中保存写入行数据的键
+type changeRowsContextKey struct{}
+
+// changeKeysContextKey 上下文中保存受影响行的键
+type changeKeysContextKey struct{}
+
+// changeOperationContextKey 上下文中保存变更事件操作类型的键
+type changeOperationContextKey struct{}
+
+// WithChangeRows 在上下文中附加 INSERT 写入的行数据，SQLEngine 发出变更事件时按行发出
+func WithChangeRows(ctx context.Context, rows ...map[string]interface{}) context.Context {
+	return context.WithValue(ctx, changeRowsContextKey{}, rows)
+}
+
+// WithChangeKeys 在上下文中附加 UPDATE、DELETE 受影响行的键，随变更事件发出
+func WithChangeKeys(ctx context.Context, keys ...map[string]interface{}) context.Context {
+	return context.WithValue(ctx, changeKeysContextKey{}, keys)
+}
+
+// WithChangeOperation 在上下文中指定变更事件的操作类型，覆盖从语句推断的类型（如软删除的 UPDATE 作为 delete 发出）
+func WithChangeOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, changeOperationContextKey{}, operation)
+}
+
+// Changes 获取引擎所在数据库连接的变更事件分发器
+func (e *SQLEngine) Changes() *ChangeHub {
+	return e.changes
+}
+
+// emitsChanges 检查引擎是否发出网关写操作的变更事件（notify 模式下由数据库触发器发出）
+func (e *SQLEngine) emitsChanges() bool {
+	subscriptions := e.config.Subscriptions
+	return subscriptions.Enabled && subscriptions.Source != config.SubscriptionSourceNotify
+}
+
+// publishesChanges 检查网关写操作的变更事件是否有接收方（订阅者或变更处理函数）
+func (e *SQLEngine) publishesChanges() bool {
+	return e.emitsChanges() || e.changes.hasHandlers()
+}
+
+// publishChange 写操作成功后发出变更事件
+func (e *SQLEngine) publishChange(ctx context.Context, query string, result *ExecuteResult) {
+	if result.AffectedRows == 0 || !e.publishesChanges() {
+		return
+	}
+
+	operation, table := writeTarget(query)
+	if table == "" {
+		return
+	}
+	if override, ok := ctx.Value(changeOperationContextKey{}).(string); ok && override != "" {
+		operation = override
+	}
+
+	// INSERT 的行数据由调用方通过上下文提供，与影响行数一致时按行发出
+	rows, _ := ctx.Value(changeRowsContextKey{}).([]map[string]interface{})
+	if operation == ChangeInsert && len(rows) > 0 && int64(len(rows)) == result.AffectedRows {
+		for _, row := range rows {
+			event := &ChangeEvent{
+				Table:     table,
+				Operation: operation,
+				Row:       row,
+				Source:    ChangeSourceGateway,
+			}
+			if len(rows) == 1 {
+				event.LastInsertID = result.LastInsertID
+			}
+			e.emitChange(ctx, event)
+		}
+		return
+	}
+
+	event := &ChangeEvent{
+		Table:        table,
+		Operation:    operation,
+		AffectedRows: result.AffectedRows,
+		LastInsertID: result.LastInsertID,
+		Source:       ChangeSourceGateway,
+	}
+	if operation != ChangeInsert {
+		event.Keys, _ = ctx.Value(changeKeysContextKey{}).([]map[string]interface{})
+	}
+	e.emitChange(ctx, event)
+}
+
+// emitChange 将网关事件分发给订阅者（notify 模式下订阅者只接收数据库事件）和变更处理函数
+func (e *SQLEngine) emitChange(ctx context.Context, event *ChangeEvent) {
+	if e.emitsChanges() {
+		e.changes.Publish(event)
+	} else {
+		event.ID = e.changes.sequence.Add(1)
+		event.Timestamp = time.Now()
+	}
+	e.changes.handle(ctx, event)
+}
+
+// writeTarget 解析写语句的操作类型和目标表
+func writeTarget(query string) (string, string) {
+	words := strings.Fields(query)
+	for i, word := range words {
+		var operation, keyword string
+		switch strings.ToLower(word) {
+		case "insert", "merge":
+			operation, keyword = ChangeInsert, "into"
+		case "update":
+			// ON DUPLICATE KEY UPDATE / ON CONFLICT DO UPDATE / WHEN MATCHED THEN UPDATE 不是 UPDATE 语句
+			if i > 0 {
+				if prev := strings.ToLower(words[i-1]); prev == "key" || prev == "do" || prev == "then" {
+					continue
+				}
+			}
+			operation = ChangeUpdate
+		case "delete":
+			operation, keyword = ChangeDelete, "from"
+		default:
+			continue
+		}
+
+		next := i + 1
+		if keyword != "" && next < len(words) && strings.EqualFold(words[next], keyword) {
+			next++
+		}
+		if next >= len(words) {
+			return operation, ""
+		}
+		return operation, unquoteTableName(words[next])
+	}
+	return "", ""
+}
+
+// unquoteTableName 去除表名中的标识符引号和标点
+func unquoteTableName(name string) string {
+	name = strings.Trim(name, "(),;")
+	if index := strings.IndexByte(name, '('); index >= 0 {
+		name = name[:index]
+	}
+	return strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(name)
+}
+
+// EqualityKeys 从结构化 where 条件中提取受影响行的键
+// 顶层条件之间为 AND 关系，只使用等值（含 eq）和 in 条件，忽略其他条件不会遗漏受影响的行；
+// 最多展开一个 in 条件，没有等值条件时返回空
+func EqualityKeys(where map[string]interface{}) []map[string]interface{} {
+	fixed := make(map[string]interface{})
+	var inColumn string
+	var inValues []interface{}
+
+	columns := make([]string, 0, len(where))
+	for column := range where {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		value := where[column]
+		if strings.HasPrefix(column, "$") || value == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if len(v) != 1 {
+				continue
+			}
+			if eq, ok := v["eq"]; ok && eq != nil {
+				fixed[column] = eq
+			} else if in, ok := v["in"].([]interface{}); ok && len(in) > 0 && inColumn == "" {
+				inColumn, inValues = column, in
+			}
+		case []interface{}:
+			continue
+		default:
+			fixed[column] = v
+		}
+	}
+
+	if inColumn == "" {
+		if len(fixed) == 0 {
+			return nil
+		}
+		return []map[string]interface{}{fixed}
+	}
+
+	keys := make([]map[string]interface{}, 0, len(inValues))
+	for _, value := range inValues {
+		key := make(map[string]interface{}, len(fixed)+1)
+		for column, v := range fixed {
+			key[column] = v
+		}
+		key[inColumn] = value
+		keys = append(keys, key)
+	}
+	return keys
+}
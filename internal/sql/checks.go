@@ -0,0 +1,268 @@
+package sql
+
+import (
+	"strings"
+	"unicode"
+)
+
+// checkToken CHECK 约束表达式的词法单元
+type checkToken struct {
+	kind     byte   // 'i' 标识符，'s' 字符串，'p' 符号
+	text     string // 标识符已去除引号，字符串已去除引号并还原转义
+	adjacent bool   // 与前一个词法单元之间没有空白
+}
+
+// applyCheckValues 将 CHECK 约束限定的取值列表设置到对应列（列已有枚举取值时保留）
+func applyCheckValues(columns []ColumnSchema, checks []string) {
+	for _, check := range checks {
+		column, values, ok := parseAllowedValues(check)
+		if !ok {
+			continue
+		}
+		for i := range columns {
+			if strings.EqualFold(columns[i].Name, column) && len(columns[i].Enum) == 0 {
+				columns[i].Enum = values
+			}
+		}
+	}
+}
+
+// enumTypeValues 解析 MySQL enum('a','b') 列类型中的取值
+func enumTypeValues(dataType string) []string {
+	if !strings.HasPrefix(strings.ToLower(dataType), "enum(") {
+		return nil
+	}
+
+	var values []string
+	for _, token := range tokenizeCheck(dataType[len("enum"):]) {
+		if token.kind == 's' {
+			values = append(values, token.text)
+		}
+	}
+	return values
+}
+
+// extractCheckClauses 从建表语句中提取所有 CHECK (...) 子句的表达式（SQLite）
+func extractCheckClauses(ddl string) []string {
+	var clauses []string
+	upper := strings.ToUpper(ddl)
+	inString := false
+	for i := 0; i < len(ddl); i++ {
+		if ddl[i] == '\'' {
+			inString = !inString
+			continue
+		}
+		if inString || !strings.HasPrefix(upper[i:], "CHECK") {
+			continue
+		}
+		if i > 0 && isIdentifierByte(ddl[i-1]) {
+			continue
+		}
+
+		start := i + len("CHECK")
+		for start < len(ddl) && strings.IndexByte(" \t\r\n", ddl[start]) >= 0 {
+			start++
+		}
+		if start >= len(ddl) || ddl[start] != '(' {
+			continue
+		}
+
+		// 匹配括号（跳过字符串中的括号）
+		depth := 0
+		quoted := false
+		for end := start; end < len(ddl); end++ {
+			switch {
+			case ddl[end] == '\'':
+				quoted = !quoted
+			case quoted:
+			case ddl[end] == '(':
+				depth++
+			case ddl[end] == ')':
+				depth--
+			}
+			if depth == 0 {
+				clauses = append(clauses, ddl[start:end+1])
+				i = end
+				break
+			}
+		}
+	}
+	return clauses
+}
+
+// parseAllowedValues 解析限定单列取值的 CHECK 约束表达式，支持以下形式：
+//   - col IN ('a', 'b')
+//   - col = ANY (ARRAY['a'::text, 'b'::text])（PostgreSQL）
+//   - col = 'a' OR col = 'b'（SQL Server 会将 IN 改写为此形式）
+//
+// 类型转换（::type）、字符集前缀（_utf8mb4'a'）和 N'a' 前缀会被忽略；取值必须都是字符串
+func parseAllowedValues(expr string) (string, []string, bool) {
+	var tokens []checkToken
+	for i, token := range tokenizeCheck(expr) {
+		if i == 0 && token.kind == 'i' && strings.EqualFold(token.text, "check") {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	tokens = normalizeCheckTokens(tokens)
+	if len(tokens) < 3 || tokens[0].kind != 'i' {
+		return "", nil, false
+	}
+	column := tokens[0].text
+
+	switch {
+	case isCheckKeyword(tokens[1], "in"):
+		values, ok := checkValueList(tokens[2:])
+		return column, values, ok
+
+	case isCheckSymbol(tokens[1], "=") && len(tokens) > 3 && isCheckKeyword(tokens[2], "any") && isCheckKeyword(tokens[3], "array"):
+		values, ok := checkValueList(tokens[4:])
+		return column, values, ok
+
+	case isCheckSymbol(tokens[1], "="):
+		// col = 'a' OR col = 'b' ...
+		var values []string
+		for i := 0; i < len(tokens); i += 4 {
+			if i+2 >= len(tokens) || tokens[i].kind != 'i' || !strings.EqualFold(tokens[i].text, column) ||
+				!isCheckSymbol(tokens[i+1], "=") || tokens[i+2].kind != 's' {
+				return "", nil, false
+			}
+			values = append(values, tokens[i+2].text)
+			if i+3 < len(tokens) && !isCheckKeyword(tokens[i+3], "or") {
+				return "", nil, false
+			}
+		}
+		return column, values, len(values) > 0
+	}
+
+	return "", nil, false
+}
+
+// normalizeCheckTokens 去除括号、类型转换和字符串前缀
+func normalizeCheckTokens(tokens []checkToken) []checkToken {
+	result := make([]checkToken, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.kind == 'p' && strings.ContainsAny(token.text, "()[]"):
+			continue
+
+		case token.kind == 'p' && token.text == "::":
+			// 跳过类型名（可能由多个单词组成，如 character varying）
+			for i+1 < len(tokens) && tokens[i+1].kind == 'i' {
+				i++
+			}
+			continue
+
+		case token.kind == 'i' && i+1 < len(tokens) && tokens[i+1].kind == 's' && tokens[i+1].adjacent &&
+			(strings.HasPrefix(token.text, "_") || strings.EqualFold(token.text, "n")):
+			continue
+		}
+		result = append(result, token)
+	}
+	return result
+}
+
+// checkValueList 解析以逗号分隔的字符串取值列表
+func checkValueList(tokens []checkToken) ([]string, bool) {
+	var values []string
+	for i, token := range tokens {
+		if i%2 == 1 {
+			if !isCheckSymbol(token, ",") {
+				return nil, false
+			}
+			continue
+		}
+		if token.kind != 's' {
+			return nil, false
+		}
+		values = append(values, token.text)
+	}
+	return values, len(values) > 0 && len(tokens)%2 == 1
+}
+
+// tokenizeCheck 将 CHECK 约束表达式拆分为词法单元
+func tokenizeCheck(expr string) []checkToken {
+	var tokens []checkToken
+	adjacent := false
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			adjacent = false
+			continue
+
+		case c == '\'':
+			// 字符串，'' 表示单引号
+			var text strings.Builder
+			i++
+			for i < len(expr) {
+				if expr[i] == '\'' {
+					if i+1 < len(expr) && expr[i+1] == '\'' {
+						text.WriteByte('\'')
+						i += 2
+						continue
+					}
+					break
+				}
+				text.WriteByte(expr[i])
+				i++
+			}
+			i++
+			tokens = append(tokens, checkToken{kind: 's', text: text.String(), adjacent: adjacent})
+
+		case c == '"' || c == '`' || c == '[' && i+1 < len(expr) && isQuotedIdentifier(expr[i+1:]):
+			// 带引号的标识符："col"、`col`、[col]
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(expr[i+1:], closing)
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, checkToken{kind: 'i', text: expr[i+1 : i+1+end], adjacent: adjacent})
+			i += end + 2
+
+		case isIdentifierByte(c):
+			start := i
+			for i < len(expr) && isIdentifierByte(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, checkToken{kind: 'i', text: expr[start:i], adjacent: adjacent})
+
+		case c == ':' && i+1 < len(expr) && expr[i+1] == ':':
+			tokens = append(tokens, checkToken{kind: 'p', text: "::", adjacent: adjacent})
+			i += 2
+
+		default:
+			tokens = append(tokens, checkToken{kind: 'p', text: string(c), adjacent: adjacent})
+			i++
+		}
+		adjacent = true
+	}
+	return tokens
+}
+
+// isQuotedIdentifier 检查 [ 之后是否为 SQL Server 的带括号标识符（而非数组下标或 []）
+func isQuotedIdentifier(rest string) bool {
+	end := strings.IndexByte(rest, ']')
+	return end > 0 && !strings.ContainsAny(rest[:end], "',[")
+}
+
+// isIdentifierByte 检查字节是否可以出现在未加引号的标识符中
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// isCheckKeyword 检查词法单元是否为指定关键字
+func isCheckKeyword(token checkToken, keyword string) bool {
+	return token.kind == 'i' && strings.EqualFold(token.text, keyword)
+}
+
+// isCheckSymbol 检查词法单元是否为指定符号
+func isCheckSymbol(token checkToken, symbol string) bool {
+	return token.kind == 'p' && token.text == symbol
+}
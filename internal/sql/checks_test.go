@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseAllowedValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		column string
+		values []string
+	}{
+		{"in list", "status IN ('new', 'done')", "status", []string{"new", "done"}},
+		{"postgres text", "CHECK ((status = ANY (ARRAY['new'::text, 'it''s'::text])))", "status", []string{"new", "it's"}},
+		{"postgres varchar", "CHECK (((status)::text = ANY ((ARRAY['new'::character varying, 'done'::character varying])::text[])))", "status", []string{"new", "done"}},
+		{"mysql", "(`status` in (_utf8mb4'new',_utf8mb4'done'))", "status", []string{"new", "done"}},
+		{"sqlserver", "([status]=N'done' OR [status]=N'new')", "status", []string{"done", "new"}},
+		{"oracle", `"STATUS" IN ('NEW','DONE')`, "STATUS", []string{"NEW", "DONE"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column, values, ok := parseAllowedValues(tt.expr)
+			if !ok || column != tt.column || !reflect.DeepEqual(values, tt.values) {
+				t.Errorf("Expected %s %v, got %s %v (ok=%v)", tt.column, tt.values, column, values, ok)
+			}
+		})
+	}
+
+	for _, expr := range []string{
+		`"NAME" IS NOT NULL`,
+		"value > 0",
+		"level IN (1, 2, 3)",
+		"status = 'a' OR other = 'b'",
+		"status IN ('a') AND value > 0",
+	} {
+		if column, values, ok := parseAllowedValues(expr); ok {
+			t.Errorf("Expected %q not to be parsed, got %s %v", expr, column, values)
+		}
+	}
+}
+
+func TestExtractCheckClauses(t *testing.T) {
+	ddl := `CREATE TABLE t (a TEXT CHECK(a IN ('x', ')')), b INTEGER, CONSTRAINT c CHECK (b > 0))`
+	clauses := extractCheckClauses(ddl)
+	expected := []string{`(a IN ('x', ')'))`, `(b > 0)`}
+	if !reflect.DeepEqual(clauses, expected) {
+		t.Errorf("Expected %q, got %q", expected, clauses)
+	}
+
+	if values := enumTypeValues("enum('small','large')"); !reflect.DeepEqual(values, []string{"small", "large"}) {
+		t.Errorf("Unexpected enum values: %v", values)
+	}
+}
+
+func TestSQLEngine_DescribeTableConstraints(t *testing.T) {
+	engine, repos := newTestEngine(t)
+
+	if err := repos.GetDB().Exec(`ALTER TABLE items ADD COLUMN status TEXT NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'done'))`).Error; err != nil {
+		t.Fatalf("Failed to add column: %v", err)
+	}
+
+	schema, err := engine.DescribeTable(context.Background(), "items")
+	if err != nil {
+		t.Fatalf("Describe failed: %v", err)
+	}
+	if !schema.Columns[0].Generated || schema.Columns[1].Generated {
+		t.Errorf("Expected only the rowid alias to be generated: %+v", schema.Columns[:2])
+	}
+	if status := schema.Columns[4]; !reflect.DeepEqual(status.Enum, []string{"new", "done"}) {
+		t.Errorf("Expected status values from CHECK constraint, got %+v", status)
+	}
+}
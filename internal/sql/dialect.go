@@ -6,6 +6,9 @@ import (
 	"strings"
 )
 
+// identifierPattern 简单标识符（可带 schema 前缀）
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
 // DatabaseDialect 数据库方言接口
 type DatabaseDialect interface {
 	// 转换分页查询
@@ -18,10 +21,25 @@ type DatabaseDialect interface {
 	ConvertDataType(value interface{}) interface{}
 	// 获取当前时间函数
 	GetCurrentTimeFunction() string
+	// 引用标识符（表名、列名）
+	QuoteIdentifier(name string) string
 	// 检查是否支持某个功能
 	SupportsFeature(feature string) bool
 }
 
+// quoteIdentifierWith 使用指定的引号引用标识符，支持 schema.table 形式
+func quoteIdentifierWith(name, open, close string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		part = strings.ReplaceAll(part, close, close+close)
+		parts[i] = open + part + close
+	}
+	return strings.Join(parts, ".")
+}
+
 // PostgreSQLDialect PostgreSQL 方言
 type PostgreSQLDialect struct{}
 
@@ -93,6 +111,11 @@ func (d *PostgreSQLDialect) GetCurrentTimeFunction() string {
 	return "NOW()"
 }
 
+// QuoteIdentifier 引用标识符（双引号）
+func (d *PostgreSQLDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(name, `"`, `"`)
+}
+
 // SupportsFeature 检查是否支持某个功能
 func (d *PostgreSQLDialect) SupportsFeature(feature string) bool {
 	supportedFeatures := map[string]bool{
@@ -103,6 +126,8 @@ func (d *PostgreSQLDialect) SupportsFeature(feature string) bool {
 		"regex":            true,  // 正则表达式
 		"full_text_search": true,  // 全文搜索
 		"upsert":           true,  // ON CONFLICT
+		"returning":        true,  // RETURNING 子句
+		"last_insert_id":   false, // 使用 RETURNING 获取自增主键
 	}
 	return supportedFeatures[feature]
 }
@@ -179,6 +204,11 @@ func (d *OracleDialect) GetCurrentTimeFunction() string {
 	return "SYSDATE"
 }
 
+// QuoteIdentifier 引用标识符（双引号）
+func (d *OracleDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(name, `"`, `"`)
+}
+
 // SupportsFeature 检查是否支持某个功能
 func (d *OracleDialect) SupportsFeature(feature string) bool {
 	supportedFeatures := map[string]bool{
@@ -189,6 +219,305 @@ func (d *OracleDialect) SupportsFeature(feature string) bool {
 		"regex":            true,  // 正则表达式
 		"full_text_search": true,  // Oracle Text
 		"upsert":           true,  // MERGE 语句
+		"returning":        true,  // RETURNING ... INTO
+		"last_insert_id":   false, // 使用 RETURNING ... INTO 获取主键
+	}
+	return supportedFeatures[feature]
+}
+
+// SQLiteDialect SQLite 方言
+type SQLiteDialect struct{}
+
+// NewSQLiteDialect 创建 SQLite 方言
+func NewSQLiteDialect() *SQLiteDialect {
+	return &SQLiteDialect{}
+}
+
+// ApplyPagination 应用分页（SQLite 使用 LIMIT OFFSET）
+func (d *SQLiteDialect) ApplyPagination(query string, offset, limit int) string {
+	if limit <= 0 {
+		return query
+	}
+
+	query = strings.TrimSpace(query)
+	if strings.HasSuffix(strings.ToLower(query), ";") {
+		query = query[:len(query)-1]
+	}
+
+	if offset > 0 {
+		return fmt.Sprintf("%s LIMIT %d OFFSET %d", query, limit, offset)
+	}
+	return fmt.Sprintf("%s LIMIT %d", query, limit)
+}
+
+// ApplySort 应用排序
+func (d *SQLiteDialect) ApplySort(query string, sortBy, sortOrder string) string {
+	if sortBy == "" {
+		return query
+	}
+
+	query = strings.TrimSpace(query)
+	if strings.HasSuffix(strings.ToLower(query), ";") {
+		query = query[:len(query)-1]
+	}
+
+	// 验证排序方向
+	if sortOrder != "ASC" && sortOrder != "DESC" {
+		sortOrder = "ASC"
+	}
+
+	// 检查查询是否已经有 ORDER BY
+	lowerQuery := strings.ToLower(query)
+	if strings.Contains(lowerQuery, "order by") {
+		return fmt.Sprintf("%s, %s %s", query, sortBy, sortOrder)
+	}
+
+	return fmt.Sprintf("%s ORDER BY %s %s", query, sortBy, sortOrder)
+}
+
+// GetLimitQuery 获取限制查询
+func (d *SQLiteDialect) GetLimitQuery(limit int) string {
+	return fmt.Sprintf("LIMIT %d", limit)
+}
+
+// ConvertDataType 转换数据类型
+func (d *SQLiteDialect) ConvertDataType(value interface{}) interface{} {
+	// SQLite 没有原生布尔类型，使用 0/1 存储
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	default:
+		return value
+	}
+}
+
+// GetCurrentTimeFunction 获取当前时间函数
+func (d *SQLiteDialect) GetCurrentTimeFunction() string {
+	return "CURRENT_TIMESTAMP"
+}
+
+// QuoteIdentifier 引用标识符（双引号）
+func (d *SQLiteDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(name, `"`, `"`)
+}
+
+// SupportsFeature 检查是否支持某个功能
+func (d *SQLiteDialect) SupportsFeature(feature string) bool {
+	supportedFeatures := map[string]bool{
+		"cte":              true,  // Common Table Expressions (3.8.3+)
+		"window_functions": true,  // 窗口函数 (3.25+)
+		"json":             true,  // JSON1 扩展
+		"arrays":           false, // 不支持数组
+		"regex":            false, // REGEXP 需要自定义函数
+		"full_text_search": true,  // FTS5 扩展
+		"upsert":           true,  // ON CONFLICT (3.24+)
+		"returning":        true,  // RETURNING 子句 (3.35+)
+		"last_insert_id":   true,  // last_insert_rowid()
+	}
+	return supportedFeatures[feature]
+}
+
+// MySQLDialect MySQL/MariaDB 方言
+type MySQLDialect struct{}
+
+// NewMySQLDialect 创建 MySQL 方言
+func NewMySQLDialect() *MySQLDialect {
+	return &MySQLDialect{}
+}
+
+// ApplyPagination 应用分页（MySQL 使用 LIMIT OFFSET）
+func (d *MySQLDialect) ApplyPagination(query string, offset, limit int) string {
+	if limit <= 0 {
+		return query
+	}
+
+	query = strings.TrimSpace(query)
+	if strings.HasSuffix(strings.ToLower(query), ";") {
+		query = query[:len(query)-1]
+	}
+
+	if offset > 0 {
+		return fmt.Sprintf("%s LIMIT %d OFFSET %d", query, limit, offset)
+	}
+	return fmt.Sprintf("%s LIMIT %d", query, limit)
+}
+
+// ApplySort 应用排序
+func (d *MySQLDialect) ApplySort(query string, sortBy, sortOrder string) string {
+	if sortBy == "" {
+		return query
+	}
+
+	query = strings.TrimSpace(query)
+	if strings.HasSuffix(strings.ToLower(query), ";") {
+		query = query[:len(query)-1]
+	}
+
+	// 验证排序方向
+	if sortOrder != "ASC" && sortOrder != "DESC" {
+		sortOrder = "ASC"
+	}
+
+	// 简单列名使用反引号引用，避免与保留字（如 order、key）冲突
+	if identifierPattern.MatchString(sortBy) {
+		sortBy = d.QuoteIdentifier(sortBy)
+	}
+
+	// 检查查询是否已经有 ORDER BY
+	lowerQuery := strings.ToLower(query)
+	if strings.Contains(lowerQuery, "order by") {
+		return fmt.Sprintf("%s, %s %s", query, sortBy, sortOrder)
+	}
+
+	return fmt.Sprintf("%s ORDER BY %s %s", query, sortBy, sortOrder)
+}
+
+// GetLimitQuery 获取限制查询
+func (d *MySQLDialect) GetLimitQuery(limit int) string {
+	return fmt.Sprintf("LIMIT %d", limit)
+}
+
+// ConvertDataType 转换数据类型
+func (d *MySQLDialect) ConvertDataType(value interface{}) interface{} {
+	// MySQL 的数据类型转换
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	default:
+		return value
+	}
+}
+
+// GetCurrentTimeFunction 获取当前时间函数
+func (d *MySQLDialect) GetCurrentTimeFunction() string {
+	return "NOW()"
+}
+
+// QuoteIdentifier 引用标识符（反引号）
+func (d *MySQLDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(name, "`", "`")
+}
+
+// SupportsFeature 检查是否支持某个功能
+func (d *MySQLDialect) SupportsFeature(feature string) bool {
+	supportedFeatures := map[string]bool{
+		"cte":              true,  // Common Table Expressions (8.0+ / MariaDB 10.2+)
+		"window_functions": true,  // 窗口函数 (8.0+ / MariaDB 10.2+)
+		"json":             true,  // JSON 支持
+		"arrays":           false, // 不支持数组
+		"regex":            true,  // REGEXP
+		"full_text_search": true,  // FULLTEXT 索引
+		"upsert":           true,  // ON DUPLICATE KEY UPDATE
+		"returning":        false, // 仅 MariaDB 10.5+ 支持，统一使用 LastInsertId
+		"last_insert_id":   true,  // LAST_INSERT_ID()
+	}
+	return supportedFeatures[feature]
+}
+
+// SQLServerDialect SQL Server 方言
+type SQLServerDialect struct{}
+
+// NewSQLServerDialect 创建 SQL Server 方言
+func NewSQLServerDialect() *SQLServerDialect {
+	return &SQLServerDialect{}
+}
+
+// ApplyPagination 应用分页（SQL Server 2012+ 使用 OFFSET FETCH，必须带 ORDER BY）
+func (d *SQLServerDialect) ApplyPagination(query string, offset, limit int) string {
+	if limit <= 0 {
+		return query
+	}
+
+	query = strings.TrimSpace(query)
+	if strings.HasSuffix(strings.ToLower(query), ";") {
+		query = query[:len(query)-1]
+	}
+
+	// OFFSET FETCH 要求查询包含 ORDER BY，没有时注入一个不改变顺序的排序
+	if !sqlServerHasOrderBy(query) {
+		query += " ORDER BY (SELECT NULL)"
+	}
+
+	return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", query, offset, limit)
+}
+
+// ApplySort 应用排序
+func (d *SQLServerDialect) ApplySort(query string, sortBy, sortOrder string) string {
+	if sortBy == "" {
+		return query
+	}
+
+	query = strings.TrimSpace(query)
+	if strings.HasSuffix(strings.ToLower(query), ";") {
+		query = query[:len(query)-1]
+	}
+
+	// 验证排序方向
+	if sortOrder != "ASC" && sortOrder != "DESC" {
+		sortOrder = "ASC"
+	}
+
+	// 检查查询是否已经有 ORDER BY
+	if sqlServerHasOrderBy(query) {
+		return fmt.Sprintf("%s, %s %s", query, sortBy, sortOrder)
+	}
+
+	return fmt.Sprintf("%s ORDER BY %s %s", query, sortBy, sortOrder)
+}
+
+// sqlServerHasOrderBy 检查查询的最外层是否有 ORDER BY
+// 子查询和窗口函数（如 ROW_NUMBER() OVER (ORDER BY ...)）中的 ORDER BY 不计入，关键字之间可以有任意空白和注释
+func sqlServerHasOrderBy(query string) bool {
+	tokens, err := lexSQL(query, "sqlserver")
+	if err != nil {
+		return false
+	}
+	return hasTopLevelOrderBy(tokens)
+}
+
+// GetLimitQuery 获取限制查询（TOP 需要紧跟在 SELECT 之后）
+func (d *SQLServerDialect) GetLimitQuery(limit int) string {
+	return fmt.Sprintf("TOP (%d)", limit)
+}
+
+// ConvertDataType 转换数据类型
+func (d *SQLServerDialect) ConvertDataType(value interface{}) interface{} {
+	// SQL Server 的数据类型转换
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	default:
+		return value
+	}
+}
+
+// GetCurrentTimeFunction 获取当前时间函数
+func (d *SQLServerDialect) GetCurrentTimeFunction() string {
+	return "GETDATE()"
+}
+
+// QuoteIdentifier 引用标识符（方括号）
+func (d *SQLServerDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(name, "[", "]")
+}
+
+// SupportsFeature 检查是否支持某个功能
+func (d *SQLServerDialect) SupportsFeature(feature string) bool {
+	supportedFeatures := map[string]bool{
+		"cte":              true,  // Common Table Expressions
+		"window_functions": true,  // 窗口函数
+		"json":             true,  // JSON 函数 (2016+)
+		"arrays":           false, // 不支持数组
+		"regex":            false, // 不支持正则表达式
+		"full_text_search": true,  // 全文索引
+		"upsert":           true,  // MERGE 语句
+		"returning":        true,  // OUTPUT INSERTED.*
+		"last_insert_id":   false, // 使用 OUTPUT INSERTED 获取主键
 	}
 	return supportedFeatures[feature]
 }
@@ -208,6 +537,12 @@ func (f *DialectFactory) CreateDialect(dbType string) DatabaseDialect {
 		return NewPostgreSQLDialect()
 	case "oracle":
 		return NewOracleDialect()
+	case "sqlite", "sqlite3":
+		return NewSQLiteDialect()
+	case "mysql", "mariadb":
+		return NewMySQLDialect()
+	case "sqlserver", "mssql":
+		return NewSQLServerDialect()
 	default:
 		// 默认使用 PostgreSQL 方言
 		return NewPostgreSQLDialect()
@@ -216,7 +551,7 @@ func (f *DialectFactory) CreateDialect(dbType string) DatabaseDialect {
 
 // GetSupportedDialects 获取支持的方言列表
 func (f *DialectFactory) GetSupportedDialects() []string {
-	return []string{"postgres", "postgresql", "oracle"}
+	return []string{"postgres", "postgresql", "oracle", "sqlite", "sqlite3", "mysql", "mariadb", "sqlserver", "mssql"}
 }
 
 // IsDialectSupported 检查是否支持指定的方言
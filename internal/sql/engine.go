@@ -5,10 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"sql2api/internal/config"
+	"sql2api/internal/model"
 	"sql2api/internal/repository"
+	"sql2api/internal/tracing"
 
 	"gorm.io/gorm"
 )
@@ -42,7 +47,7 @@ type BatchResult struct {
 // SQLEngine SQL 查询引擎
 type SQLEngine struct {
 	db           *gorm.DB
-	dbType       string // "postgres" 或 "oracle"
+	dbType       string // "postgres"、"oracle"、"sqlite"、"mysql" 或 "sqlserver"
 	config       *config.SQLConfig
 	security     *SecurityValidator
 	validator    *QueryValidator
@@ -50,6 +55,7 @@ type SQLEngine struct {
 	errorMapper  *DatabaseErrorMapper
 	monitor      *PerformanceMonitor
 	memOptimizer *MemoryOptimizer
+	changes      *ChangeHub
 }
 
 // NewSQLEngine 创建 SQL 查询引擎
@@ -69,13 +75,14 @@ func NewSQLEngine(repos *repository.Repositories, cfg *config.SQLConfig) (*SQLEn
 	// 获取数据库类型
 	dbType := "postgres" // 默认为 PostgreSQL
 	if db := repos.GetDB(); db != nil {
-		if dialector := db.Dialector.Name(); dialector == "oracle" {
-			dbType = "oracle"
+		switch dialector := db.Dialector.Name(); dialector {
+		case "oracle", "sqlite", "mysql", "sqlserver":
+			dbType = dialector
 		}
 	}
 
 	// 创建安全验证器
-	security := NewSecurityValidator(cfg)
+	security := NewSecurityValidator(cfg).WithDatabaseType(dbType)
 
 	// 创建查询验证器
 	validator := NewQueryValidator()
@@ -94,6 +101,8 @@ func NewSQLEngine(repos *repository.Repositories, cfg *config.SQLConfig) (*SQLEn
 		true,                    // logErrors
 		int64(cfg.MaxQueryTime*1000/2), // slowQueryMs (一半的超时时间作为慢查询阈值)
 	)
+	monitor.errorMapper = errorMapper
+	monitor.db = repos.GetDB()
 
 	// 创建内存优化器
 	memOptimizer := NewMemoryOptimizer(
@@ -111,6 +120,7 @@ func NewSQLEngine(repos *repository.Repositories, cfg *config.SQLConfig) (*SQLEn
 		errorMapper:  errorMapper,
 		monitor:      monitor,
 		memOptimizer: memOptimizer,
+		changes:      changeHubFor(repos.GetDB()),
 	}, nil
 }
 
@@ -118,25 +128,11 @@ func NewSQLEngine(repos *repository.Repositories, cfg *config.SQLConfig) (*SQLEn
 func (e *SQLEngine) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) (*QueryResult, error) {
 	// 开始监控
 	queryCtx := e.monitor.StartQuery(ctx, "select", e.dbType, query)
-	defer func() {
-		// 这里会在函数返回时调用，需要在后面设置结果
-	}()
-
-	// 查询结构验证
-	if err := e.validator.ValidateQueryStructure(query); err != nil {
-		queryCtx.Finish(false, 0, 0, err)
-		return nil, fmt.Errorf("query structure validation failed: %w", err)
-	}
-
-	// 安全验证
-	if err := e.security.ValidateQuery(query, params); err != nil {
-		queryCtx.Finish(false, 0, 0, err)
-		return nil, fmt.Errorf("security validation failed: %w", err)
-	}
+	queryCtx.SetParams(params)
+	ctx = queryCtx.Context
 
-	// 检查是否为查询操作
-	if !e.security.IsSelectQuery(query) {
-		err := errors.New("only SELECT queries are allowed in ExecuteQuery")
+	// 查询验证
+	if err := e.validateSelect(ctx, query, params); err != nil {
 		queryCtx.Finish(false, 0, 0, err)
 		return nil, err
 	}
@@ -146,13 +142,13 @@ func (e *SQLEngine) ExecuteQuery(ctx context.Context, query string, params map[s
 	defer cancel()
 
 	// 执行查询
-	rows, err := e.executeRawQuery(execCtx, query, params)
+	rows, release, err := e.executeRawQuery(execCtx, query, params)
 	if err != nil {
 		mappedErr := e.errorMapper.MapError(err)
 		queryCtx.Finish(false, 0, 0, err)
 		return nil, fmt.Errorf("failed to execute query: %w", mappedErr)
 	}
-	defer rows.Close()
+	defer release()
 
 	// 解析结果
 	result, err := e.parseQueryResult(rows)
@@ -177,21 +173,107 @@ func (e *SQLEngine) ExecuteQuery(ctx context.Context, query string, params map[s
 	return result, nil
 }
 
+// RowHandler 流式查询的行处理函数，返回错误时停止读取
+type RowHandler func(columns []string, row map[string]interface{}) error
+
+// StreamQuery 执行查询操作（SELECT）并逐行交给 handler 处理，结果不在内存中缓存，
+// 因此不受 max_result_size 限制；返回的结果只包含列名和行数
+func (e *SQLEngine) StreamQuery(ctx context.Context, query string, params map[string]interface{}, handler RowHandler) (*QueryResult, error) {
+	queryCtx := e.monitor.StartQuery(ctx, "select", e.dbType, query)
+	queryCtx.SetParams(params)
+	ctx = queryCtx.Context
+
+	// 查询验证
+	if err := e.validateSelect(ctx, query, params); err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, err
+	}
+
+	// 创建带超时的上下文
+	execCtx, cancel := context.WithTimeout(ctx, time.Duration(e.config.MaxQueryTime)*time.Second)
+	defer cancel()
+
+	rows, release, err := e.executeRawQuery(execCtx, query, params)
+	if err != nil {
+		mappedErr := e.errorMapper.MapError(err)
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to execute query: %w", mappedErr)
+	}
+	defer release()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	result := &QueryResult{Columns: columns}
+	scanner := newRowScanner(columns)
+	for rows.Next() {
+		row, err := scanner.scan(rows)
+		if err != nil {
+			queryCtx.Finish(false, 0, result.Total, err)
+			return nil, err
+		}
+		if err := handler(columns, row); err != nil {
+			queryCtx.Finish(false, 0, result.Total, err)
+			return nil, err
+		}
+		result.Total++
+	}
+
+	if err := rows.Err(); err != nil {
+		queryCtx.Finish(false, 0, result.Total, err)
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	queryCtx.Finish(true, 0, result.Total, nil)
+	return result, nil
+}
+
+// validateSelect 验证查询结构和安全性，并确认是 SELECT 语句
+func (e *SQLEngine) validateSelect(ctx context.Context, query string, params map[string]interface{}) error {
+	// 查询结构验证
+	if err := e.validator.ValidateQueryStructure(query); err != nil {
+		return fmt.Errorf("query structure validation failed: %w", err)
+	}
+
+	// 安全验证
+	if err := e.checkSecurity(ctx, query, params); err != nil {
+		return fmt.Errorf("security validation failed: %w", err)
+	}
+
+	// 检查是否为查询操作
+	if !e.security.IsSelectQuery(query) {
+		return errors.New("only SELECT queries are allowed in ExecuteQuery")
+	}
+	return nil
+}
+
 // ExecuteSQL 执行任意 SQL 操作（INSERT、UPDATE、DELETE）
 func (e *SQLEngine) ExecuteSQL(ctx context.Context, query string, params map[string]interface{}) (*ExecuteResult, error) {
+	// 开始监控
+	monitorCtx := e.monitor.StartQuery(ctx, e.security.extractSQLAction(strings.ToLower(query)), e.dbType, query)
+	monitorCtx.SetParams(params)
+	ctx = monitorCtx.Context
+
 	// 查询结构验证
 	if err := e.validator.ValidateQueryStructure(query); err != nil {
+		monitorCtx.Finish(false, 0, 0, err)
 		return nil, fmt.Errorf("query structure validation failed: %w", err)
 	}
 
 	// 安全验证
-	if err := e.security.ValidateQuery(query, params); err != nil {
+	if err := e.checkSecurity(ctx, query, params); err != nil {
+		monitorCtx.Finish(false, 0, 0, err)
 		return nil, fmt.Errorf("security validation failed: %w", err)
 	}
 
 	// 检查是否允许原生 SQL
 	if !e.config.EnableRawSQL {
-		return nil, errors.New("raw SQL execution is disabled")
+		err := errors.New("raw SQL execution is disabled")
+		monitorCtx.Finish(false, 0, 0, &model.SQLError{Code: model.SQLErrorPermission, Message: err.Error()})
+		return nil, err
 	}
 
 	// 创建带超时的上下文
@@ -199,15 +281,89 @@ func (e *SQLEngine) ExecuteSQL(ctx context.Context, query string, params map[str
 	defer cancel()
 
 	// 执行 SQL
-	result := e.db.WithContext(queryCtx).Exec(query, e.convertParams(params)...)
-	if result.Error != nil {
-		return nil, fmt.Errorf("failed to execute SQL: %w", result.Error)
+	result, images, err := e.execWrite(queryCtx, query, params)
+	e.auditWrite(ctx, query, params, result, images, err)
+	if err != nil {
+		monitorCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
 	}
 
-	return &ExecuteResult{
-		AffectedRows: result.RowsAffected,
-		LastInsertID: 0, // GORM 不直接支持 LastInsertID
-	}, nil
+	monitorCtx.Finish(true, result.AffectedRows, 0, nil)
+	e.publishChange(ctx, query, result)
+	return result, nil
+}
+
+// ExecuteStatement 执行服务端生成的写语句（命名查询、资源操作等）
+// 与 ExecuteSQL 相同地进行结构和安全验证，但不受 enable_raw_sql 限制
+func (e *SQLEngine) ExecuteStatement(ctx context.Context, query string, params map[string]interface{}) (*ExecuteResult, error) {
+	// 开始监控
+	queryCtx := e.monitor.StartQuery(ctx, e.security.extractSQLAction(strings.ToLower(query)), e.dbType, query)
+	queryCtx.SetParams(params)
+	ctx = queryCtx.Context
+
+	// 查询结构验证
+	if err := e.validator.ValidateQueryStructure(query); err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("query structure validation failed: %w", err)
+	}
+
+	// 安全验证
+	if err := e.checkSecurity(ctx, query, params); err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("security validation failed: %w", err)
+	}
+
+	// 创建带超时的上下文
+	execCtx, cancel := context.WithTimeout(ctx, time.Duration(e.config.MaxQueryTime)*time.Second)
+	defer cancel()
+
+	result, images, err := e.execWrite(execCtx, query, params)
+	e.auditWrite(ctx, query, params, result, images, err)
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to execute SQL: %w", e.errorMapper.MapError(err))
+	}
+
+	queryCtx.Finish(true, result.AffectedRows, 0, nil)
+	e.publishChange(ctx, query, result)
+	return result, nil
+}
+
+// execStatement 通过底层连接执行语句，以便获取 LastInsertId
+func (e *SQLEngine) execStatement(ctx context.Context, db *gorm.DB, query string, params map[string]interface{}) (*ExecuteResult, error) {
+	// 事务中的 ConnPool 为 *sql.Tx，否则为 *sql.DB，此时先从连接池获取连接
+	pool := db.Statement.ConnPool
+	if sqlDB, ok := pool.(*sql.DB); ok {
+		conn, err := e.acquireConn(ctx, sqlDB)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		pool = conn
+	}
+
+	spanCtx, span := tracing.Start(ctx, "db.exec", e.dbAttributes(query)...)
+	result, err := pool.ExecContext(spanCtx, query, e.convertParams(params)...)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	execResult := &ExecuteResult{AffectedRows: affectedRows}
+
+	// 仅 MySQL、SQLite 等支持自增主键回传的数据库读取 LastInsertId
+	if e.dialect.SupportsFeature("last_insert_id") {
+		if lastInsertID, err := result.LastInsertId(); err == nil {
+			execResult.LastInsertID = lastInsertID
+		}
+	}
+
+	return execResult, nil
 }
 
 // ExecuteBatch 执行批量 SQL 操作
@@ -220,9 +376,15 @@ func (e *SQLEngine) ExecuteBatch(ctx context.Context, queries []BatchQuery, tran
 		return nil, errors.New("no queries provided")
 	}
 
+	// 开始监控（整个批次记为一次 batch 查询）
+	monitorCtx := e.monitor.StartQuery(ctx, "batch", e.dbType, queries[0].SQL)
+	monitorCtx.SetParams(queries[0].Params)
+	ctx = monitorCtx.Context
+
 	// 验证所有查询
 	for i, query := range queries {
-		if err := e.security.ValidateQuery(query.SQL, query.Params); err != nil {
+		if err := e.checkSecurity(ctx, query.SQL, query.Params); err != nil {
+			monitorCtx.Finish(false, 0, 0, err)
 			return nil, fmt.Errorf("security validation failed for query %d: %w", i, err)
 		}
 	}
@@ -231,26 +393,54 @@ func (e *SQLEngine) ExecuteBatch(ctx context.Context, queries []BatchQuery, tran
 	batchCtx, cancel := context.WithTimeout(ctx, time.Duration(e.config.MaxQueryTime)*time.Second)
 	defer cancel()
 
+	var result *BatchResult
+	var err error
 	if transactional && e.config.EnableTransactions {
-		return e.executeBatchWithTransaction(batchCtx, queries)
+		result, err = e.executeBatchWithTransaction(batchCtx, queries)
+	} else {
+		result, err = e.executeBatchWithoutTransaction(batchCtx, queries)
+	}
+	if err != nil {
+		monitorCtx.Finish(false, 0, 0, err)
+		return nil, err
 	}
 
-	return e.executeBatchWithoutTransaction(batchCtx, queries)
+	monitorCtx.Finish(true, result.TotalAffectedRows, 0, nil)
+	return result, nil
 }
 
-// executeRawQuery 执行原生查询
-func (e *SQLEngine) executeRawQuery(ctx context.Context, query string, params map[string]interface{}) (*sql.Rows, error) {
+// executeRawQuery 执行原生查询，读取完结果后需调用返回的 release 关闭结果集并归还连接
+func (e *SQLEngine) executeRawQuery(ctx context.Context, query string, params map[string]interface{}) (*sql.Rows, func(), error) {
 	// 获取底层的 sql.DB
 	sqlDB, err := e.db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
+		return nil, nil, fmt.Errorf("failed to get sql.DB: %w", err)
 	}
 
 	// 转换参数
 	args := e.convertParams(params)
 
-	// 执行查询
-	return sqlDB.QueryContext(ctx, query, args...)
+	// 从连接池获取连接
+	conn, err := e.acquireConn(ctx, sqlDB)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 执行查询，db.query span 包括读取结果的时间
+	spanCtx, span := tracing.Start(ctx, "db.query", e.dbAttributes(query)...)
+	rows, err := conn.QueryContext(spanCtx, query, args...)
+	if err != nil {
+		tracing.End(span, err)
+		conn.Close()
+		return nil, nil, err
+	}
+
+	release := func() {
+		rows.Close()
+		tracing.End(span, rows.Err())
+		conn.Close()
+	}
+	return rows, release, nil
 }
 
 // convertParams 转换参数映射为参数数组
@@ -259,15 +449,47 @@ func (e *SQLEngine) convertParams(params map[string]interface{}) []interface{} {
 		return nil
 	}
 
-	// 简单实现：按参数名排序后转换为数组
-	// 实际应用中需要更复杂的参数替换逻辑
-	var args []interface{}
-	for _, value := range params {
-		args = append(args, value)
+	keys := orderedParamKeys(params)
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, e.dialect.ConvertDataType(params[key]))
 	}
 	return args
 }
 
+// orderedParamKeys 按参数名排序：param_N 形式的参数按序号排列，其余按名称排列，
+// 以保证与 QueryBuilder 生成的位置占位符（$N、?）一一对应
+func orderedParamKeys(params map[string]interface{}) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ni, iok := paramIndex(keys[i])
+		nj, jok := paramIndex(keys[j])
+		if iok && jok {
+			return ni < nj
+		}
+		if iok != jok {
+			return iok
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// paramIndex 解析 param_N 形式参数名中的序号
+func paramIndex(name string) (int, bool) {
+	if !strings.HasPrefix(name, "param_") {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimPrefix(name, "param_"))
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
 // parseQueryResult 解析查询结果
 func (e *SQLEngine) parseQueryResult(rows *sql.Rows) (*QueryResult, error) {
 	// 获取列信息
@@ -281,28 +503,12 @@ func (e *SQLEngine) parseQueryResult(rows *sql.Rows) (*QueryResult, error) {
 		Rows:    make([]map[string]interface{}, 0),
 	}
 
-	// 创建扫描目标
-	values := make([]interface{}, len(columns))
-	scanArgs := make([]interface{}, len(columns))
-	for i := range values {
-		scanArgs[i] = &values[i]
-	}
-
 	// 扫描所有行
+	scanner := newRowScanner(columns)
 	for rows.Next() {
-		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-
-		// 构建行数据
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				row[col] = string(b)
-			} else {
-				row[col] = val
-			}
+		row, err := scanner.scan(rows)
+		if err != nil {
+			return nil, err
 		}
 		result.Rows = append(result.Rows, row)
 	}
@@ -315,6 +521,44 @@ func (e *SQLEngine) parseQueryResult(rows *sql.Rows) (*QueryResult, error) {
 	return result, nil
 }
 
+// rowScanner 复用扫描目标逐行读取结果
+type rowScanner struct {
+	columns  []string
+	values   []interface{}
+	scanArgs []interface{}
+}
+
+// newRowScanner 创建行扫描器
+func newRowScanner(columns []string) *rowScanner {
+	scanner := &rowScanner{
+		columns:  columns,
+		values:   make([]interface{}, len(columns)),
+		scanArgs: make([]interface{}, len(columns)),
+	}
+	for i := range scanner.values {
+		scanner.scanArgs[i] = &scanner.values[i]
+	}
+	return scanner
+}
+
+// scan 读取当前行，[]byte 转换为字符串
+func (s *rowScanner) scan(rows *sql.Rows) (map[string]interface{}, error) {
+	if err := rows.Scan(s.scanArgs...); err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	row := make(map[string]interface{}, len(s.columns))
+	for i, col := range s.columns {
+		val := s.values[i]
+		if b, ok := val.([]byte); ok {
+			row[col] = string(b)
+		} else {
+			row[col] = val
+		}
+	}
+	return row, nil
+}
+
 // executeBatchWithTransaction 在事务中执行批量操作
 func (e *SQLEngine) executeBatchWithTransaction(ctx context.Context, queries []BatchQuery) (*BatchResult, error) {
 	result := &BatchResult{
@@ -337,17 +581,15 @@ func (e *SQLEngine) executeBatchWithTransaction(ctx context.Context, queries []B
 
 	// 执行所有查询
 	for i, query := range queries {
-		execResult := tx.Exec(query.SQL, e.convertParams(query.Params)...)
-		if execResult.Error != nil {
+		execResult, err := e.execStatement(ctx, tx, query.SQL, query.Params)
+		if err != nil {
 			tx.Rollback()
-			return nil, fmt.Errorf("failed to execute query %d: %w", i, execResult.Error)
+			e.auditWrite(ctx, query.SQL, query.Params, nil, nil, err)
+			return nil, fmt.Errorf("failed to execute query %d: %w", i, err)
 		}
 
-		result.Results = append(result.Results, ExecuteResult{
-			AffectedRows: execResult.RowsAffected,
-			LastInsertID: 0,
-		})
-		result.TotalAffectedRows += execResult.RowsAffected
+		result.Results = append(result.Results, *execResult)
+		result.TotalAffectedRows += execResult.AffectedRows
 	}
 
 	// 提交事务
@@ -355,6 +597,12 @@ func (e *SQLEngine) executeBatchWithTransaction(ctx context.Context, queries []B
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	// 提交后才记录审计日志和发出变更事件
+	for i, query := range queries {
+		e.auditWrite(ctx, query.SQL, query.Params, &result.Results[i], nil, nil)
+		e.publishChange(ctx, query.SQL, &result.Results[i])
+	}
+
 	result.Success = true
 	return result, nil
 }
@@ -367,16 +615,15 @@ func (e *SQLEngine) executeBatchWithoutTransaction(ctx context.Context, queries
 
 	// 逐个执行查询
 	for i, query := range queries {
-		execResult := e.db.WithContext(ctx).Exec(query.SQL, e.convertParams(query.Params)...)
-		if execResult.Error != nil {
-			return nil, fmt.Errorf("failed to execute query %d: %w", i, execResult.Error)
+		execResult, err := e.execStatement(ctx, e.db, query.SQL, query.Params)
+		e.auditWrite(ctx, query.SQL, query.Params, execResult, nil, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query %d: %w", i, err)
 		}
+		e.publishChange(ctx, query.SQL, execResult)
 
-		result.Results = append(result.Results, ExecuteResult{
-			AffectedRows: execResult.RowsAffected,
-			LastInsertID: 0,
-		})
-		result.TotalAffectedRows += execResult.RowsAffected
+		result.Results = append(result.Results, *execResult)
+		result.TotalAffectedRows += execResult.AffectedRows
 	}
 
 	result.Success = true
@@ -388,6 +635,21 @@ func (e *SQLEngine) GetDatabaseType() string {
 	return e.dbType
 }
 
+// GetMetricsSummary 获取性能监控器状态
+func (e *SQLEngine) GetMetricsSummary() map[string]interface{} {
+	return e.monitor.GetMetricsSummary()
+}
+
+// QueryTables 获取原生 SQL 引用的表名（小写，不含 schema 前缀），包括逗号分隔的表列表和子查询中的表
+func (e *SQLEngine) QueryTables(query string) ([]string, error) {
+	return referencedTables(query, e.dbType)
+}
+
+// IsTableAllowed 检查表是否在允许列表中
+func (e *SQLEngine) IsTableAllowed(table string) bool {
+	return e.security.IsTableAllowed(table)
+}
+
 // IsEnabled 检查 SQL 功能是否启用
 func (e *SQLEngine) IsEnabled() bool {
 	return e.config.Enabled
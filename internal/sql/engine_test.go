@@ -0,0 +1,356 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+	"sql2api/internal/repository"
+)
+
+// newTestEngine 创建基于内存 SQLite 的测试引擎
+func newTestEngine(t *testing.T) (*SQLEngine, *repository.Repositories) {
+	t.Helper()
+
+	repos, err := repository.NewRepositories(&config.DatabaseConfig{
+		Type:         "sqlite",
+		Path:         ":memory:",
+		MaxOpenConns: 1, // 内存数据库每个连接相互独立
+		MaxIdleConns: 1,
+		MaxLifetime:  60,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { repos.Close() })
+
+	if err := repos.GetDB().Exec(`CREATE TABLE items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		category TEXT,
+		value INTEGER
+	)`).Error; err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	engine, err := NewSQLEngine(repos, &config.SQLConfig{
+		Enabled:            true,
+		AllowedTables:      []string{"items"},
+		AllowedActions:     []string{"select", "insert", "update", "delete"},
+		MaxQueryTime:       5,
+		MaxResultSize:      100,
+		EnableRawSQL:       true,
+		EnableBatch:        true,
+		EnableTransactions: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	return engine, repos
+}
+
+func TestSQLEngine_SQLiteRoundTrip(t *testing.T) {
+	engine, _ := newTestEngine(t)
+	ctx := context.Background()
+
+	if engine.GetDatabaseType() != "sqlite" {
+		t.Fatalf("Expected database type sqlite, got %s", engine.GetDatabaseType())
+	}
+
+	builder := NewQueryBuilder(engine.GetDatabaseType())
+	query, params, err := builder.BuildInsertQuery(&model.InsertRequest{
+		Table:        "items",
+		Data:         map[string]interface{}{"name": "widget", "category": "tools", "value": 10},
+		ReturnFields: []string{"id"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build insert: %v", err)
+	}
+
+	execResult, err := engine.ExecuteSQL(ctx, query, params)
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if execResult.LastInsertID != 1 {
+		t.Errorf("Expected last insert id 1, got %d", execResult.LastInsertID)
+	}
+
+	query, params, err = builder.BuildStructuredQuery(&model.StructuredQuery{
+		Table:  "items",
+		Action: "select",
+		Fields: []string{"name", "category", "value"},
+		Where:  map[string]interface{}{"category": "tools", "value": 10},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build select: %v", err)
+	}
+
+	result, err := engine.ExecuteQuery(ctx, query, params)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+
+	if result.Total != 1 {
+		t.Fatalf("Expected 1 row, got %d", result.Total)
+	}
+	if result.Rows[0]["name"] != "widget" {
+		t.Errorf("Expected name 'widget', got %v", result.Rows[0]["name"])
+	}
+}
+
+func TestSQLEngine_SQLiteUpsert(t *testing.T) {
+	engine, _ := newTestEngine(t)
+	ctx := context.Background()
+	builder := NewQueryBuilder(engine.GetDatabaseType())
+
+	for _, value := range []int{1, 2} {
+		query, params, err := builder.BuildInsertQuery(&model.InsertRequest{
+			Table:      "items",
+			Data:       map[string]interface{}{"name": "widget", "value": value},
+			OnConflict: "update",
+		})
+		if err != nil {
+			t.Fatalf("Failed to build upsert: %v", err)
+		}
+		if _, err := engine.ExecuteSQL(ctx, query, params); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	}
+
+	result, err := engine.ExecuteQuery(ctx, "SELECT value FROM items", nil)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if result.Total != 1 {
+		t.Fatalf("Expected 1 row after upsert, got %d", result.Total)
+	}
+	if result.Rows[0]["value"] != int64(2) {
+		t.Errorf("Expected value 2 after upsert, got %v", result.Rows[0]["value"])
+	}
+}
+
+func TestDatabaseErrorMapper_SQLite(t *testing.T) {
+	engine, repos := newTestEngine(t)
+	mapper := NewDatabaseErrorMapper(engine.GetDatabaseType())
+
+	repos.GetDB().Exec("INSERT INTO items (name) VALUES ('dup')")
+	err := repos.GetDB().Exec("INSERT INTO items (name) VALUES ('dup')").Error
+	if err == nil {
+		t.Fatal("Expected unique constraint error")
+	}
+
+	sqlErr := mapper.MapError(err)
+	if sqlErr.Code != model.SQLErrorParams {
+		t.Errorf("Expected code %d for constraint violation, got %d (%s)", model.SQLErrorParams, sqlErr.Code, err)
+	}
+
+	err = repos.GetDB().Exec("SELEC * FROM items").Error
+	if sqlErr := mapper.MapError(err); sqlErr.Code != model.SQLErrorSyntax {
+		t.Errorf("Expected code %d for syntax error, got %d (%s)", model.SQLErrorSyntax, sqlErr.Code, err)
+	}
+}
+
+func TestBindRoutineParams(t *testing.T) {
+	routine := &config.RoutineConfig{
+		Name: "adjust_stock",
+		Kind: "procedure",
+		Params: []config.RoutineParamConfig{
+			{Name: "item_id", Type: "integer"},
+			{Name: "delta", Type: "number", Direction: "inout"},
+			{Name: "updated_at", Type: "timestamp", Direction: "out"},
+		},
+	}
+
+	args, err := bindRoutineParams(routine, map[string]interface{}{"item_id": float64(7), "delta": "1.5"})
+	if err != nil {
+		t.Fatalf("Failed to bind params: %v", err)
+	}
+	if len(args) != 3 || args[0].value != int64(7) || args[1].value != 1.5 || args[2].isInput() {
+		t.Errorf("Unexpected bound args: %+v", args)
+	}
+
+	for _, params := range []map[string]interface{}{
+		{"delta": 1},                 // 缺少 IN 参数
+		{"item_id": 1.5, "delta": 1}, // 非整数
+		{"item_id": 1, "delta": 1, "updated_at": "x"}, // 提供 OUT 参数
+		{"item_id": 1, "delta": 1, "unknown": true},   // 未声明参数
+	} {
+		if _, err := bindRoutineParams(routine, params); !errors.Is(err, ErrInvalidRoutineParams) {
+			t.Errorf("Expected ErrInvalidRoutineParams for %v, got %v", params, err)
+		}
+	}
+
+	engine, _ := newTestEngine(t)
+	if _, err := engine.CallRoutine(context.Background(), "adjust_stock", nil); !errors.Is(err, ErrRoutineNotAllowed) {
+		t.Errorf("Expected ErrRoutineNotAllowed, got %v", err)
+	}
+}
+
+func TestSQLEngine_SavedQuery(t *testing.T) {
+	engine, repos := newTestEngine(t)
+	ctx := context.Background()
+
+	repos.GetDB().Exec("INSERT INTO items (name, category, value) VALUES ('a', 'tools', 5), ('b', 'tools', 50), ('c', 'toys', 7)")
+
+	minValue := float64(0)
+	saved := &config.SavedQueryConfig{
+		Name: "items_by_category",
+		SQL:  "SELECT name FROM items WHERE category = :category AND value >= :min_value AND name <> ':category' ORDER BY name",
+		Params: []config.QueryParamConfig{
+			{Name: "category", Type: "string", Required: true, Enum: []interface{}{"tools", "toys"}},
+			{Name: "min_value", Type: "integer", Default: 0, Min: &minValue},
+		},
+	}
+
+	result, err := engine.ExecuteSavedQuery(ctx, saved, map[string]interface{}{"category": "tools", "min_value": "10"})
+	if err != nil {
+		t.Fatalf("Saved query failed: %v", err)
+	}
+	if result.Query == nil || result.Query.Total != 1 || result.Query.Rows[0]["name"] != "b" {
+		t.Errorf("Unexpected saved query result: %+v", result.Query)
+	}
+
+	// 所有参数错误一并返回
+	_, err = engine.ExecuteSavedQuery(ctx, saved, map[string]interface{}{"min_value": -1, "extra": 1})
+	if !errors.Is(err, ErrInvalidQueryParams) {
+		t.Fatalf("Expected ErrInvalidQueryParams, got %v", err)
+	}
+	for _, name := range []string{"category", "min_value", "extra"} {
+		if !strings.Contains(err.Error(), name+":") {
+			t.Errorf("Expected error for %s in %q", name, err)
+		}
+	}
+
+	query, params, err := CompileNamedQuery("SELECT :a::text, :a FROM t -- :b", "postgres", map[string]interface{}{"a": 1})
+	if err != nil {
+		t.Fatalf("Failed to compile: %v", err)
+	}
+	if query != "SELECT $1::text, $2 FROM t -- :b" || len(params) != 2 {
+		t.Errorf("Unexpected compiled query %q with params %v", query, params)
+	}
+}
+
+func TestSQLEngine_DescribeTable(t *testing.T) {
+	engine, _ := newTestEngine(t)
+	ctx := context.Background()
+
+	schema, err := engine.DescribeTable(ctx, "ITEMS")
+	if err != nil {
+		t.Fatalf("Describe failed: %v", err)
+	}
+
+	if len(schema.Columns) != 4 || schema.Columns[1].Name != "name" || schema.Columns[1].Nullable {
+		t.Errorf("Unexpected columns: %+v", schema.Columns)
+	}
+	if !schema.Columns[2].Nullable || schema.Columns[2].Type != "TEXT" {
+		t.Errorf("Unexpected category column: %+v", schema.Columns[2])
+	}
+	if len(schema.PrimaryKey) != 1 || schema.PrimaryKey[0] != "id" {
+		t.Errorf("Unexpected primary key: %v", schema.PrimaryKey)
+	}
+	if len(schema.Indexes) != 1 || !schema.Indexes[0].Unique || schema.Indexes[0].Columns[0] != "name" {
+		t.Errorf("Unexpected indexes: %+v", schema.Indexes)
+	}
+
+	if _, err := engine.DescribeTable(ctx, "sqlite_master"); !errors.Is(err, ErrTableNotAllowed) {
+		t.Errorf("Expected ErrTableNotAllowed, got %v", err)
+	}
+}
+
+func TestSQLEngine_BulkInsert(t *testing.T) {
+	engine, _ := newTestEngine(t)
+	ctx := context.Background()
+
+	// 行数超过单条语句的参数上限，拆分为多条 INSERT
+	rows := make([][]interface{}, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		rows = append(rows, []interface{}{fmt.Sprintf("item-%d", i), "tools", i})
+	}
+
+	var events []*ChangeEvent
+	unregister := engine.Changes().OnChange(func(ctx context.Context, event *ChangeEvent) {
+		events = append(events, event)
+	})
+	defer unregister()
+
+	result, err := engine.BulkInsert(ctx, "items", []string{"name", "category", "value"}, rows)
+	if err != nil {
+		t.Fatalf("Bulk insert failed: %v", err)
+	}
+	if result.AffectedRows != 1000 {
+		t.Errorf("Expected 1000 affected rows, got %d", result.AffectedRows)
+	}
+	if len(events) != 1 || events[0].AffectedRows != 1000 || events[0].Operation != ChangeInsert {
+		t.Errorf("Expected one summary insert event, got %+v", events)
+	}
+
+	// 任意一行失败时整批回滚
+	_, err = engine.BulkInsert(ctx, "items", []string{"name"}, [][]interface{}{{"new-item"}, {"item-1"}})
+	if err == nil {
+		t.Fatal("Expected unique constraint violation")
+	}
+
+	count, err := engine.ExecuteQuery(ctx, "SELECT COUNT(*) AS total FROM items", nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count.Rows[0]["total"] != int64(1000) {
+		t.Errorf("Expected 1000 rows after rollback, got %v", count.Rows[0]["total"])
+	}
+
+	if _, err := engine.BulkInsert(ctx, "sqlite_master", []string{"name"}, [][]interface{}{{"x"}}); !errors.Is(err, ErrTableNotAllowed) {
+		t.Errorf("Expected ErrTableNotAllowed, got %v", err)
+	}
+}
+
+func TestSQLEngine_ExecuteInsertChunks(t *testing.T) {
+	engine, _ := newTestEngine(t)
+	ctx := context.Background()
+	builder := NewQueryBuilder("sqlite")
+
+	data := make([]map[string]interface{}, 0, 500)
+	for i := 0; i < 500; i++ {
+		data = append(data, map[string]interface{}{"name": fmt.Sprintf("item-%d", i), "category": "tools", "value": i})
+	}
+	chunks, err := builder.BuildBatchInsertChunks(&model.BatchInsertRequest{Table: "items", Data: data})
+	if err != nil {
+		t.Fatalf("Failed to build chunks: %v", err)
+	}
+
+	results, err := engine.ExecuteInsertChunks(ctx, chunks, true)
+	if err != nil {
+		t.Fatalf("Execute chunks failed: %v", err)
+	}
+	if len(results) != 2 || results[0].AffectedRows != 333 || results[1].AffectedRows != 167 {
+		t.Errorf("Expected chunks of 333 and 167 rows, got %+v", results)
+	}
+
+	// 事务模式下任一语句失败则全部回滚
+	conflicting := []map[string]interface{}{{"name": "new-1"}, {"name": "item-1", "value": 1}}
+	chunks, _ = builder.BuildBatchInsertChunks(&model.BatchInsertRequest{Table: "items", Data: conflicting})
+	if _, err := engine.ExecuteInsertChunks(ctx, chunks, true); err == nil {
+		t.Fatal("Expected unique constraint violation")
+	}
+
+	// 非事务模式下逐条提交，失败的语句单独报告
+	results, err = engine.ExecuteInsertChunks(ctx, chunks, false)
+	if err != nil {
+		t.Fatalf("Execute chunks failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("Expected first chunk to succeed and second to fail, got %+v", results)
+	}
+
+	count, err := engine.ExecuteQuery(ctx, "SELECT COUNT(*) AS total FROM items", nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count.Rows[0]["total"] != int64(501) {
+		t.Errorf("Expected 501 rows, got %v", count.Rows[0]["total"])
+	}
+}
@@ -1,7 +1,9 @@
 package sql
 
 import (
+	"errors"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"sql2api/internal/model"
@@ -32,11 +34,22 @@ func (m *DatabaseErrorMapper) MapError(err error) *model.SQLError {
 		return m.mapPostgreSQLError(errMsg, err.Error())
 	case "oracle":
 		return m.mapOracleError(errMsg, err.Error())
+	case "sqlite":
+		return m.mapSQLiteError(errMsg, err.Error())
+	case "mysql":
+		return m.mapMySQLError(errMsg, err.Error())
+	case "sqlserver":
+		return m.mapSQLServerError(err, errMsg, err.Error())
 	default:
 		return m.mapGenericError(errMsg, err.Error())
 	}
 }
 
+// sqlServerError SQL Server 驱动错误（错误号不出现在错误消息中）
+type sqlServerError interface {
+	SQLErrorNumber() int32
+}
+
 // mapPostgreSQLError 映射 PostgreSQL 错误
 func (m *DatabaseErrorMapper) mapPostgreSQLError(errMsg, originalErr string) *model.SQLError {
 	// PostgreSQL 错误码模式
@@ -180,6 +193,193 @@ func (m *DatabaseErrorMapper) mapOracleError(errMsg, originalErr string) *model.
 	}
 }
 
+// mapSQLiteError 映射 SQLite 错误
+func (m *DatabaseErrorMapper) mapSQLiteError(errMsg, originalErr string) *model.SQLError {
+	// SQLite 结果码（驱动会在错误信息末尾附带 "(code)"，扩展码的低 8 位为主结果码）
+	sqliteErrorCodes := map[int]model.SQLError{
+		1:  {Code: model.SQLErrorSyntax, Message: "SQL logic error"},
+		3:  {Code: model.SQLErrorPermission, Message: "Access permission denied"},
+		5:  {Code: model.SQLErrorTransaction, Message: "Database is locked"},
+		6:  {Code: model.SQLErrorTransaction, Message: "Database table is locked"},
+		7:  {Code: model.SQLErrorResultSize, Message: "Out of memory"},
+		8:  {Code: model.SQLErrorPermission, Message: "Attempt to write a readonly database"},
+		9:  {Code: model.SQLErrorTimeout, Message: "Operation interrupted"},
+		10: {Code: model.SQLErrorConnection, Message: "Disk I/O error"},
+		11: {Code: model.SQLErrorConnection, Message: "Database disk image is malformed"},
+		13: {Code: model.SQLErrorResultSize, Message: "Database or disk is full"},
+		14: {Code: model.SQLErrorConnection, Message: "Unable to open database file"},
+		18: {Code: model.SQLErrorResultSize, Message: "String or BLOB exceeds size limit"},
+		19: {Code: model.SQLErrorParams, Message: "Constraint failed"},
+		20: {Code: model.SQLErrorParams, Message: "Datatype mismatch"},
+		23: {Code: model.SQLErrorPermission, Message: "Authorization denied"},
+		25: {Code: model.SQLErrorParams, Message: "Bind parameter index out of range"},
+		26: {Code: model.SQLErrorConnection, Message: "File is not a database"},
+	}
+
+	// 检查 SQLite 结果码
+	if match := regexp.MustCompile(`\((\d+)\)\s*$`).FindStringSubmatch(errMsg); len(match) > 1 {
+		if code, err := strconv.Atoi(match[1]); err == nil {
+			if sqlErr, exists := sqliteErrorCodes[code&0xff]; exists {
+				sqlErr.Details = originalErr
+				sqlErr.SQLState = match[1]
+				return &sqlErr
+			}
+		}
+	}
+
+	// 通用模式检查
+	patterns := []struct {
+		pattern *regexp.Regexp
+		sqlErr  model.SQLError
+	}{
+		{regexp.MustCompile(`no such table|no such column`), model.SQLError{
+			Code:    model.SQLErrorPermission,
+			Message: "Table or column does not exist",
+		}},
+		{regexp.MustCompile(`constraint failed|not null|unique`), model.SQLError{
+			Code:    model.SQLErrorParams,
+			Message: "Constraint failed",
+		}},
+		{regexp.MustCompile(`syntax error|incomplete input|unrecognized token`), model.SQLError{
+			Code:    model.SQLErrorSyntax,
+			Message: "SQL syntax error",
+		}},
+		{regexp.MustCompile(`database is locked|busy`), model.SQLError{
+			Code:    model.SQLErrorTransaction,
+			Message: "Database is locked",
+		}},
+		{regexp.MustCompile(`readonly|permission|access denied`), model.SQLError{
+			Code:    model.SQLErrorPermission,
+			Message: "Permission denied",
+		}},
+		{regexp.MustCompile(`unable to open|not a database|disk i/o`), model.SQLError{
+			Code:    model.SQLErrorConnection,
+			Message: "Database connection error",
+		}},
+		{regexp.MustCompile(`interrupted|timeout|deadline exceeded`), model.SQLError{
+			Code:    model.SQLErrorTimeout,
+			Message: "Query timeout",
+		}},
+	}
+
+	for _, p := range patterns {
+		if p.pattern.MatchString(errMsg) {
+			sqlErr := p.sqlErr
+			sqlErr.Details = originalErr
+			return &sqlErr
+		}
+	}
+
+	// 默认错误
+	return &model.SQLError{
+		Code:    model.SQLErrorSyntax,
+		Message: "Database error",
+		Details: originalErr,
+	}
+}
+
+// mapMySQLError 映射 MySQL/MariaDB 错误
+func (m *DatabaseErrorMapper) mapMySQLError(errMsg, originalErr string) *model.SQLError {
+	// MySQL 错误号（驱动错误格式为 "Error 1064 (42000): ..."，客户端错误为 2xxx）
+	mysqlErrorCodes := map[string]model.SQLError{
+		"1064": {Code: model.SQLErrorSyntax, Message: "SQL syntax error"},
+		"1054": {Code: model.SQLErrorSyntax, Message: "Unknown column"},
+		"1052": {Code: model.SQLErrorSyntax, Message: "Column is ambiguous"},
+		"1146": {Code: model.SQLErrorPermission, Message: "Table does not exist"},
+		"1044": {Code: model.SQLErrorPermission, Message: "Access denied for user to database"},
+		"1045": {Code: model.SQLErrorPermission, Message: "Access denied for user"},
+		"1142": {Code: model.SQLErrorPermission, Message: "Command denied to user for table"},
+		"1143": {Code: model.SQLErrorPermission, Message: "Command denied to user for column"},
+		"1227": {Code: model.SQLErrorPermission, Message: "Access denied; privilege required"},
+		"1048": {Code: model.SQLErrorParams, Message: "Column cannot be null"},
+		"1062": {Code: model.SQLErrorParams, Message: "Duplicate entry"},
+		"1264": {Code: model.SQLErrorParams, Message: "Out of range value"},
+		"1364": {Code: model.SQLErrorParams, Message: "Field doesn't have a default value"},
+		"1366": {Code: model.SQLErrorParams, Message: "Incorrect value for column"},
+		"1406": {Code: model.SQLErrorParams, Message: "Data too long for column"},
+		"1451": {Code: model.SQLErrorParams, Message: "Row is referenced by a foreign key"},
+		"1452": {Code: model.SQLErrorParams, Message: "Foreign key constraint fails"},
+		"1040": {Code: model.SQLErrorConnection, Message: "Too many connections"},
+		"1053": {Code: model.SQLErrorConnection, Message: "Server shutdown in progress"},
+		"2002": {Code: model.SQLErrorConnection, Message: "Can't connect to local MySQL server"},
+		"2003": {Code: model.SQLErrorConnection, Message: "Can't connect to MySQL server"},
+		"2006": {Code: model.SQLErrorConnection, Message: "MySQL server has gone away"},
+		"2013": {Code: model.SQLErrorConnection, Message: "Lost connection to MySQL server during query"},
+		"1205": {Code: model.SQLErrorTransaction, Message: "Lock wait timeout exceeded"},
+		"1213": {Code: model.SQLErrorTransaction, Message: "Deadlock found when trying to get lock"},
+		"1180": {Code: model.SQLErrorTransaction, Message: "Got error during COMMIT"},
+		"3024": {Code: model.SQLErrorTimeout, Message: "Maximum statement execution time exceeded"},
+		"1317": {Code: model.SQLErrorTimeout, Message: "Query execution was interrupted"},
+		"1104": {Code: model.SQLErrorResultSize, Message: "SELECT would examine too many rows"},
+		"1153": {Code: model.SQLErrorResultSize, Message: "Packet too large"},
+	}
+
+	// 检查 MySQL 错误号
+	if match := regexp.MustCompile(`error (\d{4})`).FindStringSubmatch(errMsg); len(match) > 1 {
+		if sqlErr, exists := mysqlErrorCodes[match[1]]; exists {
+			sqlErr.Details = originalErr
+			sqlErr.SQLState = match[1]
+			return &sqlErr
+		}
+	}
+
+	// 其余情况按通用模式处理（如驱动层的连接错误、上下文超时）
+	return m.mapGenericError(errMsg, originalErr)
+}
+
+// mapSQLServerError 映射 SQL Server 错误
+func (m *DatabaseErrorMapper) mapSQLServerError(err error, errMsg, originalErr string) *model.SQLError {
+	// SQL Server 错误号
+	sqlServerErrorCodes := map[int32]model.SQLError{
+		102:   {Code: model.SQLErrorSyntax, Message: "SQL syntax error"},
+		156:   {Code: model.SQLErrorSyntax, Message: "Incorrect syntax near keyword"},
+		105:   {Code: model.SQLErrorSyntax, Message: "Unclosed quotation mark"},
+		207:   {Code: model.SQLErrorSyntax, Message: "Invalid column name"},
+		208:   {Code: model.SQLErrorPermission, Message: "Invalid object name"},
+		229:   {Code: model.SQLErrorPermission, Message: "Permission denied on object"},
+		230:   {Code: model.SQLErrorPermission, Message: "Permission denied on column"},
+		262:   {Code: model.SQLErrorPermission, Message: "Permission denied in database"},
+		297:   {Code: model.SQLErrorPermission, Message: "User does not have permission"},
+		300:   {Code: model.SQLErrorPermission, Message: "Permission was denied"},
+		18456: {Code: model.SQLErrorPermission, Message: "Login failed"},
+		515:   {Code: model.SQLErrorParams, Message: "Cannot insert null value"},
+		2627:  {Code: model.SQLErrorParams, Message: "Unique constraint violation"},
+		2601:  {Code: model.SQLErrorParams, Message: "Duplicate key row in unique index"},
+		547:   {Code: model.SQLErrorParams, Message: "Constraint conflict"},
+		8152:  {Code: model.SQLErrorParams, Message: "String or binary data would be truncated"},
+		2628:  {Code: model.SQLErrorParams, Message: "String or binary data would be truncated"},
+		245:   {Code: model.SQLErrorParams, Message: "Conversion failed"},
+		8114:  {Code: model.SQLErrorParams, Message: "Error converting data type"},
+		8115:  {Code: model.SQLErrorParams, Message: "Arithmetic overflow error"},
+		1205:  {Code: model.SQLErrorTransaction, Message: "Transaction deadlocked"},
+		3960:  {Code: model.SQLErrorTransaction, Message: "Snapshot isolation update conflict"},
+		1222:  {Code: model.SQLErrorTransaction, Message: "Lock request timeout exceeded"},
+		-2:    {Code: model.SQLErrorTimeout, Message: "Query timeout"},
+		3980:  {Code: model.SQLErrorTimeout, Message: "Request aborted"},
+		3617:  {Code: model.SQLErrorTimeout, Message: "Query cancelled"},
+		4060:  {Code: model.SQLErrorConnection, Message: "Cannot open database"},
+		40613: {Code: model.SQLErrorConnection, Message: "Database is not currently available"},
+		10054: {Code: model.SQLErrorConnection, Message: "Connection reset by peer"},
+		10060: {Code: model.SQLErrorConnection, Message: "Connection timed out"},
+		233:   {Code: model.SQLErrorConnection, Message: "No process is on the other end of the pipe"},
+		1105:  {Code: model.SQLErrorResultSize, Message: "Could not allocate space"},
+		9002:  {Code: model.SQLErrorResultSize, Message: "Transaction log is full"},
+	}
+
+	// 检查 SQL Server 错误号
+	var serverErr sqlServerError
+	if errors.As(err, &serverErr) {
+		if sqlErr, exists := sqlServerErrorCodes[serverErr.SQLErrorNumber()]; exists {
+			sqlErr.Details = originalErr
+			sqlErr.SQLState = strconv.Itoa(int(serverErr.SQLErrorNumber()))
+			return &sqlErr
+		}
+	}
+
+	// 其余情况按通用模式处理（如驱动层的连接错误、上下文超时）
+	return m.mapGenericError(errMsg, originalErr)
+}
+
 // mapGenericError 映射通用数据库错误
 func (m *DatabaseErrorMapper) mapGenericError(errMsg, originalErr string) *model.SQLError {
 	patterns := map[*regexp.Regexp]model.SQLError{
@@ -247,6 +447,22 @@ func (m *DatabaseErrorMapper) GetSQLState(err error) string {
 		if match := regexp.MustCompile(`ora-(\d{5})`).FindStringSubmatch(errMsg); len(match) > 1 {
 			return "ORA-" + match[1]
 		}
+	case "mysql":
+		// MySQL 错误号提取
+		if match := regexp.MustCompile(`error (\d{4})`).FindStringSubmatch(errMsg); len(match) > 1 {
+			return match[1]
+		}
+	case "sqlserver":
+		// SQL Server 错误号提取
+		var serverErr sqlServerError
+		if errors.As(err, &serverErr) {
+			return strconv.Itoa(int(serverErr.SQLErrorNumber()))
+		}
+	case "sqlite":
+		// SQLite 结果码提取
+		if match := regexp.MustCompile(`\((\d+)\)\s*$`).FindStringSubmatch(errMsg); len(match) > 1 {
+			return match[1]
+		}
 	}
 
 	return ""
@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// maxNormalizedSQLLength 规范化 SQL 的最大保存长度
+const maxNormalizedSQLLength = 4096
+
+// normalizeSQL 规范化 SQL：关键字转为小写，字符串、数字和占位符替换为 ?，
+// IN 列表折叠为 in (...)，多行 VALUES 折叠为一行，空白和注释统一，
+// 因此只有字面量或参数不同的语句得到相同的结果
+func normalizeSQL(query, dbType string) string {
+	tokens, err := auditTokens(query, dbType)
+	if err != nil {
+		return strings.Join(strings.Fields(query), " ")
+	}
+
+	words := make([]string, 0, len(tokens))
+	for i, tok := range tokens {
+		switch tok.kind {
+		case tokString, tokNumber, tokParam:
+			// 负数的符号属于字面量：-1 与 1 规范化结果相同
+			if tok.kind == tokNumber && isSignToken(tokens, i-1) {
+				words[len(words)-1] = "?"
+				continue
+			}
+			words = append(words, "?")
+		case tokWord:
+			words = append(words, strings.ToLower(tok.text))
+		default:
+			words = append(words, tok.text)
+		}
+	}
+
+	words = collapseInLists(words)
+	words = collapseValuesRows(words)
+	return renderNormalized(words)
+}
+
+// isSignToken 检查 i 处是否为数字前的正负号（而不是减法运算符）
+func isSignToken(tokens []sqlToken, i int) bool {
+	if i < 0 || !(tokens[i].is("-") || tokens[i].is("+")) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	prev := tokens[i-1]
+	return prev.kind == tokSymbol && !prev.is(")")
+}
+
+// collapseInLists 将 in (?, ?, ...) 折叠为 in (...)
+func collapseInLists(words []string) []string {
+	result := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		result = append(result, words[i])
+		if words[i] != "in" || i+2 >= len(words) || words[i+1] != "(" || words[i+2] != "?" {
+			continue
+		}
+
+		j := i + 3
+		for j+1 < len(words) && words[j] == "," && words[j+1] == "?" {
+			j += 2
+		}
+		if j < len(words) && words[j] == ")" {
+			result = append(result, "(", "...", ")")
+			i = j
+		}
+	}
+	return result
+}
+
+// collapseValuesRows 将 values 后与第一行相同的多行折叠为一行，使不同批次大小的 INSERT 得到相同结果
+func collapseValuesRows(words []string) []string {
+	result := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		result = append(result, words[i])
+		if words[i] != "values" || i+1 >= len(words) || words[i+1] != "(" {
+			continue
+		}
+
+		end := matchWords(words, i+1)
+		if end < 0 {
+			continue
+		}
+		row := words[i+1 : end+1]
+		result = append(result, row...)
+
+		next := end + 1
+		for next+len(row) < len(words) && words[next] == "," && equalWords(words[next+1:next+1+len(row)], row) {
+			next += 1 + len(row)
+		}
+		i = next - 1
+	}
+	return result
+}
+
+// matchWords 查找与 open 处左括号匹配的右括号
+func matchWords(words []string, open int) int {
+	depth := 0
+	for i := open; i < len(words); i++ {
+		switch words[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// equalWords 比较两个词序列
+func equalWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// renderNormalized 以单个空格拼接词，逗号、右括号和点号前以及左括号和点号后不加空格
+func renderNormalized(words []string) string {
+	var sb strings.Builder
+	for i, word := range words {
+		if i > 0 && word != "," && word != ")" && word != "." && words[i-1] != "(" && words[i-1] != "." {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(word)
+	}
+
+	normalized := sb.String()
+	if len(normalized) > maxNormalizedSQLLength {
+		normalized = normalized[:maxNormalizedSQLLength] + "..."
+	}
+	return normalized
+}
+
+// fingerprintSQL 计算规范化 SQL 的指纹，查询类型不同的语句（如批次和单条语句）分开统计
+func fingerprintSQL(queryType, normalized string) string {
+	sum := sha256.Sum256([]byte(queryType + "\x00" + normalized))
+	return hex.EncodeToString(sum[:8])
+}
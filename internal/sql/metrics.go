@@ -0,0 +1,119 @@
+package sql
+
+import (
+	"errors"
+	"strconv"
+
+	"sql2api/internal/model"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 指标标签取值，标签只使用有限集合以控制基数
+const (
+	metricsOutcomeSuccess = "success"
+	metricsOutcomeError   = "error"
+	metricsNoAPIKey       = "none"
+	metricsOther          = "other"
+)
+
+// metricsQueryTypes 指标中保留的查询类型，其他类型记为 other
+var metricsQueryTypes = map[string]bool{
+	"select":   true,
+	"insert":   true,
+	"update":   true,
+	"delete":   true,
+	"merge":    true,
+	"batch":    true,
+	"call":     true,
+	"describe": true,
+}
+
+// metricsDatasources 指标中保留的数据源类型
+var metricsDatasources = map[string]bool{
+	"postgres":  true,
+	"mysql":     true,
+	"sqlite":    true,
+	"oracle":    true,
+	"sqlserver": true,
+}
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "sql2api",
+		Name:      "query_duration_seconds",
+		Help:      "SQL query execution time by query type, datasource, API key name and outcome.",
+		Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"type", "datasource", "api_key", "outcome"})
+
+	slowQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sql2api",
+		Name:      "slow_queries_total",
+		Help:      "SQL queries slower than the slow query threshold.",
+	}, []string{"type", "datasource"})
+
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sql2api",
+		Name:      "query_errors_total",
+		Help:      "Failed SQL queries by SQL error code.",
+	}, []string{"type", "datasource", "code"})
+
+	rowsReturned = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sql2api",
+		Name:      "rows_returned_total",
+		Help:      "Rows returned by SQL queries.",
+	}, []string{"type", "datasource"})
+
+	rowsAffected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sql2api",
+		Name:      "rows_affected_total",
+		Help:      "Rows affected by SQL statements.",
+	}, []string{"type", "datasource"})
+)
+
+// observeQuery 更新查询指标
+func observeQuery(metrics *QueryMetrics, apiKeyName string, slow bool) {
+	queryType := metricsLabel(metrics.QueryType, metricsQueryTypes)
+	datasource := metricsLabel(metrics.DatabaseType, metricsDatasources)
+
+	outcome := metricsOutcomeSuccess
+	if !metrics.Success {
+		outcome = metricsOutcomeError
+		queryErrors.WithLabelValues(queryType, datasource, strconv.Itoa(metrics.ErrorCode)).Inc()
+	}
+	if apiKeyName == "" {
+		apiKeyName = metricsNoAPIKey
+	}
+
+	queryDuration.WithLabelValues(queryType, datasource, apiKeyName, outcome).Observe(metrics.ExecutionTime.Seconds())
+	if slow {
+		slowQueries.WithLabelValues(queryType, datasource).Inc()
+	}
+	if metrics.ResultRows > 0 {
+		rowsReturned.WithLabelValues(queryType, datasource).Add(float64(metrics.ResultRows))
+	}
+	if metrics.AffectedRows > 0 {
+		rowsAffected.WithLabelValues(queryType, datasource).Add(float64(metrics.AffectedRows))
+	}
+}
+
+// metricsLabel 将不在已知集合中的取值归为 other
+func metricsLabel(value string, known map[string]bool) string {
+	if known[value] {
+		return value
+	}
+	return metricsOther
+}
+
+// metricsErrorCode 获取错误对应的 SQL 错误码，未映射的错误使用错误映射器分类
+func metricsErrorCode(err error, mapper *DatabaseErrorMapper) int {
+	var sqlErr *model.SQLError
+	if errors.As(err, &sqlErr) {
+		return sqlErr.Code
+	}
+	if mapper != nil {
+		return mapper.MapError(err).Code
+	}
+	return model.SQLErrorSyntax
+}
@@ -0,0 +1,102 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"sql2api/internal/model"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSQLEngine_QueryMetrics(t *testing.T) {
+	engine, _ := newTestEngine(t)
+	ctx := WithCaller(context.Background(), &Caller{APIKeyName: "metrics-writer"})
+
+	inserted := testutil.ToFloat64(rowsAffected.WithLabelValues("insert", "sqlite"))
+	returned := testutil.ToFloat64(rowsReturned.WithLabelValues("select", "sqlite"))
+	failed := testutil.ToFloat64(queryErrors.WithLabelValues("insert", "sqlite", strconv.Itoa(model.SQLErrorParams)))
+
+	if _, err := engine.ExecuteSQL(ctx, "INSERT INTO items (name, value) VALUES ('a', 1), ('b', 2)", nil); err != nil {
+		t.Fatalf("ExecuteSQL failed: %v", err)
+	}
+	if _, err := engine.ExecuteQuery(ctx, "SELECT id, name FROM items", nil); err != nil {
+		t.Fatalf("ExecuteQuery failed: %v", err)
+	}
+	if _, err := engine.ExecuteSQL(ctx, "INSERT INTO items (name, value) VALUES ('a', 3)", nil); err == nil {
+		t.Fatal("Expected UNIQUE violation")
+	}
+
+	if delta := testutil.ToFloat64(rowsAffected.WithLabelValues("insert", "sqlite")) - inserted; delta != 2 {
+		t.Errorf("Expected 2 affected rows, got %v", delta)
+	}
+	if delta := testutil.ToFloat64(rowsReturned.WithLabelValues("select", "sqlite")) - returned; delta != 2 {
+		t.Errorf("Expected 2 returned rows, got %v", delta)
+	}
+	if delta := testutil.ToFloat64(queryErrors.WithLabelValues("insert", "sqlite", strconv.Itoa(model.SQLErrorParams))) - failed; delta != 1 {
+		t.Errorf("Expected 1 constraint error, got %v", delta)
+	}
+
+	// 每个类型和结果组合对应一个直方图序列
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	series := map[string]uint64{}
+	for _, family := range families {
+		if family.GetName() != "sql2api_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["api_key"] == "metrics-writer" {
+				series[labels["type"]+"/"+labels["outcome"]] = metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	expected := map[string]uint64{"insert/success": 1, "insert/error": 1, "select/success": 1}
+	if !reflect.DeepEqual(series, expected) {
+		t.Errorf("Expected duration series %v, got %v", expected, series)
+	}
+}
+
+func TestMetricsLabel(t *testing.T) {
+	if label := metricsLabel("update", metricsQueryTypes); label != "update" {
+		t.Errorf("Expected update, got %s", label)
+	}
+	if label := metricsLabel("drop", metricsQueryTypes); label != metricsOther {
+		t.Errorf("Expected %s, got %s", metricsOther, label)
+	}
+}
+
+func TestRunningQueries(t *testing.T) {
+	monitor := NewPerformanceMonitor(true, false, false, 1000)
+	ctx := WithCaller(context.Background(), &Caller{APIKeyName: "runner", RequestID: "req-running"})
+	queryCtx := monitor.StartQuery(ctx, "select", "sqlite", "SELECT * FROM items WHERE name = 'secret'")
+
+	var found *model.RunningQuery
+	for _, query := range RunningQueries() {
+		if query.QueryID == queryCtx.QueryID {
+			found = &query
+		}
+	}
+	if found == nil {
+		t.Fatal("Expected started query to be running")
+	}
+	if found.SQL != "SELECT * FROM items WHERE name = ?" || found.APIKeyName != "runner" || found.RequestID != "req-running" {
+		t.Errorf("Unexpected running query: %+v", found)
+	}
+
+	queryCtx.Finish(true, 0, 0, nil)
+	for _, query := range RunningQueries() {
+		if query.QueryID == queryCtx.QueryID {
+			t.Error("Expected finished query to be removed")
+		}
+	}
+}
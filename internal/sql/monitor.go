@@ -4,8 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"sql2api/internal/model"
+	"sql2api/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 )
 
 // QueryMetrics 查询指标
@@ -23,14 +32,23 @@ type QueryMetrics struct {
 	Timestamp     time.Time     `json:"timestamp"`
 	ClientIP      string        `json:"client_ip,omitempty"`
 	APIKey        string        `json:"api_key,omitempty"` // 脱敏后的 API Key
+	TraceID       string        `json:"trace_id,omitempty"` // 链路追踪的 trace ID
 }
 
+// maxSanitizedSQLLength 脱敏 SQL 的最大保存长度
+const maxSanitizedSQLLength = 2048
+
+// runningQueries 正在执行的查询，键为 *QueryContext
+var runningQueries sync.Map
+
 // PerformanceMonitor 性能监控器
 type PerformanceMonitor struct {
 	enabled     bool
 	logQueries  bool
 	logErrors   bool
 	slowQueryMs int64 // 慢查询阈值（毫秒）
+	errorMapper *DatabaseErrorMapper // 用于为失败的查询设置错误码
+	db          *gorm.DB             // 用于查找数据库连接上启用的语句统计
 }
 
 // NewPerformanceMonitor 创建性能监控器
@@ -43,29 +61,41 @@ func NewPerformanceMonitor(enabled, logQueries, logErrors bool, slowQueryMs int6
 	}
 }
 
-// StartQuery 开始查询监控
+// StartQuery 开始查询监控，同时开始 SQLEngine.<queryType> span，
+// 后续操作应使用返回的 QueryContext.Context 以便记录为该 span 的子 span
 func (m *PerformanceMonitor) StartQuery(ctx context.Context, queryType, dbType, sql string) *QueryContext {
 	if !m.enabled {
-		return &QueryContext{enabled: false}
+		return &QueryContext{enabled: false, Context: ctx}
 	}
 
 	queryID := m.generateQueryID()
+
+	attrs := append(spanAttributes(dbType, sql),
+		attribute.String("db.operation", queryType),
+		attribute.String("sql2api.query_id", queryID),
+	)
+	ctx, span := tracing.Start(ctx, "SQLEngine."+queryType, attrs...)
 	
 	queryCtx := &QueryContext{
 		enabled:      true,
 		monitor:      m,
+		span:         span,
 		QueryID:      queryID,
 		DatabaseType: dbType,
 		QueryType:    queryType,
-		SQL:          m.sanitizeSQL(sql),
+		SQL:          m.sanitizeSQL(sql, dbType),
+		rawSQL:       sql,
 		StartTime:    time.Now(),
 		Context:      ctx,
+		TraceID:      tracing.TraceID(ctx),
 	}
 
+	runningQueries.Store(queryCtx, struct{}{})
+
 	// 记录查询开始
 	if m.logQueries {
-		log.Printf("[SQL-MONITOR] Query started - ID: %s, Type: %s, DB: %s", 
-			queryID, queryType, dbType)
+		log.Printf("[SQL-MONITOR] Query started - ID: %s, Type: %s, DB: %s%s", 
+			queryID, queryType, dbType, traceSuffix(queryCtx.TraceID))
 	}
 
 	return queryCtx
@@ -75,6 +105,9 @@ func (m *PerformanceMonitor) StartQuery(ctx context.Context, queryType, dbType,
 type QueryContext struct {
 	enabled      bool
 	monitor      *PerformanceMonitor
+	span         trace.Span
+	rawSQL       string                 // 原始 SQL，用于计算语句指纹
+	params       map[string]interface{} // 查询参数，慢查询日志中只保留参数名
 	QueryID      string
 	DatabaseType string
 	QueryType    string
@@ -83,6 +116,7 @@ type QueryContext struct {
 	Context      context.Context
 	ClientIP     string
 	APIKey       string
+	TraceID      string
 }
 
 // SetClientInfo 设置客户端信息
@@ -94,6 +128,14 @@ func (qc *QueryContext) SetClientInfo(clientIP, apiKey string) {
 	qc.APIKey = qc.monitor.sanitizeAPIKey(apiKey)
 }
 
+// SetParams 设置查询参数
+func (qc *QueryContext) SetParams(params map[string]interface{}) {
+	if !qc.enabled {
+		return
+	}
+	qc.params = params
+}
+
 // Finish 完成查询监控
 func (qc *QueryContext) Finish(success bool, affectedRows, resultRows int64, err error) {
 	if !qc.enabled {
@@ -101,6 +143,7 @@ func (qc *QueryContext) Finish(success bool, affectedRows, resultRows int64, err
 	}
 
 	executionTime := time.Since(qc.StartTime)
+	runningQueries.Delete(qc)
 	
 	metrics := QueryMetrics{
 		QueryID:       qc.QueryID,
@@ -114,26 +157,50 @@ func (qc *QueryContext) Finish(success bool, affectedRows, resultRows int64, err
 		Timestamp:     qc.StartTime,
 		ClientIP:      qc.ClientIP,
 		APIKey:        qc.APIKey,
+		TraceID:       qc.TraceID,
 	}
 
 	if err != nil {
 		metrics.ErrorMessage = err.Error()
-		// 这里可以根据错误类型设置错误码
+		metrics.ErrorCode = metricsErrorCode(err, qc.monitor.errorMapper)
 	}
 
 	// 记录指标
-	qc.monitor.recordMetrics(&metrics)
+	var caller *Caller
+	if qc.Context != nil {
+		caller = CallerFrom(qc.Context)
+	}
+	apiKeyName := ""
+	if caller != nil {
+		apiKeyName = caller.APIKeyName
+	}
+	qc.monitor.recordMetrics(&metrics, apiKeyName)
+
+	// 记录语句统计
+	if stats := statementStatsFor(qc.monitor.db); stats != nil {
+		stats.Record(&metrics, qc.rawSQL, qc.params, caller)
+	}
 
 	// 记录日志
 	qc.monitor.logQuery(&metrics)
+
+	// 结束 span
+	qc.span.SetAttributes(
+		attribute.Int64("db.rows_affected", affectedRows),
+		attribute.Int64("db.rows_returned", resultRows),
+	)
+	if err != nil {
+		qc.span.SetAttributes(attribute.Int("sql2api.error_code", metrics.ErrorCode))
+	}
+	tracing.End(qc.span, err)
 }
 
-// recordMetrics 记录查询指标
-func (m *PerformanceMonitor) recordMetrics(metrics *QueryMetrics) {
-	// 这里可以集成到监控系统，如 Prometheus、InfluxDB 等
-	// 目前只是简单记录到日志
-	
-	if metrics.ExecutionTime.Milliseconds() > m.slowQueryMs {
+// recordMetrics 记录查询指标（Prometheus），并记录慢查询日志
+func (m *PerformanceMonitor) recordMetrics(metrics *QueryMetrics, apiKeyName string) {
+	slow := metrics.ExecutionTime.Milliseconds() > m.slowQueryMs
+	observeQuery(metrics, apiKeyName, slow)
+
+	if slow {
 		log.Printf("[SQL-MONITOR] SLOW QUERY - ID: %s, Time: %dms, Type: %s", 
 			metrics.QueryID, metrics.ExecutionTime.Milliseconds(), metrics.QueryType)
 	}
@@ -142,8 +209,8 @@ func (m *PerformanceMonitor) recordMetrics(metrics *QueryMetrics) {
 // logQuery 记录查询日志
 func (m *PerformanceMonitor) logQuery(metrics *QueryMetrics) {
 	if !metrics.Success && m.logErrors {
-		log.Printf("[SQL-ERROR] Query failed - ID: %s, Error: %s, Time: %dms", 
-			metrics.QueryID, metrics.ErrorMessage, metrics.ExecutionTime.Milliseconds())
+		log.Printf("[SQL-ERROR] Query failed - ID: %s, Error: %s, Time: %dms%s", 
+			metrics.QueryID, metrics.ErrorMessage, metrics.ExecutionTime.Milliseconds(), traceSuffix(metrics.TraceID))
 	} else if metrics.Success && m.logQueries {
 		log.Printf("[SQL-SUCCESS] Query completed - ID: %s, Type: %s, Time: %dms, Affected: %d, Results: %d", 
 			metrics.QueryID, metrics.QueryType, metrics.ExecutionTime.Milliseconds(), 
@@ -151,27 +218,42 @@ func (m *PerformanceMonitor) logQuery(metrics *QueryMetrics) {
 	}
 }
 
+// traceSuffix 日志中附加的 trace ID，便于从日志找到对应的链路
+func traceSuffix(traceID string) string {
+	if traceID == "" {
+		return ""
+	}
+	return ", Trace: " + traceID
+}
+
 // generateQueryID 生成查询 ID
 func (m *PerformanceMonitor) generateQueryID() string {
 	return fmt.Sprintf("sql_%d_%d", time.Now().UnixNano(), time.Now().Nanosecond()%1000)
 }
 
-// sanitizeSQL 脱敏 SQL 语句
-func (m *PerformanceMonitor) sanitizeSQL(sql string) string {
+// sanitizeSQL 脱敏 SQL 语句：字符串和数字字面量替换为 ?，无法解析时替换引号
+func (m *PerformanceMonitor) sanitizeSQL(sql, dbType string) string {
 	if sql == "" {
 		return ""
 	}
 
 	// 移除敏感信息
 	sanitized := sql
-	
-	// 替换字符串字面量
-	sanitized = strings.ReplaceAll(sanitized, "'", "?")
-	sanitized = strings.ReplaceAll(sanitized, "\"", "?")
+	if tokens, err := lexSQL(sql, dbType); err == nil {
+		for i := range tokens {
+			if tokens[i].kind == tokString || tokens[i].kind == tokNumber {
+				tokens[i].text = "?"
+			}
+		}
+		sanitized = renderTokens(tokens)
+	} else {
+		sanitized = strings.ReplaceAll(sanitized, "'", "?")
+		sanitized = strings.ReplaceAll(sanitized, "\"", "?")
+	}
 	
 	// 限制长度
-	if len(sanitized) > 200 {
-		sanitized = sanitized[:200] + "..."
+	if len(sanitized) > maxSanitizedSQLLength {
+		sanitized = sanitized[:maxSanitizedSQLLength] + "..."
 	}
 	
 	return sanitized
@@ -199,6 +281,7 @@ func (m *PerformanceMonitor) GetMetricsSummary() map[string]interface{} {
 		"log_queries":    m.logQueries,
 		"log_errors":     m.logErrors,
 		"slow_query_ms":  m.slowQueryMs,
+		"running_queries": len(RunningQueries()),
 		"monitor_status": "active",
 	}
 }
@@ -249,3 +332,33 @@ func (mo *MemoryOptimizer) LogMemoryUsage(operation string, beforeMB, afterMB fl
 	log.Printf("[MEMORY-OPTIMIZER] %s - Memory usage: %.2fMB -> %.2fMB (%.2fMB diff)", 
 		operation, beforeMB, afterMB, afterMB-beforeMB)
 }
+
+// RunningQueries 返回所有正在执行的查询，最早开始的在前
+func RunningQueries() []model.RunningQuery {
+	now := time.Now()
+	queries := make([]model.RunningQuery, 0)
+	runningQueries.Range(func(key, _ interface{}) bool {
+		qc := key.(*QueryContext)
+		query := model.RunningQuery{
+			QueryID:      qc.QueryID,
+			QueryType:    qc.QueryType,
+			DatabaseType: qc.DatabaseType,
+			SQL:          qc.SQL,
+			StartTime:    qc.StartTime,
+			Duration:     float64(now.Sub(qc.StartTime).Microseconds()) / 1000,
+			TraceID:      qc.TraceID,
+		}
+		if caller := CallerFrom(qc.Context); caller != nil {
+			query.APIKeyName = caller.APIKeyName
+			query.RequestID = caller.RequestID
+			query.ClientIP = caller.ClientIP
+		}
+		queries = append(queries, query)
+		return true
+	})
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].StartTime.Before(queries[j].StartTime)
+	})
+	return queries
+}
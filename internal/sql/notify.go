@@ -0,0 +1,245 @@
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// 服务器管理的 PostgreSQL 变更触发器
+const (
+	changeTriggerName  = "sql2api_change"
+	changeFunctionName = "sql2api_notify_change"
+)
+
+// changeFunctionSQL 触发器函数：以 JSON 发送表名、操作和行数据，参数依次为通知通道和配置中的表名
+// NOTIFY 负载不能超过 8000 字节，超出时只发送表名和操作
+const changeFunctionSQL = `CREATE OR REPLACE FUNCTION ` + changeFunctionName + `() RETURNS trigger AS $$
+DECLARE
+	payload text;
+BEGIN
+	payload := json_build_object(
+		'table', TG_ARGV[1],
+		'operation', lower(TG_OP),
+		'row', CASE WHEN TG_OP = 'DELETE' THEN row_to_json(OLD) ELSE row_to_json(NEW) END,
+		'old', CASE WHEN TG_OP = 'UPDATE' THEN row_to_json(OLD) END
+	)::text;
+	IF octet_length(payload) > 7900 THEN
+		payload := json_build_object('table', TG_ARGV[1], 'operation', lower(TG_OP))::text;
+	END IF;
+	PERFORM pg_notify(TG_ARGV[0], payload);
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql`
+
+// triggerTablePattern 可安装触发器的表名（可带 schema 前缀，不需要引用）
+var triggerTablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// ChangeListener PostgreSQL 变更监听器
+// 通过触发器和 LISTEN/NOTIFY 捕获允许访问的表上的所有写操作，包括不经过网关的写操作
+type ChangeListener struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartChangeListener 安装变更触发器并在后台监听通知，连接断开时自动重连
+func (e *SQLEngine) StartChangeListener(ctx context.Context) (*ChangeListener, error) {
+	if e.dbType != "postgres" {
+		return nil, fmt.Errorf("change notifications are not supported for %s", e.dbType)
+	}
+
+	if err := e.installChangeTriggers(ctx); err != nil {
+		return nil, fmt.Errorf("failed to install change triggers: %w", err)
+	}
+
+	listenCtx, cancel := context.WithCancel(context.Background())
+	listener := &ChangeListener{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go listener.run(listenCtx, e)
+	return listener, nil
+}
+
+// Close 停止监听
+func (l *ChangeListener) Close() {
+	l.cancel()
+	<-l.done
+}
+
+// run 监听循环，失败后按指数退避重连（最长 30 秒）
+func (l *ChangeListener) run(ctx context.Context, e *SQLEngine) {
+	defer close(l.done)
+
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := e.listenChanges(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// 连接正常工作过一段时间后重置退避时间
+		if time.Since(started) > time.Minute {
+			backoff = time.Second
+		}
+		log.Printf("Change listener disconnected: %v (reconnecting in %v)", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// listenChanges 占用一个连接执行 LISTEN 并分发收到的通知
+func (e *SQLEngine) listenChanges(ctx context.Context) error {
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		pgConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unsupported postgres driver connection: %T", driverConn)
+		}
+
+		channel := e.config.Subscriptions.Channel
+		if _, err := pgConn.Conn().Exec(ctx, "LISTEN "+channel); err != nil {
+			return err
+		}
+		// 连接归还连接池前取消监听（取消上下文时连接已被关闭）
+		defer func() {
+			if !pgConn.Conn().IsClosed() {
+				pgConn.Conn().Exec(context.Background(), "UNLISTEN "+channel)
+			}
+		}()
+
+		for {
+			notification, err := pgConn.Conn().WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+
+			event, err := parseChangeNotification(notification.Payload)
+			if err != nil {
+				log.Printf("Ignoring invalid change notification: %v", err)
+				continue
+			}
+			e.changes.Publish(event)
+		}
+	})
+}
+
+// parseChangeNotification 解析触发器发送的通知负载，数字保留原始精度
+func parseChangeNotification(payload string) (*ChangeEvent, error) {
+	var notification struct {
+		Table     string                 `json:"table"`
+		Operation string                 `json:"operation"`
+		Row       map[string]interface{} `json:"row"`
+		Old       map[string]interface{} `json:"old"`
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&notification); err != nil {
+		return nil, err
+	}
+
+	switch notification.Operation {
+	case ChangeInsert, ChangeUpdate, ChangeDelete:
+	default:
+		return nil, fmt.Errorf("unknown operation: %s", notification.Operation)
+	}
+	if notification.Table == "" {
+		return nil, errors.New("missing table")
+	}
+
+	event := &ChangeEvent{
+		Table:     notification.Table,
+		Operation: notification.Operation,
+		Row:       notification.Row,
+		Old:       notification.Old,
+		Source:    ChangeSourceDatabase,
+	}
+	if event.Row != nil {
+		event.AffectedRows = 1
+	}
+	return event, nil
+}
+
+// installChangeTriggers 在一个事务中删除已有的变更触发器，并为所有允许访问的表重新创建
+// 使用事务级咨询锁，避免多个实例同时启动时互相干扰
+func (e *SQLEngine) installChangeTriggers(ctx context.Context) error {
+	for _, table := range e.config.AllowedTables {
+		if !triggerTablePattern.MatchString(table) {
+			return fmt.Errorf("invalid table name for change trigger: %s", table)
+		}
+	}
+
+	return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", changeTriggerName).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(changeFunctionSQL).Error; err != nil {
+			return err
+		}
+
+		// 删除已有的触发器，包括已从允许列表中移除的表上的触发器
+		var existing []struct {
+			Schema string
+			Table  string
+		}
+		err := tx.Raw(`SELECT n.nspname AS schema, c.relname AS "table"
+			FROM pg_trigger t
+			JOIN pg_class c ON c.oid = t.tgrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE t.tgname = ?`, changeTriggerName).Scan(&existing).Error
+		if err != nil {
+			return err
+		}
+		for _, trigger := range existing {
+			table := e.dialect.QuoteIdentifier(trigger.Schema) + "." + e.dialect.QuoteIdentifier(trigger.Table)
+			if err := tx.Exec(fmt.Sprintf("DROP TRIGGER %s ON %s", changeTriggerName, table)).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range e.config.AllowedTables {
+			create := fmt.Sprintf(
+				"CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE PROCEDURE %s(%s, %s)",
+				changeTriggerName, table, changeFunctionName,
+				quoteLiteral(e.config.Subscriptions.Channel), quoteLiteral(table),
+			)
+			if err := tx.Exec(create).Error; err != nil {
+				return fmt.Errorf("table %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// quoteLiteral 引用 SQL 字符串字面量
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,124 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+)
+
+// ResourceColumns 获取资源的查询列，未声明字段时返回空（查询所有列）
+func ResourceColumns(resource *config.ResourceConfig) []string {
+	columns := make([]string, 0, len(resource.Fields))
+	for _, field := range resource.Fields {
+		columns = append(columns, field.Column)
+	}
+	return columns
+}
+
+// ResourceColumn 将 API 字段名转换为列名，未声明字段的资源只接受标识符形式的列名
+func ResourceColumn(resource *config.ResourceConfig, field string) (string, bool) {
+	if len(resource.Fields) == 0 {
+		return field, columnPattern.MatchString(field)
+	}
+	for _, f := range resource.Fields {
+		if f.FieldName() == field {
+			return f.Column, true
+		}
+	}
+	return "", false
+}
+
+// ResourceRows 将查询结果的列名转换为 API 字段名（忽略大小写，兼容 Oracle 大写列名），未声明的列不返回
+func ResourceRows(resource *config.ResourceConfig, rows []map[string]interface{}) []map[string]interface{} {
+	if len(resource.Fields) == 0 {
+		return rows
+	}
+
+	converted := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]interface{}, len(resource.Fields))
+		for column, value := range row {
+			for _, field := range resource.Fields {
+				if strings.EqualFold(field.Column, column) {
+					record[field.FieldName()] = value
+					break
+				}
+			}
+		}
+		converted = append(converted, record)
+	}
+	return converted
+}
+
+// ParseResourceKey 按主键类型解析路径中的 ID
+func ParseResourceKey(resource *config.ResourceConfig, id string) (interface{}, error) {
+	if resource.KeyType == "string" {
+		return id, nil
+	}
+
+	key, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id: %s", id)
+	}
+	return key, nil
+}
+
+// ResourceListQuery 将资源列表请求转换为结构化查询
+//
+// filters 的键为 API 字段名，按等值过滤；sort 为 API 字段名，前缀 - 表示降序，为空时使用资源的默认排序
+func ResourceListQuery(resource *config.ResourceConfig, filters map[string]string, sort string, includeDeleted bool) (*model.StructuredQuery, error) {
+	query := &model.StructuredQuery{
+		Table:          resource.Table,
+		Action:         "select",
+		Fields:         ResourceColumns(resource),
+		IncludeDeleted: includeDeleted,
+	}
+
+	// 等值过滤
+	if len(filters) > 0 {
+		query.Where = make(map[string]interface{}, len(filters))
+		for field, value := range filters {
+			column, ok := ResourceColumn(resource, field)
+			if !ok {
+				return nil, fmt.Errorf("unknown filter field: %s", field)
+			}
+			query.Where[column] = value
+		}
+	}
+
+	// 排序：请求指定的字段优先，否则使用默认排序（默认排序使用列名）
+	if sort != "" {
+		field, order := strings.TrimPrefix(sort, "-"), "asc"
+		if strings.HasPrefix(sort, "-") {
+			order = "desc"
+		}
+		column, ok := ResourceColumn(resource, field)
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field: %s", field)
+		}
+		query.OrderBy = []model.OrderByClause{{Field: column, Order: order}}
+	} else if resource.DefaultSort != "" {
+		parts := strings.Fields(resource.DefaultSort)
+		orderBy := model.OrderByClause{Field: parts[0], Order: "asc"}
+		if len(parts) == 2 {
+			orderBy.Order = strings.ToLower(parts[1])
+		}
+		query.OrderBy = []model.OrderByClause{orderBy}
+	}
+
+	return query, nil
+}
+
+// ResourceKeyQuery 构建按主键读取单条记录的结构化查询，fields 为空时查询所有列
+func ResourceKeyQuery(resource *config.ResourceConfig, key interface{}, fields []string, includeDeleted bool) *model.StructuredQuery {
+	return &model.StructuredQuery{
+		Table:          resource.Table,
+		Action:         "select",
+		Fields:         fields,
+		Where:          map[string]interface{}{resource.GetPrimaryKey(): key},
+		IncludeDeleted: includeDeleted,
+	}
+}
@@ -0,0 +1,139 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+
+	"sql2api/internal/config"
+)
+
+// testResource 重命名了字段的资源
+var testResource = &config.ResourceConfig{
+	Name:       "products",
+	Table:      "items",
+	PrimaryKey: "item_id",
+	Fields: []config.ResourceFieldConfig{
+		{Column: "item_id", Name: "id", ReadOnly: true},
+		{Column: "item_name", Name: "name"},
+		{Column: "price"},
+	},
+	DefaultSort: "item_name DESC",
+}
+
+func TestResourceListQuery(t *testing.T) {
+	builder := NewQueryBuilder("postgres")
+
+	query, err := ResourceListQuery(testResource, map[string]string{"name": "widget"}, "-id", false)
+	if err != nil {
+		t.Fatalf("ResourceListQuery failed: %v", err)
+	}
+	sqlQuery, params, err := builder.BuildStructuredQuery(query)
+	if err != nil {
+		t.Fatalf("Failed to build list query: %v", err)
+	}
+	expected := "SELECT item_id, item_name, price FROM items WHERE item_name = $1 ORDER BY item_id DESC"
+	if sqlQuery != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, sqlQuery)
+	}
+	if params["param_1"] != "widget" {
+		t.Errorf("Expected filter parameter 'widget', got %v", params)
+	}
+
+	// 未指定排序时使用默认排序（列名）
+	query, _ = ResourceListQuery(testResource, nil, "", false)
+	sqlQuery, _, _ = builder.BuildStructuredQuery(query)
+	if expected := "SELECT item_id, item_name, price FROM items ORDER BY item_name DESC"; sqlQuery != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, sqlQuery)
+	}
+
+	// 列名不能代替重命名后的字段名
+	failures := []struct {
+		filters map[string]string
+		sort    string
+	}{
+		{map[string]string{"item_name": "widget"}, ""},
+		{nil, "item_id"},
+		{nil, "-unknown"},
+	}
+	for _, failure := range failures {
+		if _, err := ResourceListQuery(testResource, failure.filters, failure.sort, false); err == nil {
+			t.Errorf("Expected error for filters %v and sort %q", failure.filters, failure.sort)
+		}
+	}
+}
+
+func TestResourceListQuery_UndeclaredFields(t *testing.T) {
+	resource := &config.ResourceConfig{Name: "items", Table: "items"}
+	builder := NewQueryBuilder("mysql")
+
+	query, err := ResourceListQuery(resource, map[string]string{"category": "tools"}, "name", false)
+	if err != nil {
+		t.Fatalf("ResourceListQuery failed: %v", err)
+	}
+	sqlQuery, _, err := builder.BuildStructuredQuery(query)
+	if err != nil {
+		t.Fatalf("Failed to build list query: %v", err)
+	}
+	if expected := "SELECT * FROM items WHERE category = ? ORDER BY name ASC"; sqlQuery != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, sqlQuery)
+	}
+
+	// 未声明字段时只接受标识符形式的列名
+	if _, err := ResourceListQuery(resource, map[string]string{"name; DROP TABLE items": "x"}, "", false); err == nil {
+		t.Error("Expected error for non-identifier filter field")
+	}
+}
+
+func TestResourceKeyQuery(t *testing.T) {
+	builder := NewQueryBuilder("postgres")
+
+	key, err := ParseResourceKey(testResource, "42")
+	if err != nil || key != int64(42) {
+		t.Fatalf("Expected integer key 42, got %v (%v)", key, err)
+	}
+	if _, err := ParseResourceKey(testResource, "abc"); err == nil {
+		t.Error("Expected error for non-integer key")
+	}
+	if key, _ := ParseResourceKey(&config.ResourceConfig{KeyType: "string"}, "abc"); key != "abc" {
+		t.Errorf("Expected string key 'abc', got %v", key)
+	}
+
+	sqlQuery, params, err := builder.BuildStructuredQuery(ResourceKeyQuery(testResource, key, ResourceColumns(testResource), false))
+	if err != nil {
+		t.Fatalf("Failed to build key query: %v", err)
+	}
+	if expected := "SELECT item_id, item_name, price FROM items WHERE item_id = $1"; sqlQuery != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, sqlQuery)
+	}
+	if params["param_1"] != int64(42) {
+		t.Errorf("Expected key parameter 42, got %v", params)
+	}
+
+	// 未指定主键列时使用 id
+	sqlQuery, _, _ = builder.BuildStructuredQuery(ResourceKeyQuery(&config.ResourceConfig{Table: "items"}, "x", nil, false))
+	if expected := "SELECT * FROM items WHERE id = $1"; sqlQuery != expected {
+		t.Errorf("Expected query '%s', got '%s'", expected, sqlQuery)
+	}
+}
+
+func TestResourceRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"ITEM_ID": int64(1), "ITEM_NAME": "widget", "PRICE": 9.5, "SECRET": "x"},
+	}
+
+	got := ResourceRows(testResource, rows)
+	expected := []map[string]interface{}{{"id": int64(1), "name": "widget", "price": 9.5}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// 未声明字段时原样返回
+	plain := &config.ResourceConfig{Table: "items"}
+	if got := ResourceRows(plain, rows); !reflect.DeepEqual(got, rows) {
+		t.Errorf("Expected rows unchanged, got %v", got)
+	}
+
+	if column, ok := ResourceColumn(testResource, "name"); !ok || column != "item_name" {
+		t.Errorf("Expected field 'name' to map to 'item_name', got %q", column)
+	}
+}
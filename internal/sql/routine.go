@@ -0,0 +1,778 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+
+	go_ora "github.com/sijms/go-ora/v2"
+)
+
+var (
+	// ErrRoutineNotAllowed 例程不在允许列表中
+	ErrRoutineNotAllowed = errors.New("routine is not allowed")
+	// ErrInvalidRoutineParams 例程参数错误
+	ErrInvalidRoutineParams = errors.New("invalid routine parameters")
+)
+
+// CallResult 例程调用结果
+type CallResult struct {
+	ReturnValue interface{}              `json:"return_value,omitempty"`
+	OutParams   map[string]interface{}   `json:"out_params,omitempty"`
+	Columns     []string                 `json:"columns,omitempty"`
+	Rows        []map[string]interface{} `json:"rows,omitempty"`
+}
+
+// routineArg 绑定后的例程参数
+type routineArg struct {
+	config.RoutineParamConfig
+	value interface{} // IN / IN OUT 参数的输入值
+}
+
+// isInput 检查是否为输入参数（IN 或 IN OUT）
+func (a routineArg) isInput() bool {
+	return a.Direction != "out"
+}
+
+// isOutput 检查是否为输出参数（OUT 或 IN OUT）
+func (a routineArg) isOutput() bool {
+	return a.Direction == "out" || a.Direction == "inout"
+}
+
+// CallRoutine 调用允许列表中的存储过程或函数
+func (e *SQLEngine) CallRoutine(ctx context.Context, name string, params map[string]interface{}) (*CallResult, error) {
+	routine, ok := e.config.GetRoutine(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRoutineNotAllowed, name)
+	}
+
+	args, err := bindRoutineParams(routine, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 开始监控
+	queryCtx := e.monitor.StartQuery(ctx, "call", e.dbType, routine.DatabaseName())
+	queryCtx.SetParams(params)
+
+	// 创建带超时的上下文
+	execCtx, cancel := context.WithTimeout(queryCtx.Context, time.Duration(e.config.MaxQueryTime)*time.Second)
+	defer cancel()
+
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	// 会话变量和游标都依赖同一个连接
+	conn, err := sqlDB.Conn(execCtx)
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	var result *CallResult
+	switch e.dbType {
+	case "postgres":
+		result, err = e.callPostgres(execCtx, conn, routine, args)
+	case "oracle":
+		result, err = e.callOracle(execCtx, conn, routine, args)
+	case "mysql":
+		result, err = e.callMySQL(execCtx, conn, routine, args)
+	case "sqlserver":
+		result, err = e.callSQLServer(execCtx, conn, routine, args)
+	default:
+		err = fmt.Errorf("stored routines are not supported for %s", e.dbType)
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, err
+	}
+
+	// 例程可能修改数据，调用总是记录审计日志
+	e.auditOperation(ctx, &model.AuditRecord{
+		Table:  name,
+		Action: "call",
+		SQL:    "CALL " + routine.DatabaseName(),
+		Params: params,
+	}, err)
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to call routine: %w", e.errorMapper.MapError(err))
+	}
+
+	// 检查结果集大小限制
+	if len(result.Rows) > e.config.MaxResultSize {
+		err := fmt.Errorf("result set too large: %d rows (max: %d)", len(result.Rows), e.config.MaxResultSize)
+		queryCtx.Finish(false, 0, int64(len(result.Rows)), err)
+		return nil, err
+	}
+
+	queryCtx.Finish(true, 0, int64(len(result.Rows)), nil)
+	return result, nil
+}
+
+// callPostgres 调用 PostgreSQL 函数或存储过程
+// 游标只在事务内有效，因此整个调用在事务中执行
+func (e *SQLEngine) callPostgres(ctx context.Context, conn *sql.Conn, routine *config.RoutineConfig, args []routineArg) (*CallResult, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var placeholders []string
+	var values []interface{}
+	for _, arg := range args {
+		switch {
+		case arg.isInput():
+			values = append(values, arg.value)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(values)))
+		case routine.Kind == "procedure":
+			// 存储过程的 OUT 参数需要以 NULL 占位
+			placeholders = append(placeholders, "NULL")
+		}
+	}
+	call := fmt.Sprintf("%s(%s)", routine.DatabaseName(), strings.Join(placeholders, ", "))
+
+	result := &CallResult{}
+	hasOutput := hasOutputParams(args)
+
+	var query string
+	switch {
+	case routine.Kind == "procedure":
+		// CALL 以单行结果返回 OUT 和 IN OUT 参数
+		query = "CALL " + call
+	case routine.Returns == "rows" || (routine.Returns == "" && hasOutput):
+		// 返回集合的函数，或以记录形式返回 OUT 参数的函数
+		query = "SELECT * FROM " + call
+	default:
+		query = "SELECT " + call
+	}
+
+	rows, err := tx.QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	queryResult, err := e.parseQueryResult(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case routine.Kind == "function" && routine.Returns == "rows":
+		result.Columns, result.Rows = queryResult.Columns, queryResult.Rows
+	case routine.Kind == "function" && routine.Returns != "":
+		if len(queryResult.Rows) > 0 && len(queryResult.Columns) > 0 {
+			result.ReturnValue = queryResult.Rows[0][queryResult.Columns[0]]
+		}
+		if routine.Returns == "cursor" {
+			cursor, _ := result.ReturnValue.(string)
+			if result.Columns, result.Rows, err = e.fetchPostgresCursor(ctx, tx, cursor); err != nil {
+				return nil, err
+			}
+			result.ReturnValue = nil
+		} else {
+			result.ReturnValue = coerceRoutineValue(routine.Returns, result.ReturnValue)
+		}
+	case hasOutput && len(queryResult.Rows) > 0:
+		result.OutParams = make(map[string]interface{})
+		for _, arg := range args {
+			if !arg.isOutput() {
+				continue
+			}
+			value := lookupColumn(queryResult.Rows[0], arg.Name)
+			if arg.Type == "cursor" {
+				cursor, _ := value.(string)
+				_, cursorRows, err := e.fetchPostgresCursor(ctx, tx, cursor)
+				if err != nil {
+					return nil, err
+				}
+				result.OutParams[arg.Name] = cursorRows
+				continue
+			}
+			result.OutParams[arg.Name] = coerceRoutineValue(arg.Type, value)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// fetchPostgresCursor 读取 refcursor 中的所有行
+func (e *SQLEngine) fetchPostgresCursor(ctx context.Context, tx *sql.Tx, cursor string) ([]string, []map[string]interface{}, error) {
+	if cursor == "" {
+		return nil, nil, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, "FETCH ALL FROM "+NewPostgreSQLDialect().QuoteIdentifier(cursor))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	queryResult, err := e.parseQueryResult(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return queryResult.Columns, queryResult.Rows, nil
+}
+
+// callOracle 调用 Oracle 存储过程或函数（可位于 package 中）
+func (e *SQLEngine) callOracle(ctx context.Context, conn *sql.Conn, routine *config.RoutineConfig, args []routineArg) (*CallResult, error) {
+	result := &CallResult{}
+
+	// 管道化表函数通过 TABLE() 查询
+	if routine.Kind == "function" && routine.Returns == "rows" {
+		if hasOutputParams(args) {
+			return nil, errors.New("table functions cannot have output parameters")
+		}
+		var placeholders []string
+		var values []interface{}
+		for _, arg := range args {
+			values = append(values, arg.value)
+			placeholders = append(placeholders, fmt.Sprintf(":%d", len(values)))
+		}
+
+		query := fmt.Sprintf("SELECT * FROM TABLE(%s(%s))", routine.DatabaseName(), strings.Join(placeholders, ", "))
+		rows, err := conn.QueryContext(ctx, query, values...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		queryResult, err := e.parseQueryResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Columns, result.Rows = queryResult.Columns, queryResult.Rows
+		return result, nil
+	}
+
+	if routine.Kind == "function" && routine.Returns == "" {
+		return nil, errors.New("oracle functions must declare a return type")
+	}
+
+	var values []interface{}
+	var returnDest interface{}
+	var placeholders []string
+
+	// 函数返回值绑定为第一个输出参数
+	if routine.Kind == "function" {
+		returnDest = newOracleOutDest(routine.Returns)
+		values = append(values, oracleOut(routine.Returns, returnDest, 0, false))
+	}
+
+	outDests := make(map[string]interface{})
+	for _, arg := range args {
+		var value interface{}
+		switch {
+		case arg.isOutput():
+			dest := newOracleOutDest(arg.Type)
+			if arg.Direction == "inout" {
+				setOutDest(dest, arg.value)
+			}
+			outDests[arg.Name] = dest
+			value = oracleOut(arg.Type, dest, arg.Size, arg.Direction == "inout")
+		default:
+			value = arg.value
+		}
+		values = append(values, value)
+		placeholders = append(placeholders, fmt.Sprintf(":%d", len(values)))
+	}
+
+	call := fmt.Sprintf("%s(%s)", routine.DatabaseName(), strings.Join(placeholders, ", "))
+	query := fmt.Sprintf("BEGIN %s; END;", call)
+	if routine.Kind == "function" {
+		query = fmt.Sprintf("BEGIN :1 := %s; END;", call)
+	}
+
+	if _, err := conn.ExecContext(ctx, query, values...); err != nil {
+		return nil, err
+	}
+
+	if returnDest != nil {
+		if cursor, ok := returnDest.(*go_ora.RefCursor); ok {
+			columns, rows, err := e.readOracleCursor(ctx, conn, cursor)
+			if err != nil {
+				return nil, err
+			}
+			result.Columns, result.Rows = columns, rows
+		} else {
+			result.ReturnValue = readOutDest(returnDest)
+		}
+	}
+
+	if len(outDests) > 0 {
+		result.OutParams = make(map[string]interface{})
+		for _, arg := range args {
+			dest, ok := outDests[arg.Name]
+			if !ok {
+				continue
+			}
+			if cursor, ok := dest.(*go_ora.RefCursor); ok {
+				_, rows, err := e.readOracleCursor(ctx, conn, cursor)
+				if err != nil {
+					return nil, err
+				}
+				result.OutParams[arg.Name] = rows
+				continue
+			}
+			result.OutParams[arg.Name] = readOutDest(dest)
+		}
+	}
+
+	return result, nil
+}
+
+// newOracleOutDest 创建 Oracle 输出参数目标，游标使用 SYS_REFCURSOR
+func newOracleOutDest(paramType string) interface{} {
+	if paramType == "cursor" {
+		return &go_ora.RefCursor{}
+	}
+	return newOutDest(paramType)
+}
+
+// oracleOut 创建 Oracle 输出参数绑定
+func oracleOut(paramType string, dest interface{}, size int, in bool) interface{} {
+	if paramType == "cursor" {
+		return sql.Out{Dest: dest}
+	}
+	// 字符串输出参数需要指定缓冲区大小
+	if paramType == "string" && size <= 0 {
+		size = 4000
+	}
+	return go_ora.Out{Dest: dest, Size: size, In: in}
+}
+
+// readOracleCursor 读取 SYS_REFCURSOR 中的所有行
+func (e *SQLEngine) readOracleCursor(ctx context.Context, conn *sql.Conn, cursor *go_ora.RefCursor) ([]string, []map[string]interface{}, error) {
+	rows, err := go_ora.WrapRefCursor(ctx, conn, cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	queryResult, err := e.parseQueryResult(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return queryResult.Columns, queryResult.Rows, nil
+}
+
+// callMySQL 调用 MySQL 存储过程或函数
+// OUT 参数通过会话变量传递，调用后再查询会话变量取回
+func (e *SQLEngine) callMySQL(ctx context.Context, conn *sql.Conn, routine *config.RoutineConfig, args []routineArg) (*CallResult, error) {
+	for _, arg := range args {
+		if arg.Type == "cursor" {
+			return nil, errors.New("cursor parameters are not supported for mysql")
+		}
+	}
+
+	result := &CallResult{}
+
+	if routine.Kind == "function" {
+		if routine.Returns == "rows" || routine.Returns == "cursor" {
+			return nil, errors.New("mysql functions can only return scalar values")
+		}
+
+		var placeholders []string
+		var values []interface{}
+		for _, arg := range args {
+			placeholders = append(placeholders, "?")
+			values = append(values, arg.value)
+		}
+
+		var value interface{}
+		query := fmt.Sprintf("SELECT %s(%s)", routine.DatabaseName(), strings.Join(placeholders, ", "))
+		if err := conn.QueryRowContext(ctx, query, values...).Scan(&value); err != nil {
+			return nil, err
+		}
+		result.ReturnValue = coerceRoutineValue(routine.Returns, value)
+		return result, nil
+	}
+
+	var placeholders []string
+	var values []interface{}
+	var variables []string
+	for _, arg := range args {
+		if !arg.isOutput() {
+			placeholders = append(placeholders, "?")
+			values = append(values, arg.value)
+			continue
+		}
+
+		variable := "@sql2api_" + arg.Name
+		if arg.Direction == "inout" {
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET %s = ?", variable), arg.value); err != nil {
+				return nil, err
+			}
+		}
+		placeholders = append(placeholders, variable)
+		variables = append(variables, variable)
+	}
+
+	query := fmt.Sprintf("CALL %s(%s)", routine.DatabaseName(), strings.Join(placeholders, ", "))
+	rows, err := conn.QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 存储过程的第一个结果集作为返回数据
+	queryResult, err := e.parseQueryResult(rows)
+	if err == nil {
+		for rows.NextResultSet() {
+		}
+		err = rows.Err()
+	}
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(queryResult.Columns) > 0 {
+		result.Columns, result.Rows = queryResult.Columns, queryResult.Rows
+	}
+
+	if len(variables) > 0 {
+		outValues := make([]interface{}, len(variables))
+		scanArgs := make([]interface{}, len(variables))
+		for i := range outValues {
+			scanArgs[i] = &outValues[i]
+		}
+		if err := conn.QueryRowContext(ctx, "SELECT "+strings.Join(variables, ", ")).Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+
+		result.OutParams = make(map[string]interface{})
+		i := 0
+		for _, arg := range args {
+			if arg.isOutput() {
+				result.OutParams[arg.Name] = coerceRoutineValue(arg.Type, outValues[i])
+				i++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// callSQLServer 调用 SQL Server 存储过程或函数
+func (e *SQLEngine) callSQLServer(ctx context.Context, conn *sql.Conn, routine *config.RoutineConfig, args []routineArg) (*CallResult, error) {
+	for _, arg := range args {
+		if arg.Type == "cursor" {
+			return nil, errors.New("cursor parameters are not supported for sqlserver")
+		}
+	}
+
+	result := &CallResult{}
+
+	if routine.Kind == "function" {
+		if routine.Returns == "cursor" {
+			return nil, errors.New("sqlserver functions cannot return cursors")
+		}
+
+		var placeholders []string
+		var values []interface{}
+		for _, arg := range args {
+			values = append(values, arg.value)
+			placeholders = append(placeholders, fmt.Sprintf("@p%d", len(values)))
+		}
+		call := fmt.Sprintf("%s(%s)", routine.DatabaseName(), strings.Join(placeholders, ", "))
+
+		// 表值函数
+		if routine.Returns == "rows" {
+			rows, err := conn.QueryContext(ctx, "SELECT * FROM "+call, values...)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+
+			queryResult, err := e.parseQueryResult(rows)
+			if err != nil {
+				return nil, err
+			}
+			result.Columns, result.Rows = queryResult.Columns, queryResult.Rows
+			return result, nil
+		}
+
+		var value interface{}
+		if err := conn.QueryRowContext(ctx, "SELECT "+call, values...).Scan(&value); err != nil {
+			return nil, err
+		}
+		result.ReturnValue = coerceRoutineValue(routine.Returns, value)
+		return result, nil
+	}
+
+	// 查询文本仅为过程名时，驱动以 RPC 方式调用存储过程并支持命名输出参数
+	var values []interface{}
+	outDests := make(map[string]interface{})
+	for _, arg := range args {
+		var value interface{} = arg.value
+		if arg.isOutput() {
+			dest := newOutDest(arg.Type)
+			if arg.Direction == "inout" {
+				setOutDest(dest, arg.value)
+			}
+			outDests[arg.Name] = dest
+			value = sql.Out{Dest: dest, In: arg.Direction == "inout"}
+		}
+		values = append(values, sql.Named(arg.Name, value))
+	}
+
+	rows, err := conn.QueryContext(ctx, routine.DatabaseName(), values...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 输出参数在读取完所有结果集后才可用
+	queryResult, err := e.parseQueryResult(rows)
+	if err == nil {
+		for rows.NextResultSet() {
+		}
+		err = rows.Err()
+	}
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(queryResult.Columns) > 0 {
+		result.Columns, result.Rows = queryResult.Columns, queryResult.Rows
+	}
+
+	if len(outDests) > 0 {
+		result.OutParams = make(map[string]interface{})
+		for name, dest := range outDests {
+			result.OutParams[name] = readOutDest(dest)
+		}
+	}
+
+	return result, nil
+}
+
+// ===== 参数绑定 =====
+
+// bindRoutineParams 按声明顺序绑定并转换例程参数
+func bindRoutineParams(routine *config.RoutineConfig, params map[string]interface{}) ([]routineArg, error) {
+	declared := make(map[string]bool, len(routine.Params))
+	args := make([]routineArg, 0, len(routine.Params))
+
+	for _, param := range routine.Params {
+		declared[param.Name] = true
+		if param.Direction == "" {
+			param.Direction = "in"
+		}
+		arg := routineArg{RoutineParamConfig: param}
+
+		value, provided := params[param.Name]
+		switch {
+		case param.Direction == "out":
+			if provided {
+				return nil, fmt.Errorf("%w: %s is an output parameter", ErrInvalidRoutineParams, param.Name)
+			}
+		case !provided:
+			return nil, fmt.Errorf("%w: missing parameter %s", ErrInvalidRoutineParams, param.Name)
+		default:
+			converted, err := convertRoutineValue(param.Type, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRoutineParams, param.Name, err)
+			}
+			arg.value = converted
+		}
+
+		args = append(args, arg)
+	}
+
+	for name := range params {
+		if !declared[name] {
+			return nil, fmt.Errorf("%w: unknown parameter %s", ErrInvalidRoutineParams, name)
+		}
+	}
+
+	return args, nil
+}
+
+// convertRoutineValue 将 JSON 输入值转换为声明的参数类型
+func convertRoutineValue(paramType string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch paramType {
+	case "string":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprint(value), nil
+
+	case "integer":
+		switch v := value.(type) {
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("expected integer, got %v", v)
+			}
+			return int64(v), nil
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case string:
+			return strconv.ParseInt(v, 10, 64)
+		}
+
+	case "number":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+
+	case "date", "timestamp":
+		if s, ok := value.(string); ok {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+				if t, err := time.Parse(layout, s); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("invalid %s value: %s", paramType, s)
+		}
+	}
+
+	return nil, fmt.Errorf("expected %s, got %T", paramType, value)
+}
+
+// coerceRoutineValue 将驱动返回的通用值转换为声明的类型
+func coerceRoutineValue(paramType string, value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	switch paramType {
+	case "integer":
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(s); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// newOutDest 按声明类型创建输出参数目标
+func newOutDest(paramType string) interface{} {
+	switch paramType {
+	case "integer":
+		return &sql.NullInt64{}
+	case "number":
+		return &sql.NullFloat64{}
+	case "boolean":
+		return &sql.NullBool{}
+	case "date", "timestamp":
+		return &sql.NullTime{}
+	default:
+		return &sql.NullString{}
+	}
+}
+
+// setOutDest 为 IN OUT 参数设置输入值
+func setOutDest(dest interface{}, value interface{}) {
+	if value == nil {
+		return
+	}
+	switch d := dest.(type) {
+	case *sql.NullString:
+		d.String, d.Valid = value.(string)
+	case *sql.NullInt64:
+		d.Int64, d.Valid = value.(int64)
+	case *sql.NullFloat64:
+		d.Float64, d.Valid = value.(float64)
+	case *sql.NullBool:
+		d.Bool, d.Valid = value.(bool)
+	case *sql.NullTime:
+		d.Time, d.Valid = value.(time.Time)
+	}
+}
+
+// readOutDest 读取输出参数值，NULL 返回 nil
+func readOutDest(dest interface{}) interface{} {
+	switch d := dest.(type) {
+	case *sql.NullString:
+		if d.Valid {
+			return d.String
+		}
+	case *sql.NullInt64:
+		if d.Valid {
+			return d.Int64
+		}
+	case *sql.NullFloat64:
+		if d.Valid {
+			return d.Float64
+		}
+	case *sql.NullBool:
+		if d.Valid {
+			return d.Bool
+		}
+	case *sql.NullTime:
+		if d.Valid {
+			return d.Time
+		}
+	}
+	return nil
+}
+
+// hasOutputParams 检查是否存在输出参数
+func hasOutputParams(args []routineArg) bool {
+	for _, arg := range args {
+		if arg.isOutput() {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupColumn 按列名查找值（忽略大小写，PostgreSQL 会将未加引号的名称转为小写）
+func lookupColumn(row map[string]interface{}, name string) interface{} {
+	if value, ok := row[name]; ok {
+		return value
+	}
+	for column, value := range row {
+		if strings.EqualFold(column, name) {
+			return value
+		}
+	}
+	return nil
+}
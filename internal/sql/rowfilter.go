@@ -0,0 +1,379 @@
+package sql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RowFilter 在内存中按结构化条件匹配行（用于变更订阅）
+// 条件格式和运算符与 QueryBuilder 的 WHERE 条件相同，NULL 比较遵循 SQL 三值逻辑
+type RowFilter struct {
+	where   map[string]interface{}
+	columns []string
+}
+
+// NewRowFilter 验证条件并创建行过滤器
+func NewRowFilter(where map[string]interface{}) (*RowFilter, error) {
+	// 条件结构和运算符的验证与生成 SQL 时相同
+	if _, _, err := NewQueryBuilder("postgres").buildWhereClause(where, 1); err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]bool)
+	collectFilterColumns(where, columns)
+
+	filter := &RowFilter{where: where}
+	for column := range columns {
+		filter.columns = append(filter.columns, column)
+	}
+	sort.Strings(filter.columns)
+	return filter, nil
+}
+
+// Columns 获取条件中引用的列
+func (f *RowFilter) Columns() []string {
+	return f.columns
+}
+
+// Covers 检查行是否包含条件引用的所有列
+func (f *RowFilter) Covers(row map[string]interface{}) bool {
+	if row == nil {
+		return false
+	}
+	for _, column := range f.columns {
+		if _, ok := row[column]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Match 检查行是否满足条件，结果未知（与 NULL 比较）视为不满足
+func (f *RowFilter) Match(row map[string]interface{}) bool {
+	return matchConditions(f.where, row) == truthTrue
+}
+
+// collectFilterColumns 收集条件中引用的列
+func collectFilterColumns(conditions map[string]interface{}, columns map[string]bool) {
+	for key, value := range conditions {
+		switch key {
+		case "$not":
+			if condition, ok := value.(map[string]interface{}); ok {
+				collectFilterColumns(condition, columns)
+			}
+		case "$and", "$or":
+			for _, condition := range conditionList(value) {
+				collectFilterColumns(condition, columns)
+			}
+		default:
+			columns[key] = true
+		}
+	}
+}
+
+// conditionList 获取 $and、$or 的条件列表（结构已在创建过滤器时验证）
+func conditionList(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case []map[string]interface{}:
+		return v
+	case []interface{}:
+		conditions := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			if condition, ok := item.(map[string]interface{}); ok {
+				conditions = append(conditions, condition)
+			}
+		}
+		return conditions
+	}
+	return nil
+}
+
+// truth 三值逻辑的真值
+type truth int8
+
+const (
+	truthFalse truth = iota
+	truthTrue
+	truthUnknown
+)
+
+// truthOf 将布尔值转换为真值
+func truthOf(b bool) truth {
+	if b {
+		return truthTrue
+	}
+	return truthFalse
+}
+
+// and 三值逻辑与
+func (t truth) and(other truth) truth {
+	if t == truthFalse || other == truthFalse {
+		return truthFalse
+	}
+	if t == truthUnknown || other == truthUnknown {
+		return truthUnknown
+	}
+	return truthTrue
+}
+
+// or 三值逻辑或
+func (t truth) or(other truth) truth {
+	if t == truthTrue || other == truthTrue {
+		return truthTrue
+	}
+	if t == truthUnknown || other == truthUnknown {
+		return truthUnknown
+	}
+	return truthFalse
+}
+
+// not 三值逻辑非
+func (t truth) not() truth {
+	switch t {
+	case truthTrue:
+		return truthFalse
+	case truthFalse:
+		return truthTrue
+	}
+	return truthUnknown
+}
+
+// matchConditions 计算条件映射（各条件以 AND 连接）
+func matchConditions(conditions map[string]interface{}, row map[string]interface{}) truth {
+	result := truthTrue
+	for key, value := range conditions {
+		switch key {
+		case "$not":
+			condition, _ := value.(map[string]interface{})
+			result = result.and(matchConditions(condition, row).not())
+		case "$and":
+			for _, condition := range conditionList(value) {
+				result = result.and(matchConditions(condition, row))
+			}
+		case "$or":
+			matched := truthFalse
+			for _, condition := range conditionList(value) {
+				matched = matched.or(matchConditions(condition, row))
+			}
+			result = result.and(matched)
+		default:
+			operators, ok := asOperatorMap(value)
+			if !ok {
+				result = result.and(compareTruth(row[key], value, func(c int) bool { return c == 0 }))
+				continue
+			}
+			for operator, operand := range operators {
+				result = result.and(matchOperator(row[key], operator, operand))
+			}
+		}
+		if result == truthFalse {
+			return truthFalse
+		}
+	}
+	return result
+}
+
+// matchOperator 计算单个运算符条件
+func matchOperator(value interface{}, operator string, operand interface{}) truth {
+	switch operator {
+	case "is_null":
+		isNull, _ := operand.(bool)
+		return truthOf((value == nil) == isNull)
+	case "eq":
+		return compareTruth(value, operand, func(c int) bool { return c == 0 })
+	case "neq":
+		return compareTruth(value, operand, func(c int) bool { return c != 0 })
+	case "gt":
+		return compareTruth(value, operand, func(c int) bool { return c > 0 })
+	case "gte":
+		return compareTruth(value, operand, func(c int) bool { return c >= 0 })
+	case "lt":
+		return compareTruth(value, operand, func(c int) bool { return c < 0 })
+	case "lte":
+		return compareTruth(value, operand, func(c int) bool { return c <= 0 })
+	case "in", "nin":
+		if value == nil {
+			return truthUnknown
+		}
+		values, _ := asValueList(operand)
+		result := truthFalse
+		for _, item := range values {
+			result = result.or(compareTruth(value, item, func(c int) bool { return c == 0 }))
+		}
+		if operator == "nin" {
+			return result.not()
+		}
+		return result
+	case "like", "nlike", "ilike":
+		pattern, ok := operand.(string)
+		if value == nil || !ok {
+			return truthUnknown
+		}
+		matched := likeRegexp(pattern, operator == "ilike").MatchString(textValue(value))
+		if operator == "nlike" {
+			matched = !matched
+		}
+		return truthOf(matched)
+	case "contains", "startswith", "endswith":
+		text, ok := operand.(string)
+		if value == nil || !ok {
+			return truthUnknown
+		}
+		switch operator {
+		case "contains":
+			return truthOf(strings.Contains(textValue(value), text))
+		case "startswith":
+			return truthOf(strings.HasPrefix(textValue(value), text))
+		default:
+			return truthOf(strings.HasSuffix(textValue(value), text))
+		}
+	}
+	return truthUnknown
+}
+
+// compareTruth 比较两个值，任一值为 NULL 或类型不可比较时结果未知
+func compareTruth(value, operand interface{}, accept func(int) bool) truth {
+	c, ok := compareValues(value, operand)
+	if !ok {
+		return truthUnknown
+	}
+	return truthOf(accept(c))
+}
+
+// compareValues 比较行中的值和条件中的值
+// 数字统一按 float64 比较（布尔条件值按 0/1），时间与 RFC 3339 或日期字符串比较，其余按字符串比较
+func compareValues(value, operand interface{}) (int, bool) {
+	if value == nil || operand == nil {
+		return 0, false
+	}
+
+	if a, ok := numberValue(value); ok {
+		if b, ok := numberValue(operand); ok {
+			return compareOrdered(a, b), true
+		}
+		// SQLite、MySQL 等以整数保存布尔值
+		if b, ok := operand.(bool); ok {
+			return compareOrdered(a, float64(boolRank(b))), true
+		}
+		if s, ok := operand.(string); ok {
+			if b, err := strconv.ParseFloat(s, 64); err == nil {
+				return compareOrdered(a, b), true
+			}
+		}
+		return 0, false
+	}
+
+	if a, ok := value.(time.Time); ok {
+		b, ok := timeValue(operand)
+		if !ok {
+			return 0, false
+		}
+		return a.Compare(b), true
+	}
+
+	if a, ok := value.(bool); ok {
+		b, ok := operand.(bool)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(boolRank(a), boolRank(b)), true
+	}
+
+	a := textValue(value)
+	if b, ok := numberValue(operand); ok {
+		if n, err := strconv.ParseFloat(a, 64); err == nil {
+			return compareOrdered(n, b), true
+		}
+		return 0, false
+	}
+	return strings.Compare(a, textValue(operand)), true
+}
+
+// numberValue 将数字类型转换为 float64
+func numberValue(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		return f, !math.IsNaN(f)
+	}
+	return 0, false
+}
+
+// timeValue 解析条件中的时间值
+func timeValue(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// textValue 获取值的文本形式
+func textValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
+
+// boolRank 布尔值排序（false < true）
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// compareOrdered 比较可排序的值
+func compareOrdered[T int | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// likeRegexp 将 LIKE 模式转换为正则表达式（% 匹配任意字符串，_ 匹配单个字符）
+func likeRegexp(pattern string, ignoreCase bool) *regexp.Regexp {
+	var sb strings.Builder
+	if ignoreCase {
+		sb.WriteString("(?i)")
+	}
+	sb.WriteString("(?s)^")
+	for _, ch := range pattern {
+		switch ch {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
@@ -0,0 +1,138 @@
+package sql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRowFilter(t *testing.T) {
+	row := map[string]interface{}{
+		"id":      int64(7),
+		"name":    "Widget Kit",
+		"price":   12.5,
+		"active":  int64(1),
+		"deleted": nil,
+		"created": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		where    map[string]interface{}
+		expected bool
+	}{
+		{map[string]interface{}{"id": float64(7)}, true},
+		{map[string]interface{}{"price": map[string]interface{}{"gt": float64(10), "lte": "12.5"}}, true},
+		{map[string]interface{}{"active": true}, true},
+		{map[string]interface{}{"name": map[string]interface{}{"ilike": "widget%"}}, true},
+		{map[string]interface{}{"name": map[string]interface{}{"like": "widget%"}}, false},
+		{map[string]interface{}{"name": map[string]interface{}{"contains": "t K"}}, true},
+		{map[string]interface{}{"id": map[string]interface{}{"in": []interface{}{float64(1), float64(7)}}}, true},
+		{map[string]interface{}{"created": map[string]interface{}{"gte": "2024-01-01"}}, true},
+		{map[string]interface{}{"deleted": map[string]interface{}{"is_null": true}}, true},
+		{map[string]interface{}{"$or": []interface{}{
+			map[string]interface{}{"id": float64(1)},
+			map[string]interface{}{"name": map[string]interface{}{"startswith": "Wid"}},
+		}}, true},
+		// 与 NULL 比较的结果未知，取反后仍不满足
+		{map[string]interface{}{"deleted": map[string]interface{}{"neq": "x"}}, false},
+		{map[string]interface{}{"$not": map[string]interface{}{"deleted": "x"}}, false},
+		{map[string]interface{}{"$not": map[string]interface{}{"id": float64(8)}}, true},
+	}
+
+	for _, tt := range tests {
+		filter, err := NewRowFilter(tt.where)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.where, err)
+			continue
+		}
+		if got := filter.Match(row); got != tt.expected {
+			t.Errorf("%v: expected %v, got %v", tt.where, tt.expected, got)
+		}
+	}
+
+	if _, err := NewRowFilter(map[string]interface{}{"id": map[string]interface{}{"between": 1}}); err == nil {
+		t.Error("Expected error for unsupported operator")
+	}
+}
+
+func TestChangeHub(t *testing.T) {
+	hub := NewChangeHub()
+
+	filter, err := NewRowFilter(map[string]interface{}{"status": "active"})
+	if err != nil {
+		t.Fatalf("NewRowFilter failed: %v", err)
+	}
+	filtered := hub.Subscribe("Items", filter, 4)
+	all := hub.Subscribe("items", nil, 1)
+	other := hub.Subscribe("logs", nil, 1)
+
+	hub.Publish(&ChangeEvent{Table: "items", Operation: ChangeInsert, Row: map[string]interface{}{"id": 1, "status": "draft"}})
+	hub.Publish(&ChangeEvent{Table: "items", Operation: ChangeUpdate, Row: map[string]interface{}{"id": 1, "status": "active"}})
+	hub.Publish(&ChangeEvent{Table: "items", Operation: ChangeDelete, AffectedRows: 3})
+
+	// 不满足条件的行被过滤，没有行数据的事件以不含行数据的形式投递
+	if event := <-filtered.Events(); event.Operation != ChangeUpdate || event.ID != 2 {
+		t.Errorf("Unexpected first event: %+v", event)
+	}
+	if event := <-filtered.Events(); event.Operation != ChangeDelete || event.Row != nil {
+		t.Errorf("Unexpected second event: %+v", event)
+	}
+
+	// 缓冲已满的订阅被关闭
+	<-all.Done()
+	if err := all.Err(); err != ErrSubscriptionOverflow {
+		t.Errorf("Expected overflow, got %v", err)
+	}
+
+	if len(other.Events()) != 0 {
+		t.Error("Expected no events for other table")
+	}
+
+	hub.Close()
+	<-other.Done()
+	if other.Err() != ErrSubscriptionClosed {
+		t.Errorf("Expected closed, got %v", other.Err())
+	}
+}
+
+func TestWriteTarget(t *testing.T) {
+	tests := []struct {
+		query     string
+		operation string
+		table     string
+	}{
+		{"INSERT INTO items (name) VALUES ($1)", ChangeInsert, "items"},
+		{"INSERT INTO `items`(name) VALUES (?) ON DUPLICATE KEY UPDATE name = VALUES(name)", ChangeInsert, "items"},
+		{"UPDATE public.items SET name = $1 WHERE id = $2", ChangeUpdate, "public.items"},
+		{"delete from [items] where id = @p1", ChangeDelete, "items"},
+		{"WITH old AS (SELECT id FROM logs) DELETE FROM logs WHERE id IN (SELECT id FROM old)", ChangeDelete, "logs"},
+	}
+
+	for _, tt := range tests {
+		operation, table := writeTarget(tt.query)
+		if operation != tt.operation || table != tt.table {
+			t.Errorf("%s: expected %s %s, got %s %s", tt.query, tt.operation, tt.table, operation, table)
+		}
+	}
+}
+
+func TestEqualityKeys(t *testing.T) {
+	keys := EqualityKeys(map[string]interface{}{
+		"tenant": "a",
+		"id":     map[string]interface{}{"in": []interface{}{float64(1), float64(2)}},
+		"age":    map[string]interface{}{"gt": float64(3)},
+		"$or":    []interface{}{map[string]interface{}{"x": float64(1)}},
+	})
+	if len(keys) != 2 || keys[0]["id"] != float64(1) || keys[1]["id"] != float64(2) || keys[1]["tenant"] != "a" {
+		t.Errorf("Unexpected keys: %v", keys)
+	}
+	if _, ok := keys[0]["age"]; ok {
+		t.Errorf("Expected range condition to be ignored: %v", keys)
+	}
+
+	if keys := EqualityKeys(map[string]interface{}{"id": map[string]interface{}{"eq": float64(5)}}); len(keys) != 1 || keys[0]["id"] != float64(5) {
+		t.Errorf("Unexpected keys for eq: %v", keys)
+	}
+	if keys := EqualityKeys(map[string]interface{}{"id": map[string]interface{}{"lt": float64(5)}}); keys != nil {
+		t.Errorf("Expected no keys, got %v", keys)
+	}
+}
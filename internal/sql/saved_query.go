@@ -0,0 +1,233 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sql2api/internal/config"
+)
+
+// ErrInvalidQueryParams 命名查询参数错误
+var ErrInvalidQueryParams = errors.New("invalid query parameters")
+
+// SavedQueryResult 命名查询执行结果，查询语句填充 Query，写语句填充 Exec
+type SavedQueryResult struct {
+	Query *QueryResult
+	Exec  *ExecuteResult
+}
+
+// ExecuteSavedQuery 绑定参数并执行命名查询
+// 命名查询由管理员定义，因此写语句不受 enable_raw_sql 限制
+func (e *SQLEngine) ExecuteSavedQuery(ctx context.Context, saved *config.SavedQueryConfig, input map[string]interface{}) (*SavedQueryResult, error) {
+	values, err := BindQueryParams(saved.Params, input)
+	if err != nil {
+		return nil, err
+	}
+
+	query, params, err := CompileNamedQuery(saved.SQL, e.dbType, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.security.IsSelectQuery(query) {
+		result, err := e.ExecuteQuery(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		return &SavedQueryResult{Query: result}, nil
+	}
+
+	result, err := e.ExecuteStatement(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SavedQueryResult{Exec: result}, nil
+}
+
+// CompileNamedQuery 将 :name 形式的命名参数编译为目标数据库的位置占位符
+// 返回的参数映射使用 param_N 键，与 QueryBuilder 生成的参数一致；
+// 字符串、引号标识符、注释和 PostgreSQL 的 :: 类型转换不会被替换
+func CompileNamedQuery(query, dbType string, values map[string]interface{}) (string, map[string]interface{}, error) {
+	builder := NewQueryBuilder(dbType)
+	params := make(map[string]interface{})
+
+	var sb strings.Builder
+	index := 1
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := skipQuoted(query, i, ch)
+			sb.WriteString(query[i:end])
+			i = end
+
+		case ch == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			sb.WriteString(query[i : i+end])
+			i += end
+
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query)
+			} else {
+				end = i + 2 + end + 2
+			}
+			sb.WriteString(query[i:end])
+			i = end
+
+		case ch == ':' && strings.HasPrefix(query[i:], "::"):
+			sb.WriteString("::")
+			i += 2
+
+		case ch == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNamePart(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+
+			value, ok := values[name]
+			if !ok {
+				return "", nil, fmt.Errorf("%w: undefined parameter :%s", ErrInvalidQueryParams, name)
+			}
+
+			// 每次出现都绑定一个新的位置参数，兼容只支持 ? 占位符的数据库
+			params[fmt.Sprintf("param_%d", index)] = value
+			sb.WriteString(builder.getParameterPlaceholder(index))
+			index++
+			i = end
+
+		default:
+			sb.WriteByte(ch)
+			i++
+		}
+	}
+
+	return sb.String(), params, nil
+}
+
+// skipQuoted 返回引号内容结束后的位置，连续两个引号视为转义
+func skipQuoted(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+// isNameStart 检查是否为参数名的首字符
+func isNameStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// isNamePart 检查是否为参数名的后续字符
+func isNamePart(ch byte) bool {
+	return isNameStart(ch) || (ch >= '0' && ch <= '9')
+}
+
+// BindQueryParams 按参数定义填充默认值、转换类型并校验约束
+// 所有参数错误会一并返回
+func BindQueryParams(defs []config.QueryParamConfig, input map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(defs))
+	declared := make(map[string]bool, len(defs))
+	var problems []string
+
+	for _, def := range defs {
+		declared[def.Name] = true
+
+		raw, provided := input[def.Name]
+		if !provided || raw == nil {
+			if def.Required {
+				problems = append(problems, fmt.Sprintf("%s: is required", def.Name))
+				continue
+			}
+			raw = def.Default
+		}
+
+		value, err := convertRoutineValue(def.Type, raw)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", def.Name, err))
+			continue
+		}
+		if value != nil {
+			if err := checkQueryParam(def, value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", def.Name, err))
+				continue
+			}
+		}
+		values[def.Name] = value
+	}
+
+	for name := range input {
+		if !declared[name] {
+			problems = append(problems, fmt.Sprintf("%s: unknown parameter", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidQueryParams, strings.Join(problems, "; "))
+	}
+	return values, nil
+}
+
+// checkQueryParam 校验参数约束
+func checkQueryParam(def config.QueryParamConfig, value interface{}) error {
+	var number float64
+	isNumber := false
+	switch v := value.(type) {
+	case int64:
+		number, isNumber = float64(v), true
+	case float64:
+		number, isNumber = v, true
+	}
+
+	if isNumber {
+		if def.Min != nil && number < *def.Min {
+			return fmt.Errorf("must be >= %v", *def.Min)
+		}
+		if def.Max != nil && number > *def.Max {
+			return fmt.Errorf("must be <= %v", *def.Max)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		if def.MaxLength > 0 && len([]rune(s)) > def.MaxLength {
+			return fmt.Errorf("must be at most %d characters", def.MaxLength)
+		}
+		if def.Pattern != "" {
+			matched, err := regexp.MatchString(def.Pattern, s)
+			if err != nil || !matched {
+				return fmt.Errorf("does not match pattern %s", def.Pattern)
+			}
+		}
+	}
+
+	if len(def.Enum) > 0 {
+		for _, allowed := range def.Enum {
+			converted, err := convertRoutineValue(def.Type, allowed)
+			if err == nil && reflect.DeepEqual(converted, value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %v", def.Enum)
+	}
+
+	return nil
+}
@@ -0,0 +1,492 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTableNotAllowed 表不在允许列表中
+	ErrTableNotAllowed = errors.New("table is not allowed")
+	// ErrTableNotFound 表在数据库中不存在
+	ErrTableNotFound = errors.New("table not found")
+)
+
+// TableSchema 表结构信息
+type TableSchema struct {
+	Name        string           `json:"name"`
+	Comment     string           `json:"comment,omitempty"`
+	Columns     []ColumnSchema   `json:"columns"`
+	PrimaryKey  []string         `json:"primary_key"`
+	ForeignKeys []ForeignKeyInfo `json:"foreign_keys"`
+	Indexes     []IndexInfo      `json:"indexes"`
+}
+
+// ColumnSchema 列结构信息
+type ColumnSchema struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Nullable  bool     `json:"nullable"`
+	Default   *string  `json:"default,omitempty"`
+	Length    *int64   `json:"length,omitempty"`
+	Precision *int64   `json:"precision,omitempty"` // 精确数值类型的精度
+	Scale     *int64   `json:"scale,omitempty"`     // 精确数值类型的小数位数
+	Enum      []string `json:"enum,omitempty"`      // 枚举类型或 CHECK 约束限定的取值
+	Generated bool     `json:"generated,omitempty"` // 自增、标识列或计算列，插入时由数据库生成
+	Comment   string   `json:"comment,omitempty"`
+}
+
+// ForeignKeyInfo 外键信息
+type ForeignKeyInfo struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+}
+
+// IndexInfo 索引信息
+type IndexInfo struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+}
+
+// schemaQueries 读取表结构的目录查询，参数依次为 schema 和表名
+// 各查询返回的列：
+//   - comment:     表注释（表不存在时无结果行）
+//   - columns:     列名、类型、是否可空（YES/NO）、默认值、长度、注释、精度、小数位数、
+//     枚举取值（JSON 数组）、是否由数据库生成（YES/NO）
+//   - primaryKey:  主键列名
+//   - foreignKeys: 约束名、列名、引用表、引用列
+//   - indexes:     索引名、列名、是否唯一（YES/NO）
+//   - checks:      CHECK 约束表达式（checksFromDDL 时为建表语句）
+type schemaQueries struct {
+	comment       string
+	columns       string
+	primaryKey    string
+	foreignKeys   string
+	indexes       string
+	checks        string
+	checksFromDDL bool // checks 返回建表语句，需要从中提取 CHECK 约束
+	withSchema    bool // 查询是否接受 schema 参数
+}
+
+// schemaQueriesByType 各数据库的目录查询
+var schemaQueriesByType = map[string]schemaQueries{
+	"postgres": {
+		comment: `SELECT COALESCE(obj_description(c.oid, 'pg_class'), '')
+			FROM pg_catalog.pg_class c
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+			AND c.relkind IN ('r', 'v', 'm', 'p')`,
+		columns: `SELECT a.attname, format_type(a.atttypid, a.atttypmod),
+			CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END,
+			pg_get_expr(d.adbin, d.adrelid),
+			CASE WHEN t.typname IN ('varchar', 'bpchar') AND a.atttypmod > 4 THEN a.atttypmod - 4 END,
+			col_description(a.attrelid, a.attnum),
+			CASE WHEN t.typname = 'numeric' AND a.atttypmod > 4 THEN ((a.atttypmod - 4) >> 16) & 65535 END,
+			CASE WHEN t.typname = 'numeric' AND a.atttypmod > 4 THEN (a.atttypmod - 4) & 65535 END,
+			(SELECT json_agg(e.enumlabel ORDER BY e.enumsortorder)::text
+				FROM pg_catalog.pg_enum e WHERE e.enumtypid = a.atttypid),
+			CASE WHEN a.attidentity <> '' OR a.attgenerated <> '' THEN 'YES' ELSE 'NO' END
+			FROM pg_catalog.pg_attribute a
+			JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			JOIN pg_catalog.pg_type t ON t.oid = a.atttypid
+			LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+			WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+			AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum`,
+		primaryKey: `SELECT a.attname
+			FROM pg_catalog.pg_constraint con
+			JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord) ON true
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+			WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+			AND con.contype = 'p'
+			ORDER BY k.ord`,
+		foreignKeys: `SELECT con.conname, a.attname, rc.relname, ra.attname
+			FROM pg_catalog.pg_constraint con
+			JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			JOIN pg_catalog.pg_class rc ON rc.oid = con.confrelid
+			JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord) ON true
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+			JOIN pg_catalog.pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+			WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+			AND con.contype = 'f'
+			ORDER BY con.conname, k.ord`,
+		indexes: `SELECT ic.relname, a.attname, CASE WHEN i.indisunique THEN 'YES' ELSE 'NO' END
+			FROM pg_catalog.pg_index i
+			JOIN pg_catalog.pg_class c ON c.oid = i.indrelid
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			JOIN pg_catalog.pg_class ic ON ic.oid = i.indexrelid
+			JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord) ON true
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+			WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+			ORDER BY ic.relname, k.ord`,
+		checks: `SELECT pg_get_constraintdef(con.oid)
+			FROM pg_catalog.pg_constraint con
+			JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+			AND con.contype = 'c'`,
+		withSchema: true,
+	},
+	"oracle": {
+		comment: `SELECT comments FROM all_tab_comments
+			WHERE owner = NVL(UPPER(:1), USER) AND table_name = UPPER(:2)`,
+		columns: `SELECT c.column_name, c.data_type, CASE WHEN c.nullable = 'Y' THEN 'YES' ELSE 'NO' END,
+			c.data_default, CASE WHEN c.char_length > 0 THEN c.char_length END, cc.comments,
+			CASE WHEN c.data_type = 'NUMBER' THEN c.data_precision END,
+			CASE WHEN c.data_type = 'NUMBER' THEN c.data_scale END,
+			NULL, CASE WHEN c.identity_column = 'YES' THEN 'YES' ELSE 'NO' END
+			FROM all_tab_columns c
+			LEFT JOIN all_col_comments cc
+			ON cc.owner = c.owner AND cc.table_name = c.table_name AND cc.column_name = c.column_name
+			WHERE c.owner = NVL(UPPER(:1), USER) AND c.table_name = UPPER(:2)
+			ORDER BY c.column_id`,
+		primaryKey: `SELECT cc.column_name
+			FROM all_constraints c
+			JOIN all_cons_columns cc ON cc.owner = c.owner AND cc.constraint_name = c.constraint_name
+			WHERE c.owner = NVL(UPPER(:1), USER) AND c.table_name = UPPER(:2)
+			AND c.constraint_type = 'P'
+			ORDER BY cc.position`,
+		foreignKeys: `SELECT c.constraint_name, cc.column_name, rc.table_name, rcc.column_name
+			FROM all_constraints c
+			JOIN all_cons_columns cc ON cc.owner = c.owner AND cc.constraint_name = c.constraint_name
+			JOIN all_constraints rc ON rc.owner = c.r_owner AND rc.constraint_name = c.r_constraint_name
+			JOIN all_cons_columns rcc
+			ON rcc.owner = rc.owner AND rcc.constraint_name = rc.constraint_name AND rcc.position = cc.position
+			WHERE c.owner = NVL(UPPER(:1), USER) AND c.table_name = UPPER(:2)
+			AND c.constraint_type = 'R'
+			ORDER BY c.constraint_name, cc.position`,
+		indexes: `SELECT i.index_name, ic.column_name, CASE WHEN i.uniqueness = 'UNIQUE' THEN 'YES' ELSE 'NO' END
+			FROM all_indexes i
+			JOIN all_ind_columns ic ON ic.index_owner = i.owner AND ic.index_name = i.index_name
+			WHERE i.table_owner = NVL(UPPER(:1), USER) AND i.table_name = UPPER(:2)
+			ORDER BY i.index_name, ic.column_position`,
+		checks: `SELECT search_condition_vc FROM all_constraints
+			WHERE owner = NVL(UPPER(:1), USER) AND table_name = UPPER(:2)
+			AND constraint_type = 'C'`,
+		withSchema: true,
+	},
+	"mysql": {
+		comment: `SELECT table_comment FROM information_schema.tables
+			WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?`,
+		columns: `SELECT column_name, column_type, is_nullable, column_default,
+			character_maximum_length, column_comment,
+			CASE WHEN data_type IN ('decimal', 'numeric') THEN numeric_precision END,
+			CASE WHEN data_type IN ('decimal', 'numeric') THEN numeric_scale END,
+			NULL, CASE WHEN extra LIKE '%auto_increment%' OR extra LIKE '%GENERATED%' THEN 'YES' ELSE 'NO' END
+			FROM information_schema.columns
+			WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+			ORDER BY ordinal_position`,
+		primaryKey: `SELECT column_name FROM information_schema.key_column_usage
+			WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+			AND constraint_name = 'PRIMARY'
+			ORDER BY ordinal_position`,
+		foreignKeys: `SELECT constraint_name, column_name, referenced_table_name, referenced_column_name
+			FROM information_schema.key_column_usage
+			WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+			AND referenced_table_name IS NOT NULL
+			ORDER BY constraint_name, ordinal_position`,
+		indexes: `SELECT index_name, column_name, CASE WHEN non_unique = 0 THEN 'YES' ELSE 'NO' END
+			FROM information_schema.statistics
+			WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?
+			ORDER BY index_name, seq_in_index`,
+		checks: `SELECT cc.check_clause
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.check_constraints cc
+			ON cc.constraint_schema = tc.constraint_schema AND cc.constraint_name = tc.constraint_name
+			WHERE tc.table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND tc.table_name = ?
+			AND tc.constraint_type = 'CHECK'`,
+		withSchema: true,
+	},
+	"sqlserver": {
+		comment: `SELECT COALESCE(CAST(ep.value AS NVARCHAR(MAX)), '')
+			FROM sys.objects o
+			JOIN sys.schemas s ON s.schema_id = o.schema_id
+			LEFT JOIN sys.extended_properties ep
+			ON ep.major_id = o.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+			WHERE s.name = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND o.name = @p2
+			AND o.type IN ('U', 'V')`,
+		columns: `SELECT c.name, ty.name, CASE WHEN c.is_nullable = 1 THEN 'YES' ELSE 'NO' END,
+			OBJECT_DEFINITION(c.default_object_id),
+			CASE WHEN c.max_length = -1 THEN NULL
+				WHEN ty.name IN ('nchar', 'nvarchar') THEN c.max_length / 2
+				WHEN ty.name IN ('char', 'varchar', 'binary', 'varbinary') THEN c.max_length END,
+			CAST(ep.value AS NVARCHAR(MAX)),
+			CASE WHEN ty.name IN ('decimal', 'numeric') THEN c.precision END,
+			CASE WHEN ty.name IN ('decimal', 'numeric') THEN c.scale END,
+			NULL, CASE WHEN c.is_identity = 1 OR c.is_computed = 1 THEN 'YES' ELSE 'NO' END
+			FROM sys.columns c
+			JOIN sys.objects o ON o.object_id = c.object_id
+			JOIN sys.schemas s ON s.schema_id = o.schema_id
+			JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+			LEFT JOIN sys.extended_properties ep
+			ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+			WHERE s.name = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND o.name = @p2
+			ORDER BY c.column_id`,
+		primaryKey: `SELECT c.name
+			FROM sys.indexes i
+			JOIN sys.objects o ON o.object_id = i.object_id
+			JOIN sys.schemas s ON s.schema_id = o.schema_id
+			JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+			JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+			WHERE s.name = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND o.name = @p2
+			AND i.is_primary_key = 1
+			ORDER BY ic.key_ordinal`,
+		foreignKeys: `SELECT fk.name, COL_NAME(fkc.parent_object_id, fkc.parent_column_id),
+			OBJECT_NAME(fk.referenced_object_id), COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id)
+			FROM sys.foreign_keys fk
+			JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+			JOIN sys.objects o ON o.object_id = fk.parent_object_id
+			JOIN sys.schemas s ON s.schema_id = o.schema_id
+			WHERE s.name = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND o.name = @p2
+			ORDER BY fk.name, fkc.constraint_column_id`,
+		indexes: `SELECT i.name, c.name, CASE WHEN i.is_unique = 1 THEN 'YES' ELSE 'NO' END
+			FROM sys.indexes i
+			JOIN sys.objects o ON o.object_id = i.object_id
+			JOIN sys.schemas s ON s.schema_id = o.schema_id
+			JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+			JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+			WHERE s.name = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND o.name = @p2
+			AND i.name IS NOT NULL AND ic.is_included_column = 0
+			ORDER BY i.name, ic.key_ordinal`,
+		checks: `SELECT cc.definition
+			FROM sys.check_constraints cc
+			JOIN sys.objects o ON o.object_id = cc.parent_object_id
+			JOIN sys.schemas s ON s.schema_id = o.schema_id
+			WHERE s.name = COALESCE(NULLIF(@p1, ''), SCHEMA_NAME()) AND o.name = @p2`,
+		withSchema: true,
+	},
+	"sqlite": {
+		comment: `SELECT '' FROM sqlite_master WHERE type IN ('table', 'view') AND name = ? COLLATE NOCASE`,
+		columns: `SELECT name, type, CASE WHEN "notnull" = 0 THEN 'YES' ELSE 'NO' END,
+			dflt_value, NULL, NULL, NULL, NULL, NULL,
+			CASE WHEN pk = 1 AND UPPER(type) = 'INTEGER' AND MAX(pk) OVER () = 1 THEN 'YES' ELSE 'NO' END
+			FROM pragma_table_info(?)
+			ORDER BY cid`,
+		primaryKey: `SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`,
+		foreignKeys: `SELECT 'fk_' || id, "from", "table", "to"
+			FROM pragma_foreign_key_list(?)
+			ORDER BY id, seq`,
+		indexes: `SELECT il.name, ii.name, CASE WHEN il."unique" = 1 THEN 'YES' ELSE 'NO' END
+			FROM pragma_index_list(?) il
+			JOIN pragma_index_info(il.name) ii
+			ORDER BY il.name, ii.seqno`,
+		checks:        `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ? COLLATE NOCASE`,
+		checksFromDDL: true,
+	},
+}
+
+// DescribeTable 从数据库目录读取允许访问的表的结构
+// 表名可带 schema 前缀（schema.table），否则使用连接的当前 schema
+func (e *SQLEngine) DescribeTable(ctx context.Context, table string) (*TableSchema, error) {
+	if !e.security.IsTableAllowed(table) {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotAllowed, table)
+	}
+
+	queries, ok := schemaQueriesByType[e.dbType]
+	if !ok {
+		return nil, fmt.Errorf("schema introspection is not supported for %s", e.dbType)
+	}
+
+	schemaName, tableName := "", table
+	if idx := strings.LastIndex(table, "."); idx >= 0 {
+		schemaName, tableName = table[:idx], table[idx+1:]
+	}
+	args := []interface{}{tableName}
+	if queries.withSchema {
+		args = []interface{}{schemaName, tableName}
+	}
+
+	// 开始监控
+	queryCtx := e.monitor.StartQuery(ctx, "describe", e.dbType, table)
+
+	// 创建带超时的上下文
+	execCtx, cancel := context.WithTimeout(queryCtx.Context, time.Duration(e.config.MaxQueryTime)*time.Second)
+	defer cancel()
+
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result, err := readTableSchema(execCtx, sqlDB, queries, args)
+	if err != nil {
+		queryCtx.Finish(false, 0, 0, err)
+		if errors.Is(err, ErrTableNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrTableNotFound, table)
+		}
+		return nil, fmt.Errorf("failed to describe table: %w", e.errorMapper.MapError(err))
+	}
+	result.Name = table
+
+	queryCtx.Finish(true, 0, int64(len(result.Columns)), nil)
+	return result, nil
+}
+
+// readTableSchema 依次执行目录查询并组装表结构
+func readTableSchema(ctx context.Context, db *sql.DB, queries schemaQueries, args []interface{}) (*TableSchema, error) {
+	result := &TableSchema{
+		Columns:     make([]ColumnSchema, 0),
+		PrimaryKey:  make([]string, 0),
+		ForeignKeys: make([]ForeignKeyInfo, 0),
+		Indexes:     make([]IndexInfo, 0),
+	}
+
+	// 表注释，同时用于判断表是否存在
+	var comment sql.NullString
+	if err := db.QueryRowContext(ctx, queries.comment, args...).Scan(&comment); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTableNotFound
+		}
+		return nil, err
+	}
+	result.Comment = strings.TrimSpace(comment.String)
+
+	// 列
+	err := scanCatalogRows(ctx, db, queries.columns, args, func(rows *sql.Rows) error {
+		var name, dataType, nullable, generated string
+		var defaultValue, columnComment, enum sql.NullString
+		var length, precision, scale sql.NullInt64
+		if err := rows.Scan(&name, &dataType, &nullable, &defaultValue, &length, &columnComment,
+			&precision, &scale, &enum, &generated); err != nil {
+			return err
+		}
+
+		column := ColumnSchema{
+			Name:      name,
+			Type:      dataType,
+			Nullable:  nullable == "YES",
+			Generated: generated == "YES",
+			Comment:   columnComment.String,
+		}
+		if defaultValue.Valid {
+			value := strings.TrimSpace(defaultValue.String)
+			column.Default = &value
+		}
+		if length.Valid {
+			value := length.Int64
+			column.Length = &value
+		}
+		if precision.Valid {
+			value := precision.Int64
+			column.Precision = &value
+		}
+		if scale.Valid {
+			value := scale.Int64
+			column.Scale = &value
+		}
+		if enum.Valid {
+			if err := json.Unmarshal([]byte(enum.String), &column.Enum); err != nil {
+				return fmt.Errorf("invalid enum values for column %s: %w", name, err)
+			}
+		} else {
+			column.Enum = enumTypeValues(dataType)
+		}
+		result.Columns = append(result.Columns, column)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 主键
+	err = scanCatalogRows(ctx, db, queries.primaryKey, args, func(rows *sql.Rows) error {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return err
+		}
+		result.PrimaryKey = append(result.PrimaryKey, column)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 外键（每行一列，按约束名聚合）
+	err = scanCatalogRows(ctx, db, queries.foreignKeys, args, func(rows *sql.Rows) error {
+		var name, column, refTable, refColumn string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn); err != nil {
+			return err
+		}
+
+		last := len(result.ForeignKeys) - 1
+		if last < 0 || result.ForeignKeys[last].Name != name {
+			result.ForeignKeys = append(result.ForeignKeys, ForeignKeyInfo{Name: name, ReferencedTable: refTable})
+			last++
+		}
+		fk := &result.ForeignKeys[last]
+		fk.Columns = append(fk.Columns, column)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, refColumn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 索引（每行一列，按索引名聚合）
+	err = scanCatalogRows(ctx, db, queries.indexes, args, func(rows *sql.Rows) error {
+		var name, column, unique string
+		if err := rows.Scan(&name, &column, &unique); err != nil {
+			return err
+		}
+
+		last := len(result.Indexes) - 1
+		if last < 0 || result.Indexes[last].Name != name {
+			result.Indexes = append(result.Indexes, IndexInfo{Name: name, Unique: unique == "YES"})
+			last++
+		}
+		result.Indexes[last].Columns = append(result.Indexes[last].Columns, column)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// CHECK 约束中的取值列表，旧版本数据库不支持约束查询时忽略
+	var checks []string
+	err = scanCatalogRows(ctx, db, queries.checks, args, func(rows *sql.Rows) error {
+		var definition sql.NullString
+		if err := rows.Scan(&definition); err != nil {
+			return err
+		}
+		if queries.checksFromDDL {
+			checks = append(checks, extractCheckClauses(definition.String)...)
+		} else if definition.Valid {
+			checks = append(checks, definition.String)
+		}
+		return nil
+	})
+	if err == nil {
+		applyCheckValues(result.Columns, checks)
+	}
+
+	return result, nil
+}
+
+// scanCatalogRows 执行目录查询并逐行处理
+func scanCatalogRows(ctx context.Context, db *sql.DB, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
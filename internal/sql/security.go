@@ -12,6 +12,7 @@ import (
 // SecurityValidator SQL 安全验证器
 type SecurityValidator struct {
 	config           *config.SQLConfig
+	dbType           string // 词法分析使用的数据库方言
 	allowedTables    map[string]bool
 	allowedActions   map[string]bool
 	dangerousKeywords []string
@@ -49,6 +50,12 @@ func NewSecurityValidator(cfg *config.SQLConfig) *SecurityValidator {
 	return validator
 }
 
+// WithDatabaseType 设置词法分析使用的数据库方言（影响引号和占位符的识别）
+func (v *SecurityValidator) WithDatabaseType(dbType string) *SecurityValidator {
+	v.dbType = dbType
+	return v
+}
+
 // ValidateQuery 验证查询的安全性
 func (v *SecurityValidator) ValidateQuery(query string, params map[string]interface{}) error {
 	if query == "" {
@@ -92,6 +99,11 @@ func (v *SecurityValidator) IsSelectQuery(query string) bool {
 	return strings.HasPrefix(cleanQuery, "select")
 }
 
+// IsTableAllowed 检查表是否在允许列表中
+func (v *SecurityValidator) IsTableAllowed(table string) bool {
+	return v.allowedTables[strings.ToLower(table)]
+}
+
 // checkSQLInjection 检查 SQL 注入
 func (v *SecurityValidator) checkSQLInjection(query string) error {
 	for _, pattern := range v.sqlInjectionPatterns {
@@ -103,10 +115,23 @@ func (v *SecurityValidator) checkSQLInjection(query string) error {
 }
 
 // checkDangerousKeywords 检查危险关键字
+// 按词法单元匹配关键字和标识符（以 _ 结尾的关键字匹配名称前缀），字符串和注释不检查，
+// 因此 created_at、updated_at 等包含关键字子串的列名不会被拒绝
 func (v *SecurityValidator) checkDangerousKeywords(query string) error {
-	for _, keyword := range v.dangerousKeywords {
-		if strings.Contains(query, keyword) {
-			return fmt.Errorf("dangerous keyword '%s' not allowed", keyword)
+	tokens, err := lexSQL(query, v.dbType)
+	if err != nil {
+		return fmt.Errorf("failed to parse SQL: %w", err)
+	}
+
+	for _, tok := range tokens {
+		if !isName(tok) {
+			continue
+		}
+		name := strings.ToLower(identName(tok))
+		for _, keyword := range v.dangerousKeywords {
+			if name == keyword || strings.HasSuffix(keyword, "_") && strings.HasPrefix(name, keyword) {
+				return fmt.Errorf("dangerous keyword '%s' not allowed", keyword)
+			}
 		}
 	}
 	return nil
@@ -221,6 +246,8 @@ func (v *SecurityValidator) extractSQLAction(query string) string {
 		return "update"
 	case "delete":
 		return "delete"
+	case "merge": // SQL Server 的 upsert 语句
+		return "insert"
 	case "with": // CTE 查询
 		// 查找 CTE 后的实际操作
 		for i, word := range words {
@@ -249,6 +276,13 @@ func (v *SecurityValidator) extractTableNames(query string) []string {
 	for i, word := range words {
 		lowerWord := strings.ToLower(word)
 		
+		// ON DUPLICATE KEY UPDATE / ON CONFLICT DO UPDATE / WHEN MATCHED THEN UPDATE 后面不是表名
+		if lowerWord == "update" && i > 0 {
+			if prev := strings.ToLower(words[i-1]); prev == "key" || prev == "do" || prev == "then" {
+				continue
+			}
+		}
+
 		// 查找 FROM、JOIN、INTO、UPDATE 等关键字后的表名
 		if (lowerWord == "from" || lowerWord == "join" || lowerWord == "into" || lowerWord == "update") && i+1 < len(words) {
 			tableName := strings.ToLower(words[i+1])
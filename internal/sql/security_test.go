@@ -0,0 +1,39 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"sql2api/internal/config"
+)
+
+func TestSecurityValidator_DangerousKeywords(t *testing.T) {
+	validator := NewSecurityValidator(&config.SQLConfig{}).WithDatabaseType("sqlite")
+
+	allowed := []string{
+		"select id, created_at, updated_at, deleted_by from items order by created_at desc",
+		`select "created_at" from items`,
+		"select * from items where note = 'drop table items'",
+		"select * from items -- create index",
+		"select executed_count, system_id from items where exec_id = 1",
+	}
+	for _, query := range allowed {
+		if err := validator.checkDangerousKeywords(query); err != nil {
+			t.Errorf("Expected %q to be allowed, got %v", query, err)
+		}
+	}
+
+	rejected := map[string]string{
+		"create table t (id int)":                 "create",
+		"select * from items; drop table items":   "drop",
+		"select * from information_schema.tables": "information_schema",
+		`select * from "pg_catalog".pg_class`:     "pg_",
+		"select * from sys.objects":               "sys",
+		"select xp_cmdshell('dir')":               "xp_",
+	}
+	for query, keyword := range rejected {
+		if err := validator.checkDangerousKeywords(query); err == nil || !strings.Contains(err.Error(), "'"+keyword+"'") {
+			t.Errorf("Expected %q to be rejected for %q, got %v", query, keyword, err)
+		}
+	}
+}
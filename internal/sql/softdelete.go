@@ -0,0 +1,211 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+)
+
+// WithSoftDelete 设置软删除的表：删除改为设置删除标记，查询和更新自动排除已删除的行
+func (b *QueryBuilder) WithSoftDelete(tables []config.SoftDeleteConfig) *QueryBuilder {
+	b.softDeletes = make(map[string]config.SoftDeleteConfig, len(tables))
+	for _, table := range tables {
+		b.softDeletes[strings.ToLower(table.Table)] = table
+	}
+	return b
+}
+
+// softDeleteColumn 获取表的软删除配置
+func (b *QueryBuilder) softDeleteColumn(table string) (config.SoftDeleteConfig, bool) {
+	softDelete, ok := b.softDeletes[strings.ToLower(table)]
+	return softDelete, ok
+}
+
+// softDeleteCondition 构建软删除过滤条件，返回条件表达式和参数值
+// restore 只匹配已删除的行，delete 只匹配未删除的行，select 和 update 在未要求包含已删除的行时只匹配未删除的行
+func (b *QueryBuilder) softDeleteCondition(query *model.StructuredQuery, paramIndex int) (string, []interface{}) {
+	softDelete, ok := b.softDeleteColumn(query.Table)
+	if !ok {
+		return "", nil
+	}
+
+	action := strings.ToLower(query.Action)
+	if action == "restore" {
+		if softDelete.GetType() == config.SoftDeleteTypeTimestamp {
+			return fmt.Sprintf("%s IS NOT NULL", softDelete.Column), nil
+		}
+		return fmt.Sprintf("%s = %s", softDelete.Column, b.getParameterPlaceholder(paramIndex)), []interface{}{softDelete.DeletedValue}
+	}
+	if query.IncludeDeleted && action != "delete" {
+		return "", nil
+	}
+
+	if softDelete.GetType() == config.SoftDeleteTypeTimestamp {
+		return fmt.Sprintf("%s IS NULL", softDelete.Column), nil
+	}
+	return fmt.Sprintf("(%s IS NULL OR %s <> %s)", softDelete.Column, softDelete.Column, b.getParameterPlaceholder(paramIndex)),
+		[]interface{}{softDelete.DeletedValue}
+}
+
+// buildSoftDeleteQuery 构建软删除的 UPDATE 语句：设置删除标记（恢复时清除标记），表配置了版本列时同时递增版本
+func (b *QueryBuilder) buildSoftDeleteQuery(query *model.StructuredQuery, softDelete config.SoftDeleteConfig) (string, map[string]interface{}, error) {
+	if len(query.Where) == 0 {
+		return "", nil, fmt.Errorf("WHERE clause is required for %s operation", strings.ToUpper(query.Action))
+	}
+
+	var sql strings.Builder
+	params := make(map[string]interface{})
+	paramIndex := 1
+
+	sql.WriteString("UPDATE ")
+	sql.WriteString(query.Table)
+	sql.WriteString(" SET ")
+
+	// 删除标记
+	restore := strings.EqualFold(query.Action, "restore")
+	switch {
+	case softDelete.GetType() == config.SoftDeleteTypeTimestamp && restore:
+		sql.WriteString(fmt.Sprintf("%s = NULL", softDelete.Column))
+	case softDelete.GetType() == config.SoftDeleteTypeTimestamp:
+		sql.WriteString(fmt.Sprintf("%s = %s", softDelete.Column, b.currentTimestamp()))
+	case restore && softDelete.ActiveValue == nil:
+		sql.WriteString(fmt.Sprintf("%s = NULL", softDelete.Column))
+	default:
+		value := softDelete.DeletedValue
+		if restore {
+			value = softDelete.ActiveValue
+		}
+		sql.WriteString(fmt.Sprintf("%s = %s", softDelete.Column, b.getParameterPlaceholder(paramIndex)))
+		params[fmt.Sprintf("param_%d", paramIndex)] = value
+		paramIndex++
+	}
+	if version, ok := b.versionColumn(query.Table); ok {
+		sql.WriteString(", ")
+		sql.WriteString(b.versionIncrement(version))
+	}
+
+	// WHERE 子句
+	whereClause, err := b.buildFilteredWhere(query, params, paramIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	sql.WriteString(" WHERE ")
+	sql.WriteString(whereClause)
+
+	return sql.String(), params, nil
+}
+
+// buildRestoreQuery 构建恢复软删除行的 UPDATE 语句
+func (b *QueryBuilder) buildRestoreQuery(query *model.StructuredQuery) (string, map[string]interface{}, error) {
+	softDelete, ok := b.softDeleteColumn(query.Table)
+	if !ok {
+		return "", nil, fmt.Errorf("table %s has no soft delete configured", query.Table)
+	}
+	return b.buildSoftDeleteQuery(query, softDelete)
+}
+
+// tableListKeywords 之后是表引用的关键字
+var tableListKeywords = map[string]bool{
+	"from":   true,
+	"join":   true,
+	"into":   true,
+	"update": true,
+	"using":  true,
+	"table":  true,
+}
+
+// tableClauseKeywords 表引用之后开始下一子句的关键字，不能作为别名
+var tableClauseKeywords = map[string]bool{
+	"select": true, "where": true, "set": true, "values": true, "value": true,
+	"on": true, "using": true, "join": true, "inner": true, "left": true,
+	"right": true, "full": true, "outer": true, "cross": true, "natural": true,
+	"straight_join": true, "group": true, "order": true, "having": true, "limit": true,
+	"offset": true, "fetch": true, "union": true, "intersect": true, "except": true,
+	"minus": true, "returning": true, "output": true, "default": true, "for": true,
+	"window": true, "when": true, "then": true, "with": true, "partition": true,
+	"connect": true, "start": true, "pivot": true, "unpivot": true, "tablesample": true,
+}
+
+// referencedTables 使用词法分析找出 SQL 引用的所有表名（小写，去除引号和 schema 前缀），
+// 包括逗号分隔的表列表、带引号的表名和子查询中的表
+func referencedTables(query, dbType string) ([]string, error) {
+	tokens, err := auditTokens(query, dbType)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var tables []string
+	for i, tok := range tokens {
+		if tok.kind != tokWord || !tableListKeywords[strings.ToLower(tok.text)] {
+			continue
+		}
+		// ON DUPLICATE KEY UPDATE / ON CONFLICT DO UPDATE / WHEN MATCHED THEN UPDATE 后面不是表名
+		if tok.is("update") && i > 0 && (tokens[i-1].is("key") || tokens[i-1].is("do") || tokens[i-1].is("then")) {
+			continue
+		}
+
+		for _, table := range tableList(tokens, i+1) {
+			if !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	}
+	return tables, nil
+}
+
+// tableList 读取从 start 开始的逗号分隔的表引用列表；子查询不在这里展开，其中的表由外层循环处理
+func tableList(tokens []sqlToken, start int) []string {
+	var tables []string
+	i := start
+	for i < len(tokens) {
+		// Oracle、PostgreSQL 的修饰词
+		for i < len(tokens) && (tokens[i].is("only") || tokens[i].is("lateral")) {
+			i++
+		}
+		if i >= len(tokens) {
+			break
+		}
+
+		if tokens[i].is("(") {
+			end := matchClose(tokens, i)
+			if end < 0 {
+				break
+			}
+			i = end + 1
+		} else if isTableName(tokens[i]) {
+			name := identName(tokens[i])
+			i++
+			// schema.table 只保留表名
+			for i+1 < len(tokens) && tokens[i].is(".") && isTableName(tokens[i+1]) {
+				name = identName(tokens[i+1])
+				i += 2
+			}
+			tables = append(tables, strings.ToLower(name))
+		} else {
+			break
+		}
+
+		// 可选的别名
+		if i < len(tokens) && tokens[i].is("as") {
+			i++
+		}
+		if i < len(tokens) && isTableName(tokens[i]) && !tableClauseKeywords[strings.ToLower(tokens[i].text)] {
+			i++
+		}
+
+		if i >= len(tokens) || !tokens[i].is(",") {
+			break
+		}
+		i++
+	}
+	return tables
+}
+
+// isTableName 检查词法单元是否可以作为表名（MySQL 在 ANSI_QUOTES 模式下双引号也是标识符）
+func isTableName(tok sqlToken) bool {
+	return isName(tok) || (tok.kind == tokString && strings.HasPrefix(tok.text, "\""))
+}
@@ -0,0 +1,43 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReferencedTables(t *testing.T) {
+	tests := []struct {
+		dbType string
+		query  string
+		want   []string
+	}{
+		{"postgres", "SELECT * FROM customers c, orders o WHERE c.id = o.customer_id", []string{"customers", "orders"}},
+		{"postgres", "SELECT * FROM customers AS c,orders", []string{"customers", "orders"}},
+		{"postgres", `SELECT * FROM"orders"`, []string{"orders"}},
+		{"postgres", `SELECT * FROM public."Orders" o`, []string{"orders"}},
+		{"mysql", "SELECT * FROM `orders`", []string{"orders"}},
+		{"sqlserver", "SELECT * FROM [dbo].[orders]", []string{"orders"}},
+		{"mysql", "UPDATE customers c, orders o SET o.total = 0 WHERE c.id = o.customer_id", []string{"customers", "orders"}},
+		{"mysql", "DELETE o FROM customers c, orders o WHERE c.id = o.customer_id", []string{"customers", "orders"}},
+		{"postgres", "DELETE FROM items USING orders WHERE items.order_id = orders.id", []string{"items", "orders"}},
+		{"postgres", "SELECT * FROM (SELECT * FROM orders) x, items", []string{"items", "orders"}},
+		{"postgres", "SELECT * FROM items WHERE id IN (SELECT item_id FROM\norders)", []string{"items", "orders"}},
+		{"postgres", "INSERT INTO orders (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET id = 2", []string{"orders"}},
+		{"postgres", "SELECT 'FROM orders' FROM items -- FROM orders", []string{"items"}},
+	}
+
+	for _, test := range tests {
+		got, err := referencedTables(test.query, test.dbType)
+		if err != nil {
+			t.Errorf("referencedTables(%q) failed: %v", test.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("referencedTables(%q) = %v, want %v", test.query, got, test.want)
+		}
+	}
+
+	if _, err := referencedTables("SELECT * FROM orders WHERE name = 'x", "postgres"); err == nil {
+		t.Error("Expected error for unterminated string")
+	}
+}
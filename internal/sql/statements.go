@@ -0,0 +1,301 @@
+package sql
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// statementDurationWindow 计算 p95 时保留的最近执行耗时数
+	statementDurationWindow = 256
+	// maxStatementAPIKeys 每条语句最多记录的 API Key 数
+	maxStatementAPIKeys = 32
+	// defaultStatementLimit 语句统计默认返回的条数
+	defaultStatementLimit = 100
+)
+
+// StatementStats 按语句指纹汇总执行统计，并保留最近一段时间内最慢的查询样本
+type StatementStats struct {
+	maxStatements int
+	slowLogSize   int
+	slowLogWindow time.Duration
+	slowQuery     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*statementEntry
+	slow    []model.SlowQuerySample
+	since   time.Time
+}
+
+// statementEntry 单条语句的统计
+type statementEntry struct {
+	stat      model.StatementStat
+	apiKeys   map[string]bool
+	durations [statementDurationWindow]float64 // 最近执行耗时的环形缓冲区
+	next      int
+}
+
+// statementStats 按数据库连接保存的语句统计
+var statementStats sync.Map
+
+// NewStatementStats 创建语句统计
+func NewStatementStats(cfg *config.StatementStatsConfig) *StatementStats {
+	return &StatementStats{
+		maxStatements: cfg.MaxStatements,
+		slowLogSize:   cfg.SlowLogSize,
+		slowLogWindow: time.Duration(cfg.SlowLogWindow) * time.Minute,
+		slowQuery:     time.Duration(cfg.SlowQueryMs) * time.Millisecond,
+		entries:       make(map[string]*statementEntry),
+		since:         time.Now(),
+	}
+}
+
+// AttachStatementStats 为数据库连接上的所有 SQLEngine 启用语句统计，返回停用的函数
+func AttachStatementStats(db *gorm.DB, stats *StatementStats) func() {
+	statementStats.Store(db, stats)
+	return func() {
+		statementStats.CompareAndDelete(db, stats)
+	}
+}
+
+// statementStatsFor 获取数据库连接上启用的语句统计
+func statementStatsFor(db *gorm.DB) *StatementStats {
+	stats, ok := statementStats.Load(db)
+	if !ok {
+		return nil
+	}
+	return stats.(*StatementStats)
+}
+
+// Record 记录一次执行；rawSQL 为原始 SQL，用于计算指纹，慢查询样本只保存 metrics 中脱敏后的 SQL
+func (s *StatementStats) Record(metrics *QueryMetrics, rawSQL string, params map[string]interface{}, caller *Caller) {
+	normalized := normalizeSQL(rawSQL, metrics.DatabaseType)
+	fingerprint := fingerprintSQL(metrics.QueryType, normalized)
+	elapsed := float64(metrics.ExecutionTime.Microseconds()) / 1000
+	rows := metrics.ResultRows + metrics.AffectedRows
+	now := metrics.Timestamp.Add(metrics.ExecutionTime)
+
+	var apiKeyName string
+	if caller != nil {
+		apiKeyName = caller.APIKeyName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[fingerprint]
+	if !ok {
+		if len(s.entries) >= s.maxStatements {
+			s.evictLocked()
+		}
+		entry = &statementEntry{
+			stat: model.StatementStat{
+				Fingerprint: fingerprint,
+				Query:       normalized,
+				QueryType:   metrics.QueryType,
+				FirstSeen:   metrics.Timestamp,
+			},
+			apiKeys: make(map[string]bool),
+		}
+		s.entries[fingerprint] = entry
+	}
+
+	stat := &entry.stat
+	stat.Calls++
+	if !metrics.Success {
+		stat.Errors++
+	}
+	stat.TotalTime += elapsed
+	stat.MaxTime = math.Max(stat.MaxTime, elapsed)
+	stat.Rows += rows
+	stat.LastSeen = now
+	if apiKeyName != "" && len(entry.apiKeys) < maxStatementAPIKeys {
+		entry.apiKeys[apiKeyName] = true
+	}
+	entry.durations[entry.next%statementDurationWindow] = elapsed
+	entry.next++
+
+	if s.slowLogSize > 0 && metrics.ExecutionTime >= s.slowQuery {
+		sample := model.SlowQuerySample{
+			QueryID:       metrics.QueryID,
+			Fingerprint:   fingerprint,
+			QueryType:     metrics.QueryType,
+			SQL:           metrics.SQL,
+			Params:        redactParams(params),
+			ExecutionTime: elapsed,
+			Rows:          rows,
+			Success:       metrics.Success,
+			ErrorCode:     metrics.ErrorCode,
+			APIKeyName:    apiKeyName,
+			TraceID:       metrics.TraceID,
+			Timestamp:     metrics.Timestamp,
+		}
+		if caller != nil {
+			sample.RequestID = caller.RequestID
+		}
+		s.recordSlowLocked(sample, now)
+	}
+}
+
+// evictLocked 淘汰执行次数最少的语句，次数相同时淘汰最久未执行的
+func (s *StatementStats) evictLocked() {
+	var victim *statementEntry
+	for _, entry := range s.entries {
+		if victim == nil || entry.stat.Calls < victim.stat.Calls ||
+			(entry.stat.Calls == victim.stat.Calls && entry.stat.LastSeen.Before(victim.stat.LastSeen)) {
+			victim = entry
+		}
+	}
+	if victim != nil {
+		delete(s.entries, victim.stat.Fingerprint)
+	}
+}
+
+// recordSlowLocked 将样本加入慢查询日志，日志已满时替换其中最快的样本
+func (s *StatementStats) recordSlowLocked(sample model.SlowQuerySample, now time.Time) {
+	s.pruneSlowLocked(now)
+	if len(s.slow) < s.slowLogSize {
+		s.slow = append(s.slow, sample)
+		return
+	}
+
+	fastest := 0
+	for i := range s.slow {
+		if s.slow[i].ExecutionTime < s.slow[fastest].ExecutionTime {
+			fastest = i
+		}
+	}
+	if sample.ExecutionTime > s.slow[fastest].ExecutionTime {
+		s.slow[fastest] = sample
+	}
+}
+
+// pruneSlowLocked 移除超出保留时间的慢查询样本
+func (s *StatementStats) pruneSlowLocked(now time.Time) {
+	cutoff := now.Add(-s.slowLogWindow)
+	kept := s.slow[:0]
+	for _, sample := range s.slow {
+		if sample.Timestamp.After(cutoff) {
+			kept = append(kept, sample)
+		}
+	}
+	s.slow = kept
+}
+
+// List 返回语句统计，按指定字段降序排列
+func (s *StatementStats) List(req *model.StatementStatsRequest) *model.StatementStatsResponse {
+	s.mu.Lock()
+	statements := make([]model.StatementStat, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if req.APIKey != "" && !entry.apiKeys[req.APIKey] {
+			continue
+		}
+		statements = append(statements, entry.snapshot())
+	}
+	response := &model.StatementStatsResponse{Total: len(s.entries), Since: s.since}
+	s.mu.Unlock()
+
+	key := statementSortKey(req.Sort)
+	sort.Slice(statements, func(i, j int) bool {
+		a, b := key(&statements[i]), key(&statements[j])
+		if a != b {
+			return a > b
+		}
+		return statements[i].Fingerprint < statements[j].Fingerprint
+	})
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultStatementLimit
+	}
+	if len(statements) > limit {
+		statements = statements[:limit]
+	}
+	response.Statements = statements
+	return response
+}
+
+// SlowQueries 返回保留时间内最慢的查询样本，最慢的在前
+func (s *StatementStats) SlowQueries() []model.SlowQuerySample {
+	s.mu.Lock()
+	s.pruneSlowLocked(time.Now())
+	samples := make([]model.SlowQuerySample, len(s.slow))
+	copy(samples, s.slow)
+	s.mu.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].ExecutionTime > samples[j].ExecutionTime
+	})
+	return samples
+}
+
+// Reset 清空语句统计和慢查询日志
+func (s *StatementStats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]*statementEntry)
+	s.slow = nil
+	s.since = time.Now()
+}
+
+// snapshot 复制统计结果并计算平均和 p95 耗时
+func (e *statementEntry) snapshot() model.StatementStat {
+	stat := e.stat
+	stat.MeanTime = stat.TotalTime / float64(stat.Calls)
+
+	n := e.next
+	if n > statementDurationWindow {
+		n = statementDurationWindow
+	}
+	durations := make([]float64, n)
+	copy(durations, e.durations[:n])
+	sort.Float64s(durations)
+	stat.P95Time = durations[int(math.Ceil(float64(n)*0.95))-1]
+
+	stat.APIKeys = make([]string, 0, len(e.apiKeys))
+	for name := range e.apiKeys {
+		stat.APIKeys = append(stat.APIKeys, name)
+	}
+	sort.Strings(stat.APIKeys)
+	return stat
+}
+
+// statementSortKey 返回排序字段的取值函数，默认按总耗时
+func statementSortKey(field string) func(*model.StatementStat) float64 {
+	switch field {
+	case model.StatementSortMeanTime:
+		return func(s *model.StatementStat) float64 { return s.MeanTime }
+	case model.StatementSortMaxTime:
+		return func(s *model.StatementStat) float64 { return s.MaxTime }
+	case model.StatementSortP95Time:
+		return func(s *model.StatementStat) float64 { return s.P95Time }
+	case model.StatementSortCalls:
+		return func(s *model.StatementStat) float64 { return float64(s.Calls) }
+	case model.StatementSortErrors:
+		return func(s *model.StatementStat) float64 { return float64(s.Errors) }
+	case model.StatementSortRows:
+		return func(s *model.StatementStat) float64 { return float64(s.Rows) }
+	default:
+		return func(s *model.StatementStat) float64 { return s.TotalTime }
+	}
+}
+
+// redactParams 只保留参数名，参数值一律替换为 [REDACTED]
+func redactParams(params map[string]interface{}) map[string]string {
+	if len(params) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string, len(params))
+	for name := range params {
+		redacted[name] = redactedValue
+	}
+	return redacted
+}
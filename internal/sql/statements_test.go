@@ -0,0 +1,140 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := []struct {
+		dbType string
+		query  string
+		want   string
+	}{
+		{"postgres", "SELECT id, name FROM users WHERE id = 42 AND name = 'bob'", "select id, name from users where id = ? and name = ?"},
+		{"postgres", "select  id,name\n from users -- comment\n where id=$1", "select id, name from users where id = ?"},
+		{"mysql", "SELECT * FROM t WHERE id IN (1, 2, 3) AND x = -5", "select * from t where id in (...) and x = ?"},
+		{"mysql", "SELECT * FROM t WHERE id IN (?)", "select * from t where id in (...)"},
+		{"mysql", "SELECT a - 1 FROM t", "select a - ? from t"},
+		{"sqlite", "INSERT INTO t (a, b) VALUES (?, ?), (?, ?), (?, ?)", "insert into t (a, b) values (?, ?)"},
+		{"oracle", "SELECT * FROM t WHERE id = :id", "select * from t where id = ?"},
+		{"postgres", "SELECT * FROM t WHERE id IN (SELECT id FROM u WHERE x = 1)", "select * from t where id in (select id from u where x = ?)"},
+	}
+
+	for _, test := range tests {
+		if got := normalizeSQL(test.query, test.dbType); got != test.want {
+			t.Errorf("normalizeSQL(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+
+	a := fingerprintSQL("select", normalizeSQL("SELECT * FROM t WHERE id IN (1, 2)", "mysql"))
+	b := fingerprintSQL("select", normalizeSQL("select * from t where id in (7,8,9,10)", "mysql"))
+	if a != b {
+		t.Errorf("Expected equal fingerprints, got %s and %s", a, b)
+	}
+	if c := fingerprintSQL("batch", normalizeSQL("SELECT * FROM t WHERE id IN (1, 2)", "mysql")); c == a {
+		t.Error("Expected query type to be part of the fingerprint")
+	}
+}
+
+func TestStatementStats(t *testing.T) {
+	stats := NewStatementStats(&config.StatementStatsConfig{MaxStatements: 2, SlowLogSize: 2, SlowLogWindow: 60})
+	start := time.Now()
+	record := func(query string, elapsed time.Duration, success bool, params map[string]interface{}, apiKey string) {
+		stats.Record(&QueryMetrics{
+			QueryType:     "select",
+			DatabaseType:  "sqlite",
+			SQL:           query,
+			ExecutionTime: elapsed,
+			ResultRows:    1,
+			Success:       success,
+			Timestamp:     start,
+		}, query, params, &Caller{APIKeyName: apiKey})
+	}
+
+	record("SELECT * FROM a WHERE id = 1", 10*time.Millisecond, true, map[string]interface{}{"id": 1}, "reader")
+	record("SELECT * FROM a WHERE id = 2", 30*time.Millisecond, false, nil, "writer")
+	record("SELECT * FROM b", 5*time.Millisecond, true, nil, "reader")
+
+	response := stats.List(&model.StatementStatsRequest{})
+	if response.Total != 2 || len(response.Statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %+v", response)
+	}
+	top := response.Statements[0]
+	if top.Query != "select * from a where id = ?" || top.Calls != 2 || top.Errors != 1 || top.Rows != 2 {
+		t.Errorf("Unexpected top statement: %+v", top)
+	}
+	if top.TotalTime != 40 || top.MeanTime != 20 || top.MaxTime != 30 || top.P95Time != 30 {
+		t.Errorf("Unexpected timings: %+v", top)
+	}
+	if !reflect.DeepEqual(top.APIKeys, []string{"reader", "writer"}) {
+		t.Errorf("Unexpected API keys: %v", top.APIKeys)
+	}
+
+	byMean := stats.List(&model.StatementStatsRequest{Sort: model.StatementSortMeanTime, APIKey: "reader"})
+	if len(byMean.Statements) != 2 || byMean.Statements[0].MeanTime != 20 {
+		t.Errorf("Unexpected statements sorted by mean time: %+v", byMean.Statements)
+	}
+
+	// 超出 max_statements 时淘汰执行次数最少的语句
+	record("SELECT * FROM c", time.Millisecond, true, nil, "reader")
+	for _, stat := range stats.List(&model.StatementStatsRequest{}).Statements {
+		if stat.Query == "select * from b" {
+			t.Error("Expected least used statement to be evicted")
+		}
+	}
+
+	// 慢查询日志只保留最慢的样本，参数值已脱敏
+	slow := stats.SlowQueries()
+	if len(slow) != 2 || slow[0].ExecutionTime != 30 || slow[1].ExecutionTime != 10 {
+		t.Fatalf("Unexpected slow queries: %+v", slow)
+	}
+	if slow[1].Params["id"] != redactedValue {
+		t.Errorf("Expected redacted params, got %v", slow[1].Params)
+	}
+
+	stats.Reset()
+	if response := stats.List(&model.StatementStatsRequest{}); response.Total != 0 || len(stats.SlowQueries()) != 0 {
+		t.Errorf("Expected empty statistics after reset, got %+v", response)
+	}
+}
+
+func TestSQLEngine_StatementStats(t *testing.T) {
+	engine, repos := newTestEngine(t)
+	stats := NewStatementStats(&config.StatementStatsConfig{MaxStatements: 10, SlowLogSize: 5, SlowLogWindow: 60})
+	detach := AttachStatementStats(repos.GetDB(), stats)
+	defer detach()
+
+	ctx := WithCaller(context.Background(), &Caller{APIKeyName: "stats", RequestID: "req-1"})
+	for _, query := range []string{"SELECT id FROM items WHERE name = 'x'", "select id from items where name='y'"} {
+		if _, err := engine.ExecuteQuery(ctx, query, nil); err != nil {
+			t.Fatalf("ExecuteQuery failed: %v", err)
+		}
+	}
+
+	response := stats.List(&model.StatementStatsRequest{})
+	if len(response.Statements) != 1 || response.Statements[0].Calls != 2 {
+		t.Fatalf("Expected one statement with 2 calls, got %+v", response.Statements)
+	}
+
+	slow := stats.SlowQueries()
+	if len(slow) != 2 || slow[0].RequestID != "req-1" || slow[0].APIKeyName != "stats" {
+		t.Errorf("Unexpected slow queries: %+v", slow)
+	}
+	if slow[0].SQL != "SELECT id FROM items WHERE name = ?" && slow[0].SQL != "select id from items where name=?" {
+		t.Errorf("Unexpected sanitized SQL: %q", slow[0].SQL)
+	}
+}
+
+func TestPerformanceMonitor_SanitizeSQL(t *testing.T) {
+	monitor := NewPerformanceMonitor(true, false, false, 0)
+	got := monitor.sanitizeSQL("SELECT * FROM users WHERE email = 'a@b.c' AND age > 30", "postgres")
+	if want := "SELECT * FROM users WHERE email = ? AND age > ?"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,53 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+
+	"sql2api/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// dbSystems 数据库类型对应的 db.system 属性值
+var dbSystems = map[string]string{
+	"postgres":  "postgresql",
+	"mysql":     "mysql",
+	"sqlite":    "sqlite",
+	"oracle":    "oracle",
+	"sqlserver": "mssql",
+}
+
+// dbAttributes 数据库 span 的属性，db.statement 中的字符串字面量已脱敏
+func (e *SQLEngine) dbAttributes(query string) []attribute.KeyValue {
+	return spanAttributes(e.dbType, query)
+}
+
+// spanAttributes 生成 db.system 和脱敏后的 db.statement 属性
+func spanAttributes(dbType, query string) []attribute.KeyValue {
+	system, ok := dbSystems[dbType]
+	if !ok {
+		system = "other_sql"
+	}
+	attrs := []attribute.KeyValue{attribute.String("db.system", system)}
+	if query != "" {
+		attrs = append(attrs, attribute.String("db.statement", sanitizeAuditSQL(query, dbType)))
+	}
+	return attrs
+}
+
+// acquireConn 从连接池获取连接，等待空闲连接或建立新连接的时间记录在 db.pool.acquire span 中
+func (e *SQLEngine) acquireConn(ctx context.Context, sqlDB *sql.DB) (*sql.Conn, error) {
+	ctx, span := tracing.Start(ctx, "db.pool.acquire", e.dbAttributes("")...)
+	conn, err := sqlDB.Conn(ctx)
+	tracing.End(span, err)
+	return conn, err
+}
+
+// checkSecurity 进行安全验证，耗时记录在 SecurityValidator.ValidateQuery span 中
+func (e *SQLEngine) checkSecurity(ctx context.Context, query string, params map[string]interface{}) error {
+	_, span := tracing.Start(ctx, "SecurityValidator.ValidateQuery")
+	err := e.security.ValidateQuery(query, params)
+	tracing.End(span, err)
+	return err
+}
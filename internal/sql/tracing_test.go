@@ -0,0 +1,67 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSQLEngine_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	engine, _ := newTestEngine(t)
+	ctx, root := provider.Tracer("test").Start(context.Background(), "request")
+	if _, err := engine.ExecuteSQL(ctx, "INSERT INTO items (name, category) VALUES ('a', 'secret')", nil); err != nil {
+		t.Fatalf("ExecuteSQL failed: %v", err)
+	}
+	if _, err := engine.ExecuteQuery(ctx, "SELECT id FROM items WHERE name = ?", map[string]interface{}{"param_1": "a"}); err != nil {
+		t.Fatalf("ExecuteQuery failed: %v", err)
+	}
+	root.End()
+
+	spans := map[string][]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = append(spans[span.Name], span)
+	}
+	for _, name := range []string{"SQLEngine.insert", "SQLEngine.select", "SecurityValidator.ValidateQuery", "db.pool.acquire", "db.exec", "db.query"} {
+		if len(spans[name]) == 0 {
+			t.Fatalf("Expected span %s, got %v", name, spans)
+		}
+	}
+
+	insert := spans["SQLEngine.insert"][0]
+	if insert.Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("Expected engine span to be a child of the request span")
+	}
+	attrs := attributeMap(insert.Attributes)
+	if attrs["db.system"] != "sqlite" || attrs["sql2api.query_id"] == "" ||
+		attrs["db.statement"] != "INSERT INTO items (name, category) VALUES ('?', '?')" {
+		t.Errorf("Unexpected engine span attributes: %v", attrs)
+	}
+
+	// 驱动层 span 是引擎 span 的子 span
+	query := spans["db.query"][0]
+	if query.Parent.SpanID() != spans["SQLEngine.select"][0].SpanContext.SpanID() {
+		t.Errorf("Expected db.query to be a child of SQLEngine.select")
+	}
+	if exec := spans["db.exec"][0]; exec.Parent.SpanID() != insert.SpanContext.SpanID() {
+		t.Errorf("Expected db.exec to be a child of SQLEngine.insert")
+	}
+}
+
+// attributeMap 将 span 属性转换为字符串映射
+func attributeMap(attrs []attribute.KeyValue) map[string]string {
+	values := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		values[string(attr.Key)] = attr.Value.Emit()
+	}
+	return values
+}
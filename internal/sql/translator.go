@@ -0,0 +1,1149 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortableDialect 可移植 SQL 方言名称
+// 可移植 SQL 接受各数据库的常见写法（LIMIT/OFFSET、FETCH FIRST、TOP、NOW()、
+// SYSDATE、||、CONCAT、TRUE/FALSE、ILIKE、INTERVAL、? 或 $N 占位符），
+// 并转换为目标数据库的等价写法
+const PortableDialect = "portable"
+
+// translatorDialects 支持的源方言（含别名）
+var translatorDialects = map[string]string{
+	PortableDialect: PortableDialect,
+	"postgres":      "postgres",
+	"postgresql":    "postgres",
+	"oracle":        "oracle",
+	"sqlite":        "sqlite",
+	"sqlite3":       "sqlite",
+	"mysql":         "mysql",
+	"mariadb":       "mysql",
+	"sqlserver":     "sqlserver",
+	"mssql":         "sqlserver",
+}
+
+// NormalizeSQLDialect 规范化方言名称，不支持时返回空字符串
+func NormalizeSQLDialect(name string) string {
+	return translatorDialects[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// SQLTranslator SQL 方言转换器
+type SQLTranslator struct {
+	targetType string
+	target     DatabaseDialect
+}
+
+// NewSQLTranslator 创建 SQL 方言转换器
+func NewSQLTranslator(targetType string) *SQLTranslator {
+	target := NormalizeSQLDialect(targetType)
+	if target == "" || target == PortableDialect {
+		target = "postgres"
+	}
+
+	return &SQLTranslator{
+		targetType: target,
+		target:     NewDialectFactory().CreateDialect(target),
+	}
+}
+
+// TargetType 获取目标数据库类型
+func (t *SQLTranslator) TargetType() string {
+	return t.targetType
+}
+
+// Translate 将源方言的 SQL 转换为目标数据库的 SQL
+func (t *SQLTranslator) Translate(query, sourceDialect string) (string, error) {
+	source := NormalizeSQLDialect(sourceDialect)
+	if source == "" {
+		return "", fmt.Errorf("unsupported sql dialect: %s", sourceDialect)
+	}
+
+	// 源方言与目标一致时无需转换
+	if source == t.targetType {
+		return query, nil
+	}
+
+	tokens, err := lexSQL(query, source)
+	if err != nil {
+		return "", err
+	}
+
+	tokens = t.rewriteTimeFunctions(tokens)
+	tokens = t.rewriteBooleans(tokens)
+	tokens = t.rewriteQuoting(tokens)
+
+	if tokens, err = t.rewriteIntervals(tokens); err != nil {
+		return "", err
+	}
+	if tokens, err = t.rewriteConcat(tokens); err != nil {
+		return "", err
+	}
+	if tokens, err = t.rewriteILike(tokens); err != nil {
+		return "", err
+	}
+	if tokens, err = t.rewritePagination(tokens); err != nil {
+		return "", err
+	}
+
+	// 占位符最后处理，以便检查分页改写后的参数顺序
+	if err := t.rewritePlaceholders(tokens); err != nil {
+		return "", err
+	}
+
+	return renderTokens(tokens), nil
+}
+
+// ===== 词法分析 =====
+
+// sqlTokenKind 词法单元类型
+type sqlTokenKind int
+
+const (
+	tokSpace   sqlTokenKind = iota // 空白
+	tokComment                     // 注释
+	tokString                      // 字符串字面量
+	tokIdent                       // 带引号的标识符
+	tokWord                        // 关键字或未加引号的标识符
+	tokNumber                      // 数字
+	tokParam                       // 参数占位符
+	tokSymbol                      // 运算符和标点
+)
+
+// sqlToken 词法单元
+type sqlToken struct {
+	kind  sqlTokenKind
+	text  string
+	index int // 占位符的参数序号（从 1 开始）
+}
+
+// is 检查词法单元是否为指定的关键字或符号（忽略大小写）
+func (tok sqlToken) is(text string) bool {
+	return (tok.kind == tokWord || tok.kind == tokSymbol) && strings.EqualFold(tok.text, text)
+}
+
+// isTrivia 检查是否为空白或注释
+func (tok sqlToken) isTrivia() bool {
+	return tok.kind == tokSpace || tok.kind == tokComment
+}
+
+// lexSQL 将 SQL 切分为词法单元，占位符按源方言识别
+func lexSQL(query, source string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	positional := 0
+	numbered := false
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			for i < len(query) && strings.IndexByte(" \t\n\r", query[i]) >= 0 {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokSpace, text: query[start:i]})
+
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokComment, text: query[start:i]})
+
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated comment")
+			}
+			i += end + 4
+			tokens = append(tokens, sqlToken{kind: tokComment, text: query[start:i]})
+
+		case c == '\'':
+			end, err := scanQuoted(query, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, sqlToken{kind: tokString, text: query[start:i]})
+
+		case c == '"':
+			end, err := scanQuoted(query, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			// MySQL 默认将双引号视为字符串
+			kind := tokIdent
+			if source == "mysql" {
+				kind = tokString
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: query[start:i]})
+
+		case c == '`':
+			end, err := scanQuoted(query, i, '`')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, sqlToken{kind: tokIdent, text: query[start:i]})
+
+		case c == '[' && source == "sqlserver":
+			end, err := scanQuoted(query, i, ']')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, sqlToken{kind: tokIdent, text: query[start:i]})
+
+		case isWordStart(c):
+			for i < len(query) && isWordPart(query[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokWord, text: query[start:i]})
+
+		case c >= '0' && c <= '9':
+			for i < len(query) && (query[i] >= '0' && query[i] <= '9' || query[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: tokNumber, text: query[start:i]})
+
+		default:
+			if index, end, ok := scanPlaceholder(query, i, source); ok {
+				if index == 0 {
+					positional++
+					index = positional
+				} else {
+					numbered = true
+				}
+				i = end
+				tokens = append(tokens, sqlToken{kind: tokParam, text: query[start:i], index: index})
+				continue
+			}
+
+			i++
+			if i < len(query) {
+				switch query[start : i+1] {
+				case "||", "::", "<=", ">=", "<>", "!=":
+					i++
+				}
+			}
+			tokens = append(tokens, sqlToken{kind: tokSymbol, text: query[start:i]})
+		}
+	}
+
+	if positional > 0 && numbered {
+		return nil, errors.New("cannot mix positional and numbered placeholders")
+	}
+
+	return tokens, nil
+}
+
+// scanQuoted 扫描引号包围的内容，连续两个结束引号视为转义
+func scanQuoted(query string, start int, closeQuote byte) (int, error) {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != closeQuote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == closeQuote {
+			i++
+			continue
+		}
+		return i + 1, nil
+	}
+	return 0, fmt.Errorf("unterminated quoted text starting at position %d", start)
+}
+
+// scanPlaceholder 按源方言识别占位符，返回参数序号（? 为 0）和结束位置
+func scanPlaceholder(query string, i int, source string) (int, int, bool) {
+	c := query[i]
+	portable := source == PortableDialect
+
+	switch {
+	case c == '?' && (portable || source == "mysql" || source == "sqlite"):
+		return 0, i + 1, true
+	case c == '$' && (portable || source == "postgres"):
+		return scanNumberedPlaceholder(query, i+1)
+	case c == ':' && (portable || source == "oracle"):
+		// 排除 PostgreSQL 的 :: 类型转换
+		if i > 0 && query[i-1] == ':' {
+			return 0, 0, false
+		}
+		return scanNumberedPlaceholder(query, i+1)
+	case c == '@' && (portable || source == "sqlserver"):
+		if i+1 < len(query) && (query[i+1] == 'p' || query[i+1] == 'P') {
+			return scanNumberedPlaceholder(query, i+2)
+		}
+	}
+	return 0, 0, false
+}
+
+// scanNumberedPlaceholder 解析 $1、:1、@p1 形式的占位符序号
+func scanNumberedPlaceholder(query string, digits int) (int, int, bool) {
+	end := digits
+	for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+		end++
+	}
+	if end == digits || (end < len(query) && isWordPart(query[end])) {
+		return 0, 0, false
+	}
+
+	index, err := strconv.Atoi(query[digits:end])
+	if err != nil || index == 0 {
+		return 0, 0, false
+	}
+	return index, end, true
+}
+
+// isWordStart 检查是否为标识符首字符
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// isWordPart 检查是否为标识符字符（Oracle 允许 $ 和 #）
+func isWordPart(c byte) bool {
+	return isWordStart(c) || (c >= '0' && c <= '9') || c == '$' || c == '#'
+}
+
+// renderTokens 拼接词法单元
+func renderTokens(tokens []sqlToken) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteString(tok.text)
+	}
+	return sb.String()
+}
+
+// ===== 词法单元导航 =====
+
+// prevSignificant 查找 i 之前的第一个非空白词法单元
+func prevSignificant(tokens []sqlToken, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if !tokens[j].isTrivia() {
+			return j
+		}
+	}
+	return -1
+}
+
+// nextSignificant 查找 i 之后的第一个非空白词法单元
+func nextSignificant(tokens []sqlToken, i int) int {
+	for j := i + 1; j < len(tokens); j++ {
+		if !tokens[j].isTrivia() {
+			return j
+		}
+	}
+	return -1
+}
+
+// matchClose 查找与 i 处左括号匹配的右括号
+func matchClose(tokens []sqlToken, i int) int {
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		if tokens[j].is("(") {
+			depth++
+		} else if tokens[j].is(")") {
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// matchOpen 查找与 i 处右括号匹配的左括号
+func matchOpen(tokens []sqlToken, i int) int {
+	depth := 0
+	for j := i; j >= 0; j-- {
+		if tokens[j].is(")") {
+			depth++
+		} else if tokens[j].is("(") {
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// isOperandToken 检查词法单元能否单独作为操作数
+func isOperandToken(tok sqlToken) bool {
+	switch tok.kind {
+	case tokWord, tokIdent, tokString, tokNumber, tokParam:
+		return true
+	}
+	return false
+}
+
+// operandBefore 查找 i 之前紧邻的操作数起始位置
+// 支持列名、table.column、字面量、占位符、函数调用、括号表达式和 :: 类型转换
+func operandBefore(tokens []sqlToken, i int) int {
+	j := prevSignificant(tokens, i)
+	if j < 0 {
+		return -1
+	}
+
+	start := j
+	switch {
+	case tokens[j].is(")"):
+		start = matchOpen(tokens, j)
+		if start < 0 {
+			return -1
+		}
+		// 紧邻左括号的单词为函数名
+		if start > 0 && tokens[start-1].kind == tokWord {
+			start--
+		}
+	case isOperandToken(tokens[j]):
+	default:
+		return -1
+	}
+
+	// schema.table.column 形式
+	for start >= 2 && tokens[start-1].is(".") && (tokens[start-2].kind == tokWord || tokens[start-2].kind == tokIdent) {
+		start -= 2
+	}
+
+	// PostgreSQL 的 value::type 类型转换
+	if start > 0 && tokens[start-1].is("::") {
+		return operandBefore(tokens, start-1)
+	}
+
+	return start
+}
+
+// operandAfter 查找 i 之后紧邻的操作数，返回 [start, end) 区间
+func operandAfter(tokens []sqlToken, i int) (int, int) {
+	start := nextSignificant(tokens, i)
+	if start < 0 {
+		return -1, -1
+	}
+
+	end := start + 1
+	switch {
+	case tokens[start].is("("):
+		closeIdx := matchClose(tokens, start)
+		if closeIdx < 0 {
+			return -1, -1
+		}
+		end = closeIdx + 1
+	case tokens[start].is("-") || tokens[start].is("+"):
+		// 带符号的数字或参数
+		if end < len(tokens) && (tokens[end].kind == tokNumber || tokens[end].kind == tokParam) {
+			end++
+		} else {
+			return -1, -1
+		}
+	case tokens[start].kind == tokWord && end < len(tokens) && tokens[end].is("("):
+		closeIdx := matchClose(tokens, end)
+		if closeIdx < 0 {
+			return -1, -1
+		}
+		end = closeIdx + 1
+	case isOperandToken(tokens[start]):
+		for end+1 < len(tokens) && tokens[end].is(".") &&
+			(tokens[end+1].kind == tokWord || tokens[end+1].kind == tokIdent || tokens[end+1].is("*")) {
+			end += 2
+		}
+	default:
+		return -1, -1
+	}
+
+	// PostgreSQL 的 value::type 类型转换
+	for end+1 < len(tokens) && tokens[end].is("::") && tokens[end+1].kind == tokWord {
+		end += 2
+	}
+
+	return start, end
+}
+
+// trimTrivia 去除首尾空白词法单元
+func trimTrivia(tokens []sqlToken) []sqlToken {
+	for len(tokens) > 0 && tokens[0].isTrivia() {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].isTrivia() {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// splice 用 replacement 替换 tokens[start:end]
+func splice(tokens []sqlToken, start, end int, replacement []sqlToken) []sqlToken {
+	result := make([]sqlToken, 0, len(tokens)-(end-start)+len(replacement))
+	result = append(result, tokens[:start]...)
+	result = append(result, replacement...)
+	return append(result, tokens[end:]...)
+}
+
+// word、symbol、space 构造词法单元
+func word(text string) sqlToken   { return sqlToken{kind: tokWord, text: text} }
+func symbol(text string) sqlToken { return sqlToken{kind: tokSymbol, text: text} }
+func space() sqlToken             { return sqlToken{kind: tokSpace, text: " "} }
+
+// call 构造函数调用 name(arg1, arg2, ...)
+func call(name string, args ...[]sqlToken) []sqlToken {
+	result := []sqlToken{word(name), symbol("(")}
+	for i, arg := range args {
+		if i > 0 {
+			result = append(result, symbol(","), space())
+		}
+		result = append(result, arg...)
+	}
+	return append(result, symbol(")"))
+}
+
+// parens 用括号包围词法单元
+func parens(tokens []sqlToken) []sqlToken {
+	result := append([]sqlToken{symbol("(")}, tokens...)
+	return append(result, symbol(")"))
+}
+
+// ===== 简单替换 =====
+
+// rewriteTimeFunctions 将 NOW()、SYSDATE、GETDATE() 等当前时间写法替换为目标函数
+func (t *SQLTranslator) rewriteTimeFunctions(tokens []sqlToken) []sqlToken {
+	replacement := t.target.GetCurrentTimeFunction()
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.kind != tokWord {
+			continue
+		}
+
+		end := i + 1
+		switch strings.ToLower(tok.text) {
+		case "now", "getdate":
+			// 需要紧跟空参数列表
+			if end+1 >= len(tokens) || !tokens[end].is("(") || !tokens[end+1].is(")") {
+				continue
+			}
+			end += 2
+		case "sysdate", "systimestamp", "current_timestamp":
+			// 跳过 table.sysdate 之类的列名
+			if i > 0 && tokens[i-1].is(".") {
+				continue
+			}
+		default:
+			continue
+		}
+
+		tokens = splice(tokens, i, end, []sqlToken{word(replacement)})
+	}
+
+	return tokens
+}
+
+// rewriteBooleans 为不支持布尔字面量的数据库将 TRUE/FALSE 替换为 1/0
+func (t *SQLTranslator) rewriteBooleans(tokens []sqlToken) []sqlToken {
+	switch t.targetType {
+	case "oracle", "sqlite", "sqlserver":
+	default:
+		return tokens
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.kind != tokWord || (!tok.is("true") && !tok.is("false")) {
+			continue
+		}
+		value := "0"
+		if tok.is("true") {
+			value = "1"
+		}
+		tokens[i] = sqlToken{kind: tokNumber, text: value}
+
+		// x IS [NOT] TRUE 改写为 x = 1 / x <> 1
+		prev := prevSignificant(tokens, i)
+		if prev < 0 {
+			continue
+		}
+		if tokens[prev].is("is") {
+			tokens[prev] = symbol("=")
+		} else if beforeNot := prevSignificant(tokens, prev); tokens[prev].is("not") && beforeNot >= 0 && tokens[beforeNot].is("is") {
+			tokens = splice(tokens, beforeNot, i, []sqlToken{symbol("<>"), space()})
+			i = beforeNot + 2
+		}
+	}
+
+	return tokens
+}
+
+// rewriteQuoting 转换标识符引号和双引号字符串
+func (t *SQLTranslator) rewriteQuoting(tokens []sqlToken) []sqlToken {
+	for i, tok := range tokens {
+		switch tok.kind {
+		case tokIdent:
+			closeQuote := tok.text[len(tok.text)-1:]
+			name := strings.ReplaceAll(tok.text[1:len(tok.text)-1], closeQuote+closeQuote, closeQuote)
+			tokens[i].text = t.target.QuoteIdentifier(name)
+		case tokString:
+			if tok.text[0] == '"' {
+				value := strings.ReplaceAll(tok.text[1:len(tok.text)-1], `""`, `"`)
+				tokens[i].text = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+		}
+	}
+	return tokens
+}
+
+// rewritePlaceholders 将占位符转换为目标数据库的风格
+func (t *SQLTranslator) rewritePlaceholders(tokens []sqlToken) error {
+	expected := 1
+	for i, tok := range tokens {
+		if tok.kind != tokParam {
+			continue
+		}
+
+		switch t.targetType {
+		case "mysql", "sqlite":
+			// ? 占位符按出现顺序绑定，无法表达重复或乱序的参数
+			if tok.index != expected {
+				return fmt.Errorf("placeholder %s cannot be expressed as positional '?' for %s", tok.text, t.targetType)
+			}
+			expected++
+			tokens[i].text = "?"
+		case "oracle":
+			tokens[i].text = fmt.Sprintf(":%d", tok.index)
+		case "sqlserver":
+			tokens[i].text = fmt.Sprintf("@p%d", tok.index)
+		default:
+			tokens[i].text = fmt.Sprintf("$%d", tok.index)
+		}
+	}
+	return nil
+}
+
+// ===== 表达式改写 =====
+
+// intervalUnits 时间间隔单位（含复数形式）
+var intervalUnits = map[string]string{
+	"year": "YEAR", "years": "YEAR",
+	"month": "MONTH", "months": "MONTH",
+	"day": "DAY", "days": "DAY",
+	"hour": "HOUR", "hours": "HOUR",
+	"minute": "MINUTE", "minutes": "MINUTE",
+	"second": "SECOND", "seconds": "SECOND",
+}
+
+// parseInterval 解析 INTERVAL '1 day'、INTERVAL '1' DAY 和 INTERVAL 1 DAY，返回数量、单位和结束位置
+func parseInterval(tokens []sqlToken, i int) (string, string, int, error) {
+	valueIdx := nextSignificant(tokens, i)
+	if valueIdx < 0 {
+		return "", "", 0, errors.New("INTERVAL requires a value")
+	}
+
+	value := tokens[valueIdx]
+	switch value.kind {
+	case tokString:
+		content := strings.TrimSpace(value.text[1 : len(value.text)-1])
+		if parts := strings.Fields(content); len(parts) == 2 {
+			// INTERVAL '1 day'
+			unit, ok := intervalUnits[strings.ToLower(parts[1])]
+			if !ok {
+				return "", "", 0, fmt.Errorf("unsupported interval unit: %s", parts[1])
+			}
+			if _, err := strconv.ParseFloat(parts[0], 64); err != nil {
+				return "", "", 0, fmt.Errorf("invalid interval amount: %s", parts[0])
+			}
+			return parts[0], unit, valueIdx + 1, nil
+		}
+		if _, err := strconv.ParseFloat(content, 64); err != nil {
+			return "", "", 0, fmt.Errorf("invalid interval amount: %s", content)
+		}
+		value.text = content
+	case tokNumber:
+	default:
+		return "", "", 0, fmt.Errorf("unsupported interval value: %s", value.text)
+	}
+
+	unitIdx := nextSignificant(tokens, valueIdx)
+	if unitIdx < 0 || tokens[unitIdx].kind != tokWord {
+		return "", "", 0, errors.New("INTERVAL requires a unit")
+	}
+	unit, ok := intervalUnits[strings.ToLower(tokens[unitIdx].text)]
+	if !ok {
+		return "", "", 0, fmt.Errorf("unsupported interval unit: %s", tokens[unitIdx].text)
+	}
+
+	return value.text, unit, unitIdx + 1, nil
+}
+
+// negateAmount 对数量取反
+func negateAmount(amount string) string {
+	if strings.HasPrefix(amount, "-") {
+		return amount[1:]
+	}
+	return "-" + strings.TrimPrefix(amount, "+")
+}
+
+// rewriteIntervals 改写日期加减 INTERVAL 的写法
+func (t *SQLTranslator) rewriteIntervals(tokens []sqlToken) ([]sqlToken, error) {
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("interval") {
+			continue
+		}
+
+		amount, unit, end, err := parseInterval(tokens, i)
+		if err != nil {
+			return nil, err
+		}
+
+		// 只有 PostgreSQL、Oracle、MySQL 支持独立的 INTERVAL 表达式
+		opIdx := prevSignificant(tokens, i)
+		hasOperator := opIdx >= 0 && (tokens[opIdx].is("+") || tokens[opIdx].is("-"))
+
+		var replacement []sqlToken
+		start := i
+
+		switch t.targetType {
+		case "postgres":
+			replacement = []sqlToken{word("INTERVAL"), space(),
+				{kind: tokString, text: fmt.Sprintf("'%s %s'", amount, strings.ToLower(unit))}}
+		case "mysql":
+			replacement = []sqlToken{word("INTERVAL"), space(), {kind: tokNumber, text: amount}, space(), word(unit)}
+		case "oracle":
+			// NUMTODSINTERVAL 不受 INTERVAL 字面量默认两位精度的限制
+			fn := "NUMTODSINTERVAL"
+			if unit == "YEAR" || unit == "MONTH" {
+				fn = "NUMTOYMINTERVAL"
+			}
+			replacement = call(fn, []sqlToken{{kind: tokNumber, text: amount}}, []sqlToken{{kind: tokString, text: "'" + unit + "'"}})
+		case "sqlite", "sqlserver":
+			if !hasOperator {
+				return nil, fmt.Errorf("standalone INTERVAL is not supported for %s", t.targetType)
+			}
+			start = operandBefore(tokens, opIdx)
+			if start < 0 {
+				return nil, errors.New("cannot determine the date expression before INTERVAL")
+			}
+			if tokens[opIdx].is("-") {
+				amount = negateAmount(amount)
+			}
+			date := trimTrivia(tokens[start:opIdx])
+
+			if t.targetType == "sqlite" {
+				if !strings.HasPrefix(amount, "-") {
+					amount = "+" + strings.TrimPrefix(amount, "+")
+				}
+				modifier := fmt.Sprintf("'%s %ss'", amount, strings.ToLower(unit))
+				replacement = call("datetime", date, []sqlToken{{kind: tokString, text: modifier}})
+			} else {
+				replacement = call("DATEADD", []sqlToken{word(unit)}, []sqlToken{{kind: tokNumber, text: amount}}, date)
+			}
+		}
+
+		tokens = splice(tokens, start, end, replacement)
+		i = start + len(replacement) - 1
+	}
+
+	return tokens, nil
+}
+
+// splitArguments 按顶层逗号拆分函数参数
+func splitArguments(tokens []sqlToken) [][]sqlToken {
+	var args [][]sqlToken
+	depth := 0
+	last := 0
+	for i, tok := range tokens {
+		switch {
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		case tok.is(",") && depth == 0:
+			args = append(args, trimTrivia(tokens[last:i]))
+			last = i + 1
+		}
+	}
+	return append(args, trimTrivia(tokens[last:]))
+}
+
+// rewriteConcat 在 || 与 CONCAT() 之间转换字符串拼接
+func (t *SQLTranslator) rewriteConcat(tokens []sqlToken) ([]sqlToken, error) {
+	// MySQL 和 SQL Server 使用 CONCAT，其余数据库使用 ||
+	useFunction := t.targetType == "mysql" || t.targetType == "sqlserver"
+
+	for i := 0; i < len(tokens); i++ {
+		var start, end int
+		var operands [][]sqlToken
+
+		switch {
+		case useFunction && tokens[i].is("||"):
+			start = operandBefore(tokens, i)
+			if start < 0 {
+				return nil, errors.New("cannot determine the operand before '||'")
+			}
+			operands = append(operands, trimTrivia(tokens[start:i]))
+
+			for op := i; ; {
+				operandStart, operandEnd := operandAfter(tokens, op)
+				if operandStart < 0 {
+					return nil, errors.New("cannot determine the operand after '||'")
+				}
+				operands = append(operands, tokens[operandStart:operandEnd])
+				end = operandEnd
+
+				next := nextSignificant(tokens, operandEnd-1)
+				if next < 0 || !tokens[next].is("||") {
+					break
+				}
+				op = next
+			}
+
+		case !useFunction && tokens[i].is("concat") && i+1 < len(tokens) && tokens[i+1].is("("):
+			closeIdx := matchClose(tokens, i+1)
+			if closeIdx < 0 {
+				return nil, errors.New("unbalanced parentheses in CONCAT")
+			}
+			start, end = i, closeIdx+1
+			operands = splitArguments(tokens[i+2 : closeIdx])
+
+		default:
+			continue
+		}
+
+		// 操作数内部可能还有嵌套的拼接
+		for j, operand := range operands {
+			rewritten, err := t.rewriteConcat(operand)
+			if err != nil {
+				return nil, err
+			}
+			operands[j] = rewritten
+		}
+
+		var replacement []sqlToken
+		if useFunction {
+			replacement = call("CONCAT", operands...)
+		} else {
+			var joined []sqlToken
+			for j, operand := range operands {
+				if j > 0 {
+					joined = append(joined, space(), symbol("||"), space())
+				}
+				joined = append(joined, operand...)
+			}
+			replacement = parens(joined)
+		}
+
+		tokens = splice(tokens, start, end, replacement)
+		i = start + len(replacement) - 1
+	}
+
+	return tokens, nil
+}
+
+// rewriteILike 将 ILIKE 改写为 LOWER(x) LIKE LOWER(y)（PostgreSQL 以外的数据库）
+func (t *SQLTranslator) rewriteILike(tokens []sqlToken) ([]sqlToken, error) {
+	if t.targetType == "postgres" {
+		return tokens, nil
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("ilike") {
+			continue
+		}
+
+		// x NOT ILIKE y
+		leftEnd := i
+		negated := false
+		if prev := prevSignificant(tokens, i); prev >= 0 && tokens[prev].is("not") {
+			leftEnd = prev
+			negated = true
+		}
+
+		start := operandBefore(tokens, leftEnd)
+		rightStart, end := operandAfter(tokens, i)
+		if start < 0 || rightStart < 0 {
+			return nil, errors.New("cannot determine the operands of ILIKE")
+		}
+
+		left, err := t.rewriteILike(trimTrivia(tokens[start:leftEnd]))
+		if err != nil {
+			return nil, err
+		}
+		right, err := t.rewriteILike(tokens[rightStart:end])
+		if err != nil {
+			return nil, err
+		}
+
+		replacement := append(call("LOWER", left), space())
+		if negated {
+			replacement = append(replacement, word("NOT"), space())
+		}
+		replacement = append(replacement, word("LIKE"), space())
+		replacement = append(replacement, call("LOWER", right)...)
+
+		tokens = splice(tokens, start, end, replacement)
+		i = start + len(replacement) - 1
+	}
+
+	return tokens, nil
+}
+
+// ===== 分页改写 =====
+
+// pagination 解析出的分页参数（数字或占位符）
+type pagination struct {
+	limit  []sqlToken
+	offset []sqlToken
+}
+
+// rewritePagination 将顶层的 LIMIT/OFFSET、FETCH FIRST 或 TOP 转换为目标数据库的分页写法
+func (t *SQLTranslator) rewritePagination(tokens []sqlToken) ([]sqlToken, error) {
+	// 结尾的分号保持在分页子句之后
+	bodyEnd := len(tokens)
+	for bodyEnd > 0 && (tokens[bodyEnd-1].isTrivia() || tokens[bodyEnd-1].is(";")) {
+		bodyEnd--
+	}
+	body, tail := tokens[:bodyEnd], tokens[bodyEnd:]
+
+	page, body, err := extractTop(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查找顶层的第一个分页关键字
+	clauseStart := -1
+	depth := 0
+	for i, tok := range body {
+		switch {
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		case depth == 0 && (tok.is("limit") || tok.is("offset") || tok.is("fetch")):
+			clauseStart = i
+		}
+		if clauseStart >= 0 {
+			break
+		}
+	}
+
+	if clauseStart >= 0 {
+		if page != nil {
+			return nil, errors.New("cannot combine TOP with LIMIT/OFFSET/FETCH")
+		}
+		if page, err = parsePagination(body[clauseStart:]); err != nil {
+			return nil, err
+		}
+		body = trimTrivia(body[:clauseStart])
+	}
+
+	if page == nil || (page.limit == nil && page.offset == nil) {
+		return tokens, nil
+	}
+
+	var result []sqlToken
+	if t.targetType == "sqlserver" && page.offset == nil {
+		result = insertTop(body, page.limit)
+	} else {
+		result = append(append(result, body...), t.renderPagination(body, page)...)
+	}
+
+	return append(result, tail...), nil
+}
+
+// extractTop 移除 SELECT [DISTINCT] TOP (n) 中的 TOP 子句
+func extractTop(tokens []sqlToken) (*pagination, []sqlToken, error) {
+	selectIdx := nextSignificant(tokens, -1)
+	if selectIdx < 0 || !tokens[selectIdx].is("select") {
+		return nil, tokens, nil
+	}
+
+	topIdx := nextSignificant(tokens, selectIdx)
+	if topIdx >= 0 && (tokens[topIdx].is("distinct") || tokens[topIdx].is("all")) {
+		topIdx = nextSignificant(tokens, topIdx)
+	}
+	if topIdx < 0 || !tokens[topIdx].is("top") {
+		return nil, tokens, nil
+	}
+
+	valueStart, valueEnd := operandAfter(tokens, topIdx)
+	if valueStart < 0 {
+		return nil, nil, errors.New("TOP requires a value")
+	}
+
+	value := tokens[valueStart:valueEnd]
+	if value[0].is("(") {
+		value = trimTrivia(value[1 : len(value)-1])
+	}
+	if len(value) != 1 || (value[0].kind != tokNumber && value[0].kind != tokParam) {
+		return nil, nil, errors.New("TOP value must be a number or placeholder")
+	}
+	if next := nextSignificant(tokens, valueEnd-1); next >= 0 && (tokens[next].is("percent") || tokens[next].is("with")) {
+		return nil, nil, errors.New("TOP PERCENT and WITH TIES are not supported")
+	}
+
+	// 同时移除 TOP 子句后的空白
+	end := valueEnd
+	for end < len(tokens) && tokens[end].isTrivia() {
+		end++
+	}
+
+	return &pagination{limit: value}, splice(tokens, topIdx, end, nil), nil
+}
+
+// parsePagination 解析 LIMIT n [OFFSET m]、LIMIT m, n、OFFSET m ROWS [FETCH ...] 和 FETCH FIRST n ROWS ONLY
+func parsePagination(tokens []sqlToken) (*pagination, error) {
+	var words []sqlToken
+	for _, tok := range tokens {
+		if !tok.isTrivia() {
+			words = append(words, tok)
+		}
+	}
+
+	page := &pagination{}
+	isValue := func(i int) bool {
+		return i < len(words) && (words[i].kind == tokNumber || words[i].kind == tokParam)
+	}
+	value := func(i int) []sqlToken {
+		return []sqlToken{words[i]}
+	}
+	invalid := errors.New("unsupported pagination clause: " + renderTokens(tokens))
+
+	for i := 0; i < len(words); {
+		switch {
+		case words[i].is("limit"):
+			if i+1 < len(words) && words[i+1].is("all") {
+				i += 2
+				continue
+			}
+			if !isValue(i + 1) {
+				return nil, invalid
+			}
+			// MySQL 的 LIMIT offset, count
+			if i+2 < len(words) && words[i+2].is(",") {
+				if !isValue(i + 3) {
+					return nil, invalid
+				}
+				page.offset, page.limit = value(i+1), value(i+3)
+				i += 4
+				continue
+			}
+			page.limit = value(i + 1)
+			i += 2
+
+		case words[i].is("offset"):
+			if !isValue(i + 1) {
+				return nil, invalid
+			}
+			page.offset = value(i + 1)
+			i += 2
+			if i < len(words) && (words[i].is("row") || words[i].is("rows")) {
+				i++
+			}
+
+		case words[i].is("fetch"):
+			// FETCH FIRST|NEXT n ROW|ROWS ONLY
+			if i+4 >= len(words) || !(words[i+1].is("first") || words[i+1].is("next")) || !isValue(i+2) ||
+				!(words[i+3].is("row") || words[i+3].is("rows")) || !words[i+4].is("only") {
+				return nil, invalid
+			}
+			page.limit = value(i + 2)
+			i += 5
+
+		default:
+			return nil, invalid
+		}
+	}
+
+	return page, nil
+}
+
+// renderPagination 生成目标数据库的分页子句
+func (t *SQLTranslator) renderPagination(body []sqlToken, page *pagination) []sqlToken {
+	var result []sqlToken
+	add := func(items ...sqlToken) {
+		result = append(result, items...)
+	}
+	clause := func(keyword string, value []sqlToken, suffix ...string) {
+		add(space(), word(keyword), space())
+		add(value...)
+		for _, s := range suffix {
+			add(space(), word(s))
+		}
+	}
+
+	switch t.targetType {
+	case "oracle", "sqlserver":
+		// OFFSET FETCH 在 SQL Server 中必须带 ORDER BY
+		if t.targetType == "sqlserver" && !hasTopLevelOrderBy(body) {
+			add(space(), word("ORDER"), space(), word("BY"), space())
+			add(parens([]sqlToken{word("SELECT"), space(), word("NULL")})...)
+		}
+		if page.offset != nil || t.targetType == "sqlserver" {
+			offset := page.offset
+			if offset == nil {
+				offset = []sqlToken{{kind: tokNumber, text: "0"}}
+			}
+			clause("OFFSET", offset, "ROWS")
+		}
+		if page.limit != nil {
+			keyword := "NEXT"
+			if page.offset == nil {
+				keyword = "FIRST"
+			}
+			add(space(), word("FETCH"), space(), word(keyword), space())
+			add(page.limit...)
+			add(space(), word("ROWS"), space(), word("ONLY"))
+		}
+	default:
+		limit := page.limit
+		if limit == nil && t.targetType != "postgres" {
+			// SQLite、MySQL 的 OFFSET 必须跟在 LIMIT 之后
+			unlimited := "-1"
+			if t.targetType == "mysql" {
+				unlimited = "18446744073709551615"
+			}
+			limit = []sqlToken{{kind: tokNumber, text: unlimited}}
+		}
+		if limit != nil {
+			clause("LIMIT", limit)
+		}
+		if page.offset != nil {
+			clause("OFFSET", page.offset)
+		}
+	}
+
+	return result
+}
+
+// hasTopLevelOrderBy 检查查询顶层是否有 ORDER BY
+func hasTopLevelOrderBy(tokens []sqlToken) bool {
+	depth := 0
+	for i, tok := range tokens {
+		switch {
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		case depth == 0 && tok.is("order"):
+			if next := nextSignificant(tokens, i); next >= 0 && tokens[next].is("by") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// insertTop 在 SELECT [DISTINCT] 之后插入 TOP (n)
+func insertTop(tokens []sqlToken, limit []sqlToken) []sqlToken {
+	selectIdx := nextSignificant(tokens, -1)
+	if selectIdx < 0 || !tokens[selectIdx].is("select") {
+		return tokens
+	}
+
+	insertAt := selectIdx
+	if next := nextSignificant(tokens, selectIdx); next >= 0 && (tokens[next].is("distinct") || tokens[next].is("all")) {
+		insertAt = next
+	}
+
+	top := append([]sqlToken{space(), word("TOP"), space()}, parens(limit)...)
+	return splice(tokens, insertAt+1, insertAt+1, top)
+}
@@ -0,0 +1,55 @@
+package sql
+
+import "testing"
+
+func TestSQLTranslator_Portable(t *testing.T) {
+	cases := []struct {
+		target   string
+		query    string
+		expected string
+	}{
+		{"oracle", "SELECT * FROM items WHERE active = TRUE AND created_at > NOW() - INTERVAL '7 days' LIMIT 10 OFFSET 20",
+			"SELECT * FROM items WHERE active = 1 AND created_at > SYSDATE - NUMTODSINTERVAL(7, 'DAY') OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{"postgres", "SELECT TOP (5) name FROM items WHERE name ILIKE ? AND category = ?",
+			"SELECT name FROM items WHERE name ILIKE $1 AND category = $2 LIMIT 5"},
+		{"mysql", `SELECT "name" || '-' || category AS label FROM items WHERE name ILIKE $1 FETCH FIRST 3 ROWS ONLY`,
+			"SELECT CONCAT(`name`, '-', category) AS label FROM items WHERE LOWER(name) LIKE LOWER(?) LIMIT 3"},
+		{"sqlserver", "SELECT name FROM items WHERE active IS NOT FALSE LIMIT ?",
+			"SELECT TOP (@p1) name FROM items WHERE active <> 0"},
+		{"sqlserver", "SELECT name FROM items WHERE created_at < NOW() + INTERVAL 1 HOUR LIMIT 10 OFFSET 10;",
+			"SELECT name FROM items WHERE created_at < DATEADD(HOUR, 1, GETDATE()) ORDER BY (SELECT NULL) OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY;"},
+		{"sqlite", "SELECT CONCAT(name, category) FROM items WHERE updated_at - INTERVAL '1' MONTH > created_at",
+			"SELECT (name || category) FROM items WHERE datetime(updated_at, '-1 months') > created_at"},
+	}
+
+	for _, c := range cases {
+		translated, err := NewSQLTranslator(c.target).Translate(c.query, PortableDialect)
+		if err != nil {
+			t.Errorf("Failed to translate for %s: %v", c.target, err)
+			continue
+		}
+		if translated != c.expected {
+			t.Errorf("Unexpected %s translation:\n got: %s\nwant: %s", c.target, translated, c.expected)
+		}
+	}
+}
+
+func TestSQLTranslator_SourceDialect(t *testing.T) {
+	// MySQL 的 LIMIT offset, count 转换为 PostgreSQL 时保持参数绑定
+	translated, err := NewSQLTranslator("postgres").Translate("SELECT * FROM items LIMIT ?, ?", "mysql")
+	if err != nil {
+		t.Fatalf("Failed to translate: %v", err)
+	}
+	if expected := "SELECT * FROM items LIMIT $2 OFFSET $1"; translated != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, translated)
+	}
+
+	// 乱序参数无法用 ? 表达
+	if _, err := NewSQLTranslator("mysql").Translate("SELECT * FROM items OFFSET $1 LIMIT $2", "postgres"); err == nil {
+		t.Error("Expected error for reordered placeholders")
+	}
+
+	if _, err := NewSQLTranslator("mysql").Translate("SELECT 1", "db2"); err == nil {
+		t.Error("Expected error for unsupported dialect")
+	}
+}
@@ -0,0 +1,310 @@
+package sql
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sql2api/internal/model"
+)
+
+// URLQuery PostgREST 风格 URL 查询的解析结果
+type URLQuery struct {
+	Query  *model.StructuredQuery
+	Offset int
+	Limit  int  // 0 表示不限制行数
+	Ranged bool // 通过 Range 请求头或 limit/offset 参数请求了部分结果
+	Count  bool // Prefer: count=exact，需要返回总行数
+}
+
+// columnPattern URL 查询中的列名
+var columnPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// rangePattern Range 请求头，如 0-24 或 items=0-24（结束位置可省略）
+var rangePattern = regexp.MustCompile(`^(?:items=)?(\d+)-(\d*)$`)
+
+// urlNegatedOperators not. 前缀对应的取反运算符
+var urlNegatedOperators = map[string]string{
+	"eq":   "neq",
+	"neq":  "eq",
+	"gt":   "lte",
+	"gte":  "lt",
+	"lt":   "gte",
+	"lte":  "gt",
+	"like": "nlike",
+	"in":   "nin",
+}
+
+// ParseURLQuery 将 PostgREST 风格的查询参数和请求头解析为结构化查询
+//
+// 支持的语法：
+//   - select=id,name,label:title 选择列（alias:column 为列设置别名）
+//   - <column>=<op>.<value> 过滤条件，op 为 eq、neq、gt、gte、lt、lte、like、ilike、in、is，可加 not. 前缀
+//   - order=created_at.desc,id 排序
+//   - limit、offset 或 Range 请求头分页，Prefer: count=exact 返回总行数
+func ParseURLQuery(table string, values url.Values, header http.Header) (*URLQuery, error) {
+	result := &URLQuery{
+		Query: &model.StructuredQuery{
+			Table:  table,
+			Action: "select",
+		},
+	}
+
+	// Range 请求头分页，limit/offset 参数优先
+	if rangeHeader := strings.TrimSpace(header.Get("Range")); rangeHeader != "" {
+		offset, limit, err := parseRangeHeader(rangeHeader)
+		if err != nil {
+			return nil, err
+		}
+		result.Offset, result.Limit, result.Ranged = offset, limit, true
+	}
+
+	for _, prefer := range strings.Split(header.Get("Prefer"), ",") {
+		if strings.TrimSpace(prefer) == "count=exact" {
+			result.Count = true
+		}
+	}
+
+	for key, items := range values {
+		switch key {
+		case "select":
+			fields, err := parseSelect(items[len(items)-1])
+			if err != nil {
+				return nil, err
+			}
+			result.Query.Fields = fields
+
+		case "order":
+			orderBy, err := parseOrder(items[len(items)-1])
+			if err != nil {
+				return nil, err
+			}
+			result.Query.OrderBy = orderBy
+
+		case "limit":
+			limit, err := parseNonNegative(key, items[len(items)-1])
+			if err != nil {
+				return nil, err
+			}
+			result.Limit, result.Ranged = limit, true
+
+		case "offset":
+			offset, err := parseNonNegative(key, items[len(items)-1])
+			if err != nil {
+				return nil, err
+			}
+			result.Offset, result.Ranged = offset, true
+
+		default:
+			// 其余参数为过滤条件，同一列可以出现多次（如 price=gt.10&price=lt.20）
+			if !columnPattern.MatchString(key) {
+				return nil, fmt.Errorf("invalid column name: %s", key)
+			}
+			operators := make(map[string]interface{}, len(items))
+			for _, item := range items {
+				op, value, err := parseFilter(key, item)
+				if err != nil {
+					return nil, err
+				}
+				if _, exists := operators[op]; exists {
+					return nil, fmt.Errorf("duplicate operator %s on %s", op, key)
+				}
+				operators[op] = value
+			}
+			if result.Query.Where == nil {
+				result.Query.Where = make(map[string]interface{})
+			}
+			result.Query.Where[key] = operators
+		}
+	}
+
+	return result, nil
+}
+
+// parseRangeHeader 解析 Range 请求头，返回偏移量和行数（结束位置省略时不限制行数）
+func parseRangeHeader(header string) (int, int, error) {
+	matches := rangePattern.FindStringSubmatch(header)
+	if matches == nil {
+		return 0, 0, fmt.Errorf("invalid Range header: %s", header)
+	}
+
+	start, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Range header: %s", header)
+	}
+	if matches[2] == "" {
+		return start, 0, nil
+	}
+
+	end, err := strconv.Atoi(matches[2])
+	if err != nil || end < start {
+		return 0, 0, fmt.Errorf("invalid Range header: %s", header)
+	}
+	return start, end - start + 1, nil
+}
+
+// parseSelect 解析 select 参数
+func parseSelect(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "*" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		alias, column := "", item
+		if idx := strings.Index(item, ":"); idx >= 0 {
+			alias, column = item[:idx], item[idx+1:]
+			if !columnPattern.MatchString(alias) {
+				return nil, fmt.Errorf("invalid alias in select: %s", item)
+			}
+		}
+		if !columnPattern.MatchString(column) {
+			return nil, fmt.Errorf("invalid column in select: %s", item)
+		}
+
+		if alias != "" {
+			fields = append(fields, column+" AS "+alias)
+		} else {
+			fields = append(fields, column)
+		}
+	}
+	return fields, nil
+}
+
+// parseOrder 解析 order 参数，如 created_at.desc,id
+func parseOrder(value string) ([]model.OrderByClause, error) {
+	var orderBy []model.OrderByClause
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ".")
+		if !columnPattern.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid column in order: %s", item)
+		}
+
+		clause := model.OrderByClause{Field: parts[0], Order: "asc"}
+		for _, modifier := range parts[1:] {
+			switch modifier {
+			case "asc", "desc":
+				clause.Order = modifier
+			default:
+				return nil, fmt.Errorf("unsupported order modifier %s in %s", modifier, item)
+			}
+		}
+		orderBy = append(orderBy, clause)
+	}
+	return orderBy, nil
+}
+
+// parseNonNegative 解析非负整数参数
+func parseNonNegative(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+// parseFilter 解析单个过滤条件，返回 QueryBuilder 的条件运算符和参数值
+func parseFilter(column, value string) (string, interface{}, error) {
+	negated := false
+	if strings.HasPrefix(value, "not.") {
+		negated, value = true, value[len("not."):]
+	}
+
+	idx := strings.Index(value, ".")
+	if idx < 0 {
+		return "", nil, fmt.Errorf("invalid filter on %s: expected <operator>.<value>", column)
+	}
+	op, operand := value[:idx], value[idx+1:]
+
+	switch op {
+	case "eq", "neq", "gt", "gte", "lt", "lte":
+		return negate(op, negated), operand, nil
+
+	case "like", "ilike":
+		// PostgREST 使用 * 作为通配符，避免在 URL 中转义 %
+		pattern := strings.ReplaceAll(operand, "*", "%")
+		if op == "ilike" && negated {
+			return "", nil, fmt.Errorf("operator not.ilike on %s is not supported", column)
+		}
+		return negate(op, negated), pattern, nil
+
+	case "in":
+		items, err := parseList(operand)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid in filter on %s: %w", column, err)
+		}
+		return negate(op, negated), items, nil
+
+	case "is":
+		switch operand {
+		case "null":
+			return "is_null", !negated, nil
+		case "true", "false":
+			if negated {
+				return "", nil, fmt.Errorf("operator not.is.%s on %s is not supported", operand, column)
+			}
+			return "eq", operand == "true", nil
+		}
+		return "", nil, fmt.Errorf("invalid is filter on %s: expected null, true or false", column)
+	}
+
+	return "", nil, fmt.Errorf("unsupported operator %s on %s", op, column)
+}
+
+// negate 返回 not. 前缀取反后的运算符
+func negate(op string, negated bool) string {
+	if !negated {
+		return op
+	}
+	return urlNegatedOperators[op]
+}
+
+// parseList 解析 in 条件的列表，如 (a,b,"c,d")
+func parseList(value string) ([]interface{}, error) {
+	if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+		return nil, fmt.Errorf("expected a list in parentheses")
+	}
+	value = value[1 : len(value)-1]
+
+	var items []interface{}
+	var current strings.Builder
+	quoted, wasQuoted := false, false
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch == '"':
+			quoted = !quoted
+			wasQuoted = true
+		case ch == '\\' && quoted && i+1 < len(value):
+			i++
+			current.WriteByte(value[i])
+		case ch == ',' && !quoted:
+			items = append(items, listItem(current.String(), wasQuoted))
+			current.Reset()
+			wasQuoted = false
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	items = append(items, listItem(current.String(), wasQuoted))
+
+	if len(items) == 1 && items[0] == "" && !wasQuoted {
+		return nil, fmt.Errorf("list cannot be empty")
+	}
+	return items, nil
+}
+
+// listItem 未加引号的列表项去掉首尾空白
+func listItem(value string, quoted bool) interface{} {
+	if quoted {
+		return value
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,74 @@
+package sql
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestParseURLQuery(t *testing.T) {
+	values, _ := url.ParseQuery(`select=id,label:name&price=gt.10&price=not.gte.50&category=in.(a,"b,c")&note=is.null&name=like.*kit*&order=created_at.desc,id`)
+	header := http.Header{}
+	header.Set("Range", "10-19")
+	header.Set("Prefer", "return=representation, count=exact")
+
+	query, err := ParseURLQuery("items", values, header)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if query.Offset != 10 || query.Limit != 10 || !query.Ranged || !query.Count {
+		t.Errorf("Unexpected paging: %+v", query)
+	}
+
+	fields := query.Query.Fields
+	if len(fields) != 2 || fields[0] != "id" || fields[1] != "name AS label" {
+		t.Errorf("Unexpected fields: %v", fields)
+	}
+
+	orderBy := query.Query.OrderBy
+	if len(orderBy) != 2 || orderBy[0].Field != "created_at" || orderBy[0].Order != "desc" || orderBy[1].Order != "asc" {
+		t.Errorf("Unexpected order: %+v", orderBy)
+	}
+
+	price := query.Query.Where["price"].(map[string]interface{})
+	if price["gt"] != "10" || price["lt"] != "50" {
+		t.Errorf("Unexpected price filter: %v", price)
+	}
+	category := query.Query.Where["category"].(map[string]interface{})["in"].([]interface{})
+	if len(category) != 2 || category[1] != "b,c" {
+		t.Errorf("Unexpected in filter: %v", category)
+	}
+	if query.Query.Where["note"].(map[string]interface{})["is_null"] != true {
+		t.Errorf("Unexpected is filter: %v", query.Query.Where["note"])
+	}
+	if query.Query.Where["name"].(map[string]interface{})["like"] != "%kit%" {
+		t.Errorf("Unexpected like filter: %v", query.Query.Where["name"])
+	}
+
+	// limit/offset 参数优先于 Range 请求头
+	values, _ = url.ParseQuery("limit=5")
+	query, _ = ParseURLQuery("items", values, header)
+	if query.Offset != 10 || query.Limit != 5 {
+		t.Errorf("Expected limit parameter to override Range, got %+v", query)
+	}
+}
+
+func TestParseURLQuery_Invalid(t *testing.T) {
+	cases := []string{
+		"price=between.1",
+		"price=10",
+		"price=eq.1&price=eq.2",
+		"category=in.()",
+		"select=id,name+desc",
+		"order=id.nullsfirst",
+		"limit=-1",
+		"1col=eq.1",
+	}
+	for _, raw := range cases {
+		values, _ := url.ParseQuery(raw)
+		if _, err := ParseURLQuery("items", values, http.Header{}); err == nil {
+			t.Errorf("Expected %q to fail", raw)
+		}
+	}
+}
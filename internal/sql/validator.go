@@ -7,6 +7,9 @@ import (
 	"strings"
 )
 
+// sqlServerPlaceholderPattern 匹配 SQL Server 参数占位符
+var sqlServerPlaceholderPattern = regexp.MustCompile(`(^|[^@\w])@p\d+\b`)
+
 // QueryValidator 查询验证器
 type QueryValidator struct {
 	maxQueryLength    int
@@ -31,6 +34,7 @@ func NewQueryValidator() *QueryValidator {
 			// 日期函数
 			"now": true, "current_date": true, "current_time": true, "current_timestamp": true,
 			"date": true, "time": true, "year": true, "month": true, "day": true,
+			"strftime": true, "julianday": true, "sysdatetime": true, // 时间戳版本列的递增和比较
 			// 条件函数
 			"case": true, "when": true, "then": true, "else": true, "end": true,
 			"coalesce": true, "nullif": true, "isnull": true, "ifnull": true,
@@ -124,6 +128,9 @@ func (v *QueryValidator) calculateQueryComplexity(query string) int {
 
 // checkForbiddenPatterns 检查禁止的模式
 func (v *QueryValidator) checkForbiddenPatterns(query string) error {
+	// SQL Server 的 @pN 参数占位符不属于变量访问
+	query = sqlServerPlaceholderPattern.ReplaceAllString(query, "${1}?")
+
 	for _, pattern := range v.forbiddenPatterns {
 		if pattern.MatchString(query) {
 			return fmt.Errorf("query contains forbidden pattern")
@@ -149,14 +156,18 @@ func (v *QueryValidator) validateFunctions(query string) error {
 
 // extractFunctions 提取查询中的函数
 func (v *QueryValidator) extractFunctions(query string) []string {
-	// 使用正则表达式匹配函数调用模式
-	funcPattern := regexp.MustCompile(`(\w+)\s*\(`)
+	// 使用正则表达式匹配函数调用模式，同时捕获前一个单词
+	// 用于排除 INSERT INTO table (...) 中的表名和 AS alias (...) 中的别名
+	funcPattern := regexp.MustCompile(`(?:(\w+)\s+)?(\w+)\s*\(`)
 	matches := funcPattern.FindAllStringSubmatch(query, -1)
 
 	var functions []string
 	for _, match := range matches {
-		if len(match) > 1 {
-			funcName := strings.ToLower(match[1])
+		if len(match) > 2 {
+			funcName := strings.ToLower(match[2])
+			if prev := strings.ToLower(match[1]); prev == "into" || prev == "as" {
+				continue
+			}
 			// 过滤掉 SQL 关键字
 			if !v.isSQLKeyword(funcName) {
 				functions = append(functions, funcName)
@@ -177,6 +188,7 @@ func (v *QueryValidator) isSQLKeyword(word string) bool {
 		"union": true, "all": true, "exists": true, "in": true, "not": true,
 		"is": true, "null": true, "like": true, "between": true, "case": true,
 		"when": true, "then": true, "else": true, "end": true, "with": true,
+		"values": true, "into": true, "insert": true, "using": true,
 	}
 	return keywords[strings.ToLower(word)]
 }
@@ -267,7 +279,7 @@ func (v *QueryValidator) checkBasicSQLStructure(query string) error {
 	lowerQuery := strings.ToLower(strings.TrimSpace(query))
 
 	// 检查是否以有效的 SQL 关键字开始
-	validStarters := []string{"select", "insert", "update", "delete", "with"}
+	validStarters := []string{"select", "insert", "update", "delete", "with", "merge"}
 	hasValidStarter := false
 	for _, starter := range validStarters {
 		if strings.HasPrefix(lowerQuery, starter) {
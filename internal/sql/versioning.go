@@ -0,0 +1,188 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"sql2api/internal/config"
+	"sql2api/internal/model"
+)
+
+// timestampFunctions 各数据库写入时间戳版本列和软删除标记使用的当前时间表达式（尽量使用最高精度，避免同一秒内的更新得到相同版本）
+var timestampFunctions = map[string]string{
+	"postgres":  "CURRENT_TIMESTAMP",
+	"oracle":    "SYSTIMESTAMP",
+	"sqlite":    "strftime('%Y-%m-%d %H:%M:%f', 'now')",
+	"mysql":     "CURRENT_TIMESTAMP(6)",
+	"sqlserver": "SYSDATETIME()",
+}
+
+// versionTimeLayouts 解析时间戳版本值时接受的格式
+var versionTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// sqliteVersionTimeLayout SQLite 以文本保存时间，绑定期望版本时使用的格式
+const sqliteVersionTimeLayout = "2006-01-02 15:04:05.999999999"
+
+// WithVersioning 设置表的版本列：更新时自动递增版本列，更新和删除可按期望版本过滤
+func (b *QueryBuilder) WithVersioning(versions []config.VersionConfig) *QueryBuilder {
+	b.versions = make(map[string]config.VersionConfig, len(versions))
+	for _, version := range versions {
+		b.versions[strings.ToLower(version.Table)] = version
+	}
+	return b
+}
+
+// versionColumn 获取表的版本列配置
+func (b *QueryBuilder) versionColumn(table string) (config.VersionConfig, bool) {
+	version, ok := b.versions[strings.ToLower(table)]
+	return version, ok
+}
+
+// versionIncrement 构建递增版本列的 SET 子句
+func (b *QueryBuilder) versionIncrement(version config.VersionConfig) string {
+	if version.GetType() == config.VersionTypeTimestamp {
+		return fmt.Sprintf("%s = %s", version.Column, b.currentTimestamp())
+	}
+	return fmt.Sprintf("%s = %s + 1", version.Column, version.Column)
+}
+
+// currentTimestamp 获取当前时间表达式
+func (b *QueryBuilder) currentTimestamp() string {
+	if function, ok := timestampFunctions[b.dbType]; ok {
+		return function
+	}
+	return "CURRENT_TIMESTAMP"
+}
+
+// versionCondition 构建期望版本条件，返回条件表达式和参数值；未指定期望版本时返回空条件
+func (b *QueryBuilder) versionCondition(query *model.StructuredQuery, paramIndex int) (string, interface{}, error) {
+	if query.ExpectedVersion == nil {
+		return "", nil, nil
+	}
+
+	version, ok := b.versionColumn(query.Table)
+	if !ok {
+		return "", nil, fmt.Errorf("table %s has no version column configured", query.Table)
+	}
+
+	value, err := b.versionValue(version, query.ExpectedVersion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	placeholder := b.getParameterPlaceholder(paramIndex)
+	if b.dbType == "sqlite" && version.GetType() == config.VersionTypeTimestamp {
+		// SQLite 的时间文本可能省略小数部分，按时间值比较
+		return fmt.Sprintf("julianday(%s) = julianday(%s)", version.Column, placeholder), value, nil
+	}
+	return fmt.Sprintf("%s = %s", version.Column, placeholder), value, nil
+}
+
+// versionValue 将期望版本转换为版本列类型的参数值
+func (b *QueryBuilder) versionValue(version config.VersionConfig, value interface{}) (interface{}, error) {
+	if version.GetType() == config.VersionTypeTimestamp {
+		var t time.Time
+		switch v := value.(type) {
+		case time.Time:
+			t = v
+		case string:
+			parsed, ok := parseVersionTime(v)
+			if !ok {
+				return nil, fmt.Errorf("invalid expected_version: %q is not a timestamp", v)
+			}
+			t = parsed
+		default:
+			return nil, fmt.Errorf("invalid expected_version: timestamp expected, got %T", value)
+		}
+
+		if b.dbType == "sqlite" {
+			return t.UTC().Format(sqliteVersionTimeLayout), nil
+		}
+		return t, nil
+	}
+
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("invalid expected_version: %v is not an integer", v)
+		}
+		return int64(v), nil
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected_version: %s is not an integer", v)
+		}
+		return n, nil
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected_version: %q is not an integer", v)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("invalid expected_version: integer expected, got %T", value)
+	}
+}
+
+// parseVersionTime 解析时间戳版本值
+func parseVersionTime(value string) (time.Time, bool) {
+	for _, layout := range versionTimeLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// VersionTag 将版本列的取值格式化为强实体标签（ETag），时间统一为 UTC 的 RFC 3339 格式
+func VersionTag(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		text = v.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+		if t, ok := parseVersionTime(v); ok {
+			text = t.UTC().Format(time.RFC3339Nano)
+		}
+	default:
+		text = fmt.Sprint(v)
+	}
+	return `"` + text + `"`
+}
+
+// ParseVersionTag 解析 If-Match 请求头中的实体标签，返回标签内容；"*" 表示任意版本，返回空字符串
+// 弱标签（W/）按强比较规则永远不匹配，返回 weak 为 true
+func ParseVersionTag(header string) (tag string, weak bool, err error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return "", false, nil
+	}
+	if strings.Contains(header, ",") {
+		return "", false, fmt.Errorf("If-Match must contain a single entity tag")
+	}
+	if strings.HasPrefix(header, "W/") {
+		return "", true, nil
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return "", false, fmt.Errorf("invalid entity tag: %s", header)
+	}
+	return header[1 : len(header)-1], false, nil
+}
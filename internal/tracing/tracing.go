@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"sql2api/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 本服务创建 span 时使用的 tracer 名称
+const instrumentationName = "sql2api"
+
+// Setup 按配置创建 TracerProvider 并设置为全局 provider，同时启用 W3C Trace Context 传播，
+// 返回用于导出剩余 span 并关闭导出器的函数。未启用时 span 不会被记录
+func Setup(ctx context.Context, cfg *config.TracingConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeOutput != nil {
+			err = errors.Join(err, closeOutput())
+		}
+		return err
+	}, nil
+}
+
+// newExporter 创建 span 导出器，file 导出方式同时返回关闭文件的函数
+func newExporter(ctx context.Context, cfg *config.TracingConfig) (sdktrace.SpanExporter, func() error, error) {
+	switch cfg.Exporter {
+	case config.TracingExporterOTLP:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			options = append(options, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		exporter, err := otlptracehttp.New(ctx, options...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case config.TracingExporterStdout:
+		exporter, err := newWriterExporter(os.Stdout)
+		return exporter, nil, err
+	case config.TracingExporterFile:
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open tracing file: %w", err)
+		}
+		exporter, err := newWriterExporter(file)
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.Exporter)
+	}
+}
+
+// newWriterExporter 创建以 JSON 格式逐行写出 span 的导出器
+func newWriterExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create span writer: %w", err)
+	}
+	return exporter, nil
+}
+
+// Start 开始一个 span，ctx 中已有 span 时作为其子 span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 结束 span，err 不为空时记录错误并将 span 标记为失败
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID 获取 ctx 中当前 span 的 trace ID，没有时返回空字符串
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
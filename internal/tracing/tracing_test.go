@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sql2api/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetup_FileExporter(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	file := filepath.Join(t.TempDir(), "traces.jsonl")
+	shutdown, err := Setup(context.Background(), &config.TracingConfig{
+		Enabled:     true,
+		Exporter:    config.TracingExporterFile,
+		File:        file,
+		ServiceName: "sql2api-test",
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	// 解析上游的 traceparent
+	carrier := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+
+	ctx, parent := Start(ctx, "parent")
+	if traceID := TraceID(ctx); traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected upstream trace ID, got %q", traceID)
+	}
+	_, child := Start(ctx, "child")
+	End(child, errors.New("boom"))
+	End(parent, nil)
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("Failed to open trace file: %v", err)
+	}
+	defer f.Close()
+
+	type exportedSpan struct {
+		Name        string
+		SpanContext struct{ TraceID string }
+		Status      struct{ Code string }
+	}
+	var spans []exportedSpan
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var span exportedSpan
+		if err := json.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatalf("Invalid span line %q: %v", scanner.Text(), err)
+		}
+		spans = append(spans, span)
+	}
+
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.SpanContext.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("Expected span %s in upstream trace, got %s", span.Name, span.SpanContext.TraceID)
+		}
+	}
+	if spans[0].Name != "child" || spans[0].Status.Code != "Error" {
+		t.Errorf("Expected failed child span first, got %+v", spans[0])
+	}
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), &config.TracingConfig{})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	if traceID := TraceID(context.Background()); traceID != "" {
+		t.Errorf("Expected no trace ID, got %q", traceID)
+	}
+}